@@ -0,0 +1,158 @@
+package clsag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+)
+
+// This file contains keys and key images for linkable ring signatures on Bandersnatch, following the structure of CLSAG (Goodell, Noether, Blue: "Concise Linkable Ring Signatures and Forgery Against Adversarial Keys").
+//
+// Notation: G is the generator curvePoints.SubgroupGenerator_xtw_subgroup of the prime-order subgroup, a private key is an exponent x != 0 and its public key is P = x*G.
+// A ring is a list of distinct public keys (P_0, ..., P_{n-1}) of type curvePoints.CurvePointSlice_xtw_subgroup.
+// The key image of x is I = x*H_p(P), where H_p(P) = hash_to_curve(enc(P)) uses the suite bandersnatch_XMD:SHA-512_ELL2_RO_ from RFC 9380 with DST KeyImageDST.
+// Since the key image only depends on the private key, two signatures (on arbitrary messages with arbitrary rings) created with the same key have the same key image,
+// which allows to detect double-spending without learning the signer.
+//
+// We only support a single key per ring member; for this case, CLSAG coincides with bLSAG (Noether, Mackenzie: "Ring Confidential Transactions").
+// Signatures are described in signature.go.
+//
+// Here, enc is the Banderwagon encoding pointserializer.BanderwagonShort; exponents are encoded as numbers with the same endianness (i.e. little endian).
+// Since the Banderwagon encoding can only represent points in the prime-order subgroup, decoding key images enforces subgroup membership.
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / clsag: "
+
+const (
+	PublicKeySize  = keypair.PointSize  // size in bytes of encoded public keys (ring members)
+	PrivateKeySize = keypair.ScalarSize // size in bytes of encoded private keys
+	KeyImageSize   = keypair.PointSize  // size in bytes of encoded key images
+)
+
+// Domain separation tags for hashing to the curve, hashing to exponents and the nonce derivation.
+const (
+	KeyImageDST  = "BANDERSNATCH-CLSAG-V01-CS01-with-bandersnatch_XMD:SHA-512_ELL2_RO_"
+	ChallengeDST = "BANDERSNATCH-CLSAG-V01-CHALLENGE"
+	NonceDST     = "BANDERSNATCH-CLSAG-V01-NONCE"
+	// RingDST is prepended to the hash of the ring, key image and message that enters all challenges.
+	RingDST = "BANDERSNATCH-CLSAG-V01-RING"
+)
+
+var (
+	ErrInvalidPrivateKey = errors.New(ErrorPrefix + "invalid private key")
+	ErrInvalidKeyImage   = errors.New(ErrorPrefix + "invalid key image")
+	ErrInvalidRing       = errors.New(ErrorPrefix + "invalid ring")
+	ErrNotInRing         = errors.New(ErrorPrefix + "public key of signer is not in the ring")
+	ErrInvalidSignature  = errors.New(ErrorPrefix + "invalid ring signature encoding")
+)
+
+// PrivateKey is a private key for ring signatures. The zero value is not a valid private key; use GenerateKey or PrivateKeyFromBytes to create one.
+type PrivateKey struct {
+	key      keypair.PrivateKey // private exponent x and public key P = x*G
+	keyImage KeyImage           // I = x*H_p(P)
+}
+
+// KeyImage is the key image I = x*H_p(P) of a private key x. Use PrivateKey.KeyImage, Signature.KeyImage or KeyImageFromBytes to obtain one.
+//
+// Key images are never the neutral element.
+type KeyImage struct {
+	point   curvePoints.Point_xtw_subgroup
+	encoded [KeyImageSize]byte // cached encoding of point
+}
+
+// hashPublicKey computes H_p(P) from the encoding of P.
+func hashPublicKey(encodedPublicKey *[PublicKeySize]byte) curvePoints.Point_xtw_subgroup {
+	return curvePoints.HashToCurve(encodedPublicKey[:], []byte(KeyImageDST))
+}
+
+// GenerateKey generates a new private key, reading randomness from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// We return an error if reading from rnd fails.
+func GenerateKey(rnd io.Reader) (*PrivateKey, error) {
+	key, err := keypair.GenerateKey(rnd)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivateKey(&key), nil
+}
+
+// newPrivateKey creates a private key for ring signatures from key, computing its key image.
+func newPrivateKey(key *keypair.PrivateKey) (ret *PrivateKey) {
+	ret = &PrivateKey{key: *key}
+	encodedPublicKey := key.PublicKey().Encoding()
+	hashedPublicKey := hashPublicKey(&encodedPublicKey)
+	ret.keyImage.point.ExponentiateConstantTime(&hashedPublicKey, key.Scalar())
+	ret.keyImage.encoded = keypair.EncodePoint(&ret.keyImage.point)
+	return
+}
+
+// PrivateKeyFromBytes decodes a private key in the format written by PrivateKey.Bytes.
+//
+// We return an error wrapping ErrInvalidPrivateKey if buf has the wrong length, encodes a number >= p253 or encodes 0.
+func PrivateKeyFromBytes(buf []byte) (*PrivateKey, error) {
+	key, err := keypair.PrivateKeyFromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+	return newPrivateKey(&key), nil
+}
+
+// Bytes returns the encoding of the private key. Note that the result is secret.
+func (sk *PrivateKey) Bytes() []byte {
+	return sk.key.Bytes()
+}
+
+// PublicKey returns the public key P = x*G corresponding to sk, which is what needs to be put into rings.
+func (sk *PrivateKey) PublicKey() curvePoints.Point_xtw_subgroup {
+	return *sk.key.PublicKey().Point()
+}
+
+// KeyImage returns the key image I = x*H_p(P) of sk, which is contained in every signature created with sk.
+func (sk *PrivateKey) KeyImage() KeyImage {
+	return sk.keyImage
+}
+
+// Equal checks whether sk and other are the same private key.
+func (sk *PrivateKey) Equal(other *PrivateKey) bool {
+	return sk.key.Equal(&other.key)
+}
+
+// KeyImageFromBytes decodes a key image in the format written by KeyImage.Bytes.
+//
+// We return an error wrapping ErrInvalidKeyImage if buf is not a valid encoding of a point in the prime-order subgroup or encodes the neutral element.
+func KeyImageFromBytes(buf []byte) (KeyImage, error) {
+	point, err := keypair.DecodePoint(buf)
+	if err != nil {
+		return KeyImage{}, fmt.Errorf("%w: %v", ErrInvalidKeyImage, err)
+	}
+	if point.IsNeutralElement() {
+		return KeyImage{}, fmt.Errorf("%w: key image is the neutral element", ErrInvalidKeyImage)
+	}
+	ret := KeyImage{point: point}
+	copy(ret.encoded[:], buf)
+	return ret, nil
+}
+
+// Bytes returns the encoding of the key image.
+func (ki *KeyImage) Bytes() []byte {
+	ret := ki.encoded
+	return ret[:]
+}
+
+// Encoding returns the encoding of the key image as an array. Since the encoding is unique, this can be used as a key in maps to detect double-spending.
+func (ki *KeyImage) Encoding() [KeyImageSize]byte {
+	return ki.encoded
+}
+
+// Point returns the key image as a curve point.
+func (ki *KeyImage) Point() curvePoints.Point_xtw_subgroup {
+	return ki.point
+}
+
+// IsEqual checks whether ki and other are the same key image.
+func (ki *KeyImage) IsEqual(other *KeyImage) bool {
+	return ki.encoded == other.encoded
+}
@@ -0,0 +1,211 @@
+package clsag
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// makeRing creates n private keys and the ring of their public keys.
+func makeRing(n int, rnd *rand.Rand) ([]*PrivateKey, curvePoints.CurvePointSlice_xtw_subgroup) {
+	keys := make([]*PrivateKey, n)
+	ring := make(curvePoints.CurvePointSlice_xtw_subgroup, n)
+	for i := range keys {
+		var err error
+		keys[i], err = GenerateKey(rnd)
+		if err != nil {
+			panic(err)
+		}
+		ring[i] = keys[i].PublicKey()
+	}
+	return keys, ring
+}
+
+func TestSignVerify(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	msg := []byte("ring signature test message")
+	for _, n := range []int{1, 2, 3, 7} {
+		keys, ring := makeRing(n, rnd)
+		for j := range keys {
+			sig, err := Sign(keys[j], ring, msg, rnd)
+			testutils.FatalUnless(t, err == nil, "Sign failed: %v", err)
+			testutils.FatalUnless(t, sig.RingSize() == n, "wrong ring size")
+			testutils.FatalUnless(t, Verify(ring, msg, sig), "valid signature rejected for ring size %v and signer %v", n, j)
+			keyImage, expected := sig.KeyImage(), keys[j].KeyImage()
+			testutils.FatalUnless(t, keyImage.IsEqual(&expected), "signature has wrong key image")
+
+			testutils.FatalUnless(t, !Verify(ring, []byte("other message"), sig), "signature verifies for other message")
+			if n > 1 {
+				testutils.FatalUnless(t, !Verify(ring[1:], msg, sig), "signature verifies for smaller ring")
+				permuted := append(curvePoints.CurvePointSlice_xtw_subgroup(nil), ring...)
+				permuted[0], permuted[n-1] = permuted[n-1], permuted[0]
+				testutils.FatalUnless(t, !Verify(permuted, msg, sig), "signature verifies for permuted ring")
+			}
+			var one exponents.Exponent
+			one.SetOne()
+			for i := range sig.s {
+				modified := *sig
+				modified.s = append([]exponents.Exponent(nil), sig.s...)
+				modified.s[i].Add(&modified.s[i], &one)
+				testutils.FatalUnless(t, !Verify(ring, msg, &modified), "signature with modified s_%v verifies", i)
+			}
+			modified := *sig
+			modified.c0.Add(&modified.c0, &one)
+			testutils.FatalUnless(t, !Verify(ring, msg, &modified), "signature with modified c_0 verifies")
+			// replacing the key image breaks the signature
+			otherKey, _ := GenerateKey(rnd)
+			modified = *sig
+			modified.keyImage = otherKey.KeyImage()
+			testutils.FatalUnless(t, !Verify(ring, msg, &modified), "signature with modified key image verifies")
+		}
+	}
+}
+
+func TestLinkability(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	keys, ring1 := makeRing(4, rnd)
+	otherKeys, ring2 := makeRing(3, rnd)
+	ring2[1] = keys[2].PublicKey()
+
+	sig1, err := Sign(keys[2], ring1, []byte("first"), rnd)
+	testutils.FatalUnless(t, err == nil, "Sign failed: %v", err)
+	sig2, err := Sign(keys[2], ring2, []byte("second"), rnd)
+	testutils.FatalUnless(t, err == nil, "Sign failed: %v", err)
+	sig3, err := Sign(keys[1], ring1, []byte("first"), rnd)
+	testutils.FatalUnless(t, err == nil, "Sign failed: %v", err)
+	sig4, err := Sign(otherKeys[0], ring2, []byte("second"), rnd)
+	testutils.FatalUnless(t, err == nil, "Sign failed: %v", err)
+	testutils.FatalUnless(t, Verify(ring1, []byte("first"), sig1) && Verify(ring2, []byte("second"), sig2), "valid signature rejected")
+	testutils.FatalUnless(t, Link(sig1, sig2), "signatures with the same key are not linked")
+	testutils.FatalUnless(t, !Link(sig1, sig3) && !Link(sig2, sig4), "signatures with different keys are linked")
+
+	// detection of double-signing via the encoding of key images
+	seen := make(map[[KeyImageSize]byte]int)
+	for i, sig := range []*Signature{sig1, sig3, sig4, sig2} {
+		keyImage := sig.KeyImage()
+		if previous, ok := seen[keyImage.Encoding()]; ok {
+			testutils.FatalUnless(t, previous == 0 && i == 3, "wrong double-signing detected")
+		}
+		seen[keyImage.Encoding()] = i
+	}
+	testutils.FatalUnless(t, len(seen) == 3, "double-signing not detected")
+
+	// the key image is independent of the ring and the randomness
+	sig5, _ := Sign(keys[2], ring1, []byte("first"), rnd)
+	testutils.FatalUnless(t, Link(sig1, sig5), "signatures with the same key are not linked")
+	testutils.FatalUnless(t, !sig1.c0.IsEqual(&sig5.c0), "signing is deterministic")
+}
+
+func TestSignErrors(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	keys, ring := makeRing(3, rnd)
+	outsider, _ := GenerateKey(rnd)
+	msg := []byte("message")
+	_, err := Sign(outsider, ring, msg, rnd)
+	testutils.FatalUnless(t, errors.Is(err, ErrNotInRing), "signing with key outside the ring succeeded")
+	_, err = Sign(keys[0], nil, msg, rnd)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidRing), "empty ring accepted")
+
+	badRings := []curvePoints.CurvePointSlice_xtw_subgroup{
+		{ring[0], ring[1], ring[0]},
+		{ring[0], curvePoints.NeutralElement_xtw_subgroup},
+		{ring[0], curvePoints.Point_xtw_subgroup{}},
+	}
+	for _, badRing := range badRings {
+		_, err = Sign(keys[0], badRing, msg, rnd)
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidRing), "invalid ring accepted by Sign")
+	}
+	sig, _ := Sign(keys[0], ring[0:2], msg, rnd)
+	for _, badRing := range badRings[0:2] {
+		wrongSize := *sig
+		wrongSize.s = append(wrongSize.s, wrongSize.s[0])[0:len(badRing)]
+		testutils.FatalUnless(t, !Verify(badRing, msg, &wrongSize), "invalid ring accepted by Verify")
+	}
+
+	designatedErr := errors.New("designated error")
+	_, err = Sign(keys[0], ring, msg, iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "Sign did not report randomness failure")
+	_, err = GenerateKey(iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "GenerateKey did not report randomness failure")
+}
+
+func TestEncoding(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	keys, ring := makeRing(5, rnd)
+	msg := []byte("message")
+	sig, err := Sign(keys[3], ring, msg, rnd)
+	testutils.FatalUnless(t, err == nil, "Sign failed: %v", err)
+	encoded := sig.Bytes()
+	testutils.FatalUnless(t, len(encoded) == SignatureSize(5), "wrong signature length")
+	decoded, err := SignatureFromBytes(encoded)
+	testutils.FatalUnless(t, err == nil && Verify(ring, msg, decoded), "signature roundtrip failed: %v", err)
+	testutils.FatalUnless(t, Link(sig, decoded), "signature roundtrip changed key image")
+
+	for _, length := range []int{0, SignatureSize(0), SignatureSize(1) - 1, len(encoded) + 1} {
+		_, err = SignatureFromBytes(make([]byte, length))
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignature), "signature of length %v accepted", length)
+	}
+	bad := append([]byte(nil), encoded...)
+	for i := len(bad) - exponents.ExponentBytesLength; i < len(bad); i++ {
+		bad[i] = 0xff
+	}
+	_, err = SignatureFromBytes(bad)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignature), "non-reduced exponent accepted")
+	bad = append([]byte(nil), encoded...)
+	neutral := keypair.EncodePoint(&curvePoints.NeutralElement_xtw_subgroup)
+	copy(bad, neutral[:])
+	_, err = SignatureFromBytes(bad)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignature), "neutral key image accepted")
+
+	// key images
+	keyImage := keys[0].KeyImage()
+	decodedKeyImage, err := KeyImageFromBytes(keyImage.Bytes())
+	testutils.FatalUnless(t, err == nil && decodedKeyImage.IsEqual(&keyImage), "key image roundtrip failed: %v", err)
+	p1, p2 := keyImage.Point(), decodedKeyImage.Point()
+	testutils.FatalUnless(t, p1.IsEqual(&p2), "key image roundtrip failed")
+	_, err = KeyImageFromBytes(neutral[:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidKeyImage), "neutral key image accepted")
+	_, err = KeyImageFromBytes(neutral[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidKeyImage), "key image with wrong length accepted")
+
+	// private keys
+	decodedKey, err := PrivateKeyFromBytes(keys[0].Bytes())
+	testutils.FatalUnless(t, err == nil && decodedKey.Equal(keys[0]), "private key roundtrip failed: %v", err)
+	decodedKeyImage = decodedKey.KeyImage()
+	testutils.FatalUnless(t, decodedKeyImage.IsEqual(&keyImage), "private key roundtrip changed key image")
+	_, err = PrivateKeyFromBytes(make([]byte, PrivateKeySize))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "zero private key accepted")
+}
+
+// TestKeyImageDefinition checks that the key image is x*H_p(P).
+func TestKeyImageDefinition(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	sk, _ := GenerateKey(rnd)
+	var x exponents.Exponent
+	testutils.FatalUnless(t, x.SetCanonicalBytes(sk.Bytes(), keypair.ScalarEndianness) == nil, "could not decode private key")
+	publicKey := sk.PublicKey()
+	encodedPublicKey := keypair.EncodePoint(&publicKey)
+	hashed := curvePoints.HashToCurve(encodedPublicKey[:], []byte(KeyImageDST))
+	var expected curvePoints.Point_xtw_subgroup
+	expected.Exponentiate(&hashed, &x)
+	keyImage := sk.KeyImage()
+	got := keyImage.Point()
+	testutils.FatalUnless(t, got.IsEqual(&expected), "key image is not x*H_p(P)")
+}
+
+func BenchmarkVerify(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	keys, ring := makeRing(16, rnd)
+	msg := []byte("message")
+	sig, _ := Sign(keys[5], ring, msg, rnd)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Verify(ring, msg, sig)
+	}
+}
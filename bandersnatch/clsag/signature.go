@@ -0,0 +1,252 @@
+package clsag
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+)
+
+// This file contains linkable ring signatures. Notation is as in clsag.go.
+//
+// To sign a message msg with the private key x of the ring member P_j (with key image I), we compute
+//
+//	m = SHA-512(RingDST || n || enc(P_0) || ... || enc(P_{n-1}) || enc(I) || msg)
+//
+// where n is encoded as a big-endian uint64. For a nonce a, we set c_{j+1} = H(m || enc(a*G) || enc(a*H_p(P_j))) and, for i = j+1, ..., j-1 (indices modulo n) and random s_i,
+//
+//	c_{i+1} = H(m || enc(s_i*G + c_i*P_i) || enc(s_i*H_p(P_i) + c_i*I))
+//
+// where H is exponents.HashToExponent with DST ChallengeDST. Finally, s_j = a - c_j*x, which closes the ring. The signature is (I, c_0, s_0, ..., s_{n-1}).
+// A verifier recomputes the c_i, starting from c_0, and checks that c_n == c_0. This takes 2n two-term multi-exponentiations, 2n hashes and n hash-to-curve operations.
+//
+// The nonce a is derived from fresh randomness, x and m (via exponents.HashToExponent with DST NonceDST), so a bad source of randomness does not leak x.
+//
+// NOTE: Signing is constant-time with respect to x, but NOT with respect to the position j of the signer in the ring.
+
+// SignatureSize returns the size in bytes of an encoded signature for a ring with ringSize members: enc(I) || c_0 || s_0 || ... || s_{n-1}.
+func SignatureSize(ringSize int) int {
+	return KeyImageSize + (ringSize+1)*exponents.ExponentBytesLength
+}
+
+// Signature is a linkable ring signature (I, c_0, s_0, ..., s_{n-1}). Use Sign or SignatureFromBytes to obtain one.
+type Signature struct {
+	keyImage KeyImage
+	c0       exponents.Exponent   // fully reduced
+	s        []exponents.Exponent // fully reduced, one per ring member
+}
+
+// preparedRing holds the values derived from a ring that are needed for signing and verification.
+type preparedRing struct {
+	points  curvePoints.CurvePointSlice_xtw_subgroup // P_i
+	hashed  curvePoints.CurvePointSlice_xtw_subgroup // H_p(P_i)
+	encoded [][PublicKeySize]byte                    // enc(P_i)
+}
+
+// prepareRing validates ring and computes H_p(P_i) for all ring members.
+//
+// We return an error wrapping ErrInvalidRing if ring is empty, contains a NaP or the neutral element or contains a public key more than once.
+func prepareRing(ring curvePoints.CurvePointSlice_xtw_subgroup) (*preparedRing, error) {
+	if len(ring) == 0 {
+		return nil, fmt.Errorf("%w: ring is empty", ErrInvalidRing)
+	}
+	ret := &preparedRing{
+		points:  append(curvePoints.CurvePointSlice_xtw_subgroup(nil), ring...),
+		hashed:  make(curvePoints.CurvePointSlice_xtw_subgroup, len(ring)),
+		encoded: make([][PublicKeySize]byte, len(ring)),
+	}
+	seen := make(map[[PublicKeySize]byte]struct{}, len(ring))
+	for i := range ring {
+		if ring[i].IsNaP() {
+			return nil, fmt.Errorf("%w: ring member %v is a NaP", ErrInvalidRing, i)
+		}
+		if ring[i].IsNeutralElement() {
+			return nil, fmt.Errorf("%w: ring member %v is the neutral element", ErrInvalidRing, i)
+		}
+		ret.encoded[i] = keypair.EncodePoint(&ring[i])
+		if _, ok := seen[ret.encoded[i]]; ok {
+			return nil, fmt.Errorf("%w: ring member %v appears more than once", ErrInvalidRing, i)
+		}
+		seen[ret.encoded[i]] = struct{}{}
+		ret.hashed[i] = hashPublicKey(&ret.encoded[i])
+	}
+	return ret, nil
+}
+
+// messageHash computes m = SHA-512(RingDST || n || enc(P_0) || ... || enc(P_{n-1}) || enc(I) || msg), which enters all challenges.
+func (r *preparedRing) messageHash(keyImage *KeyImage, msg []byte) []byte {
+	h := sha512.New()
+	h.Write([]byte(RingDST))
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(r.points)))
+	h.Write(length[:])
+	for i := range r.encoded {
+		h.Write(r.encoded[i][:])
+	}
+	h.Write(keyImage.encoded[:])
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// challenge computes H(m || enc(L) || enc(R)).
+func challenge(m []byte, L, R curvePoints.CurvePointPtrInterfaceRead) exponents.Exponent {
+	encodedL, encodedR := keypair.EncodePoint(L), keypair.EncodePoint(R)
+	input := make([]byte, 0, len(m)+2*PublicKeySize)
+	input = append(input, m...)
+	input = append(input, encodedL[:]...)
+	input = append(input, encodedR[:]...)
+	c, err := exponents.HashToExponent(input, []byte(ChallengeDST))
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when computing challenge: %w", err))
+	}
+	return c
+}
+
+// nextChallenge computes c_{i+1} = H(m || enc(s_i*G + c_i*P_i) || enc(s_i*H_p(P_i) + c_i*I)).
+func (r *preparedRing) nextChallenge(m []byte, i int, c *exponents.Exponent, s *exponents.Exponent, keyImage *KeyImage) exponents.Exponent {
+	scalars := []exponents.Exponent{*s, *c}
+	L := curvePoints.MultiExponentiate(curvePoints.CurvePointSlice_xtw_subgroup{curvePoints.SubgroupGenerator_xtw_subgroup, r.points[i]}, scalars)
+	R := curvePoints.MultiExponentiate(curvePoints.CurvePointSlice_xtw_subgroup{r.hashed[i], keyImage.point}, scalars)
+	return challenge(m, &L, &R)
+}
+
+// Sign creates a linkable ring signature on msg with respect to ring, reading randomness from rnd. If rnd is nil, we use crypto/rand.Reader.
+// The public key of sk must be contained in ring.
+//
+// We return an error wrapping ErrInvalidRing if the ring is invalid (see Verify), ErrNotInRing if the public key of sk is not in the ring
+// and an error if reading from rnd fails.
+func Sign(sk *PrivateKey, ring curvePoints.CurvePointSlice_xtw_subgroup, msg []byte, rnd io.Reader) (*Signature, error) {
+	r, err := prepareRing(ring)
+	if err != nil {
+		return nil, err
+	}
+	n := len(r.points)
+	j := -1
+	encodedPublicKey := sk.key.PublicKey().Encoding()
+	for i := range r.encoded {
+		if r.encoded[i] == encodedPublicKey {
+			j = i
+			break
+		}
+	}
+	if j < 0 {
+		return nil, ErrNotInRing
+	}
+	m := r.messageHash(&sk.keyImage, msg)
+
+	// derive the nonce a from fresh randomness, x and m
+	nonceInput := make([]byte, 32, 32+PrivateKeySize+len(m))
+	if err = common.ReadRandomBytes(rnd, nonceInput); err != nil {
+		return nil, err
+	}
+	nonceInput = append(nonceInput, sk.Bytes()...)
+	nonceInput = append(nonceInput, m...)
+	a := keypair.DeriveNonce(nonceInput, NonceDST)
+
+	ret := &Signature{keyImage: sk.keyImage, s: make([]exponents.Exponent, n)}
+	var L, R curvePoints.Point_xtw_subgroup
+	L.ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, &a)
+	R.ExponentiateConstantTime(&r.hashed[j], &a)
+	c := challenge(m, &L, &R)
+	for k := 1; k < n; k++ {
+		i := (j + k) % n
+		if i == 0 {
+			ret.c0 = c
+		}
+		if err = ret.s[i].SetRandom(rnd); err != nil {
+			return nil, err
+		}
+		c = r.nextChallenge(m, i, &c, &ret.s[i], &sk.keyImage)
+	}
+	if j == 0 {
+		ret.c0 = c
+	}
+	// s_j = a - c_j * x
+	ret.s[j].Mul(&c, sk.key.Scalar())
+	ret.s[j].Sub(&a, &ret.s[j])
+	ret.s[j] = ret.s[j].ModuloP253()
+	ret.c0 = ret.c0.ModuloP253()
+	return ret, nil
+}
+
+// Verify checks whether sig is a valid ring signature on msg with respect to ring.
+//
+// We return false if ring is invalid, i.e. if it is empty, contains a NaP or the neutral element or contains a public key more than once, or if the ring size does not match the signature.
+// Note that the key image of a valid signature needs to be checked against previously seen key images (e.g. via Link or KeyImage.Encoding) to detect double-signing.
+func Verify(ring curvePoints.CurvePointSlice_xtw_subgroup, msg []byte, sig *Signature) bool {
+	if len(sig.s) != len(ring) {
+		return false
+	}
+	r, err := prepareRing(ring)
+	if err != nil {
+		return false
+	}
+	m := r.messageHash(&sig.keyImage, msg)
+	c := sig.c0
+	for i := range r.points {
+		c = r.nextChallenge(m, i, &c, &sig.s[i], &sig.keyImage)
+	}
+	return c.IsEqual(&sig.c0)
+}
+
+// Link checks whether two signatures were created with the same private key, i.e. whether they have the same key image.
+// This is only meaningful for valid signatures.
+func Link(sig1, sig2 *Signature) bool {
+	return sig1.keyImage.IsEqual(&sig2.keyImage)
+}
+
+// KeyImage returns the key image I of the signature.
+func (sig *Signature) KeyImage() KeyImage {
+	return sig.keyImage
+}
+
+// RingSize returns the size of the ring the signature was created for.
+func (sig *Signature) RingSize() int {
+	return len(sig.s)
+}
+
+// Bytes returns the encoding enc(I) || c_0 || s_0 || ... || s_{n-1} of the signature.
+func (sig *Signature) Bytes() []byte {
+	ret := make([]byte, 0, SignatureSize(len(sig.s)))
+	ret = append(ret, sig.keyImage.encoded[:]...)
+	c0Bytes := keypair.EncodeScalar(&sig.c0)
+	ret = append(ret, c0Bytes[:]...)
+	for i := range sig.s {
+		sBytes := keypair.EncodeScalar(&sig.s[i])
+		ret = append(ret, sBytes[:]...)
+	}
+	return ret
+}
+
+// SignatureFromBytes decodes a signature in the format written by Signature.Bytes. The ring size is determined by the length of buf.
+//
+// We return an error wrapping ErrInvalidSignature if the length of buf does not correspond to a ring of size at least 1, the key image is invalid or some exponent encodes a number >= p253.
+// Note that succesfully decoding a signature says nothing about its validity.
+func SignatureFromBytes(buf []byte) (*Signature, error) {
+	if len(buf) < SignatureSize(1) || (len(buf)-KeyImageSize)%exponents.ExponentBytesLength != 0 {
+		return nil, fmt.Errorf("%w: signature has invalid length %v", ErrInvalidSignature, len(buf))
+	}
+	ringSize := (len(buf)-KeyImageSize)/exponents.ExponentBytesLength - 1
+	var ret Signature
+	var err error
+	if ret.keyImage, err = KeyImageFromBytes(buf[0:KeyImageSize]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	buf = buf[KeyImageSize:]
+	if ret.c0, err = keypair.DecodeScalar(buf[0:exponents.ExponentBytesLength]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	ret.s = make([]exponents.Exponent, ringSize)
+	for i := range ret.s {
+		buf = buf[exponents.ExponentBytesLength:]
+		if ret.s[i], err = keypair.DecodeScalar(buf[0:exponents.ExponentBytesLength]); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+	}
+	return &ret, nil
+}
@@ -0,0 +1,177 @@
+package common
+
+import (
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// This file contains expand_message_xmd and expand_message_xof from RFC 9380, Sections 5.3.1 and 5.3.2.
+// expand_message turns a message of arbitrary length into a pseudorandom byte string of a requested length,
+// which is then used to derive field elements, exponents or curve points (hash-to-field / hash-to-curve) without modular bias.
+//
+// The domain separation tag (DST) must be chosen by the application; it should be unique per protocol and per use, see RFC 9380, Section 3.1.
+// DSTs longer than 255 bytes are handled as described in RFC 9380, Section 5.3.3.
+
+// ErrEmptyDST is returned if expand_message is called with an empty domain separation tag, which RFC 9380 forbids.
+var ErrEmptyDST = errors.New(ErrorPrefix + "domain separation tag for expand_message must be non-empty")
+
+// ErrExpandMessageLength is returned (possibly wrapped) if the requested output length of expand_message is too large.
+var ErrExpandMessageLength = errors.New(ErrorPrefix + "requested output length for expand_message is too large")
+
+// MessageExpander is an interface for the expand_message functions of RFC 9380.
+//
+// ExpandMessage(msg, dst, lenInBytes) returns a pseudorandom byte slice of length lenInBytes, derived from msg with domain separation tag dst.
+type MessageExpander interface {
+	ExpandMessage(msg []byte, dst []byte, lenInBytes int) ([]byte, error)
+}
+
+// ExpanderXMD is a [MessageExpander] implementing expand_message_xmd from RFC 9380 for the given hash function.
+//
+// Note that the caller is responsible for linking in the hash function (e.g. by importing crypto/sha256 resp. crypto/sha512).
+type ExpanderXMD struct {
+	Hash crypto.Hash
+}
+
+// ExpandMessage implements [MessageExpander] via expand_message_xmd with hash function e.Hash.
+func (e ExpanderXMD) ExpandMessage(msg []byte, dst []byte, lenInBytes int) ([]byte, error) {
+	return ExpandMessageXMD(e.Hash, msg, dst, lenInBytes)
+}
+
+// oversizeDSTPrefix is the prefix used to shorten DSTs that are longer than 255 bytes.
+const oversizeDSTPrefix = "H2C-OVERSIZE-DST-"
+
+// ExpandMessageXMD implements expand_message_xmd from RFC 9380, Section 5.3.1 with the given hash function.
+//
+// It outputs lenInBytes many pseudorandom bytes, derived from msg using the domain separation tag dst.
+// The hash function needs to be a Merkle-Damgard hash function such as SHA-256 or SHA-512 and must be linked into the binary.
+// The function returns an error if dst is empty or lenInBytes is too large (i.e. > 65535 or > 255 * hash.Size()).
+func ExpandMessageXMD(hash crypto.Hash, msg []byte, dst []byte, lenInBytes int) ([]byte, error) {
+	if len(dst) == 0 {
+		return nil, ErrEmptyDST
+	}
+	if !hash.Available() {
+		panic(fmt.Errorf(ErrorPrefix+"ExpandMessageXMD called with unavailable hash function %v", hash))
+	}
+	h := hash.New()
+	bInBytes := h.Size()
+	sInBytes := h.BlockSize()
+	if lenInBytes < 0 {
+		panic(fmt.Errorf(ErrorPrefix+"ExpandMessageXMD called with negative output length %v", lenInBytes))
+	}
+	ell := (lenInBytes + bInBytes - 1) / bInBytes
+	if ell > 255 || lenInBytes > 65535 {
+		return nil, fmt.Errorf("%w: requested %v bytes with hash function %v", ErrExpandMessageLength, lenInBytes, hash)
+	}
+
+	// RFC 9380, Section 5.3.3: Long DSTs are replaced by H("H2C-OVERSIZE-DST-" || DST)
+	if len(dst) > 255 {
+		h.Write([]byte(oversizeDSTPrefix))
+		h.Write(dst)
+		dst = h.Sum(nil)
+		h.Reset()
+	}
+	// DST_prime = DST || I2OSP(len(DST), 1)
+	dstPrime := make([]byte, len(dst)+1)
+	copy(dstPrime, dst)
+	dstPrime[len(dst)] = byte(len(dst))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	var lenInBytesStr [2]byte
+	binary.BigEndian.PutUint16(lenInBytesStr[:], uint16(lenInBytes))
+	h.Write(make([]byte, sInBytes))
+	h.Write(msg)
+	h.Write(lenInBytesStr[:])
+	h.Write([]byte{0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
+	uniformBytes := make([]byte, 0, ell*bInBytes)
+	bi := make([]byte, bInBytes)
+	for i := 1; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j] // for i == 1, bi is all-zero, so this sets bi = b_0
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(bi[:0])
+		uniformBytes = append(uniformBytes, bi...)
+	}
+	return uniformBytes[:lenInBytes], nil
+}
+
+// ExtendableOutputFunction is the interface satisfied by extendable-output functions such as SHAKE128 or SHAKE256.
+//
+// Input is absorbed via Write; the output is then squeezed out via Read. Reset restores the initial state.
+// Note that (the pointer types of) sha3.SHAKE from the standard library (Go 1.24+) and sha3.ShakeHash from golang.org/x/crypto/sha3 satisfy this interface.
+type ExtendableOutputFunction interface {
+	io.Writer
+	io.Reader
+	Reset()
+}
+
+// ExpanderXOF is a [MessageExpander] implementing expand_message_xof from RFC 9380.
+//
+// NewXOF must return a freshly initialized extendable-output function. SecurityLevel is the target security level k in bits (e.g. 128 for SHAKE128);
+// this is only used to shorten long DSTs.
+//
+// We do not depend on any particular XOF implementation; users need to provide their own (e.g. SHAKE128 or SHAKE256).
+type ExpanderXOF struct {
+	NewXOF        func() ExtendableOutputFunction
+	SecurityLevel int
+}
+
+// ExpandMessage implements [MessageExpander] via expand_message_xof.
+func (e ExpanderXOF) ExpandMessage(msg []byte, dst []byte, lenInBytes int) ([]byte, error) {
+	return ExpandMessageXOF(e.NewXOF(), e.SecurityLevel, msg, dst, lenInBytes)
+}
+
+// ExpandMessageXOF implements expand_message_xof from RFC 9380, Section 5.3.2 with the given extendable-output function xof.
+//
+// It outputs lenInBytes many pseudorandom bytes, derived from msg using the domain separation tag dst.
+// xof must be in its initial state. securityLevel is the target security level k in bits, which is only used to shorten DSTs longer than 255 bytes.
+// The function returns an error if dst is empty or lenInBytes > 65535.
+func ExpandMessageXOF(xof ExtendableOutputFunction, securityLevel int, msg []byte, dst []byte, lenInBytes int) ([]byte, error) {
+	if len(dst) == 0 {
+		return nil, ErrEmptyDST
+	}
+	if lenInBytes < 0 {
+		panic(fmt.Errorf(ErrorPrefix+"ExpandMessageXOF called with negative output length %v", lenInBytes))
+	}
+	if lenInBytes > 65535 {
+		return nil, fmt.Errorf("%w: requested %v bytes", ErrExpandMessageLength, lenInBytes)
+	}
+
+	// RFC 9380, Section 5.3.3: Long DSTs are replaced by H("H2C-OVERSIZE-DST-" || DST, ceil(2 * k / 8))
+	if len(dst) > 255 {
+		if securityLevel <= 0 || securityLevel > 1000 {
+			panic(fmt.Errorf(ErrorPrefix+"ExpandMessageXOF called with invalid security level %v", securityLevel))
+		}
+		xof.Write([]byte(oversizeDSTPrefix))
+		xof.Write(dst)
+		dst = make([]byte, (2*securityLevel+7)/8)
+		if _, err := io.ReadFull(xof, dst); err != nil {
+			panic(fmt.Errorf(ErrorPrefix+"reading from XOF failed: %w", err))
+		}
+		xof.Reset()
+	}
+
+	// uniform_bytes = H(msg || I2OSP(len_in_bytes, 2) || DST || I2OSP(len(DST), 1), len_in_bytes)
+	var lenInBytesStr [2]byte
+	binary.BigEndian.PutUint16(lenInBytesStr[:], uint16(lenInBytes))
+	xof.Write(msg)
+	xof.Write(lenInBytesStr[:])
+	xof.Write(dst)
+	xof.Write([]byte{byte(len(dst))})
+	uniformBytes := make([]byte, lenInBytes)
+	if _, err := io.ReadFull(xof, uniformBytes); err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"reading from XOF failed: %w", err))
+	}
+	return uniformBytes, nil
+}
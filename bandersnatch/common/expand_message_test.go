@@ -0,0 +1,62 @@
+package common
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+var _ MessageExpander = ExpanderXMD{}
+
+// test vectors from RFC 9380, Appendix K.1 and K.3
+var expandMessageXMDTestVectors = []struct {
+	hash       crypto.Hash
+	dst        string
+	msg        string
+	lenInBytes int
+	expected   string
+}{
+	{crypto.SHA256, "QUUX-V01-CS02-with-expander-SHA256-128", "", 0x20, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+	{crypto.SHA256, "QUUX-V01-CS02-with-expander-SHA256-128", "abc", 0x20, "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+	{crypto.SHA256, "QUUX-V01-CS02-with-expander-SHA256-128", "", 0x80, "af84c27ccfd45d41914fdff5df25293e221afc53d8ad2ac06d5e3e29485dadbee0d121587713a3e0dd4d5e69e93eb7cd4f5df4cd103e188cf60cb02edc3edf18eda8576c412b18ffb658e3dd6ec849469b979d444cf7b26911a08e63cf31f9dcc541708d3491184472c2c29bb749d4286b004ceb5ee6b9a7fa5b646c993f0ced"},
+	{crypto.SHA512, "QUUX-V01-CS02-with-expander-SHA512-256", "", 0x20, "6b9a7312411d92f921c6f68ca0b6380730a1a4d982c507211a90964c394179ba"},
+}
+
+func TestExpandMessageXMD(t *testing.T) {
+	for _, testVector := range expandMessageXMDTestVectors {
+		result, err := ExpandMessageXMD(testVector.hash, []byte(testVector.msg), []byte(testVector.dst), testVector.lenInBytes)
+		testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+		testutils.FatalUnless(t, hex.EncodeToString(result) == testVector.expected, "ExpandMessageXMD does not match test vector for msg %q, length %v", testVector.msg, testVector.lenInBytes)
+		result2, err := ExpanderXMD{Hash: testVector.hash}.ExpandMessage([]byte(testVector.msg), []byte(testVector.dst), testVector.lenInBytes)
+		testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+		testutils.FatalUnless(t, bytes.Equal(result, result2), "ExpanderXMD differs from ExpandMessageXMD")
+	}
+
+	// prefixes of the output for shorter lengths are NOT related (the length is part of the input), but lengths that are not a multiple of the hash size should work.
+	result, err := ExpandMessageXMD(crypto.SHA256, []byte("abc"), []byte("DST"), 47)
+	testutils.FatalUnless(t, err == nil && len(result) == 47, "ExpandMessageXMD failed for length 47")
+
+	// long DSTs are hashed
+	longDST := bytes.Repeat([]byte("x"), 256)
+	hashedDST := sha256.Sum256(append([]byte(oversizeDSTPrefix), longDST...))
+	result1, err1 := ExpandMessageXMD(crypto.SHA256, []byte("abc"), longDST, 32)
+	result2, err2 := ExpandMessageXMD(crypto.SHA256, []byte("abc"), hashedDST[:], 32)
+	testutils.FatalUnless(t, err1 == nil && err2 == nil, "Unexpected error")
+	testutils.FatalUnless(t, bytes.Equal(result1, result2), "Long DST not handled as specified")
+
+	// errors
+	_, err = ExpandMessageXMD(crypto.SHA256, []byte("abc"), []byte{}, 32)
+	testutils.FatalUnless(t, errors.Is(err, ErrEmptyDST), "Empty DST did not give expected error")
+	_, err = ExpandMessageXMD(crypto.SHA256, []byte("abc"), []byte("DST"), 255*32+1)
+	testutils.FatalUnless(t, errors.Is(err, ErrExpandMessageLength), "Too large output length did not give expected error")
+	_, err = ExpandMessageXMD(crypto.SHA512, []byte("abc"), []byte("DST"), 255*64+1)
+	testutils.FatalUnless(t, errors.Is(err, ErrExpandMessageLength), "Too large output length did not give expected error")
+	result, err = ExpandMessageXMD(crypto.SHA512, []byte("abc"), []byte("DST"), 255*64)
+	testutils.FatalUnless(t, err == nil && len(result) == 255*64, "ExpandMessageXMD failed for maximal output length")
+}
@@ -0,0 +1,40 @@
+//go:build go1.24
+
+package common
+
+import (
+	"bytes"
+	"crypto/sha3"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// This test uses SHAKE128 from the standard library, which is only available from Go 1.24 onwards.
+
+var _ MessageExpander = ExpanderXOF{}
+
+var expanderSHAKE128 = ExpanderXOF{NewXOF: func() ExtendableOutputFunction { return sha3.NewSHAKE128() }, SecurityLevel: 128}
+
+func TestExpandMessageXOF(t *testing.T) {
+	// test vector from RFC 9380, Appendix K.4
+	result, err := expanderSHAKE128.ExpandMessage([]byte("abc"), []byte("QUUX-V01-CS02-with-expander-SHAKE128"), 0x20)
+	testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+	testutils.FatalUnless(t, hex.EncodeToString(result) == "8696af52a4d862417c0763556073f47bc9b9ba43c99b505305cb1ec04a9ab468", "ExpandMessageXOF does not match test vector")
+
+	// long DSTs are hashed to 2*k/8 bytes
+	longDST := bytes.Repeat([]byte("x"), 256)
+	hashedDST := sha3.SumSHAKE128(append([]byte(oversizeDSTPrefix), longDST...), 32)
+	result1, err1 := expanderSHAKE128.ExpandMessage([]byte("abc"), longDST, 32)
+	result2, err2 := expanderSHAKE128.ExpandMessage([]byte("abc"), hashedDST, 32)
+	testutils.FatalUnless(t, err1 == nil && err2 == nil, "Unexpected error")
+	testutils.FatalUnless(t, bytes.Equal(result1, result2), "Long DST not handled as specified")
+
+	// errors
+	_, err = expanderSHAKE128.ExpandMessage([]byte("abc"), []byte{}, 32)
+	testutils.FatalUnless(t, errors.Is(err, ErrEmptyDST), "Empty DST did not give expected error")
+	_, err = expanderSHAKE128.ExpandMessage([]byte("abc"), []byte("DST"), 65536)
+	testutils.FatalUnless(t, errors.Is(err, ErrExpandMessageLength), "Too large output length did not give expected error")
+}
@@ -0,0 +1,23 @@
+package common
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// This file contains a helper for reading cryptographically secure randomness, used by the SetRandom methods of field elements, exponents and curve points.
+
+// ReadRandomBytes fills buf with random bytes from rnd. If rnd is nil, we use [crypto/rand.Reader].
+//
+// On failure, the returned error wraps the error returned by rnd (which is [io.ErrUnexpectedEOF] if rnd ran out of randomness).
+func ReadRandomBytes(rnd io.Reader, buf []byte) error {
+	if rnd == nil {
+		rnd = rand.Reader
+	}
+	_, err := io.ReadFull(rnd, buf)
+	if err != nil {
+		return fmt.Errorf(ErrorPrefix+"failed to read randomness: %w", err)
+	}
+	return nil
+}
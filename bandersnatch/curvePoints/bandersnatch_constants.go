@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
 	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/fieldElements"
 	"github.com/GottfriedHerold/Bandersnatch/internal/utils"
 )
@@ -20,6 +21,7 @@ const ErrorPrefix = "bandersnatch / curve points: "
 
 type FieldElement = fieldElements.FieldElement
 type IsInputTrusted = common.IsInputTrusted
+type Exponent = exponents.Exponent
 
 // These are COPIES and unexported by design.
 var fieldElementZero = fieldElements.FieldElementZero
@@ -0,0 +1,230 @@
+package curvePoints
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/fieldElements"
+)
+
+// This file contains the Banderwagon type, which represents elements of the Banderwagon group as specified for Ethereum's Verkle tries.
+//
+// The Banderwagon group is the quotient 2E/E[2]', where E is the Bandersnatch curve, 2E are the multiples of 2 and E[2]' = {N, A} is generated by the affine 2-torsion point A.
+// It is isomorphic to the prime-order subgroup, but points P and P+A represent the same element. Note that if P = (x,y) in affine twisted Edwards coordinates, then P+A = (-x,-y).
+// All operations of the Banderwagon type are defined purely in terms of the quotient:
+//
+//   - Equality of (x1,y1) and (x2,y2) is x1*y2 == x2*y1.
+//   - MapToFieldElement maps (x,y) to x/y, which is well-defined on the quotient; the neutral element maps to 0.
+//   - MapToScalarField interprets x/y as an integer in [0, BaseFieldSize) and reduces it modulo p253.
+//   - The encoding is the 32-byte big-endian encoding of x if y is lexicographically largest (i.e. y > (BaseFieldSize-1)/2) and of -x otherwise.
+//     Decoding rejects non-canonical field elements and elements outside the group.
+//
+// This matches the Verkle specification (and its reference implementations). Note that the encoding differs from pointserializer.BanderwagonShort,
+// which uses the opposite sign convention, little-endian byte order and a header bit.
+
+// BanderwagonBytesLength is the length of the encoding of a Banderwagon element in bytes.
+const BanderwagonBytesLength = 32
+
+// ErrInvalidBanderwagonEncoding is returned (possibly wrapped) by Banderwagon.SetBytes for invalid encodings.
+var ErrInvalidBanderwagonEncoding = errors.New(ErrorPrefix + "invalid Banderwagon encoding")
+
+// Banderwagon is an element of the Banderwagon group. See the documentation at the top of this file.
+//
+// The zero value is not a valid element (it behaves like a NaP). Use BanderwagonGenerator, BanderwagonNeutralElement, SetFromSubgroupPoint or SetBytes to obtain valid elements.
+type Banderwagon struct {
+	point Point_xtw_subgroup
+}
+
+var (
+	// BanderwagonGenerator is the generator of the Banderwagon group used by the Verkle specification. It is the class of SubgroupGenerator_xtw_subgroup.
+	BanderwagonGenerator Banderwagon = Banderwagon{point: SubgroupGenerator_xtw_subgroup}
+	// BanderwagonNeutralElement is the neutral element of the Banderwagon group.
+	BanderwagonNeutralElement Banderwagon = Banderwagon{point: NeutralElement_xtw_subgroup}
+)
+
+// SetFromSubgroupPoint sets z to the class of the given point, which must be in the prime-order subgroup. See Point_xtw_subgroup.SetFromSubgroupPoint for the meaning of trusted.
+//
+// The return value indicates success. On failure, z is unchanged.
+func (z *Banderwagon) SetFromSubgroupPoint(input CurvePointPtrInterfaceRead, trusted IsInputTrusted) (ok bool) {
+	var point Point_xtw_subgroup
+	if ok = point.SetFromSubgroupPoint(input, trusted); ok {
+		z.point = point
+	}
+	return
+}
+
+// Point returns a representative of z as a point in the prime-order subgroup.
+func (z *Banderwagon) Point() Point_xtw_subgroup {
+	return z.point
+}
+
+// IsNaP checks whether z is a NaP (e.g. the zero value).
+func (z *Banderwagon) IsNaP() bool {
+	return z.point.IsNaP()
+}
+
+// Add sets z = x + y.
+func (z *Banderwagon) Add(x, y *Banderwagon) {
+	z.point.Add(&x.point, &y.point)
+}
+
+// Sub sets z = x - y.
+func (z *Banderwagon) Sub(x, y *Banderwagon) {
+	z.point.Sub(&x.point, &y.point)
+}
+
+// Neg sets z = -x.
+func (z *Banderwagon) Neg(x *Banderwagon) {
+	z.point.Neg(&x.point)
+}
+
+// Double sets z = 2*x.
+func (z *Banderwagon) Double(x *Banderwagon) {
+	z.point.Double(&x.point)
+}
+
+// Exponentiate sets z = exponent * x.
+//
+// NOTE: The running time depends on exponent. Use ExponentiateConstantTime for secret exponents.
+func (z *Banderwagon) Exponentiate(x *Banderwagon, exponent *Exponent) {
+	z.point.Exponentiate(&x.point, exponent)
+}
+
+// ExponentiateConstantTime sets z = exponent * x. The running time does not depend on exponent.
+func (z *Banderwagon) ExponentiateConstantTime(x *Banderwagon, exponent *Exponent) {
+	z.point.ExponentiateConstantTime(&x.point, exponent)
+}
+
+// projectiveXY returns projective coordinates X, Y of a representative of z (without modifying z, so this is safe for concurrent reads).
+func (z *Banderwagon) projectiveXY() (X, Y FieldElement) {
+	point := z.point
+	X = point.X_decaf_projective()
+	Y = point.Y_decaf_projective()
+	return
+}
+
+// IsEqual checks whether z and x are the same element of the Banderwagon group, i.e. whether x1*y2 == x2*y1. NaPs are never equal to anything.
+func (z *Banderwagon) IsEqual(x *Banderwagon) bool {
+	if z.IsNaP() || x.IsNaP() {
+		napEncountered("Comparing NaPs of type Banderwagon", false, &z.point, &x.point)
+		return false
+	}
+	X1, Y1 := z.projectiveXY()
+	X2, Y2 := x.projectiveXY()
+	X1.MulEq(&Y2)
+	X2.MulEq(&Y1)
+	return X1.IsEqual(&X2)
+}
+
+// IsNeutralElement checks whether z is the neutral element, i.e. whether x == 0.
+func (z *Banderwagon) IsNeutralElement() bool {
+	if z.IsNaP() {
+		napEncountered("Called IsNeutralElement on a NaP of type Banderwagon", false, &z.point)
+		return false
+	}
+	X, _ := z.projectiveXY()
+	return X.IsZero()
+}
+
+// MapToFieldElement returns x/y, which is a well-defined map from the Banderwagon group to the base field (it is injective up to sign).
+//
+// z must not be a NaP, else we panic.
+func (z *Banderwagon) MapToFieldElement() (ret FieldElement) {
+	if z.IsNaP() {
+		panic(ErrorPrefix + "MapToFieldElement called on a NaP")
+	}
+	X, Y := z.projectiveXY()
+	ret.Divide(&X, &Y) // Y != 0 for all points in the subgroup
+	return
+}
+
+// MapToScalarField returns MapToFieldElement(), interpreted as an integer in [0, BaseFieldSize) and reduced modulo p253.
+//
+// z must not be a NaP, else we panic.
+func (z *Banderwagon) MapToScalarField() Exponent {
+	fe := z.MapToFieldElement()
+	return fieldElementToScalar(&fe)
+}
+
+// fieldElementToScalar interprets fe as an integer in [0, BaseFieldSize) and reduces it modulo p253.
+func fieldElementToScalar(fe *FieldElement) (ret Exponent) {
+	ret.SetBigInt(fe.ToBigInt())
+	return ret.ModuloP253()
+}
+
+// BanderwagonBatchMapToScalarField computes MapToScalarField for all given elements, using a single field inversion.
+//
+// The elements must not be NaPs, else we panic.
+func BanderwagonBatchMapToScalarField(elements []Banderwagon) []Exponent {
+	xs := make([]FieldElement, len(elements))
+	ys := make([]FieldElement, len(elements))
+	for i := range elements {
+		if elements[i].IsNaP() {
+			panic(fmt.Errorf(ErrorPrefix+"BanderwagonBatchMapToScalarField called with a NaP at position %v", i))
+		}
+		xs[i], ys[i] = elements[i].projectiveXY()
+	}
+	if err := fieldElements.MultiInvertEqSlice(ys); err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error in BanderwagonBatchMapToScalarField: %w", err))
+	}
+	ret := make([]Exponent, len(elements))
+	for i := range elements {
+		xs[i].MulEq(&ys[i])
+		ret[i] = fieldElementToScalar(&xs[i])
+	}
+	return ret
+}
+
+// Bytes returns the encoding of z. See the documentation at the top of this file for the format.
+//
+// z must not be a NaP, else we panic.
+func (z *Banderwagon) Bytes() (ret [BanderwagonBytesLength]byte) {
+	if z.IsNaP() {
+		panic(ErrorPrefix + "cannot encode a NaP of type Banderwagon")
+	}
+	point := z.point
+	X, Y := point.XY_affine()
+	// Y.Sign() < 0 means that Y is lexicographically largest.
+	if Y.Sign() > 0 {
+		X.NegEq()
+	}
+	var buf bytes.Buffer
+	if _, err := X.Serialize(&buf, common.BigEndian); err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when encoding Banderwagon element: %w", err))
+	}
+	copy(ret[:], buf.Bytes())
+	return
+}
+
+// SetBytes sets z to the element encoded in buf. See the documentation at the top of this file for the format.
+//
+// We return an error wrapping ErrInvalidBanderwagonEncoding if buf does not have length BanderwagonBytesLength, is not the canonical encoding of a field element
+// or does not encode an element of the Banderwagon group. On error, z is unchanged.
+func (z *Banderwagon) SetBytes(buf []byte) error {
+	if len(buf) != BanderwagonBytesLength {
+		return fmt.Errorf("%w: input has length %v, expected %v", ErrInvalidBanderwagonEncoding, len(buf), BanderwagonBytesLength)
+	}
+	var X FieldElement
+	if _, err := X.Deserialize(bytes.NewReader(buf), common.BigEndian); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBanderwagonEncoding, err)
+	}
+	// In terms of CurvePointFromXTimesSignY_subgroup, which expects X*Sign(Y) with Sign(Y) == -1 for lexicographically largest Y, the encoding is -X*Sign(Y).
+	X.NegEq()
+	point, err := CurvePointFromXTimesSignY_subgroup(&X, untrustedInput)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBanderwagonEncoding, err)
+	}
+	z.point.SetFrom(&point)
+	return nil
+}
+
+// String returns the hex encoding of z (or "NaP" for NaPs).
+func (z Banderwagon) String() string {
+	if z.IsNaP() {
+		return "NaP"
+	}
+	buf := z.Bytes()
+	return fmt.Sprintf("%x", buf[:])
+}
@@ -0,0 +1,185 @@
+package curvePoints
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// banderwagonSpecVectors are the fixed encoding vectors from the Verkle specification: the encodings of 2^i * G for i = 0, ..., 15, where G is the generator.
+var banderwagonSpecVectors = [16]string{
+	"4a2c7486fd924882bf02c6908de395122843e3e05264d7991e18e7985dad51e9",
+	"43aa74ef706605705989e8fd38df46873b7eae5921fbed115ac9d937399ce4d5",
+	"5e5f550494159f38aa54d2ed7f11a7e93e4968617990445cc93ac8e59808c126",
+	"0e7e3748db7c5c999a7bcd93d71d671f1f40090423792266f94cb27ca43fce5c",
+	"14ddaa48820cb6523b9ae5fe9fe257cbbd1f3d598a28e670a40da5d1159d864a",
+	"6989d1c82b2d05c74b62fb0fbdf8843adae62ff720d370e209a7b84e14548a7d",
+	"26b8df6fa414bf348a3dc780ea53b70303ce49f3369212dec6fbe4b349b832bf",
+	"37e46072db18f038f2cc7d3d5b5d1374c0eb86ca46f869d6a95fc2fb092c0d35",
+	"2c1ce64f26e1c772282a6633fac7ca73067ae820637ce348bb2c8477d228dc7d",
+	"297ab0f5a8336a7a4e2657ad7a33a66e360fb6e50812d4be3326fab73d6cee07",
+	"5b285811efa7a965bd6ef5632151ebf399115fcc8f5b9b8083415ce533cc39ce",
+	"1f939fa2fd457b3effb82b25d3fe8ab965f54015f108f8c09d67e696294ab626",
+	"3088dcb4d3f4bacd706487648b239e0be3072ed2059d981fe04ce6525af6f1b8",
+	"35fbc386a16d0227ff8673bc3760ad6b11009f749bb82d4facaea67f58fc60ed",
+	"00f29b4f3255e318438f0a31e058e4c081085426adb0479f14c64985d0b956e0",
+	"3fa4384b2fa0ecc3c0582223602921daaa893a97b64bdf94dcaa504e8b7b9e5f",
+}
+
+func sampleBanderwagonForTest(rnd *rand.Rand) (ret Banderwagon) {
+	ret.point.sampleRandomUnsafe(rnd)
+	return
+}
+
+func TestBanderwagonSpecVectors(t *testing.T) {
+	element := BanderwagonGenerator
+	for i, expected := range banderwagonSpecVectors {
+		encoded := element.Bytes()
+		testutils.FatalUnless(t, hex.EncodeToString(encoded[:]) == expected, "encoding of 2^%v * G does not match spec vector: got %x, expected %v", i, encoded, expected)
+		testutils.FatalUnless(t, element.String() == expected, "String() differs from encoding")
+
+		var decoded Banderwagon
+		err := decoded.SetBytes(encoded[:])
+		testutils.FatalUnless(t, err == nil, "could not decode spec vector %v: %v", i, err)
+		testutils.FatalUnless(t, decoded.IsEqual(&element), "decoding spec vector %v gave wrong element", i)
+
+		element.Double(&element)
+	}
+}
+
+func TestBanderwagonQuotient(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		P := sampleBanderwagonForTest(rnd)
+		// PA has the representative (-x, -y), i.e. it is P + A as a curve point.
+		PA := P
+		PA.point.flipDecaf()
+		testutils.FatalUnless(t, PA.IsEqual(&P) && P.IsEqual(&PA), "P and P+A are not equal")
+		x1, _ := P.projectiveXY()
+		x2, _ := PA.projectiveXY()
+		testutils.FatalUnless(t, !x1.IsEqual(&x2), "representatives did not differ")
+
+		testutils.FatalUnless(t, P.Bytes() == PA.Bytes(), "encoding depends on representative")
+		f1, f2 := P.MapToFieldElement(), PA.MapToFieldElement()
+		testutils.FatalUnless(t, f1.IsEqual(&f2), "MapToFieldElement depends on representative")
+		s1, s2 := P.MapToScalarField(), PA.MapToScalarField()
+		testutils.FatalUnless(t, s1.IsEqual(&s2), "MapToScalarField depends on representative")
+
+		var negP Banderwagon
+		negP.Neg(&P)
+		testutils.FatalUnless(t, !negP.IsEqual(&P), "P == -P")
+		f3 := negP.MapToFieldElement()
+		f3.NegEq()
+		testutils.FatalUnless(t, f3.IsEqual(&f1), "MapToFieldElement(-P) != -MapToFieldElement(P)")
+	}
+}
+
+func TestBanderwagonMapToField(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	neutral := BanderwagonNeutralElement
+	f := neutral.MapToFieldElement()
+	testutils.FatalUnless(t, f.IsZero(), "neutral element not mapped to 0")
+	s := neutral.MapToScalarField()
+	testutils.FatalUnless(t, s.IsZero_Subgroup(), "neutral element not mapped to 0")
+
+	elements := make([]Banderwagon, 20)
+	for i := range elements {
+		elements[i] = sampleBanderwagonForTest(rnd)
+	}
+	elements[5] = BanderwagonNeutralElement
+	batch := BanderwagonBatchMapToScalarField(elements)
+	for i := range elements {
+		point := elements[i].Point()
+		x, y := point.XY_affine()
+		var expected FieldElement
+		expected.Divide(&x, &y)
+		got := elements[i].MapToFieldElement()
+		testutils.FatalUnless(t, got.IsEqual(&expected), "MapToFieldElement is not x/y")
+
+		expectedScalar := new(big.Int).Mod(expected.ToBigInt(), GroupOrder_Int)
+		gotScalar := elements[i].MapToScalarField()
+		testutils.FatalUnless(t, gotScalar.ToBigInt_Subgroup().Cmp(expectedScalar) == 0, "MapToScalarField wrong")
+		testutils.FatalUnless(t, batch[i].IsEqual(&gotScalar), "BanderwagonBatchMapToScalarField differs at %v", i)
+	}
+	testutils.FatalUnless(t, len(BanderwagonBatchMapToScalarField(nil)) == 0, "batch map of empty slice")
+	testutils.FatalUnless(t, testutils.CheckPanic(BanderwagonBatchMapToScalarField, []Banderwagon{{}}), "no panic on NaP")
+}
+
+func TestBanderwagonArithmetic(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		P, Q := sampleBanderwagonForTest(rnd), sampleBanderwagonForTest(rnd)
+		pointP, pointQ := P.Point(), Q.Point()
+
+		var sum, diff, expected Banderwagon
+		sum.Add(&P, &Q)
+		testutils.FatalUnless(t, expected.SetFromSubgroupPoint(&pointP, untrustedInput), "SetFromSubgroupPoint failed")
+		testutils.FatalUnless(t, expected.IsEqual(&P), "SetFromSubgroupPoint roundtrip failed")
+		expected.point.Add(&pointP, &pointQ)
+		testutils.FatalUnless(t, sum.IsEqual(&expected), "Add differs from point addition")
+		diff.Sub(&sum, &Q)
+		testutils.FatalUnless(t, diff.IsEqual(&P), "Sub is not inverse to Add")
+		diff.Sub(&P, &P)
+		testutils.FatalUnless(t, diff.IsNeutralElement(), "P - P is not neutral")
+		testutils.FatalUnless(t, !P.IsNeutralElement(), "random element is neutral")
+
+		var exponent Exponent
+		exponent.SetUInt(3)
+		var triple, expectedTriple Banderwagon
+		triple.Exponentiate(&P, &exponent)
+		expectedTriple.Double(&P)
+		expectedTriple.Add(&expectedTriple, &P)
+		testutils.FatalUnless(t, triple.IsEqual(&expectedTriple), "Exponentiate wrong")
+		triple.ExponentiateConstantTime(&P, &exponent)
+		testutils.FatalUnless(t, triple.IsEqual(&expectedTriple), "ExponentiateConstantTime wrong")
+	}
+	var fullPoint Point_xtw_full
+	fullPoint.SetFrom(&AffineOrderTwoPoint_xtw)
+	var wrong Banderwagon
+	testutils.FatalUnless(t, !wrong.SetFromSubgroupPoint(&fullPoint, untrustedInput), "point outside the subgroup accepted")
+	testutils.FatalUnless(t, wrong.IsNaP(), "failed SetFromSubgroupPoint modified receiver")
+}
+
+func TestBanderwagonSetBytesErrors(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var z Banderwagon
+	err := z.SetBytes(make([]byte, 31))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidBanderwagonEncoding), "wrong length accepted")
+	testutils.FatalUnless(t, z.IsNaP(), "receiver modified on error")
+
+	// neutral element
+	err = z.SetBytes(make([]byte, BanderwagonBytesLength))
+	testutils.FatalUnless(t, err == nil && z.IsNeutralElement(), "could not decode neutral element: %v", err)
+
+	// non-canonical field element: BaseFieldSize + 0 is not allowed
+	nonCanonical := BaseFieldSize_Int.FillBytes(make([]byte, BanderwagonBytesLength))
+	err = z.SetBytes(nonCanonical)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidBanderwagonEncoding), "non-canonical encoding accepted")
+
+	// about half of all field elements are not valid encodings
+	var numInvalid int
+	for i := 0; i < 100; i++ {
+		var buf [BanderwagonBytesLength]byte
+		rnd.Read(buf[:])
+		buf[0] &= 0x1f
+		z = Banderwagon{}
+		if err = z.SetBytes(buf[:]); err != nil {
+			testutils.FatalUnless(t, errors.Is(err, ErrInvalidBanderwagonEncoding), "wrong error type %v", err)
+			testutils.FatalUnless(t, z.IsNaP(), "receiver modified on error")
+			numInvalid++
+		} else {
+			reencoded := z.Bytes()
+			testutils.FatalUnless(t, reencoded == buf, "decoding is not canonical")
+		}
+	}
+	testutils.FatalUnless(t, numInvalid > 20 && numInvalid < 80, "unexpected number %v of invalid encodings", numInvalid)
+
+	var nap Banderwagon
+	testutils.FatalUnless(t, testutils.CheckPanic(nap.Bytes), "encoding NaP did not panic")
+	testutils.FatalUnless(t, testutils.CheckPanic(nap.MapToFieldElement), "MapToFieldElement on NaP did not panic")
+	testutils.FatalUnless(t, nap.String() == "NaP", "String on NaP")
+}
@@ -294,3 +294,20 @@ func (points CurvePointSlice_axtw_full) BatchNormalizeForZ() (failingIndices []i
 func (points CurvePointSlice_axtw_subgroup) BatchNormalizeForZ() (failingIndices []int) {
 	return nil
 }
+
+// batchToAffine_subgroup converts a slice of Point_xtw_subgroup's into a freshly allocated slice of Point_axtw_subgroup's, using a single batch inversion.
+//
+// points must not contain NaPs. Note that points is modified (it gets normalized to Z==1).
+func batchToAffine_subgroup(points CurvePointSlice_xtw_subgroup) (ret []Point_axtw_subgroup) {
+	failingIndices := points.BatchNormalizeForZ()
+	if failingIndices != nil {
+		panic(ErrorPrefix + "batchToAffine_subgroup called with NaPs")
+	}
+	ret = make([]Point_axtw_subgroup, len(points))
+	for i := range points {
+		ret[i].x = points[i].x
+		ret[i].y = points[i].y
+		ret[i].t = points[i].t
+	}
+	return
+}
@@ -71,6 +71,9 @@ type CurvePointPtrInterfaceWrite interface {
 	Endo(CurvePointPtrInterfaceRead) // p.Endo(q) sets p to the result of applying the efficient degree-2 endomorphism of the Bandersnatch curve on q
 	EndoEq()                         // p.EndoEq() is shorthand for p.Endo(p)
 
+	Exponentiate(CurvePointPtrInterfaceRead, *Exponent) // p.Exponentiate(q, n) sets p to n*q (in additive notation), i.e. scalar multiplication.
+	ExponentiateEq(*Exponent)                           // p.ExponentiateEq(n) is shorthand for p.Exponentiate(p, n)
+
 	SetFrom(CurvePointPtrInterfaceRead)                                        // p.SetFrom(q) sets p to (a copy of) the value of q. This is also used to convert between types. Note that it cannot be used to convert from types that store arbitrary curve points to types that only store points on the prime-order subgroup. Use SetFromSubgroupPoint for that.
 	SetFromSubgroupPoint(CurvePointPtrInterfaceRead, IsInputTrusted) (ok bool) // p.SetFromSubgroupPoint(q) ensures/assumes (depends on second argment) that q is inside the prime-order subgroup and sets p to a copy of q. If q is not in the subgroup and we check it, does not change p. This method works even if the types of p and q differ in whether they can represent curve points outside the prime-order subgroup.
 
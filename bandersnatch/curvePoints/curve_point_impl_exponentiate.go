@@ -0,0 +1,284 @@
+package curvePoints
+
+import (
+	"math/bits"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains the actual implementations of exponentiation (a.k.a. scalar multiplication) algorithms.
+// The Exponentiate and ExponentiateEq methods defined on the individual point types just dispatch to the functions here.
+//
+// Note: exponentiate_slidingWindow and exponentiate_constantTime assume that the points are in the subgroup (modulo A).
+// Points that might not be in the subgroup are taken care of by exponentiate_full:
+// Basically, we just remove the lsb of the exponent and double the base to reduce to the subgroup case.
+
+// simpleSlidingWindowSize is the number of bits per window used in exponentiate_slidingWindow.
+const simpleSlidingWindowSize = 3
+
+// exponentiate_slidingWindow computes exponent * arg, where arg is required to be of a type that can only represent subgroup elements.
+//
+// The algorithm uses the GLV decomposition of the exponent to write exponent * arg == u * arg + v * Endo(arg) with u, v of size approx. sqrt(p253).
+// Both u and v are then processed simultaneously in a left-to-right sliding window fashion, using a table of odd multiples of arg.
+//
+// NOTE: This function is not constant-time.
+func exponentiate_slidingWindow(arg CurvePointPtrInterfaceRead, exponent *Exponent) (ret Point_efgh_subgroup) {
+	const k = simpleSlidingWindowSize
+	if !arg.CanOnlyRepresentSubgroup() {
+		panic(ErrorPrefix + "exponentiate_slidingWindow called on a point that might not be in the subgroup")
+	}
+	if arg.IsNaP() {
+		napEncountered("exponentiating a NaP", false, arg)
+		ret = Point_efgh_subgroup{}
+		return
+	}
+	glv := exponents.GLV_representation(exponent)
+	u_decomp := exponents.DecomposeUnalignedSignedAdic(glv.U, simpleSlidingWindowSize)
+	v_decomp := exponents.DecomposeUnalignedSignedAdic(glv.V, simpleSlidingWindowSize)
+	const precomputedTableSize = 1 << (k - 1)
+	// precompute all odd k-bit multiples of arg
+	var table [precomputedTableSize]Point_xtw_subgroup
+	var p2 Point_xtw_subgroup
+	table[0].SetFrom(arg)
+	p2.Double(&table[0])
+	for i := 1; i < precomputedTableSize; i++ {
+		table[i].Add(&table[i-1], &p2)
+	}
+	var doublingsRemaining int = -1
+	var nextU, nextV int
+	var nextUExponent, nextVExponent int
+	nextU = len(u_decomp) - 1
+	nextV = len(v_decomp) - 1
+	var uLeft bool = (nextU >= 0)
+	var vLeft bool = (nextV >= 0)
+	if uLeft {
+		nextUExponent = int(u_decomp[nextU].Position)
+	} else {
+		nextUExponent = -1
+	}
+
+	if vLeft {
+		nextVExponent = int(v_decomp[nextV].Position)
+	} else {
+		nextVExponent = -1
+	}
+
+	// We want to maintain the following invariants:
+	// 2^doublingsRemaining * ret + sum_i^last_u u_decomp[i]*arg + \sum_j^last_v v_decomp[j] * Endo(arg)
+	// nextUExponent / nextVExponent is the largest exponent appearing in u_decomp resp. v_decomp
+	// uLeft resp. vLeft are equivalent to lastU >= 0 resp. lastV >= 0.
+	// If uLeft resp. vLeft are false, we set nextVExponent resp. nextUExponent to -1
+
+	if nextUExponent > nextVExponent {
+		doublingsRemaining = nextUExponent
+		tableIndex := (u_decomp[nextU].Coeff - 1) / 2
+		ret.SetFrom(&table[tableIndex])
+		if u_decomp[nextU].Sign < 0 {
+			ret.NegEq()
+		}
+		nextU--
+		uLeft = (nextU >= 0)
+		if uLeft {
+			nextUExponent = int(u_decomp[nextU].Position)
+		} else {
+			nextUExponent = -1
+		}
+	} else if nextUExponent < nextVExponent {
+		doublingsRemaining = nextVExponent
+		tableIndex := (v_decomp[nextV].Coeff - 1) / 2
+		ret.Endo(&table[tableIndex])
+		if v_decomp[nextV].Sign < 0 {
+			ret.NegEq()
+		}
+		nextV--
+		vLeft = (nextV >= 0)
+		if vLeft {
+			nextVExponent = int(v_decomp[nextV].Position)
+		} else {
+			nextVExponent = -1
+		}
+	} else { // nextUExponent == nextVExponent
+		if nextUExponent == -1 {
+			ret.SetNeutral()
+			return
+		}
+		doublingsRemaining = nextUExponent
+		tableIndexV := (v_decomp[nextV].Coeff - 1) / 2
+		ret.Endo(&table[tableIndexV])
+		if v_decomp[nextV].Sign < 0 {
+			ret.NegEq()
+		}
+		tableIndexU := (u_decomp[nextU].Coeff - 1) / 2
+		if u_decomp[nextU].Sign < 0 {
+			ret.SubEq(&table[tableIndexU])
+		} else {
+			ret.AddEq(&table[tableIndexU])
+		}
+		nextU--
+		nextV--
+		uLeft = (nextU >= 0)
+		vLeft = (nextV >= 0)
+		if uLeft {
+			nextUExponent = int(u_decomp[nextU].Position)
+		} else {
+			nextUExponent = -1
+		}
+		if vLeft {
+			nextVExponent = int(v_decomp[nextV].Position)
+		} else {
+			nextVExponent = -1
+		}
+	}
+
+	for doublingsRemaining > 0 {
+		ret.DoubleEq()
+		doublingsRemaining--
+		if doublingsRemaining == nextUExponent {
+			tableIndex := (u_decomp[nextU].Coeff - 1) / 2
+			if u_decomp[nextU].Sign > 0 {
+				ret.AddEq(&table[tableIndex])
+			} else {
+				ret.SubEq(&table[tableIndex])
+			}
+			nextU--
+			uLeft = (nextU >= 0)
+			if uLeft {
+				nextUExponent = int(u_decomp[nextU].Position)
+			} else {
+				nextUExponent = -1
+			}
+		}
+
+		if doublingsRemaining == nextVExponent {
+			tableIndex := (v_decomp[nextV].Coeff - 1) / 2
+			var temp Point_efgh_subgroup
+			temp.Endo(&table[tableIndex])
+			if v_decomp[nextV].Sign > 0 {
+				ret.AddEq(&temp)
+			} else {
+				ret.SubEq(&temp)
+			}
+			nextV--
+			vLeft = (nextV >= 0)
+			if vLeft {
+				nextVExponent = int(v_decomp[nextV].Position)
+			} else {
+				nextVExponent = -1
+			}
+		}
+	}
+	return
+}
+
+// exponentiate_full computes exponent * arg for arbitrary rational curve points arg, which may be outside the prime-order subgroup.
+//
+// If the type of arg can only represent subgroup elements, this just uses exponentiate_slidingWindow.
+// Otherwise, we write exponent = 2*quotient + remainder and compute exponent * arg = quotient * (2*arg) + remainder * arg.
+// Since 2*arg is always in the prime-order subgroup, the first summand can be computed by exponentiate_slidingWindow.
+// The final addition uses the (slower) addition law that works for all rational curve points.
+func exponentiate_full(arg CurvePointPtrInterfaceRead, exponent *Exponent) (ret Point_efgh_full) {
+	if arg.IsNaP() {
+		napEncountered("exponentiating a NaP", false, arg)
+		ret = Point_efgh_full{}
+		return
+	}
+	if arg.CanOnlyRepresentSubgroup() {
+		result := exponentiate_slidingWindow(arg, exponent)
+		ret.SetFrom(&result)
+		return
+	}
+	quotient, remainder := exponent.DivModTwo()
+	var doubled Point_xtw_subgroup
+	doubled.Double(arg)
+	result := exponentiate_slidingWindow(&doubled, &quotient)
+	if remainder == 0 {
+		ret.SetFrom(&result)
+	} else {
+		ret.Add(&result, arg)
+	}
+	return
+}
+
+// constantTimeWindowSize is the number of bits per window used in exponentiate_constantTime.
+const constantTimeWindowSize = 4
+
+// constantTimeTableSize is the number of precomputed multiples (including 0) used in exponentiate_constantTime.
+// The signed digits d that we process satisfy |d| <= 2^(constantTimeWindowSize-1), so we need the multiples 0,1,..., 2^(constantTimeWindowSize-1).
+const constantTimeTableSize = 1<<(constantTimeWindowSize-1) + 1
+
+// equalConstantTime returns 1 if x == y and 0 otherwise, without branching on x or y.
+func equalConstantTime(x, y int) int {
+	diff := uint(x ^ y)
+	return int(1 ^ ((diff | -diff) >> (bits.UintSize - 1)))
+}
+
+// lookupConstantTime returns digit * table[1], where table[i] is assumed to be i * table[1] for 0 <= i < constantTimeTableSize.
+// The running time and memory access pattern does not depend on digit, which must satisfy |digit| < constantTimeTableSize.
+//
+// We do this by going over the whole table and conditionally assigning the entry we are looking for. Negative digits are handled by a conditional negation.
+func lookupConstantTime(table *[constantTimeTableSize]Point_xtw_subgroup, digit int) (ret Point_xtw_subgroup) {
+	signMask := digit >> (bits.UintSize - 1) // -1 if digit < 0, 0 otherwise
+	absDigit := (digit ^ signMask) - signMask
+	for i := 0; i < constantTimeTableSize; i++ {
+		cond := equalConstantTime(i, absDigit)
+		ret.x.CondAssign(cond, &table[i].x)
+		ret.y.CondAssign(cond, &table[i].y)
+		ret.t.CondAssign(cond, &table[i].t)
+		ret.z.CondAssign(cond, &table[i].z)
+	}
+	var negX, negT FieldElement
+	negX.Neg(&ret.x)
+	negT.Neg(&ret.t)
+	ret.x.CondAssign(signMask&1, &negX)
+	ret.t.CondAssign(signMask&1, &negT)
+	return
+}
+
+// exponentiate_constantTime computes exponent * arg, where arg is required to be of a type that can only represent subgroup elements.
+//
+// As opposed to exponentiate_slidingWindow, this is intended for secret exponents:
+// We use a GLV decomposition of the exponent that is computed in constant time and recode both GLV halves u, v into a fixed number of signed digits (which may be zero).
+// We then perform a fixed sequence of doublings and additions, where the summands are taken from tables of multiples of arg resp. Endo(arg) via constant-time lookups.
+// In particular, there are no early exits or skipped additions for zero digits.
+//
+// NOTE: The algorithm itself does not branch on the exponent or access memory depending on it; the field additions, subtractions and multiplications that we use do not branch on their values either.
+// Conversions to affine coordinates (which involve a field inversion) are not constant-time.
+func exponentiate_constantTime(arg CurvePointPtrInterfaceRead, exponent *Exponent) (ret Point_efgh_subgroup) {
+	const w = constantTimeWindowSize
+	if !arg.CanOnlyRepresentSubgroup() {
+		panic(ErrorPrefix + "exponentiate_constantTime called on a point that might not be in the subgroup")
+	}
+	if arg.IsNaP() {
+		napEncountered("exponentiating a NaP", false, arg)
+		ret = Point_efgh_subgroup{}
+		return
+	}
+	glv := exponents.GLV_representation_ConstantTime(exponent)
+	u_digits := exponents.DecomposeSignedFixedWindow(glv.U, w)
+	v_digits := exponents.DecomposeSignedFixedWindow(glv.V, w)
+
+	// precompute table[i] = i * arg and endoTable[i] = i * Endo(arg) for 0 <= i < constantTimeTableSize
+	var table, endoTable [constantTimeTableSize]Point_xtw_subgroup
+	table[0].SetNeutral()
+	endoTable[0].SetNeutral()
+	table[1].SetFrom(arg)
+	endoTable[1].Endo(&table[1])
+	for i := 2; i < constantTimeTableSize; i++ {
+		table[i].Add(&table[i-1], &table[1])
+		endoTable[i].Endo(&table[i])
+	}
+
+	var accumulator, summand Point_xtw_subgroup
+	accumulator.SetNeutral()
+	for i := len(u_digits) - 1; i >= 0; i-- {
+		for j := 0; j < w; j++ {
+			accumulator.DoubleEq()
+		}
+		summand = lookupConstantTime(&table, u_digits[i])
+		accumulator.AddEq(&summand)
+		summand = lookupConstantTime(&endoTable, v_digits[i])
+		accumulator.AddEq(&summand)
+	}
+	ret.SetFrom(&accumulator)
+	return
+}
@@ -0,0 +1,142 @@
+//go:build dudect
+
+package curvePoints
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+)
+
+// This file contains a dudect-style test for timing leaks of ExponentiateConstantTime,
+// see Reparaz, Balasch, Verbauwhede: "Dude, is my code constant time?"
+//
+// We measure the running time for two classes of inputs: a fixed exponent (class 0) and random exponents (class 1), where the classes are interleaved in random order.
+// We then perform Welch's t-test on the measurements (both on all measurements and on measurements cropped at several percentiles to get rid of outliers).
+// A large |t| value indicates a timing leak.
+//
+// These tests are slow and inherently somewhat noisy, so they are only run if the dudect build tag is set:
+//
+//	go test -tags dudect -run Dudect ./bandersnatch/curvePoints/
+
+// dudectThreshold is the value of |t| above which we consider the timing leak to be "definitely there". This value is taken from the dudect paper.
+const dudectThreshold = 10
+
+// dudectMeasurements is the number of measurements we take.
+const dudectMeasurements = 20000
+
+// welchStatistic accumulates measurements for two classes and computes Welch's t-statistic.
+// We use Welford's online algorithm for numerical stability.
+type welchStatistic struct {
+	n    [2]float64
+	mean [2]float64
+	m2   [2]float64
+}
+
+// add adds the measurement x for the given class (0 or 1)
+func (w *welchStatistic) add(class int, x float64) {
+	w.n[class]++
+	delta := x - w.mean[class]
+	w.mean[class] += delta / w.n[class]
+	w.m2[class] += delta * (x - w.mean[class])
+}
+
+// t computes Welch's t-statistic
+func (w *welchStatistic) t() float64 {
+	if w.n[0] < 2 || w.n[1] < 2 {
+		return 0
+	}
+	var0 := w.m2[0] / (w.n[0] - 1)
+	var1 := w.m2[1] / (w.n[1] - 1)
+	return (w.mean[0] - w.mean[1]) / math.Sqrt(var0/w.n[0]+var1/w.n[1])
+}
+
+// dudectMaxT runs f on inputs from either class (selected uniformly at random) and returns the largest |t| - value of Welch's t-test among all considered croppings.
+// f(class, i) is supposed to run the function under test for the i'th input, which is of class class.
+func dudectMaxT(drng *rand.Rand, measurements int, f func(class int, i int)) float64 {
+	classes := make([]int, measurements)
+	timings := make([]float64, measurements)
+	for i := 0; i < measurements; i++ {
+		classes[i] = drng.Intn(2)
+	}
+	for i := 0; i < measurements; i++ {
+		start := time.Now()
+		f(classes[i], i)
+		timings[i] = float64(time.Since(start).Nanoseconds())
+	}
+
+	sorted := make([]float64, measurements)
+	copy(sorted, timings)
+	sort.Float64s(sorted)
+	var maxT float64
+	for _, percentile := range []float64{1.0, 0.99, 0.95, 0.9, 0.75, 0.5} {
+		cutoff := sorted[int(percentile*float64(measurements-1))]
+		var stat welchStatistic
+		for i := 0; i < measurements; i++ {
+			if timings[i] <= cutoff {
+				stat.add(classes[i], timings[i])
+			}
+		}
+		maxT = math.Max(maxT, math.Abs(stat.t()))
+	}
+	return maxT
+}
+
+// makeDudectExponents creates inputs for dudect-style tests: For class 0, all exponents are equal to fixedExponent, for class 1, they are random.
+func makeDudectExponents(drng *rand.Rand, measurements int, fixedExponent *Exponent) (exponents [2][]Exponent) {
+	exponents[0] = make([]Exponent, measurements)
+	exponents[1] = make([]Exponent, measurements)
+	for i := 0; i < measurements; i++ {
+		exponents[0][i] = *fixedExponent
+		exponents[1][i].SetBigInt(new(big.Int).Rand(drng, common.GroupOrder_Int))
+	}
+	return
+}
+
+func TestDudectExponentiateConstantTime(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	point := MakeRandomPointUnsafe_xtw_subgroup(drng)
+
+	var fixedExponents [3]Exponent
+	fixedExponents[0].SetZero()
+	fixedExponents[1].SetOne()
+	fixedExponents[2].SetBigInt(new(big.Int).Rand(drng, common.GroupOrder_Int))
+
+	for _, fixedExponent := range fixedExponents {
+		exponents := makeDudectExponents(drng, dudectMeasurements, &fixedExponent)
+		var result Point_xtw_subgroup
+
+		// warm up caches etc.
+		for i := 0; i < 100; i++ {
+			result.ExponentiateConstantTime(&point, &exponents[1][i])
+		}
+
+		maxT := dudectMaxT(drng, dudectMeasurements, func(class int, i int) {
+			result.ExponentiateConstantTime(&point, &exponents[class][i])
+		})
+		t.Logf("ExponentiateConstantTime with fixed exponent %v: max |t| = %v", fixedExponent, maxT)
+		if maxT > dudectThreshold {
+			t.Errorf("ExponentiateConstantTime appears to leak timing information about the exponent: |t| = %v for fixed exponent %v", maxT, fixedExponent)
+		}
+	}
+}
+
+// TestDudectExponentiateControl runs the dudect-test on the (non-constant-time) Exponentiate function.
+// This is not expected to pass and merely serves to show that our measurements can detect timing leaks; we just log the result.
+func TestDudectExponentiateControl(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	point := MakeRandomPointUnsafe_xtw_subgroup(drng)
+	var fixedExponent Exponent
+	fixedExponent.SetOne()
+	exponents := makeDudectExponents(drng, dudectMeasurements, &fixedExponent)
+	var result Point_xtw_subgroup
+	maxT := dudectMaxT(drng, dudectMeasurements, func(class int, i int) {
+		result.Exponentiate(&point, &exponents[class][i])
+	})
+	t.Logf("Exponentiate (not constant-time) with fixed exponent %v: max |t| = %v (expected to be large)", fixedExponent, maxT)
+}
@@ -0,0 +1,292 @@
+package curvePoints
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+)
+
+// This file contains tests for exponentiation (scalar multiplication) of curve points.
+//
+// Notably, we check that
+// naive square-and-multiply is sane (this is what we compare against)
+// exponentiate_slidingWindow matches the naive implementation
+// Exponentiate and ExponentiateEq match the naive implementation for all point types, including points outside the subgroup
+// ExponentiateConstantTime matches Exponentiate
+//
+// Tests for timing leaks of ExponentiateConstantTime are in curve_point_test_dudect_test.go (these need to be enabled with the dudect build tag)
+
+func TestSimpleExponentiation(t *testing.T) {
+	const iterations = 10
+	var temp1, temp2, temp3, temp4 Point_xtw_subgroup
+	temp1.exp_naive_xx(&example_generator_xtw, GroupOrder_Int)
+	if !temp1.IsNeutralElement() {
+		t.Fatal("Either naive exponentiation is wrong or example point not in subgroup")
+	}
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	var exp1 = big.NewInt(0)
+	var exp2 = big.NewInt(1)
+	var exp3 = big.NewInt(-1)
+
+	temp1.sampleRandomUnsafe(drng)
+	temp2.exp_naive_xx(&temp1.point_xtw_base, exp2) // exponent is 1
+	if !temp2.IsEqual(&temp1) {
+		t.Error("1 * P != P for naive exponentiation")
+	}
+	temp2.exp_naive_xx(&temp1.point_xtw_base, exp1) // exponent is 0
+	if !temp2.IsNeutralElement() {
+		t.Error("0 * P != Neutral element for naive exponentiation")
+	}
+	temp2.exp_naive_xx(&temp1.point_xtw_base, exp3)
+	temp1.NegEq()
+	if !temp1.IsEqual(&temp2) {
+		t.Error("-1 * P != -P for naive exponentiation")
+	}
+
+	var p1, p2, p3 Point_xtw_subgroup
+	for i := 0; i < iterations; i++ {
+		p1.sampleRandomUnsafe(drng)
+		p2.sampleRandomUnsafe(drng)
+		p3.Add(&p1, &p2)
+		exp1.Rand(drng, common.CurveOrder_Int)
+		exp2.Rand(drng, common.CurveOrder_Int)
+		exp3.Add(exp1, exp2)
+		temp1.exp_naive_xx(&p1.point_xtw_base, exp1)
+		temp2.exp_naive_xx(&p2.point_xtw_base, exp1)
+		temp3.exp_naive_xx(&p3.point_xtw_base, exp1)
+		temp4.Add(&temp1, &temp2)
+		if !temp3.IsEqual(&temp4) {
+			t.Error("a * (P+Q) != a*P + a*Q for naive exponentiation")
+		}
+		temp2.exp_naive_xx(&p1.point_xtw_base, exp2)
+		temp3.exp_naive_xx(&p1.point_xtw_base, exp3)
+		temp4.Add(&temp1, &temp2)
+		if !temp3.IsEqual(&temp4) {
+			t.Error("(a+b) * P != a*P + b*P for naive exponentiation")
+		}
+	}
+}
+
+// getTestExponent returns the i'th exponent used in the exponentiation tests.
+// The first few exponents are "special" in the sense that the GLV - decomposition only has one component or they are close to multiples of the group (exponent).
+// Afterwards, we use random exponents.
+func getTestExponent(i int, drng *rand.Rand) (exponent *big.Int) {
+	exponent = big.NewInt(0)
+	var EVPlusOne *big.Int = big.NewInt(1)
+	EVPlusOne.Add(EVPlusOne, EndomorphismEigenvalue_Int)
+	switch {
+	case i < 128:
+		exponent.SetInt64(int64(i - 64))
+	case i < 256:
+		exponent.SetInt64(int64(i - 64 - 128))
+		exponent.Mul(exponent, EndomorphismEigenvalue_Int)
+	case i < 384:
+		exponent.SetInt64(int64(i - 64 - 256))
+		exponent.Mul(exponent, EVPlusOne)
+	case i < 392:
+		exponent.SetInt64(int64(i - 388))
+		exponent.Add(exponent, GroupOrder_Int)
+	case i < 400:
+		exponent.SetInt64(int64(i - 396))
+		exponent.Add(exponent, common.CurveExponent_Int)
+	default:
+		exponent.Rand(drng, common.CurveOrder_Int)
+	}
+	return
+}
+
+// exp_naive_any computes exponent * p via square-and-multiply, using the group operations of the given receiver type.
+// As opposed to exp_naive_xx, this works (for full receiver types) for points outside the subgroup.
+func exp_naive_any(receiverType PointType, p CurvePointPtrInterfaceRead, exponent *big.Int) (ret CurvePointPtrInterface) {
+	var absexponent *big.Int = new(big.Int).Abs(exponent)
+	var to_add CurvePointPtrInterface = makeCurvePointPtrInterface(receiverType)
+	to_add.SetFrom(p)
+	if exponent.Sign() < 0 {
+		to_add.NegEq()
+	}
+	ret = makeCurvePointPtrInterface(receiverType)
+	ret.SetNeutral()
+	for i := absexponent.BitLen() - 1; i >= 0; i-- {
+		ret.DoubleEq()
+		if absexponent.Bit(i) == 1 {
+			ret.AddEq(to_add)
+		}
+	}
+	return
+}
+
+func TestSlidingWindowExponentiation(t *testing.T) {
+	// check equality with naive implementation
+
+	var checkfun_equal_naive checkfunction = func(s *TestSample) (bool, string) {
+		s.AssertNumberOfPoints(1)
+		singular := s.AnyFlags().CheckFlag(PointFlagNAP)
+		if singular {
+			return true, "skipped"
+		}
+
+		const iterations = 500
+		var drng *rand.Rand = rand.New(rand.NewSource(1024))
+
+		var P1, P2 Point_xtw_subgroup
+		P1.SetFrom(s.Points[0])
+		P2.SetFrom(s.Points[0])
+		for i := 0; i < iterations; i++ {
+			exponent := getTestExponent(i, drng)
+			var resultNaive Point_xtw_subgroup
+			var resultSlidingWindow Point_efgh_subgroup
+			resultNaive.exp_naive_xx(&P1.point_xtw_base, exponent)
+			var exponent_ScalarField Exponent
+			exponent_ScalarField.SetBigInt(exponent)
+			resultSlidingWindow = exponentiate_slidingWindow(&P2, &exponent_ScalarField)
+			if !resultNaive.IsEqual(&resultSlidingWindow) {
+				return false, "expnaive and sliding window results differ"
+			}
+		}
+		return true, ""
+	}
+	make_samples1_and_run_tests(t, checkfun_equal_naive, "comparison of square-and-multiply and sliding window", pointTypeXTWSubgroup, 30, PointFlagNAP)
+}
+
+// make_checkfun_exponentiate checks that Exponentiate and ExponentiateEq agree with naive exponentiation for the given receiver type.
+func make_checkfun_exponentiate(receiverType PointType) checkfunction {
+	return func(s *TestSample) (bool, string) {
+		s.AssertNumberOfPoints(1)
+		singular := s.AnyFlags().CheckFlag(PointFlagNAP)
+		infinite := s.AnyFlags().CheckFlag(PointFlag_infinite)
+		if infinite && !typeCanRepresentInfinity(receiverType) {
+			return true, "" // skip test in that case
+		}
+		var drng *rand.Rand = rand.New(rand.NewSource(1024))
+		const iterations = 50
+
+		// the naive exponentiation is done with the group law of a type that can hold the input.
+		var naiveType PointType = pointTypeXTWFull
+		if typeCanOnlyRepresentSubgroup(getPointType(s.Points[0])) {
+			naiveType = pointTypeXTWSubgroup
+		}
+
+		for i := 0; i < iterations; i++ {
+			// spread the used exponents over the special cases of getTestExponent; the last few are random
+			exponentInt := getTestExponent(i*10, drng)
+			var exponent Exponent
+			exponent.SetBigInt(exponentInt)
+
+			var naive CurvePointPtrInterface
+			if !singular {
+				naive = exp_naive_any(naiveType, s.Points[0], exponentInt)
+				if naive.IsAtInfinity() && !typeCanRepresentInfinity(receiverType) {
+					continue
+				}
+			}
+
+			result := makeCurvePointPtrInterface(receiverType)
+			result.Exponentiate(s.Points[0], &exponent)
+			resultEq := makeCurvePointPtrInterface(receiverType)
+			resultEq.SetFrom(s.Points[0])
+			resultEq.ExponentiateEq(&exponent)
+
+			if singular {
+				if !result.IsNaP() || !resultEq.IsNaP() {
+					return false, "Exponentiating a NaP did not result in a NaP"
+				}
+				continue
+			}
+			if !result.(validateable).Validate() {
+				return false, "Exponentiate resulted in invalid point"
+			}
+			if !result.IsEqual(resultEq) {
+				return false, "Exponentiate and ExponentiateEq differ"
+			}
+			if !result.IsEqual(naive) {
+				return false, "Exponentiate differs from naive square-and-multiply"
+			}
+		}
+		return true, ""
+	}
+}
+
+func TestExponentiate(t *testing.T) {
+	for _, receiverType := range allTestPointTypes {
+		point_string := pointTypeToString(receiverType)
+		for _, type1 := range allTestPointTypes {
+			if typeCanOnlyRepresentSubgroup(receiverType) && !typeCanOnlyRepresentSubgroup(type1) {
+				continue
+			}
+			make_samples1_and_run_tests(t, make_checkfun_exponentiate(receiverType), "Exponentiate did not match naive exponentiation "+point_string+" "+pointTypeToString(type1), type1, 5, excludeNoPoints)
+		}
+	}
+}
+
+// exponentiatorConstantTime is the interface satisfied by point types that have an ExponentiateConstantTime method (these are exactly the types that can only represent subgroup elements).
+type exponentiatorConstantTime interface {
+	CurvePointPtrInterface
+	ExponentiateConstantTime(CurvePointPtrInterfaceRead, *Exponent)
+}
+
+var (
+	_ exponentiatorConstantTime = &Point_xtw_subgroup{}
+	_ exponentiatorConstantTime = &Point_axtw_subgroup{}
+	_ exponentiatorConstantTime = &Point_efgh_subgroup{}
+)
+
+// make_checkfun_exponentiate_constant_time checks that ExponentiateConstantTime agrees with Exponentiate for the given receiver type.
+func make_checkfun_exponentiate_constant_time(receiverType PointType) checkfunction {
+	return func(s *TestSample) (bool, string) {
+		s.AssertNumberOfPoints(1)
+		singular := s.AnyFlags().CheckFlag(PointFlagNAP)
+		var drng *rand.Rand = rand.New(rand.NewSource(1024))
+		const iterations = 50
+
+		for i := 0; i < iterations; i++ {
+			exponentInt := getTestExponent(i*10, drng)
+			var exponent Exponent
+			exponent.SetBigInt(exponentInt)
+
+			result := makeCurvePointPtrInterface(receiverType).(exponentiatorConstantTime)
+			result.ExponentiateConstantTime(s.Points[0], &exponent)
+			if singular {
+				if !result.IsNaP() {
+					return false, "ExponentiateConstantTime of a NaP did not result in a NaP"
+				}
+				continue
+			}
+			expected := makeCurvePointPtrInterface(receiverType)
+			expected.Exponentiate(s.Points[0], &exponent)
+			if !result.IsEqual(expected) {
+				return false, "ExponentiateConstantTime differs from Exponentiate"
+			}
+		}
+		return true, ""
+	}
+}
+
+func TestExponentiateConstantTime(t *testing.T) {
+	for _, receiverType := range allTestPointTypes {
+		if !typeCanOnlyRepresentSubgroup(receiverType) {
+			continue
+		}
+		point_string := pointTypeToString(receiverType)
+		for _, type1 := range allTestPointTypes {
+			if !typeCanOnlyRepresentSubgroup(type1) {
+				continue
+			}
+			make_samples1_and_run_tests(t, make_checkfun_exponentiate_constant_time(receiverType), "ExponentiateConstantTime did not match Exponentiate "+point_string+" "+pointTypeToString(type1), type1, 5, excludeNoPoints)
+		}
+	}
+}
+
+func BenchmarkExponentiate(bOuter *testing.B) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	var exponents [benchSizeCurvePoint]Exponent
+	for i := 0; i < benchSizeCurvePoint; i++ {
+		exponents[i].SetBigInt(new(big.Int).Rand(drng, common.CurveExponent_Int))
+	}
+	benchmarkForPointTypes(bOuter, benchSizeCurvePoint, func(b *testing.B, receivers []CurvePointPtrInterfaceTestSample, points []CurvePointPtrInterfaceTestSample) {
+		for n := 0; n < b.N; n++ {
+			receivers[n%benchSizeCurvePoint].Exponentiate(points[n%benchSizeCurvePoint], &exponents[n%benchSizeCurvePoint])
+		}
+	}, "Exponentiate(%[2]v)->%[1]v", filterTypes_CompatibilityCond, allTestPointTypes, allTestPointTypes)
+}
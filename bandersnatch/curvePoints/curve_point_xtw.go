@@ -1008,6 +1008,54 @@ func (p *Point_xtw_full) EndoEq() {
 	p.Endo(p)
 }
 
+// Exponentiate computes scalar multiplication of a curve point by an exponent.
+// Use p.Exponentiate(&x, &n) for p = n * x (in additive notation).
+//
+// The exponent is taken modulo p253 if the input is in the prime-order subgroup (and modulo 2*p253 in general).
+//
+// NOTE: Since the receiver can only hold subgroup elements, so must the input (we panic otherwise). Use SetFromSubgroupPoint to convert first.
+func (p *Point_xtw_subgroup) Exponentiate(input CurvePointPtrInterfaceRead, exponent *Exponent) {
+	ensureSubgroupOnly(input)
+	result := exponentiate_slidingWindow(input, exponent)
+	p.SetFrom(&result)
+}
+
+// Exponentiate computes scalar multiplication of a curve point by an exponent.
+// Use p.Exponentiate(&x, &n) for p = n * x (in additive notation).
+//
+// The exponent is taken modulo p253 if the input is in the prime-order subgroup (and modulo 2*p253 in general).
+// This works for arbitrary rational input points, including those outside the prime-order subgroup.
+func (p *Point_xtw_full) Exponentiate(input CurvePointPtrInterfaceRead, exponent *Exponent) {
+	result := exponentiate_full(input, exponent)
+	p.SetFrom(&result)
+}
+
+// ExponentiateEq multiplies the given point p by the exponent, overwriting p.
+//
+// p.ExponentiateEq(&n) is equivalent to p.Exponentiate(&p, &n).
+func (p *Point_xtw_subgroup) ExponentiateEq(exponent *Exponent) {
+	p.Exponentiate(p, exponent)
+}
+
+// ExponentiateEq multiplies the given point p by the exponent, overwriting p.
+//
+// p.ExponentiateEq(&n) is equivalent to p.Exponentiate(&p, &n).
+func (p *Point_xtw_full) ExponentiateEq(exponent *Exponent) {
+	p.Exponentiate(p, exponent)
+}
+
+// ExponentiateConstantTime computes scalar multiplication of a curve point by a secret exponent.
+// Use p.ExponentiateConstantTime(&x, &n) for p = n * x (in additive notation).
+//
+// As opposed to Exponentiate, the sequence of curve operations and the memory access pattern do not depend on the exponent.
+// This is slower than Exponentiate and should be used if the exponent is secret (e.g. a private key).
+// Since the receiver can only hold subgroup elements, so must the input (we panic otherwise).
+func (p *Point_xtw_subgroup) ExponentiateConstantTime(input CurvePointPtrInterfaceRead, exponent *Exponent) {
+	ensureSubgroupOnly(input)
+	result := exponentiate_constantTime(input, exponent)
+	p.SetFrom(&result)
+}
+
 // AddEq adds (via the elliptic curve group addition law) the given curve point x to the received p, overwriting p.
 //
 // p.AddEq(&x) is equivalent to p.AddEq(&p, &x)
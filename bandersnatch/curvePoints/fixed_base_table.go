@@ -0,0 +1,179 @@
+package curvePoints
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains the FixedBaseTable type, which holds precomputed multiples of a fixed base point in order to speed up repeated exponentiations with the same base.
+//
+// The algorithm is a signed fixed-window method on the GLV decomposition of the exponent:
+// We write exponent * P = u * P + v * Endo(P) with |u|,|v| < 2^127 and write u = sum_i u_i * 2^(i*windowSize), v = sum_i v_i * 2^(i*windowSize) with signed digits |u_i|,|v_i| <= 2^(windowSize-1).
+// The table stores j * 2^(i*windowSize) * P and j * 2^(i*windowSize) * Endo(P) for all positions i and 1 <= j <= 2^(windowSize-1) in affine form.
+// An exponentiation then only requires one mixed addition (or subtraction) per non-zero digit and no doublings at all.
+//
+// Note that (de)serialization of tables is done by the pointserializer package, which imports this package. We only provide the Points and NewFixedBaseTableFromPoints functions here.
+
+// DefaultFixedBaseTableWindowSize is a reasonable default for the windowSize argument of NewFixedBaseTable.
+// With this choice, a table holds 1472 points and an exponentiation takes (at most) 46 mixed additions.
+const DefaultFixedBaseTableWindowSize = 6
+
+// ErrInvalidFixedBaseTable is returned by NewFixedBaseTableFromPoints if the given points do not form a valid table.
+var ErrInvalidFixedBaseTable = errors.New(ErrorPrefix + "the given points do not form a valid FixedBaseTable")
+
+// FixedBaseTable holds precomputed multiples of a fixed curve point in the prime-order subgroup.
+// Its Exponentiate method is much faster than exponentiating the base point directly.
+//
+// FixedBaseTables are created via NewFixedBaseTable or NewFixedBaseTableFromPoints. The zero value is not a valid table.
+// Tables are not modified after creation, so they are safe for concurrent use.
+type FixedBaseTable struct {
+	windowSize uint // number of bits per digit
+	numDigits  int  // number of digits per GLV half
+	// entries holds the precomputed multiples. The entry for j * 2^(i*windowSize) * Endo^(half)(P) is at index
+	// (half * numDigits + i) * 2^(windowSize-1) + (j-1) for half in {0,1}, 0 <= i < numDigits and 1 <= j <= 2^(windowSize-1)
+	entries []Point_axtw_subgroup
+}
+
+// fixedBaseTableDimensions returns the number of digits per GLV half and the total number of entries for a FixedBaseTable with the given window size.
+func fixedBaseTableDimensions(windowSize uint) (numDigits int, numEntries int) {
+	// This needs to match the output length of exponents.DecomposeSignedFixedWindow.
+	numDigits = int((128+windowSize-1)/windowSize + 1)
+	numEntries = 2 * numDigits * (1 << (windowSize - 1))
+	return
+}
+
+// NewFixedBaseTable creates a table of precomputed multiples of the given base point for the given window size.
+//
+// windowSize must be between 1 and 8. Larger windows mean faster exponentiation, but the size of the table grows exponentially in windowSize;
+// DefaultFixedBaseTableWindowSize is a reasonable choice.
+// base must be in the prime-order subgroup; we panic otherwise (and also if base is a NaP).
+func NewFixedBaseTable(base CurvePointPtrInterfaceRead, windowSize uint) *FixedBaseTable {
+	if windowSize == 0 || windowSize > 8 {
+		panic(fmt.Errorf(ErrorPrefix+"NewFixedBaseTable called with invalid window size %v. The window size must be between 1 and 8", windowSize))
+	}
+	if base.IsNaP() {
+		napEncountered("NaP encountered when creating a FixedBaseTable", false, base)
+		panic(ErrorPrefix + "NewFixedBaseTable called with a NaP as base point")
+	}
+	var basePoint Point_xtw_subgroup
+	if !basePoint.SetFromSubgroupPoint(base, untrustedInput) {
+		panic(ErrorPrefix + "NewFixedBaseTable called with a base point that is not in the prime-order subgroup")
+	}
+	return newFixedBaseTable(&basePoint, windowSize)
+}
+
+// newFixedBaseTable is the actual implementation of NewFixedBaseTable without any checks of the input.
+func newFixedBaseTable(base *Point_xtw_subgroup, windowSize uint) *FixedBaseTable {
+	numDigits, numEntries := fixedBaseTableDimensions(windowSize)
+	halfWindow := 1 << (windowSize - 1)
+
+	// We first compute everything in xtw coordinates and then batch-normalize to obtain affine coordinates.
+	var entries_xtw CurvePointSlice_xtw_subgroup = make([]Point_xtw_subgroup, numEntries)
+	var current Point_xtw_subgroup = *base // current == 2^(i*windowSize) * base at the start of the i'th loop iteration.
+	for i := 0; i < numDigits; i++ {
+		row := entries_xtw[i*halfWindow : (i+1)*halfWindow]
+		row[0] = current
+		for j := 1; j < halfWindow; j++ {
+			row[j].Add(&row[j-1], &current)
+		}
+		current.Double(&row[halfWindow-1]) // 2 * 2^(windowSize-1) * current
+	}
+	// The second half of the table is obtained by applying the endomorphism to the first half.
+	for k := 0; k < numEntries/2; k++ {
+		entries_xtw[numEntries/2+k].Endo(&entries_xtw[k])
+	}
+	return &FixedBaseTable{windowSize: windowSize, numDigits: numDigits, entries: batchToAffine_subgroup(entries_xtw)}
+}
+
+// WindowSize returns the window size that was used to create the table.
+func (table *FixedBaseTable) WindowSize() uint {
+	return table.windowSize
+}
+
+// Base returns the base point of the table.
+func (table *FixedBaseTable) Base() (ret Point_axtw_subgroup) {
+	return table.entries[0]
+}
+
+// Points returns (a copy of) all precomputed points stored in the table.
+//
+// This is intended for serialization. The table can be recreated from the returned slice via NewFixedBaseTableFromPoints.
+func (table *FixedBaseTable) Points() []Point_axtw_subgroup {
+	ret := make([]Point_axtw_subgroup, len(table.entries))
+	copy(ret, table.entries)
+	return ret
+}
+
+// NewFixedBaseTableFromPoints recreates a FixedBaseTable from the output of the Points method. The window size is determined from the number of points.
+//
+// If trustLevel indicates untrusted input, we check that the given points are actually the correct multiples of the base point (which is points[0]);
+// this check is about as expensive as creating the table via NewFixedBaseTable.
+// For trusted input, we only check that the number of points is valid for some window size.
+// On failure, we return a nil table and an error wrapping ErrInvalidFixedBaseTable.
+func NewFixedBaseTableFromPoints(points []Point_axtw_subgroup, trustLevel IsInputTrusted) (table *FixedBaseTable, err error) {
+	var windowSize uint
+	for w := uint(1); w <= 8; w++ {
+		if _, numEntries := fixedBaseTableDimensions(w); numEntries == len(points) {
+			windowSize = w
+			break
+		}
+	}
+	if windowSize == 0 {
+		err = fmt.Errorf("%w: the number of points %v does not match the size of a table for any valid window size", ErrInvalidFixedBaseTable, len(points))
+		return
+	}
+	for i := range points {
+		if points[i].IsNaP() {
+			err = fmt.Errorf("%w: the point at index %v is a NaP", ErrInvalidFixedBaseTable, i)
+			return
+		}
+	}
+	if !trustLevel.Bool() {
+		var base Point_xtw_subgroup
+		base.SetFrom(&points[0])
+		expected := newFixedBaseTable(&base, windowSize)
+		for i := range points {
+			if !points[i].IsEqual(&expected.entries[i]) {
+				err = fmt.Errorf("%w: the point at index %v is not the expected multiple of the base point", ErrInvalidFixedBaseTable, i)
+				return
+			}
+		}
+	}
+	numDigits, _ := fixedBaseTableDimensions(windowSize)
+	table = &FixedBaseTable{windowSize: windowSize, numDigits: numDigits, entries: make([]Point_axtw_subgroup, len(points))}
+	copy(table.entries, points)
+	return
+}
+
+// Exponentiate computes exponent * base, where base is the point the table was created from.
+//
+// NOTE: This function is not constant-time.
+func (table *FixedBaseTable) Exponentiate(exponent *Exponent) (ret Point_efgh_subgroup) {
+	if table.entries == nil {
+		panic(ErrorPrefix + "called Exponentiate on an uninitialized FixedBaseTable")
+	}
+	halfWindow := 1 << (table.windowSize - 1)
+	glv := exponents.GLV_representation_ConstantTime(exponent)
+	u_digits := exponents.DecomposeSignedFixedWindow(glv.U, table.windowSize)
+	v_digits := exponents.DecomposeSignedFixedWindow(glv.V, table.windowSize)
+
+	var accumulator Point_xtw_subgroup
+	accumulator.SetNeutral()
+	for half, digits := range [2][]int{u_digits, v_digits} {
+		for i, digit := range digits {
+			if digit == 0 {
+				continue
+			}
+			offset := (half*table.numDigits + i) * halfWindow
+			if digit > 0 {
+				accumulator.add_tta(&accumulator.point_xtw_base, &table.entries[offset+digit-1].point_axtw_base)
+			} else {
+				accumulator.sub_tta(&accumulator.point_xtw_base, &table.entries[offset-digit-1].point_axtw_base)
+			}
+		}
+	}
+	ret.SetFrom(&accumulator)
+	return
+}
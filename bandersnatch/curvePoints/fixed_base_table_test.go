@@ -0,0 +1,106 @@
+package curvePoints
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// TestFixedBaseTable checks that exponentiation via a FixedBaseTable gives the same result as ordinary exponentiation for all window sizes.
+func TestFixedBaseTable(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	for windowSize := uint(1); windowSize <= 8; windowSize++ {
+		base := MakeRandomPointUnsafe_xtw_subgroup(drng)
+		table := NewFixedBaseTable(&base, windowSize)
+		testutils.FatalUnless(t, table.WindowSize() == windowSize, "FixedBaseTable has wrong window size")
+		tableBase := table.Base()
+		testutils.FatalUnless(t, tableBase.IsEqual(&base), "FixedBaseTable does not hold correct base point")
+		iterations := 500
+		if windowSize == 8 {
+			iterations = 100
+		}
+		for i := 0; i < iterations; i++ {
+			var exponent Exponent
+			exponent.SetBigInt(getTestExponent(i, drng))
+			result := table.Exponentiate(&exponent)
+			var expected Point_xtw_subgroup
+			expected.Exponentiate(&base, &exponent)
+			testutils.FatalUnless(t, result.IsEqual(&expected), "FixedBaseTable.Exponentiate differs from Exponentiate for window size %v and exponent %v", windowSize, exponent)
+		}
+	}
+}
+
+// TestFixedBaseTableCreation checks that NewFixedBaseTable accepts any subgroup point type and panics on invalid input.
+func TestFixedBaseTableCreation(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	var base Point_xtw_full = MakeRandomPointUnsafe_xtw_full(drng)
+	base.DoubleEq() // ensure we are in the subgroup
+	var base_subgroup Point_efgh_subgroup
+	base_subgroup.SetFromSubgroupPoint(&base, trustedInput)
+	table1 := NewFixedBaseTable(&base, 3)
+	table2 := NewFixedBaseTable(&base_subgroup, 3)
+	var exponent Exponent
+	exponent.SetBigInt(new(big.Int).Rand(drng, common.CurveExponent_Int))
+	result1 := table1.Exponentiate(&exponent)
+	result2 := table2.Exponentiate(&exponent)
+	testutils.FatalUnless(t, result1.IsEqual(&result2), "FixedBaseTable depends on type of base point")
+
+	var notInSubgroup Point_xtw_full = MakeRandomPointUnsafe_xtw_full(drng)
+	for notInSubgroup.IsInSubgroup() {
+		notInSubgroup = MakeRandomPointUnsafe_xtw_full(drng)
+	}
+	testutils.FatalUnless(t, testutils.CheckPanic(NewFixedBaseTable, &notInSubgroup, uint(3)), "NewFixedBaseTable did not panic for point outside subgroup")
+	testutils.FatalUnless(t, testutils.CheckPanic(NewFixedBaseTable, &base, uint(0)), "NewFixedBaseTable did not panic for window size 0")
+	testutils.FatalUnless(t, testutils.CheckPanic(NewFixedBaseTable, &base, uint(9)), "NewFixedBaseTable did not panic for window size 9")
+}
+
+// TestFixedBaseTableFromPoints checks NewFixedBaseTableFromPoints, including its validity checks.
+func TestFixedBaseTableFromPoints(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	base := MakeRandomPointUnsafe_xtw_subgroup(drng)
+	table := NewFixedBaseTable(&base, 4)
+	points := table.Points()
+
+	for _, trustLevel := range []IsInputTrusted{trustedInput, untrustedInput} {
+		recreated, err := NewFixedBaseTableFromPoints(points, trustLevel)
+		testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+		testutils.FatalUnless(t, recreated.WindowSize() == 4, "Recreated FixedBaseTable has wrong window size")
+		var exponent Exponent
+		exponent.SetBigInt(new(big.Int).Rand(drng, common.CurveExponent_Int))
+		result1 := table.Exponentiate(&exponent)
+		result2 := recreated.Exponentiate(&exponent)
+		testutils.FatalUnless(t, result1.IsEqual(&result2), "Recreated FixedBaseTable differs from original")
+
+		_, err = NewFixedBaseTableFromPoints(points[1:], trustLevel)
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidFixedBaseTable), "NewFixedBaseTableFromPoints did not detect wrong number of points")
+	}
+
+	// modify a single entry. This is only detected for untrusted input.
+	points[17].DoubleEq()
+	_, err := NewFixedBaseTableFromPoints(points, untrustedInput)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidFixedBaseTable), "NewFixedBaseTableFromPoints did not detect invalid table")
+	_, err = NewFixedBaseTableFromPoints(points, trustedInput)
+	testutils.FatalUnless(t, err == nil, "NewFixedBaseTableFromPoints checks trusted input")
+
+	// Points must return a copy
+	points2 := table.Points()
+	testutils.FatalUnless(t, !points[17].IsEqual(&points2[17]), "Points did not return a copy")
+}
+
+func BenchmarkFixedBaseTableExponentiate(b *testing.B) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	var exponents [benchSizeCurvePoint]Exponent
+	for i := 0; i < benchSizeCurvePoint; i++ {
+		exponents[i].SetBigInt(new(big.Int).Rand(drng, common.CurveExponent_Int))
+	}
+	base := MakeRandomPointUnsafe_xtw_subgroup(drng)
+	table := NewFixedBaseTable(&base, DefaultFixedBaseTableWindowSize)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = table.Exponentiate(&exponents[n%benchSizeCurvePoint])
+	}
+}
@@ -0,0 +1,209 @@
+package curvePoints
+
+import (
+	"crypto"
+	_ "crypto/sha256" // links in SHA-256 for the XMD:SHA-256 suites
+	_ "crypto/sha512" // links in SHA-512 for the XMD:SHA-512 suites
+	"fmt"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/fieldElements"
+)
+
+// This file contains hash_to_curve and encode_to_curve for Bandersnatch, following RFC 9380.
+//
+// We use the Elligator 2 map (RFC 9380, Section 6.7.1) on the Montgomery curve K*t^2 = s^3 + J*s^2 + s that is birationally equivalent to Bandersnatch,
+// where J = 2(a+d)/(a-d) and K = 4/(a-d). The rational map to twisted Edwards form is (s,t) -> (s/t, (s-1)/(s+1)), see RFC 9380, Section 6.8.2.
+// Exceptional cases of this rational map are mapped to the neutral element.
+//
+// The non-square constant for Elligator 2 is Z = 5, which is the output of find_z_ell2 from RFC 9380, Appendix H.3.
+// For cofactor clearing, we multiply by h_eff = Cofactor = 4.
+// hash_to_field uses expand_message_xmd with L = 48 bytes per field element (see [fieldElements.HashToFieldBytesPerElement]).
+//
+// The suite IDs (see RFC 9380, Section 8.10) are
+//
+//	bandersnatch_XMD:SHA-512_ELL2_RO_
+//	bandersnatch_XMD:SHA-512_ELL2_NU_
+//	bandersnatch_XMD:SHA-256_ELL2_RO_
+//	bandersnatch_XMD:SHA-256_ELL2_NU_
+//
+// where the _RO_ suites implement hash_to_curve (a random oracle to the prime-order subgroup) and the _NU_ suites implement encode_to_curve (which is not indistinguishable from a random oracle).
+// Note that the suite ID is NOT included in the domain separation tag automatically; applications are supposed to choose a DST that includes the suite ID, e.g. "MYAPP-V01-CS01-with-bandersnatch_XMD:SHA-512_ELL2_RO_"
+//
+// NOTE: The implementation is not constant-time. In particular, the running time may leak information about the message.
+
+// Constants for Elligator 2.
+//
+// elligator2_JOverK = J/K = (a+d)/2
+// elligator2_OneOverKSquared = 1/K^2 = (a-d)^2 / 16
+// elligator2_K = K = 4/(a-d)
+// elligator2_Z is the non-square Z = 5.
+var (
+	elligator2_JOverK          FieldElement = fieldElements.InitFieldElementFromString[FieldElement]("0x31c4e09319e133e5e3371dfc35f1db6c65b333b8bbf2a7c959b4f97a8c46ac71")
+	elligator2_OneOverKSquared FieldElement = fieldElements.InitFieldElementFromString[FieldElement]("0x4e73b361c820997fa19b6f1789fe957a544bddb76a1e7d86dfbb904be14f50e1")
+	elligator2_K               FieldElement = fieldElements.InitFieldElementFromString[FieldElement]("0x384d1c153c878eea316b96e5c340cf6abd025b636bd74122926c66eb6fa86d15")
+	elligator2_Z               FieldElement = fieldElements.InitFieldElementFromString[FieldElement]("5")
+)
+
+// HashToCurveSuite is a hash-to-curve suite for Bandersnatch in the sense of RFC 9380.
+//
+// Use one of the predefined suites HashToCurveSuite_XMD_SHA512_RO etc. The zero value is not a valid suite.
+type HashToCurveSuite struct {
+	suiteID      string
+	expander     common.ExpanderXMD
+	randomOracle bool // true for hash_to_curve (_RO_), false for encode_to_curve (_NU_)
+}
+
+// Predefined hash-to-curve suites for Bandersnatch. See the description of hash_to_curve.go for details.
+var (
+	HashToCurveSuite_XMD_SHA512_RO = &HashToCurveSuite{suiteID: "bandersnatch_XMD:SHA-512_ELL2_RO_", expander: common.ExpanderXMD{Hash: crypto.SHA512}, randomOracle: true}
+	HashToCurveSuite_XMD_SHA512_NU = &HashToCurveSuite{suiteID: "bandersnatch_XMD:SHA-512_ELL2_NU_", expander: common.ExpanderXMD{Hash: crypto.SHA512}, randomOracle: false}
+	HashToCurveSuite_XMD_SHA256_RO = &HashToCurveSuite{suiteID: "bandersnatch_XMD:SHA-256_ELL2_RO_", expander: common.ExpanderXMD{Hash: crypto.SHA256}, randomOracle: true}
+	HashToCurveSuite_XMD_SHA256_NU = &HashToCurveSuite{suiteID: "bandersnatch_XMD:SHA-256_ELL2_NU_", expander: common.ExpanderXMD{Hash: crypto.SHA256}, randomOracle: false}
+)
+
+// SuiteID returns the suite ID of the hash-to-curve suite, e.g. "bandersnatch_XMD:SHA-512_ELL2_RO_"
+func (suite *HashToCurveSuite) SuiteID() string {
+	return suite.suiteID
+}
+
+// IsRandomOracle tells whether the suite implements hash_to_curve (true) or encode_to_curve (false).
+func (suite *HashToCurveSuite) IsRandomOracle() bool {
+	return suite.randomOracle
+}
+
+// Hash maps msg to a point in the prime-order subgroup, using the domain separation tag dst.
+//
+// Depending on the suite, this is either hash_to_curve or encode_to_curve from RFC 9380. dst must be non-empty, else we panic.
+func (suite *HashToCurveSuite) Hash(msg []byte, dst []byte) (ret Point_xtw_subgroup) {
+	if suite == nil || suite.expander.Hash == 0 {
+		panic(ErrorPrefix + "Called Hash on invalid HashToCurveSuite")
+	}
+	count := 1
+	if suite.randomOracle {
+		count = 2
+	}
+	u, err := fieldElements.HashToFieldElementsWithExpander(suite.expander, msg, dst, count)
+	if err != nil {
+		// The only possible error for our choice of parameters is an empty dst.
+		panic(fmt.Errorf(ErrorPrefix+"hash to curve failed: %w", err))
+	}
+	ret = mapToCurveAndClearCofactor(&u[0])
+	if suite.randomOracle {
+		Q1 := mapToCurveAndClearCofactor(&u[1])
+		ret.AddEq(&Q1)
+	}
+	return
+}
+
+// HashToCurve implements hash_to_curve from RFC 9380 with the suite bandersnatch_XMD:SHA-512_ELL2_RO_.
+//
+// It maps msg to a point in the prime-order subgroup, using the domain separation tag dst. dst must be non-empty, else we panic.
+// The output distribution is indistinguishable from uniform (in the random oracle model).
+func HashToCurve(msg []byte, dst []byte) Point_xtw_subgroup {
+	return HashToCurveSuite_XMD_SHA512_RO.Hash(msg, dst)
+}
+
+// EncodeToCurve implements encode_to_curve from RFC 9380 with the suite bandersnatch_XMD:SHA-512_ELL2_NU_.
+//
+// It maps msg to a point in the prime-order subgroup, using the domain separation tag dst. dst must be non-empty, else we panic.
+// Note that the output distribution is NOT uniform; use [HashToCurve] unless you know that this is fine for your application.
+func EncodeToCurve(msg []byte, dst []byte) Point_xtw_subgroup {
+	return HashToCurveSuite_XMD_SHA512_NU.Hash(msg, dst)
+}
+
+// mapToCurveAndClearCofactor computes clear_cofactor(map_to_curve(u)).
+//
+// Note that clear_cofactor(Q0 + Q1) == clear_cofactor(Q0) + clear_cofactor(Q1), so we can clear the cofactors before adding;
+// this way, we can use the addition law for the subgroup, which has no exceptional cases.
+func mapToCurveAndClearCofactor(u *FieldElement) (ret Point_xtw_subgroup) {
+	var point Point_xtw_full
+	s, t := mapToCurve_Elligator2(u)
+	montgomeryToTwistedEdwards(&point, &s, &t)
+	// multiply by Cofactor == 4
+	point.DoubleEq()
+	point.DoubleEq()
+	ok := ret.SetFromSubgroupPoint(&point, trustedInput)
+	if !ok {
+		panic(ErrorPrefix + "Internal error: cofactor clearing in hash to curve did not give a subgroup point")
+	}
+	return
+}
+
+// sgn0 implements the sgn0 function from RFC 9380, Section 4.1, which is the parity of the integer representative in [0, BaseFieldSize).
+//
+// Note that this is different from x.Sign()
+func sgn0(x *FieldElement) uint64 {
+	var xInt fieldElements.Uint256
+	x.ToUint256(&xInt)
+	return xInt[0] & 1
+}
+
+// mapToCurve_Elligator2 implements the (straight-line) Elligator 2 method from RFC 9380, Section 6.7.1.
+//
+// It outputs a point (s,t) on the Montgomery curve K*t^2 = s^3 + J*s^2 + s that is birationally equivalent to Bandersnatch.
+func mapToCurve_Elligator2(u *FieldElement) (s FieldElement, t FieldElement) {
+	var tv1, x1, x2, gx1, gx2, x, y FieldElement
+
+	// x1 = -(J / K) * inv0(1 + Z * u^2); If x1 == 0, set x1 = -(J / K)
+	// Note that 1 + Z * u^2 is never zero, as -1 is a square and Z is not. We handle this case anyway.
+	tv1.Square(u)
+	tv1.MulEq(&elligator2_Z)
+	tv1.AddEq(&fieldElementOne)
+	if tv1.IsZero() {
+		x1.Neg(&elligator2_JOverK)
+	} else {
+		x1.Divide(&elligator2_JOverK, &tv1)
+		x1.NegEq()
+	}
+	// x2 = -x1 - (J / K)
+	x2.Add(&x1, &elligator2_JOverK)
+	x2.NegEq()
+
+	// gx = x^3 + (J / K) * x^2 + x / K^2 = x * (x * (x + J/K) + 1/K^2)
+	gx1.Add(&x1, &elligator2_JOverK)
+	gx1.MulEq(&x1)
+	gx1.AddEq(&elligator2_OneOverKSquared)
+	gx1.MulEq(&x1)
+
+	// If is_square(gx1), set x = x1, y = sqrt(gx1) with sgn0(y) == 1. Else set x = x2, y = sqrt(gx2) with sgn0(y) == 0.
+	if y.SquareRoot(&gx1) {
+		x = x1
+		if sgn0(&y) == 0 {
+			y.NegEq()
+		}
+	} else {
+		gx2.Add(&x2, &elligator2_JOverK)
+		gx2.MulEq(&x2)
+		gx2.AddEq(&elligator2_OneOverKSquared)
+		gx2.MulEq(&x2)
+		x = x2
+		if !y.SquareRoot(&gx2) {
+			panic(ErrorPrefix + "Internal error: Elligator 2 failed to find a square root")
+		}
+		if sgn0(&y) == 1 {
+			y.NegEq()
+		}
+	}
+	s.Mul(&x, &elligator2_K)
+	t.Mul(&y, &elligator2_K)
+	return
+}
+
+// montgomeryToTwistedEdwards sets out to the image of the point (s,t) on the Montgomery curve under the rational map (s,t) -> (s/t, (s-1)/(s+1)).
+//
+// If t == 0 or s == -1, the map is not defined (at least in this form) and we output the neutral element, as required by RFC 9380, Section 6.8.2.
+func montgomeryToTwistedEdwards(out *Point_xtw_full, s *FieldElement, t *FieldElement) {
+	var sPlusOne, sMinusOne FieldElement
+	sPlusOne.Add(s, &fieldElementOne)
+	if t.IsZero() || sPlusOne.IsZero() {
+		out.SetNeutral()
+		return
+	}
+	sMinusOne.Sub(s, &fieldElementOne)
+	// X:Y:T:Z = s(s+1) : t(s-1) : s(s-1) : t(s+1). This avoids inversions.
+	out.x.Mul(s, &sPlusOne)
+	out.y.Mul(t, &sMinusOne)
+	out.t.Mul(s, &sMinusOne)
+	out.z.Mul(t, &sPlusOne)
+}
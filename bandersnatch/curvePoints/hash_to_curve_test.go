@@ -0,0 +1,256 @@
+package curvePoints
+
+import (
+	"crypto"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/fieldElements"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// Test vectors for the hash-to-curve suites. The DST is "QUUX-V01-CS02-with-" followed by the suite ID, following the conventions of RFC 9380, Appendix J.
+// These were generated by this implementation and cross-checked against the independent big.Int-based reference implementation below (and an independent Python implementation).
+// x and y are the affine coordinates of the output point (as an element of the curve, not modulo A).
+var hashToCurveTestVectors = []struct {
+	suite *HashToCurveSuite
+	msg   string
+	x     string
+	y     string
+}{
+	{HashToCurveSuite_XMD_SHA512_RO, "", "0x66c1860e4d60778385319e198cbcc719ea7018af1420836c21f0eeb05ff8188c", "0x0267388473ccddf7a8335c076178a9c80c01cb54fdf92b684ceabdaaccb03981"},
+	{HashToCurveSuite_XMD_SHA512_RO, "abc", "0x4c3590db10476f38fa93d5c0a4bcb383a0b81e212df074e0029cd0124951e1db", "0x62f8bdd064844d10ffe9997dfd26315090cb13bf2efb64cadadbc3f989f8756c"},
+	{HashToCurveSuite_XMD_SHA512_RO, "abcdef0123456789", "0x5c9c8d65c5d679073c15724dc6adf79b00322a973002f8df98cf7a70a04880bc", "0x19897ace8ab5b0d4788e78edf44ce9f12c991064cd9e06aa00c31b4e3bf2f414"},
+	{HashToCurveSuite_XMD_SHA512_RO, "q128_" + strings.Repeat("q", 128), "0x53d856a8eb9778517eca0467d3b0f132cf74a3457b493b567a32e83ca3c5f5a0", "0x3216d98a65f6d426742c25a529a4d947524d55db61371e5afc0ddaf5b1930d61"},
+	{HashToCurveSuite_XMD_SHA512_RO, "a512_" + strings.Repeat("a", 512), "0x3febed9c20725abfce10b0743bb153c0ae08ba4feef40e60f25d3836909d4d20", "0x557a292af2a348e76d53346acfef112723d06fb20e3b1c432d4faff6b5a2abec"},
+	{HashToCurveSuite_XMD_SHA512_NU, "", "0x5272378e4f223c0c087d1f7b122a772052d548f5d3b8870f30e13491e624aa31", "0x1e4e7d8465d8297cc28881f19be9faa7daa9380c19ef85044a8c4af4299b4bfc"},
+	{HashToCurveSuite_XMD_SHA512_NU, "abc", "0x09c5491cddb97b92200eecea08af2d616faa9225fdfcae7856daf6802a7d0bd7", "0x1596d9a73ec759b2d80a39d47b1a6b8e7fdc8140245169e9684ce54c89f276ef"},
+	{HashToCurveSuite_XMD_SHA512_NU, "abcdef0123456789", "0x3e2f97a52bffe1763a3c2234d482f2a2cca0214856568b33ffb63d102f93499a", "0x48039dae8d9a706d2bda5bf8075828e6fead1d45670bbe8c70f03949df5360cc"},
+	{HashToCurveSuite_XMD_SHA512_NU, "q128_" + strings.Repeat("q", 128), "0x447ae1f612809276b599c001572cf276533289367b153e36589c33dd4707b2dc", "0x346ca449105d66b430569ffa1eb90a83a707695ba712e219e4f2dea212332e50"},
+	{HashToCurveSuite_XMD_SHA512_NU, "a512_" + strings.Repeat("a", 512), "0x3073f812f2f0dd09892e2ab3cda61374e3f6c68054f1bbd5db35753948dea25b", "0x2691a2605995238b6ed0d737417213fdb7ab44cef6359bfd4b737f28a24d5e83"},
+	{HashToCurveSuite_XMD_SHA256_RO, "", "0x362cbe1679e5eb978746234fdfbd2d3da1a08cb93d288b5cd5fe0e50b0eaa4a0", "0x60a59626a7172fd8053b87b41660319dfa9bce0d6c6ba09691812e588adc9d51"},
+	{HashToCurveSuite_XMD_SHA256_RO, "abc", "0x122e0430ed6e04eeff9664d4d181309391c21febc6bc2f4353618f0613daffba", "0x149fcc2b33dfb656334654024bcfd4eea4abb14bdb3f85236ccbf8c37c556585"},
+	{HashToCurveSuite_XMD_SHA256_RO, "abcdef0123456789", "0x0d446e22d25ee469130c3189885e82ab3ec8813a24af235250c4ff8b6dfe498b", "0x2a684740fc14bb453fdeb9e509251771bc0f8df547c18107d0fdd3704965ea07"},
+	{HashToCurveSuite_XMD_SHA256_RO, "q128_" + strings.Repeat("q", 128), "0x29719204a22c52bc2ce637a2d6dc6d7c1b7266b5192c79d41ea8abe9d6f781c0", "0x4a54525da0b6b4c72d878cb66ffc31205c2a03bca3aa947a0ee9aa2bb924a778"},
+	{HashToCurveSuite_XMD_SHA256_RO, "a512_" + strings.Repeat("a", 512), "0x5cf8a7a6c272332e492dd6fb2b7c7bc19d6bc817ba72252fbdf2f553a0790b05", "0x4f2bd35ba80365969e4c3d74087abff3192b5ae40b66861d9e45c5e5929f51ea"},
+	{HashToCurveSuite_XMD_SHA256_NU, "", "0x1b3196a34da951e9802cf997fe2f357b33ee8ae65ceb44fb2ff2859fab9ea561", "0x4332f693f4869cf3fd5c71f6fe90774821fb1b06c9c9f052d2765606e29313ce"},
+	{HashToCurveSuite_XMD_SHA256_NU, "abc", "0x2e9dc4d0848c5e694f2d0faae0f53eca3bf182e8c59a3d749af40d976859793c", "0x2ea432c0af1b0f98e0312fe0bf1a8dbeb20b67ef6a65fb2befadf47ec8281a45"},
+	{HashToCurveSuite_XMD_SHA256_NU, "abcdef0123456789", "0x37c33afac0b2d3a2836f0696a8a0d64fcd12d610621e62626fde75f04361d6c0", "0x3dc8ee41a202fa205c5fce96a0c9ace376e053c7176eb5b00fd923a26368bbea"},
+	{HashToCurveSuite_XMD_SHA256_NU, "q128_" + strings.Repeat("q", 128), "0x5578bc371f1ec28e1d4925c53582981fb143aa0e19ca5eb079d876248096542e", "0x388a2558ea0e3455210d560358b5147a87a809fa6ce991e89556afbfdf61432e"},
+	{HashToCurveSuite_XMD_SHA256_NU, "a512_" + strings.Repeat("a", 512), "0x6498c5c48bd3a29aa069815928a8450e77a050019fc288f1943c1b4513763875", "0x3a633c6bf2e8c2393c908177f55e499113bba1409ab7c404f4383151b5b2d171"},
+}
+
+func TestHashToCurveTestVectors(t *testing.T) {
+	for _, testVector := range hashToCurveTestVectors {
+		dst := []byte("QUUX-V01-CS02-with-" + testVector.suite.SuiteID())
+		x := fieldElements.InitFieldElementFromString[FieldElement](testVector.x)
+		y := fieldElements.InitFieldElementFromString[FieldElement](testVector.y)
+		expected, err := CurvePointFromXYAffine_subgroup(&x, &y, untrustedInput)
+		testutils.FatalUnless(t, err == nil, "Test vector is not a valid subgroup point: %v", err)
+		result := testVector.suite.Hash([]byte(testVector.msg), dst)
+		testutils.FatalUnless(t, result.IsEqual(&expected), "Hash to curve does not match test vector for suite %v and message %q", testVector.suite.SuiteID(), testVector.msg)
+		reference := hashToCurve_reference(testVector.suite, []byte(testVector.msg), dst)
+		testutils.FatalUnless(t, result.IsEqual(&reference), "Hash to curve does not match reference implementation for suite %v and message %q", testVector.suite.SuiteID(), testVector.msg)
+	}
+}
+
+// TestHashToCurveReference compares the hash-to-curve suites against the big.Int reference implementation for random messages.
+func TestHashToCurveReference(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	for _, suite := range []*HashToCurveSuite{HashToCurveSuite_XMD_SHA512_RO, HashToCurveSuite_XMD_SHA512_NU, HashToCurveSuite_XMD_SHA256_RO, HashToCurveSuite_XMD_SHA256_NU} {
+		for i := 0; i < 20; i++ {
+			msg := make([]byte, drng.Intn(100))
+			drng.Read(msg)
+			dst := []byte("TEST-DST-" + suite.SuiteID())
+			result := suite.Hash(msg, dst)
+			reference := hashToCurve_reference(suite, msg, dst)
+			testutils.FatalUnless(t, result.IsEqual(&reference), "Hash to curve does not match reference implementation for suite %v", suite.SuiteID())
+			testutils.FatalUnless(t, !result.IsNaP(), "Hash to curve output NaP")
+		}
+	}
+	// Check that HashToCurve and EncodeToCurve use the suites they claim to use and that hash_to_curve and encode_to_curve differ.
+	result1 := HashToCurve([]byte("abc"), []byte("DST"))
+	result2 := HashToCurveSuite_XMD_SHA512_RO.Hash([]byte("abc"), []byte("DST"))
+	testutils.FatalUnless(t, result1.IsEqual(&result2), "HashToCurve does not use SHA-512 RO suite")
+	result1 = EncodeToCurve([]byte("abc"), []byte("DST"))
+	result2 = HashToCurveSuite_XMD_SHA512_NU.Hash([]byte("abc"), []byte("DST"))
+	testutils.FatalUnless(t, result1.IsEqual(&result2), "EncodeToCurve does not use SHA-512 NU suite")
+	result2 = HashToCurve([]byte("abc"), []byte("DST"))
+	testutils.FatalUnless(t, !result1.IsEqual(&result2), "HashToCurve and EncodeToCurve give the same result")
+	// Domain separation
+	result1 = HashToCurve([]byte("abc"), []byte("DST1"))
+	result2 = HashToCurve([]byte("abc"), []byte("DST2"))
+	testutils.FatalUnless(t, !result1.IsEqual(&result2), "HashToCurve ignores DST")
+
+	testutils.FatalUnless(t, testutils.CheckPanic(HashToCurve, []byte("abc"), []byte{}), "HashToCurve did not panic on empty DST")
+	testutils.FatalUnless(t, HashToCurveSuite_XMD_SHA512_RO.IsRandomOracle() && !HashToCurveSuite_XMD_SHA256_NU.IsRandomOracle(), "IsRandomOracle wrong")
+}
+
+// TestElligator2 checks the constants used in the Elligator 2 map and compares the map itself against the reference implementation.
+func TestElligator2(t *testing.T) {
+	var aPlusD, aMinusD, temp FieldElement
+	aPlusD.Add(&CurveParameterA_fe, &CurveParameterD_fe)
+	aMinusD.Sub(&CurveParameterA_fe, &CurveParameterD_fe)
+	temp.Mul(&elligator2_JOverK, &fieldElementTwo)
+	testutils.FatalUnless(t, temp.IsEqual(&aPlusD), "J/K constant is wrong")
+	temp.Mul(&elligator2_K, &aMinusD)
+	temp.SubInt64(&temp, 4)
+	testutils.FatalUnless(t, temp.IsZero(), "K constant is wrong")
+	temp.Square(&elligator2_K)
+	temp.MulEq(&elligator2_OneOverKSquared)
+	testutils.FatalUnless(t, temp.IsOne(), "1/K^2 constant is wrong")
+	testutils.FatalUnless(t, elligator2_Z.Jacobi() == -1, "Z is a square")
+	// Z = 5 is the output of find_z_ell2, so +/-1, ... +/-4 must be squares.
+	for i := int64(1); i < 5; i++ {
+		temp.SetInt64(i)
+		testutils.FatalUnless(t, temp.Jacobi() == 1, "Z is not minimal")
+		temp.SetInt64(-i)
+		testutils.FatalUnless(t, temp.Jacobi() == 1, "Z is not minimal")
+	}
+
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	for i := 0; i < 200; i++ {
+		var u FieldElement
+		u.SetRandomUnsafe(drng)
+		if i == 0 {
+			u.SetZero()
+		}
+		s, tt := mapToCurve_Elligator2(&u)
+		sRef, tRef := elligator2_reference(u.ToBigInt())
+		testutils.FatalUnless(t, s.ToBigInt().Cmp(sRef) == 0 && tt.ToBigInt().Cmp(tRef) == 0, "Elligator 2 differs from reference implementation")
+	}
+}
+
+// TestMontgomeryToTwistedEdwardsExceptional checks that the exceptional cases of the rational map give the neutral element.
+func TestMontgomeryToTwistedEdwardsExceptional(t *testing.T) {
+	var s, tt FieldElement
+	var point Point_xtw_full
+	s.SetZero()
+	tt.SetZero()
+	montgomeryToTwistedEdwards(&point, &s, &tt)
+	testutils.FatalUnless(t, point.IsNeutralElement(), "Rational map did not map (0,0) to neutral element")
+	s.SetInt64(-1)
+	tt.SetOne()
+	montgomeryToTwistedEdwards(&point, &s, &tt)
+	testutils.FatalUnless(t, point.IsNeutralElement(), "Rational map did not map s=-1 to neutral element")
+}
+
+// elligator2_reference is a reference implementation of Elligator 2 (RFC 9380, Section 6.7.1) using big.Int arithmetic.
+func elligator2_reference(u *big.Int) (s *big.Int, t *big.Int) {
+	p := BaseFieldSize_Int
+	mod := func(x *big.Int) *big.Int { return x.Mod(x, p) }
+	inv := func(x *big.Int) *big.Int { return new(big.Int).ModInverse(x, p) }
+	a := CurveParameterA_Int
+	d := CurveParameterD_Int
+	aMinusD := mod(new(big.Int).Sub(a, d))
+	K := mod(new(big.Int).Mul(big.NewInt(4), inv(aMinusD)))
+	J := mod(new(big.Int).Mul(big.NewInt(2), new(big.Int).Mul(new(big.Int).Add(a, d), inv(aMinusD))))
+	JOverK := mod(new(big.Int).Mul(J, inv(K)))
+	oneOverK2 := inv(mod(new(big.Int).Mul(K, K)))
+	Z := big.NewInt(5)
+
+	g := func(x *big.Int) *big.Int {
+		x2 := new(big.Int).Mul(x, x)
+		ret := new(big.Int).Mul(x2, x)
+		ret.Add(ret, new(big.Int).Mul(JOverK, x2))
+		ret.Add(ret, new(big.Int).Mul(oneOverK2, x))
+		return mod(ret)
+	}
+	tv1 := mod(new(big.Int).Add(big.NewInt(1), new(big.Int).Mul(Z, new(big.Int).Mul(u, u))))
+	x1 := mod(new(big.Int).Neg(new(big.Int).Mul(JOverK, inv(tv1))))
+	x2 := mod(new(big.Int).Sub(new(big.Int).Neg(x1), JOverK))
+	var x, y *big.Int
+	if big.Jacobi(g(x1), p) >= 0 {
+		x = x1
+		y = new(big.Int).ModSqrt(g(x1), p)
+		if y.Bit(0) == 0 {
+			y = mod(y.Neg(y))
+		}
+	} else {
+		x = x2
+		y = new(big.Int).ModSqrt(g(x2), p)
+		if y.Bit(0) == 1 {
+			y = mod(y.Neg(y))
+		}
+	}
+	s = mod(new(big.Int).Mul(x, K))
+	t = mod(new(big.Int).Mul(y, K))
+	return
+}
+
+// edwardsAdd_reference adds two affine points on the Bandersnatch curve, using big.Int arithmetic. The points must be such that the addition law works.
+func edwardsAdd_reference(x1, y1, x2, y2 *big.Int) (x3, y3 *big.Int) {
+	p := BaseFieldSize_Int
+	dxxyy := new(big.Int).Mul(CurveParameterD_Int, new(big.Int).Mul(new(big.Int).Mul(x1, x2), new(big.Int).Mul(y1, y2)))
+	x3 = new(big.Int).Add(new(big.Int).Mul(x1, y2), new(big.Int).Mul(y1, x2))
+	x3.Mul(x3, new(big.Int).ModInverse(new(big.Int).Mod(new(big.Int).Add(big.NewInt(1), dxxyy), p), p))
+	x3.Mod(x3, p)
+	y3 = new(big.Int).Sub(new(big.Int).Mul(y1, y2), new(big.Int).Mul(CurveParameterA_Int, new(big.Int).Mul(x1, x2)))
+	y3.Mul(y3, new(big.Int).ModInverse(new(big.Int).Mod(new(big.Int).Sub(big.NewInt(1), dxxyy), p), p))
+	y3.Mod(y3, p)
+	return
+}
+
+// hashToCurve_reference is a reference implementation of hash-to-curve using big.Int arithmetic.
+func hashToCurve_reference(suite *HashToCurveSuite, msg []byte, dst []byte) Point_axtw_subgroup {
+	p := BaseFieldSize_Int
+	count := 1
+	if suite.randomOracle {
+		count = 2
+	}
+	var hash crypto.Hash = crypto.SHA256
+	if strings.Contains(suite.SuiteID(), "SHA-512") {
+		hash = crypto.SHA512
+	}
+	uniformBytes, err := common.ExpandMessageXMD(hash, msg, dst, 48*count)
+	if err != nil {
+		panic(err)
+	}
+	x, y := big.NewInt(0), big.NewInt(1)
+	for i := 0; i < count; i++ {
+		u := new(big.Int).SetBytes(uniformBytes[48*i : 48*(i+1)])
+		u.Mod(u, p)
+		s, t := elligator2_reference(u)
+		sPlusOne := new(big.Int).Add(s, big.NewInt(1))
+		sPlusOne.Mod(sPlusOne, p)
+		var xi, yi *big.Int
+		if t.Sign() == 0 || sPlusOne.Sign() == 0 {
+			xi, yi = big.NewInt(0), big.NewInt(1)
+		} else {
+			xi = new(big.Int).Mul(s, new(big.Int).ModInverse(t, p))
+			xi.Mod(xi, p)
+			yi = new(big.Int).Mul(new(big.Int).Sub(s, big.NewInt(1)), new(big.Int).ModInverse(sPlusOne, p))
+			yi.Mod(yi, p)
+		}
+		// clear cofactor by doubling twice
+		xi, yi = edwardsAdd_reference(xi, yi, xi, yi)
+		xi, yi = edwardsAdd_reference(xi, yi, xi, yi)
+		x, y = edwardsAdd_reference(x, y, xi, yi)
+	}
+	var xFE, yFE FieldElement
+	xFE.SetBigInt(x)
+	yFE.SetBigInt(y)
+	ret, err2 := CurvePointFromXYAffine_subgroup(&xFE, &yFE, untrustedInput)
+	if err2 != nil {
+		panic(err2)
+	}
+	return ret
+}
+
+func BenchmarkHashToCurve(b *testing.B) {
+	msg := []byte("abc")
+	dst := []byte("QUUX-V01-CS02-with-bandersnatch_XMD:SHA-512_ELL2_RO_")
+	b.Run("HashToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = HashToCurve(msg, dst)
+		}
+	})
+	b.Run("EncodeToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = EncodeToCurve(msg, dst)
+		}
+	})
+}
@@ -0,0 +1,322 @@
+package curvePoints
+
+import (
+	"fmt"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains multi-exponentiation (a.k.a. multi-scalar multiplication) algorithms, i.e. algorithms that compute sum_i scalars[i] * points[i].
+//
+// We first use the GLV decomposition to write scalars[i] * points[i] = u_i * points[i] + v_i * Endo(points[i]) with |u_i|, |v_i| < 2^127.
+// This gives a multi-exponentiation with twice as many points, but exponents of only half the length.
+// The GLV halves are then recoded into signed digits of a fixed window size (see exponents.DecomposeSignedFixedWindow)
+//
+// For few points, we use Straus' algorithm (i.e. interleaved fixed-window exponentiation with a table of small multiples for each point).
+// For many points, we use Pippenger's bucket method. The buckets are kept in extended twisted Edwards coordinates and accumulate the affine expanded points via mixed additions.
+//
+// NOTE: These algorithms are not constant-time.
+
+// multiExponentiateStrausThreshold is the number of points up to which MultiExponentiate uses Straus' algorithm rather than Pippenger's.
+const multiExponentiateStrausThreshold = 12
+
+// strausWindowSize is the window size used in multiExponentiate_Straus.
+const strausWindowSize = 4
+
+// strausMaxWindowSize is the maximal window size used by strausMultiWindowSize.
+const strausMaxWindowSize = 8
+
+// pippengerMaxWindowSize is the maximal window size used in multiExponentiate_Pippenger.
+const pippengerMaxWindowSize = 16
+
+// MultiExponentiate computes sum_i scalars[i] * points[i].
+//
+// points and scalars must have the same length, else we panic. All points must be of a type that can only represent subgroup elements;
+// if this is not the case, convert the points explicitly first (e.g. via SetFromSubgroupPoint).
+// If any point is a NaP, the result is a NaP.
+//
+// NOTE: This function is not constant-time.
+func MultiExponentiate(points CurvePointSlice, scalars []Exponent) (ret Point_efgh_subgroup) {
+	if !checkMultiExponentiationInput(points, scalars, "MultiExponentiate") {
+		ret = Point_efgh_subgroup{}
+		return
+	}
+	if points.Len() <= multiExponentiateStrausThreshold {
+		return multiExponentiate_Straus(points, scalars)
+	}
+	return multiExponentiate_Pippenger(points, scalars)
+}
+
+// checkMultiExponentiationInput checks the input to multi-exponentiation functions; caller is the name of the calling function, used in error messages.
+//
+// We panic if the lengths of points and scalars differ or if the points are not of a type that can only represent subgroup elements.
+// If any of the points is a NaP, we call the NaP handler and return false.
+func checkMultiExponentiationInput(points CurvePointSlice, scalars []Exponent, caller string) (ok bool) {
+	n := points.Len()
+	if n != len(scalars) {
+		panic(fmt.Errorf(ErrorPrefix+"%v called with %v points, but %v scalars", caller, n, len(scalars)))
+	}
+	return checkMultiExponentiationPoints(points, caller)
+}
+
+// checkMultiExponentiationPoints performs the checks of checkMultiExponentiationInput that only concern the points.
+func checkMultiExponentiationPoints(points CurvePointSlice, caller string) (ok bool) {
+	n := points.Len()
+	for i := 0; i < n; i++ {
+		point := points.GetByIndex(i)
+		ensureSubgroupOnly(point)
+		if point.IsNaP() {
+			napEncountered("NaP encountered in "+caller, false, point)
+			return false
+		}
+	}
+	return true
+}
+
+// glvExpandRange computes the GLV decomposition of scalars[i] = u_i + v_i * EndomorphismEigenvalue for from <= i < to and decomposes u_i and v_i into signed digits of the given window size.
+//
+// The output is written to the (preallocated) expanded[2*i], expanded[2*i+1] (points[i] and Endo(points[i])) and digits[2*i], digits[2*i+1] (the digits of u_i and v_i).
+// This means that sum_i scalars[i] * points[i] == sum_{k,j} digits[k][j] * 2^(windowSize * j) * expanded[k].
+//
+// The points are assumed to be in the subgroup and not NaPs.
+func glvExpandRange(points CurvePointSlice, scalars []Exponent, windowSize uint, from int, to int, expanded CurvePointSlice_xtw_subgroup, digits [][]int) {
+	glvExpandPointsRange(points, from, to, expanded)
+	glvExpandDigitsRange(scalars, windowSize, from, to, digits)
+}
+
+// glvExpandPointsRange performs the part of glvExpandRange that only depends on the points, i.e. it sets expanded[2*i] = points[i] and expanded[2*i+1] = Endo(points[i]) for from <= i < to.
+func glvExpandPointsRange(points CurvePointSlice, from int, to int, expanded CurvePointSlice_xtw_subgroup) {
+	for i := from; i < to; i++ {
+		expanded[2*i].SetFrom(points.GetByIndex(i))
+		expanded[2*i+1].Endo(&expanded[2*i])
+	}
+}
+
+// glvExpandDigitsRange performs the part of glvExpandRange that only depends on the scalars, i.e. it writes the digits of the GLV decomposition of scalars[i] to digits[2*i] and digits[2*i+1] for from <= i < to.
+func glvExpandDigitsRange(scalars []Exponent, windowSize uint, from int, to int, digits [][]int) {
+	for i := from; i < to; i++ {
+		glv := exponents.GLV_representation_ConstantTime(&scalars[i])
+		digits[2*i] = exponents.DecomposeSignedFixedWindow(glv.U, windowSize)
+		digits[2*i+1] = exponents.DecomposeSignedFixedWindow(glv.V, windowSize)
+	}
+}
+
+// multiExponentiate_Straus computes sum_i scalars[i] * points[i] via Straus' algorithm, using the GLV decomposition.
+//
+// points are assumed to be in the subgroup and not NaPs.
+func multiExponentiate_Straus(points CurvePointSlice, scalars []Exponent) (ret Point_efgh_subgroup) {
+	return strausEvaluate(strausPrecompute(points, strausWindowSize), scalars, strausWindowSize)
+}
+
+// strausMultiWindowSize returns the window size to use in Straus' algorithm if the same tables are used for numRows multi-exponentiations.
+//
+// With window size w, computing the tables takes about 2^(w-1) additions per GLV-expanded point and each evaluation takes about 128/w + 1 additions per GLV-expanded point
+// (plus 128 doublings, which do not depend on w). Since the tables are shared, larger windows pay off for more rows. Note that the number of points does not matter.
+// For numRows == 1, this gives strausWindowSize.
+func strausMultiWindowSize(numRows int) (windowSize uint) {
+	var bestCost int = -1
+	for w := uint(2); w <= strausMaxWindowSize; w++ {
+		cost := strausCost(1, numRows, w)
+		if bestCost < 0 || cost < bestCost {
+			bestCost = cost
+			windowSize = w
+		}
+	}
+	return
+}
+
+// strausCost estimates the number of additions in Straus' algorithm with window size w for numRows multi-exponentiations of the same numPoints (GLV-expanded) points,
+// including the computation of the shared tables. Doublings are not counted.
+func strausCost(numPoints int, numRows int, w uint) int {
+	numWindows := int((128+w-1)/w + 1)
+	return numPoints * ((1 << (w - 1)) + numRows*numWindows)
+}
+
+// strausPrecompute computes the tables of small multiples of the GLV-expanded points that are used in Straus' algorithm with window size w.
+//
+// For each expanded point P (see glvExpandRange), we compute 1*P, 2*P, ..., 2^(w-1) * P.
+// The multiples of expanded[k] are stored at k*2^(w-1) ... (k+1)*2^(w-1) - 1
+//
+// points are assumed to be in the subgroup and not NaPs.
+func strausPrecompute(points CurvePointSlice, w uint) (tables []Point_axtw_subgroup) {
+	tableSize := 1 << (w - 1)
+	n := points.Len()
+	var expanded CurvePointSlice_xtw_subgroup = make([]Point_xtw_subgroup, 2*n)
+	glvExpandPointsRange(points, 0, n, expanded)
+
+	var tables_xtw CurvePointSlice_xtw_subgroup = make([]Point_xtw_subgroup, len(expanded)*tableSize)
+	for k := range expanded {
+		table := tables_xtw[k*tableSize : (k+1)*tableSize]
+		table[0] = expanded[k]
+		for j := 1; j < tableSize; j++ {
+			table[j].Add(&table[j-1], &expanded[k])
+		}
+	}
+	return batchToAffine_subgroup(tables_xtw)
+}
+
+// strausEvaluate computes sum_i scalars[i] * points[i] via Straus' algorithm with window size w, where tables is the output of strausPrecompute(points, w).
+func strausEvaluate(tables []Point_axtw_subgroup, scalars []Exponent, w uint) (ret Point_efgh_subgroup) {
+	tableSize := 1 << (w - 1)
+	n := len(scalars)
+	if n == 0 {
+		ret.SetNeutral()
+		return
+	}
+	digits := make([][]int, 2*n)
+	glvExpandDigitsRange(scalars, w, 0, n, digits)
+
+	var accumulator Point_xtw_subgroup
+	accumulator.SetNeutral()
+	numDigits := len(digits[0])
+	for pos := numDigits - 1; pos >= 0; pos-- {
+		if pos != numDigits-1 {
+			for j := uint(0); j < w; j++ {
+				accumulator.DoubleEq()
+			}
+		}
+		for k := range digits {
+			digit := digits[k][pos]
+			if digit > 0 {
+				accumulator.add_tta(&accumulator.point_xtw_base, &tables[k*tableSize+digit-1].point_axtw_base)
+			} else if digit < 0 {
+				accumulator.sub_tta(&accumulator.point_xtw_base, &tables[k*tableSize-digit-1].point_axtw_base)
+			}
+		}
+	}
+	ret.SetFrom(&accumulator)
+	return
+}
+
+// pippengerWindowSize returns the window size that we use in multiExponentiate_Pippenger for the given number of (GLV-expanded) points.
+//
+// Processing a window of size c takes approximately numPoints + 2^c additions (as we use signed digits, we have 2^(c-1) buckets and summing up the buckets takes 2 additions per bucket).
+// We have 128/c + 1 windows, so we just choose c to minimize (128/c + 1) * (numPoints + 2^c).
+func pippengerWindowSize(numPoints int) (windowSize uint) {
+	var bestCost int = -1
+	for c := uint(1); c <= pippengerMaxWindowSize; c++ {
+		cost := pippengerCost(numPoints, c)
+		if bestCost < 0 || cost < bestCost {
+			bestCost = cost
+			windowSize = c
+		}
+	}
+	return
+}
+
+// pippengerCost estimates the number of additions in Pippenger's bucket method with window size c for numPoints (GLV-expanded) points, see pippengerWindowSize.
+func pippengerCost(numPoints int, c uint) int {
+	numWindows := int((128+c-1)/c + 1)
+	return numWindows * (numPoints + (1 << c))
+}
+
+// pippengerScratchSpace holds the buckets used by Pippenger's bucket method, so they can be reused across calls to pippengerWindowSum.
+type pippengerScratchSpace struct {
+	buckets    []Point_xtw_subgroup
+	bucketUsed []bool
+}
+
+// newPippengerScratchSpace allocates a pippengerScratchSpace for window size c.
+func newPippengerScratchSpace(c uint) *pippengerScratchSpace {
+	numBuckets := 1 << (c - 1)
+	return &pippengerScratchSpace{buckets: make([]Point_xtw_subgroup, numBuckets), bucketUsed: make([]bool, numBuckets)}
+}
+
+// multiExponentiate_Pippenger computes sum_i scalars[i] * points[i] via Pippenger's bucket method, using the GLV decomposition.
+//
+// points are assumed to be in the subgroup and not NaPs.
+func multiExponentiate_Pippenger(points CurvePointSlice, scalars []Exponent) (ret Point_efgh_subgroup) {
+	c := pippengerWindowSize(2 * points.Len())
+	return pippengerEvaluate(pippengerPrecompute(points), scalars, c, newPippengerScratchSpace(c))
+}
+
+// pippengerPrecompute returns the GLV-expanded points (see glvExpandRange) in affine coordinates.
+//
+// points are assumed to be in the subgroup and not NaPs.
+func pippengerPrecompute(points CurvePointSlice) (expanded []Point_axtw_subgroup) {
+	n := points.Len()
+	var expanded_xtw CurvePointSlice_xtw_subgroup = make([]Point_xtw_subgroup, 2*n)
+	glvExpandPointsRange(points, 0, n, expanded_xtw)
+	return batchToAffine_subgroup(expanded_xtw)
+}
+
+// pippengerEvaluate computes sum_i scalars[i] * points[i] via Pippenger's bucket method with window size c, where expanded is the output of pippengerPrecompute(points).
+//
+// scratch must have been created by newPippengerScratchSpace(c).
+func pippengerEvaluate(expanded []Point_axtw_subgroup, scalars []Exponent, c uint, scratch *pippengerScratchSpace) (ret Point_efgh_subgroup) {
+	n := len(scalars)
+	if n == 0 {
+		ret.SetNeutral()
+		return
+	}
+	digits := make([][]int, 2*n)
+	glvExpandDigitsRange(scalars, c, 0, n, digits)
+
+	var accumulator Point_xtw_subgroup
+	accumulator.SetNeutral()
+	numDigits := len(digits[0])
+	for pos := numDigits - 1; pos >= 0; pos-- {
+		if pos != numDigits-1 {
+			for j := uint(0); j < c; j++ {
+				accumulator.DoubleEq()
+			}
+		}
+		windowSum := pippengerWindowSum(expanded, digits, pos, scratch.buckets, scratch.bucketUsed)
+		accumulator.AddEq(&windowSum)
+	}
+	ret.SetFrom(&accumulator)
+	return
+}
+
+// pippengerWindowSum computes sum_k digits[k][pos] * expanded[k] via the bucket method.
+//
+// buckets and bucketUsed are used as scratch space; their length must be 2^(c-1), where c is the window size that was used to compute the digits.
+func pippengerWindowSum(expanded []Point_axtw_subgroup, digits [][]int, pos int, buckets []Point_xtw_subgroup, bucketUsed []bool) (windowSum Point_xtw_subgroup) {
+	// bucket[j] holds the sum of all expanded points k with digits[k][pos] == j+1, minus those with digits[k][pos] == -(j+1).
+	// bucketUsed[j] tells whether bucket[j] holds a valid value; otherwise, it is considered to be the neutral element.
+	for j := range bucketUsed {
+		bucketUsed[j] = false
+	}
+
+	// put points into buckets. We call add_tta / sub_tta directly rather than going through AddEq / SubEq, which would convert the affine input.
+	for k := range expanded {
+		digit := digits[k][pos]
+		if digit == 0 {
+			continue
+		}
+		if digit > 0 {
+			if bucketUsed[digit-1] {
+				buckets[digit-1].add_tta(&buckets[digit-1].point_xtw_base, &expanded[k].point_axtw_base)
+			} else {
+				buckets[digit-1].SetFrom(&expanded[k])
+				bucketUsed[digit-1] = true
+			}
+		} else {
+			if bucketUsed[-digit-1] {
+				buckets[-digit-1].sub_tta(&buckets[-digit-1].point_xtw_base, &expanded[k].point_axtw_base)
+			} else {
+				buckets[-digit-1].neg_ta(&expanded[k].point_axtw_base)
+				bucketUsed[-digit-1] = true
+			}
+		}
+	}
+
+	// compute sum_j (j+1) * buckets[j] as sum_j (sum_{j' >= j} buckets[j'])
+	var runningSum Point_xtw_subgroup
+	var runningSumUsed bool = false
+	windowSum.SetNeutral()
+	for j := len(buckets) - 1; j >= 0; j-- {
+		if bucketUsed[j] {
+			if runningSumUsed {
+				runningSum.AddEq(&buckets[j])
+			} else {
+				runningSum.SetFrom(&buckets[j])
+				runningSumUsed = true
+			}
+		}
+		if runningSumUsed {
+			windowSum.AddEq(&runningSum)
+		}
+	}
+	return
+}
@@ -0,0 +1,130 @@
+package curvePoints
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// This file contains a parallel variant of the multi-exponentiation algorithm from multi_exponentiate.go
+//
+// We parallelize Pippenger's bucket method in two stages:
+// First, the points are split into contiguous chunks; each worker computes the GLV decomposition and affine coordinates for one chunk at a time.
+// Second, the windows (digit positions) are distributed among the workers. Each window is processed independently with its own set of buckets.
+// The window sums are then combined serially, which only takes about 128 doublings and a few additions.
+
+// MultiExponentiateParallel computes sum_i scalars[i] * points[i], using up to numWorkers goroutines. The result is the same as for MultiExponentiate.
+//
+// If numWorkers <= 0, we use runtime.GOMAXPROCS(0) many workers.
+// ctx can be used for cancellation; if ctx is done before the computation finishes, we return (a meaningless point and) ctx.Err().
+// Note that points.GetByIndex is called concurrently from several goroutines; this is fine for all CurvePointSlice implementations in this package.
+//
+// The requirements on points and scalars are the same as for MultiExponentiate; in particular, we panic if their lengths differ.
+//
+// NOTE: This function is not constant-time.
+func MultiExponentiateParallel(ctx context.Context, points CurvePointSlice, scalars []Exponent, numWorkers int) (ret Point_efgh_subgroup, err error) {
+	if !checkMultiExponentiationInput(points, scalars, "MultiExponentiateParallel") {
+		ret = Point_efgh_subgroup{}
+		return
+	}
+	if numWorkers <= 0 {
+		numWorkers = runtime.GOMAXPROCS(0)
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	n := points.Len()
+	// There is no point in parallelizing for small inputs.
+	if numWorkers == 1 || n <= multiExponentiateStrausThreshold {
+		ret = MultiExponentiate(points, scalars)
+		return
+	}
+	return multiExponentiate_PippengerParallel(ctx, points, scalars, numWorkers)
+}
+
+// multiExponentiateParallelChunkSize is the number of (original) points that a worker processes at once when computing the GLV decomposition and affine coordinates.
+const multiExponentiateParallelChunkSize = 256
+
+// runParallel calls f(0), ..., f(numJobs-1) using up to numWorkers goroutines and waits for all of them to finish.
+// If ctx is done, no new calls to f are started and we return ctx.Err() (after the currently running calls have finished).
+func runParallel(ctx context.Context, numWorkers int, numJobs int, f func(job int)) error {
+	if numWorkers > numJobs {
+		numWorkers = numJobs
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				f(job)
+			}
+		}()
+	}
+	var err error
+jobLoop:
+	for job := 0; job < numJobs; job++ {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break jobLoop
+		case jobs <- job:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return err
+}
+
+// multiExponentiate_PippengerParallel is the parallel variant of multiExponentiate_Pippenger.
+//
+// points are assumed to be in the subgroup and not NaPs.
+func multiExponentiate_PippengerParallel(ctx context.Context, points CurvePointSlice, scalars []Exponent, numWorkers int) (ret Point_efgh_subgroup, err error) {
+	n := points.Len()
+	c := pippengerWindowSize(2 * n)
+
+	// GLV-expand the input and compute affine coordinates. This is done in chunks of the input.
+	expanded_xtw := make(CurvePointSlice_xtw_subgroup, 2*n)
+	expanded := make([]Point_axtw_subgroup, 2*n)
+	digits := make([][]int, 2*n)
+	numChunks := (n + multiExponentiateParallelChunkSize - 1) / multiExponentiateParallelChunkSize
+	err = runParallel(ctx, numWorkers, numChunks, func(chunk int) {
+		from := chunk * multiExponentiateParallelChunkSize
+		to := from + multiExponentiateParallelChunkSize
+		if to > n {
+			to = n
+		}
+		glvExpandRange(points, scalars, c, from, to, expanded_xtw, digits)
+		copy(expanded[2*from:2*to], batchToAffine_subgroup(expanded_xtw[2*from:2*to]))
+	})
+	if err != nil {
+		return
+	}
+
+	// Compute the individual window sums. Each window is a separate job.
+	numDigits := len(digits[0])
+	windowSums := make([]Point_xtw_subgroup, numDigits)
+	var scratchPool sync.Pool = sync.Pool{New: func() any {
+		return newPippengerScratchSpace(c)
+	}}
+	err = runParallel(ctx, numWorkers, numDigits, func(pos int) {
+		scratch := scratchPool.Get().(*pippengerScratchSpace)
+		windowSums[pos] = pippengerWindowSum(expanded, digits, pos, scratch.buckets, scratch.bucketUsed)
+		scratchPool.Put(scratch)
+	})
+	if err != nil {
+		return
+	}
+
+	// combine the window sums
+	var accumulator Point_xtw_subgroup = windowSums[numDigits-1]
+	for pos := numDigits - 2; pos >= 0; pos-- {
+		for j := uint(0); j < c; j++ {
+			accumulator.DoubleEq()
+		}
+		accumulator.AddEq(&windowSums[pos])
+	}
+	ret.SetFrom(&accumulator)
+	return
+}
@@ -0,0 +1,74 @@
+package curvePoints
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// TestMultiExponentiateParallel compares MultiExponentiateParallel against naive summation and the serial MultiExponentiate for various numbers of workers.
+func TestMultiExponentiateParallel(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	for _, n := range []int{0, 1, 5, 13, 100, 257, 1000} {
+		points, scalars := makeMultiExponentiationSample(n, drng)
+		expected := multiExponentiate_naive(points, scalars)
+		serial := MultiExponentiate(AsCurvePointSlice(points), scalars)
+		testutils.FatalUnless(t, serial.IsEqual(&expected), "MultiExponentiate differs from naive result for %v points", n)
+		for _, numWorkers := range []int{-1, 0, 1, 2, 3, 8, 100} {
+			result, err := MultiExponentiateParallel(context.Background(), AsCurvePointSlice(points), scalars, numWorkers)
+			testutils.FatalUnless(t, err == nil, "MultiExponentiateParallel returned unexpected error %v", err)
+			testutils.FatalUnless(t, result.IsEqual(&expected), "MultiExponentiateParallel differs from naive result for %v points and %v workers", n, numWorkers)
+		}
+	}
+}
+
+// TestMultiExponentiateParallelCancel checks that MultiExponentiateParallel respects cancellation via its context argument.
+func TestMultiExponentiateParallelCancel(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	points, scalars := makeMultiExponentiationSample(100, drng)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := MultiExponentiateParallel(ctx, AsCurvePointSlice(points), scalars, 4)
+	testutils.FatalUnless(t, errors.Is(err, context.Canceled), "MultiExponentiateParallel did not report cancellation. Error was %v", err)
+
+	// cancel during the computation (jobs that have not yet started are skipped)
+	ctx, cancel = context.WithCancel(context.Background())
+	var jobsRun int
+	err = runParallel(ctx, 1, 100, func(job int) {
+		jobsRun++
+		if job == 10 {
+			cancel()
+		}
+	})
+	testutils.FatalUnless(t, errors.Is(err, context.Canceled), "runParallel did not report cancellation. Error was %v", err)
+	testutils.FatalUnless(t, jobsRun < 100, "runParallel did not stop after cancellation")
+}
+
+// TestMultiExponentiateParallelInvalidInput checks that MultiExponentiateParallel panics on invalid inputs and handles NaPs.
+func TestMultiExponentiateParallelInvalidInput(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	points, scalars := makeMultiExponentiationSample(100, drng)
+	testutils.FatalUnless(t, testutils.CheckPanic(MultiExponentiateParallel, context.Background(), AsCurvePointSlice(points), scalars[1:], 2), "MultiExponentiateParallel did not panic on length mismatch")
+	points[50] = Point_xtw_subgroup{}
+	var result Point_efgh_subgroup
+	napDetected := wasInvalidPointEncountered(func() {
+		result, _ = MultiExponentiateParallel(context.Background(), AsCurvePointSlice(points), scalars, 2)
+	})
+	testutils.FatalUnless(t, napDetected, "MultiExponentiateParallel did not report NaP")
+	testutils.FatalUnless(t, result.IsNaP(), "MultiExponentiateParallel did not return NaP for NaP input")
+}
+
+func BenchmarkMultiExponentiateParallel(b *testing.B) {
+	for _, n := range []int{256, 4096} {
+		b.Run("Parallel-"+strconv.Itoa(n), func(b *testing.B) {
+			benchmarkMultiExponentiate(b, n, func(points CurvePointSlice, scalars []Exponent) Point_efgh_subgroup {
+				ret, _ := MultiExponentiateParallel(context.Background(), points, scalars, 0)
+				return ret
+			})
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package curvePoints
+
+import (
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// makeMultiExponentiationSample creates n random points in the subgroup and n scalars for testing multi-exponentiation.
+// The scalars are a mix of special and random values.
+func makeMultiExponentiationSample(n int, drng *rand.Rand) (points []Point_xtw_subgroup, scalars []Exponent) {
+	points = make([]Point_xtw_subgroup, n)
+	scalars = make([]Exponent, n)
+	for i := 0; i < n; i++ {
+		points[i] = MakeRandomPointUnsafe_xtw_subgroup(drng)
+		switch drng.Intn(8) {
+		case 0:
+			scalars[i].SetZero()
+		case 1:
+			scalars[i].SetOne()
+		case 2:
+			scalars[i].SetInt(-1)
+		case 3:
+			scalars[i].SetBigInt(getTestExponent(drng.Intn(400), drng))
+		default:
+			scalars[i].SetBigInt(new(big.Int).Rand(drng, common.CurveExponent_Int))
+		}
+	}
+	// repeat some point to ensure we hit the case where the same points ends up in a bucket multiple times.
+	if n >= 3 {
+		points[n-1] = points[0]
+		scalars[n-1] = scalars[0]
+	}
+	return
+}
+
+// multiExponentiate_naive computes sum_i scalars[i] * points[i] by exponentiating each point individually.
+func multiExponentiate_naive(points []Point_xtw_subgroup, scalars []Exponent) (ret Point_efgh_subgroup) {
+	ret.SetNeutral()
+	for i := range points {
+		var temp Point_xtw_subgroup
+		temp.Exponentiate(&points[i], &scalars[i])
+		ret.AddEq(&temp)
+	}
+	return
+}
+
+// TestMultiExponentiate compares MultiExponentiate and the individual algorithms against the naive approach.
+func TestMultiExponentiate(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	for _, n := range []int{0, 1, 2, 3, 4, 5, 6, 10, 17, 50, 256, 600} {
+		points, scalars := makeMultiExponentiationSample(n, drng)
+		expected := multiExponentiate_naive(points, scalars)
+
+		result := MultiExponentiate(AsCurvePointSlice(points), scalars)
+		testutils.FatalUnless(t, result.IsEqual(&expected), "MultiExponentiate differs from naive result for %v points", n)
+		if n <= 50 {
+			result = multiExponentiate_Straus(AsCurvePointSlice(points), scalars)
+			testutils.FatalUnless(t, result.IsEqual(&expected), "Straus multi-exponentiation differs from naive result for %v points", n)
+		}
+		result = multiExponentiate_Pippenger(AsCurvePointSlice(points), scalars)
+		testutils.FatalUnless(t, result.IsEqual(&expected), "Pippenger multi-exponentiation differs from naive result for %v points", n)
+	}
+}
+
+// TestMultiExponentiatePointTypes checks that MultiExponentiate works with slices of all subgroup point types and with generic slices.
+func TestMultiExponentiatePointTypes(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	for _, n := range []int{3, 30} {
+		points, scalars := makeMultiExponentiationSample(n, drng)
+		expected := multiExponentiate_naive(points, scalars)
+
+		points_axtw := make([]Point_axtw_subgroup, n)
+		points_efgh := make([]Point_efgh_subgroup, n)
+		points_generic := make(GenericPointSlice, n)
+		for i := 0; i < n; i++ {
+			points_axtw[i].SetFrom(&points[i])
+			points_efgh[i].SetFrom(&points[i])
+			switch i % 3 {
+			case 0:
+				points_generic[i] = &points[i]
+			case 1:
+				points_generic[i] = &points_axtw[i]
+			case 2:
+				points_generic[i] = &points_efgh[i]
+			}
+		}
+		for _, slice := range []CurvePointSlice{AsCurvePointSlice(points_axtw), AsCurvePointSlice(points_efgh), points_generic} {
+			result := MultiExponentiate(slice, scalars)
+			testutils.FatalUnless(t, result.IsEqual(&expected), "MultiExponentiate gives wrong result for slice of type %T", slice)
+		}
+	}
+}
+
+// TestMultiExponentiateInvalidInput checks that MultiExponentiate panics on invalid inputs and handles NaPs.
+func TestMultiExponentiateInvalidInput(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	points, scalars := makeMultiExponentiationSample(10, drng)
+	testutils.FatalUnless(t, testutils.CheckPanic(MultiExponentiate, AsCurvePointSlice(points), scalars[1:]), "MultiExponentiate did not panic on length mismatch")
+
+	points_full := make([]Point_xtw_full, 10)
+	for i := range points_full {
+		points_full[i].SetFrom(&points[i])
+	}
+	testutils.FatalUnless(t, testutils.CheckPanic(MultiExponentiate, AsCurvePointSlice(points_full), scalars), "MultiExponentiate did not panic on points of full type")
+
+	points[5] = Point_xtw_subgroup{}
+	var result Point_efgh_subgroup
+	napDetected := wasInvalidPointEncountered(func() { result = MultiExponentiate(AsCurvePointSlice(points), scalars) })
+	testutils.FatalUnless(t, napDetected, "MultiExponentiate did not report NaP")
+	testutils.FatalUnless(t, result.IsNaP(), "MultiExponentiate did not return NaP for NaP input")
+}
+
+// TestPippengerWindowSize checks that pippengerWindowSize gives sane values.
+func TestPippengerWindowSize(t *testing.T) {
+	var last uint = 0
+	for numPoints := 1; numPoints < 1<<22; numPoints *= 2 {
+		c := pippengerWindowSize(numPoints)
+		testutils.FatalUnless(t, c >= 1 && c <= pippengerMaxWindowSize, "pippengerWindowSize out of range")
+		testutils.FatalUnless(t, c >= last, "pippengerWindowSize not monotonous")
+		last = c
+	}
+}
+
+func benchmarkMultiExponentiate(b *testing.B, n int, multiExp func(CurvePointSlice, []Exponent) Point_efgh_subgroup) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	points := make([]Point_axtw_subgroup, n)
+	scalars := make([]Exponent, n)
+	for i := 0; i < n; i++ {
+		point := MakeRandomPointUnsafe_xtw_subgroup(drng)
+		points[i].SetFrom(&point)
+		scalars[i].SetBigInt(new(big.Int).Rand(drng, common.CurveExponent_Int))
+	}
+	slice := AsCurvePointSlice(points)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = multiExp(slice, scalars)
+	}
+}
+
+func BenchmarkMultiExponentiate(b *testing.B) {
+	for _, n := range []int{2, 4, 8, 16, 256, 4096} {
+		b.Run("Straus-"+strconv.Itoa(n), func(b *testing.B) { benchmarkMultiExponentiate(b, n, multiExponentiate_Straus) })
+		b.Run("Pippenger-"+strconv.Itoa(n), func(b *testing.B) { benchmarkMultiExponentiate(b, n, multiExponentiate_Pippenger) })
+	}
+}
@@ -0,0 +1,49 @@
+package curvePoints
+
+import "fmt"
+
+// This file contains multi-multi-exponentiation, i.e. computing several linear combinations sum_i scalars[j][i] * points[i] of the same points.
+//
+// Compared to calling MultiExponentiate for each j, we only perform the work that depends on the points alone once.
+// This is the GLV-expansion of the points, the conversion to affine coordinates and (for Straus' algorithm) the tables of small multiples.
+// By itself, this is only a small part of the cost of a multi-exponentiation. For Straus' algorithm, we therefore also use a larger window size
+// (and hence larger tables) the more rows share the tables, see strausMultiWindowSize. This reduces the number of additions in each row.
+// For the same reason, Straus' algorithm remains preferable to Pippenger's for more points than in MultiExponentiate.
+// For Pippenger's algorithm, the buckets depend on the scalars, so apart from the point preprocessing, nothing is shared.
+
+// MultiMultiExponentiate computes ret[j] = sum_i scalars[j][i] * points[i] for each j. The result is the same as calling MultiExponentiate(points, scalars[j]) for each j.
+//
+// Each scalars[j] must have the same length as points, else we panic. All points must be of a type that can only represent subgroup elements.
+// If any point is a NaP, all results are NaPs.
+//
+// NOTE: This function is not constant-time.
+func MultiMultiExponentiate(points CurvePointSlice, scalars [][]Exponent) (ret []Point_efgh_subgroup) {
+	n := points.Len()
+	ret = make([]Point_efgh_subgroup, len(scalars))
+	for j := range scalars {
+		if len(scalars[j]) != n {
+			panic(fmt.Errorf(ErrorPrefix+"MultiMultiExponentiate called with %v points, but %v scalars in row %v", n, len(scalars[j]), j))
+		}
+	}
+	if !checkMultiExponentiationPoints(points, "MultiMultiExponentiate") {
+		// ret consists of zero-initialized Point_efgh_subgroup's, which are NaPs.
+		return
+	}
+	k := len(scalars)
+	w := strausMultiWindowSize(k)
+	c := pippengerWindowSize(2 * n)
+	// For a single row, we use the same (empirically determined) threshold as MultiExponentiate.
+	if n <= multiExponentiateStrausThreshold || (k > 1 && strausCost(2*n, k, w) <= k*pippengerCost(2*n, c)) {
+		tables := strausPrecompute(points, w)
+		for j := range scalars {
+			ret[j] = strausEvaluate(tables, scalars[j], w)
+		}
+		return
+	}
+	expanded := pippengerPrecompute(points)
+	scratch := newPippengerScratchSpace(c)
+	for j := range scalars {
+		ret[j] = pippengerEvaluate(expanded, scalars[j], c, scratch)
+	}
+	return
+}
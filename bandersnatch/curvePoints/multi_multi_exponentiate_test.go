@@ -0,0 +1,91 @@
+package curvePoints
+
+import (
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// TestMultiMultiExponentiate compares MultiMultiExponentiate against naive computation of each linear combination.
+func TestMultiMultiExponentiate(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	for _, n := range []int{0, 1, 5, 12, 13, 100} {
+		for _, k := range []int{0, 1, 4, 40} { // 4 and 40 rows give larger Straus windows
+			points, _ := makeMultiExponentiationSample(n, drng)
+			scalars := make([][]Exponent, k)
+			for j := 0; j < k; j++ {
+				_, scalars[j] = makeMultiExponentiationSample(n, drng)
+			}
+			results := MultiMultiExponentiate(AsCurvePointSlice(points), scalars)
+			testutils.FatalUnless(t, len(results) == k, "MultiMultiExponentiate returned wrong number of results")
+			for j := 0; j < k; j++ {
+				expected := multiExponentiate_naive(points, scalars[j])
+				testutils.FatalUnless(t, results[j].IsEqual(&expected), "MultiMultiExponentiate differs from naive result for %v points, row %v", n, j)
+			}
+		}
+	}
+}
+
+func TestStrausMultiWindowSize(t *testing.T) {
+	testutils.FatalUnless(t, strausMultiWindowSize(1) == strausWindowSize, "strausMultiWindowSize is inconsistent with strausWindowSize")
+	for k := 1; k < 100; k++ {
+		testutils.FatalUnless(t, strausMultiWindowSize(k) <= strausMultiWindowSize(k+1), "strausMultiWindowSize is not monotone")
+	}
+}
+
+// TestMultiMultiExponentiateInvalidInput checks that MultiMultiExponentiate panics on invalid inputs and handles NaPs.
+func TestMultiMultiExponentiateInvalidInput(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1024))
+	points, scalars0 := makeMultiExponentiationSample(10, drng)
+	_, scalars1 := makeMultiExponentiationSample(10, drng)
+	testutils.FatalUnless(t, testutils.CheckPanic(MultiMultiExponentiate, AsCurvePointSlice(points), [][]Exponent{scalars0, scalars1[1:]}), "MultiMultiExponentiate did not panic on length mismatch")
+
+	points_full := make([]Point_xtw_full, 10)
+	for i := range points_full {
+		points_full[i].SetFrom(&points[i])
+	}
+	testutils.FatalUnless(t, testutils.CheckPanic(MultiMultiExponentiate, AsCurvePointSlice(points_full), [][]Exponent{scalars0}), "MultiMultiExponentiate did not panic on points of full type")
+
+	points[5] = Point_xtw_subgroup{}
+	var results []Point_efgh_subgroup
+	napDetected := wasInvalidPointEncountered(func() { results = MultiMultiExponentiate(AsCurvePointSlice(points), [][]Exponent{scalars0, scalars1}) })
+	testutils.FatalUnless(t, napDetected, "MultiMultiExponentiate did not report NaP")
+	testutils.FatalUnless(t, len(results) == 2, "MultiMultiExponentiate returned wrong number of results")
+	testutils.FatalUnless(t, results[0].IsNaP() && results[1].IsNaP(), "MultiMultiExponentiate did not return NaP for NaP input")
+}
+
+func BenchmarkMultiMultiExponentiate(b *testing.B) {
+	const k = 8
+	for _, n := range []int{4, 16, 64, 256} {
+		var drng *rand.Rand = rand.New(rand.NewSource(1024))
+		points := make([]Point_axtw_subgroup, n)
+		for i := 0; i < n; i++ {
+			point := MakeRandomPointUnsafe_xtw_subgroup(drng)
+			points[i].SetFrom(&point)
+		}
+		scalars := make([][]Exponent, k)
+		for j := 0; j < k; j++ {
+			scalars[j] = make([]Exponent, n)
+			for i := 0; i < n; i++ {
+				scalars[j][i].SetBigInt(new(big.Int).Rand(drng, common.CurveExponent_Int))
+			}
+		}
+		slice := AsCurvePointSlice(points)
+		b.Run("Shared-"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = MultiMultiExponentiate(slice, scalars)
+			}
+		})
+		b.Run("Independent-"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < k; j++ {
+					_ = MultiExponentiate(slice, scalars[j])
+				}
+			}
+		})
+	}
+}
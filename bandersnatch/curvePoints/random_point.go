@@ -0,0 +1,46 @@
+package curvePoints
+
+import (
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+)
+
+// This file contains cryptographically secure sampling of random curve points.
+// By contrast, the sampleRandomUnsafe methods and MakeRandomPointUnsafe_* functions are only meant for testing.
+
+// RandomSubgroupPoint samples a uniformly random point in the prime-order subgroup, using randomness read from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// We sample a uniform affine x-coordinate (rejecting those that do not correspond to a rational point) and a random sign for y and then double the resulting point.
+// As every element of the subgroup except the neutral element has exactly 4 affine preimages under doubling, the output distribution is
+// statistically close to uniform (the dominant error is the statistical distance < 2^-128 from sampling x).
+//
+// If reading from rnd fails, we return an error (wrapping the error from rnd); the returned point must not be used in that case.
+//
+// NOTE: This function is not constant-time (the number of rejections depends on the random choices). This does not leak information about the output.
+func RandomSubgroupPoint(rnd io.Reader) (ret Point_xtw_subgroup, err error) {
+	var x, y FieldElement
+	for {
+		if err = x.SetRandom(rnd); err != nil {
+			return
+		}
+		var errRecover error
+		y, errRecover = recoverYFromXAffine(&x, false)
+		if errRecover == nil {
+			break
+		}
+	}
+	var signBuf [1]byte
+	if err = common.ReadRandomBytes(rnd, signBuf[:]); err != nil {
+		return
+	}
+	if signBuf[0]&1 == 1 {
+		y.NegEq()
+	}
+	ret.x = x
+	ret.y = y
+	ret.t.Mul(&x, &y)
+	ret.z.SetOne()
+	ret.DoubleEq()
+	return
+}
@@ -0,0 +1,53 @@
+package curvePoints
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/fieldElements"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func TestRandomSubgroupPoint(t *testing.T) {
+	p, err := RandomSubgroupPoint(nil)
+	testutils.FatalUnless(t, err == nil, "RandomSubgroupPoint(nil) failed: %v", err)
+	q, err := RandomSubgroupPoint(nil)
+	testutils.FatalUnless(t, err == nil, "RandomSubgroupPoint(nil) failed: %v", err)
+	testutils.FatalUnless(t, !p.IsEqual(&q), "RandomSubgroupPoint(nil) gave identical outputs")
+
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		p, err = RandomSubgroupPoint(drng)
+		testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+		testutils.FatalUnless(t, p.Validate(), "RandomSubgroupPoint gave invalid point")
+		var pFull Point_xtw_full
+		pFull.SetFrom(&p)
+		testutils.FatalUnless(t, pFull.IsInSubgroup(), "RandomSubgroupPoint gave point outside subgroup")
+	}
+
+	designatedErr := errors.New("designated error")
+	_, err = RandomSubgroupPoint(iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "RandomSubgroupPoint did not report error")
+}
+
+// TestRandomSubgroupPointUniformity performs chi-squared tests on small projections of the output of RandomSubgroupPoint.
+func TestRandomSubgroupPointUniformity(t *testing.T) {
+	const numBuckets = 16
+	const numSamples = 4000
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	var xBits, yBits [numBuckets]int
+	var xInt, yInt fieldElements.Uint256
+	for i := 0; i < numSamples; i++ {
+		p, err := RandomSubgroupPoint(drng)
+		testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+		x, y := p.XY_affine()
+		x.ToUint256(&xInt)
+		y.ToUint256(&yInt)
+		xBits[xInt[0]%numBuckets]++
+		yBits[yInt[0]%numBuckets]++
+	}
+	testutils.FatalUnless(t, testutils.ChiSquaredIsUniform(xBits[:]), "x-coordinates of RandomSubgroupPoint fail chi-squared test: %v", xBits)
+	testutils.FatalUnless(t, testutils.ChiSquaredIsUniform(yBits[:]), "y-coordinates of RandomSubgroupPoint fail chi-squared test: %v", yBits)
+}
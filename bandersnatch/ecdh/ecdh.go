@@ -0,0 +1,167 @@
+package ecdh
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+)
+
+// This file contains Elliptic-Curve Diffie-Hellman key agreement on the prime-order subgroup of Bandersnatch.
+// The API mirrors Go's crypto/ecdh (restricted to a single curve).
+//
+// A private key is a non-zero exponent x modulo p253, the public key is X = x*B for the generator B = curvePoints.SubgroupGenerator_xtw_subgroup.
+// The shared secret of a private key x and a remote public key Y is the encoding of x*Y.
+// Public keys and shared secrets use the Banderwagon encoding pointserializer.BanderwagonShort, private keys are encoded as 32-byte numbers with the same endianness.
+//
+// Public keys are always in the prime-order subgroup and never the neutral element. This is enforced by decoding (the Banderwagon encoding can only represent subgroup elements) and
+// by NewPublicKeyFromPoint, which performs a subgroup check for points of types that can represent arbitrary curve points (e.g. obtained via deserializers for _full point types).
+// Consequently, small-subgroup attacks are not possible and the shared secret is never the neutral element.
+// Scalar multiplications involving the private key are done in constant time.
+//
+// NOTE: As with crypto/ecdh, the shared secret is not uniformly random and should be passed through a KDF (such as DeriveKey) before use.
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / ecdh: "
+
+const (
+	PublicKeySize    = keypair.PointSize  // size in bytes of encoded public keys
+	PrivateKeySize   = keypair.ScalarSize // size in bytes of encoded private keys
+	SharedSecretSize = keypair.PointSize  // size in bytes of shared secrets returned by PrivateKey.ECDH
+)
+
+var (
+	ErrInvalidPrivateKey = errors.New(ErrorPrefix + "invalid private key")
+	ErrInvalidPublicKey  = errors.New(ErrorPrefix + "invalid public key")
+)
+
+// PrivateKey is an ECDH private key. The zero value is not a valid private key; use GenerateKey or NewPrivateKey to create one.
+type PrivateKey struct {
+	key keypair.PrivateKey // private exponent x and public key x*B
+}
+
+// PublicKey is an ECDH public key. The zero value is not a valid public key; use NewPublicKey, NewPublicKeyFromPoint or PrivateKey.PublicKey to obtain one.
+type PublicKey struct {
+	key keypair.PublicKey // X and its encoding
+}
+
+// GenerateKey generates a new private key, reading randomness from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// We return an error if reading from rnd fails.
+func GenerateKey(rnd io.Reader) (*PrivateKey, error) {
+	key, err := keypair.GenerateKey(rnd)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{key: key}, nil
+}
+
+// NewPrivateKey decodes a private key in the format written by PrivateKey.Bytes.
+//
+// We return an error wrapping ErrInvalidPrivateKey if key has the wrong length, encodes a number >= p253 or encodes 0.
+func NewPrivateKey(key []byte) (*PrivateKey, error) {
+	decoded, err := keypair.PrivateKeyFromBytes(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+	return &PrivateKey{key: decoded}, nil
+}
+
+// Bytes returns the encoding of the private key. Note that the result is secret.
+func (sk *PrivateKey) Bytes() []byte {
+	return sk.key.Bytes()
+}
+
+// PublicKey returns the public key corresponding to sk.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{key: *sk.key.PublicKey()}
+}
+
+// Public implements the implicit interface of all standard library private keys. It is equivalent to PublicKey.
+func (sk *PrivateKey) Public() crypto.PublicKey {
+	return sk.PublicKey()
+}
+
+// Equal checks whether sk and x are the same private key. It returns false if x is not a *PrivateKey.
+//
+// The comparison takes constant time.
+func (sk *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(*PrivateKey)
+	if !ok {
+		return false
+	}
+	a, b := sk.key.Bytes(), other.key.Bytes()
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+// ECDH performs the key agreement with the remote public key and returns the shared secret (the encoding of x*Y for the private exponent x and the remote point Y).
+//
+// Since public keys are guaranteed to be valid, this can only fail if remote is not a valid public key (e.g. the zero value), in which case we return an error wrapping ErrInvalidPublicKey.
+// The result is the same for sk.ECDH(remote) and remoteSk.ECDH(sk.PublicKey()).
+func (sk *PrivateKey) ECDH(remote *PublicKey) ([]byte, error) {
+	if remote.key.Point().IsNaP() {
+		return nil, fmt.Errorf("%w: remote public key is not initialized", ErrInvalidPublicKey)
+	}
+	var shared curvePoints.Point_xtw_subgroup
+	shared.ExponentiateConstantTime(remote.key.Point(), sk.key.Scalar())
+	if shared.IsNeutralElement() {
+		// not supposed to be reachable, since x != 0 and remote is in the prime-order subgroup and not the neutral element.
+		return nil, fmt.Errorf("%w: shared secret is the neutral element", ErrInvalidPublicKey)
+	}
+	ret := keypair.EncodePoint(&shared)
+	return ret[:], nil
+}
+
+// NewPublicKey decodes a public key in the format written by PublicKey.Bytes.
+//
+// We return an error wrapping ErrInvalidPublicKey if key is not a valid encoding of a point in the prime-order subgroup or encodes the neutral element.
+func NewPublicKey(key []byte) (*PublicKey, error) {
+	decoded, err := keypair.PublicKeyFromBytes(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return &PublicKey{key: decoded}, nil
+}
+
+// NewPublicKeyFromPoint creates a public key from the given curve point, which may have any point type.
+//
+// We return an error wrapping ErrInvalidPublicKey if p is a NaP, not in the prime-order subgroup (this includes all points of small order) or the neutral element.
+// This check is needed for points of _full type, e.g. obtained from deserializers that are not restricted to the subgroup.
+func NewPublicKeyFromPoint(p curvePoints.CurvePointPtrInterfaceRead) (*PublicKey, error) {
+	if p.IsNaP() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, keypair.ErrNaP)
+	}
+	var point curvePoints.Point_xtw_subgroup
+	if !point.SetFromSubgroupPoint(p, common.UntrustedInput) {
+		return nil, fmt.Errorf("%w: public key is not in the prime-order subgroup", ErrInvalidPublicKey)
+	}
+	key, err := keypair.PublicKeyFromPoint(&point)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return &PublicKey{key: key}, nil
+}
+
+// Bytes returns the encoding of the public key.
+func (pk *PublicKey) Bytes() []byte {
+	return pk.key.Bytes()
+}
+
+// Point returns the public key as a curve point.
+func (pk *PublicKey) Point() curvePoints.Point_xtw_subgroup {
+	return *pk.key.Point()
+}
+
+// Equal checks whether pk and x are the same public key. It returns false if x is not a *PublicKey.
+func (pk *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	return pk.key.Equal(&other.key)
+}
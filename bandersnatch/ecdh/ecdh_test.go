@@ -0,0 +1,173 @@
+package ecdh
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/pointserializer"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func generateKeyForTest(rnd *rand.Rand) *PrivateKey {
+	sk, err := GenerateKey(rnd)
+	if err != nil {
+		panic(err)
+	}
+	return sk
+}
+
+func TestKeyAgreement(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		alice, bob, eve := generateKeyForTest(rnd), generateKeyForTest(rnd), generateKeyForTest(rnd)
+		secretAlice, err := alice.ECDH(bob.PublicKey())
+		testutils.FatalUnless(t, err == nil, "ECDH failed: %v", err)
+		secretBob, err := bob.ECDH(alice.PublicKey())
+		testutils.FatalUnless(t, err == nil, "ECDH failed: %v", err)
+		testutils.FatalUnless(t, len(secretAlice) == SharedSecretSize, "shared secret has wrong length")
+		testutils.FatalUnless(t, bytes.Equal(secretAlice, secretBob), "shared secrets differ")
+		secretEve, _ := eve.ECDH(bob.PublicKey())
+		testutils.FatalUnless(t, !bytes.Equal(secretAlice, secretEve), "shared secret does not depend on private key")
+
+		// the shared secret is the encoding of x*Y
+		var expected curvePoints.Point_xtw_subgroup
+		bobPoint := bob.PublicKey().Point()
+		expected.Exponentiate(&bobPoint, alice.key.Scalar())
+		expectedEncoding := keypair.EncodePoint(&expected)
+		testutils.FatalUnless(t, bytes.Equal(secretAlice, expectedEncoding[:]), "shared secret is not the encoding of x*Y")
+
+		keyAlice, err := alice.DeriveSharedKey(bob.PublicKey(), nil, []byte("info"), 48)
+		testutils.FatalUnless(t, err == nil, "DeriveSharedKey failed: %v", err)
+		keyBob, err := bob.DeriveSharedKey(alice.PublicKey(), nil, []byte("info"), 48)
+		testutils.FatalUnless(t, err == nil, "DeriveSharedKey failed: %v", err)
+		testutils.FatalUnless(t, len(keyAlice) == 48 && bytes.Equal(keyAlice, keyBob), "derived shared keys differ")
+		plainKey, _ := DeriveKey(secretAlice, nil, []byte("info"), 48)
+		testutils.FatalUnless(t, !bytes.Equal(keyAlice, plainKey), "DeriveSharedKey does not bind public keys")
+		otherInfo, _ := alice.DeriveSharedKey(bob.PublicKey(), nil, []byte("other"), 48)
+		testutils.FatalUnless(t, !bytes.Equal(keyAlice, otherInfo), "DeriveSharedKey ignores info")
+	}
+}
+
+func TestPrivateKeyEncoding(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		sk := generateKeyForTest(rnd)
+		encoded := sk.Bytes()
+		testutils.FatalUnless(t, len(encoded) == PrivateKeySize, "private key has wrong length")
+		decoded, err := NewPrivateKey(encoded)
+		testutils.FatalUnless(t, err == nil, "could not decode private key: %v", err)
+		testutils.FatalUnless(t, decoded.Equal(sk) && sk.Equal(decoded), "private key roundtrip failed")
+		testutils.FatalUnless(t, decoded.PublicKey().Equal(sk.PublicKey()), "public key of decoded private key differs")
+		testutils.FatalUnless(t, !sk.Equal(generateKeyForTest(rnd)), "different private keys are equal")
+		var foreign crypto.PrivateKey = encoded
+		testutils.FatalUnless(t, !sk.Equal(foreign), "private key equal to foreign type")
+	}
+
+	_, err := NewPrivateKey(make([]byte, PrivateKeySize))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "zero private key accepted")
+	_, err = NewPrivateKey(make([]byte, PrivateKeySize-1))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "private key of wrong length accepted")
+	_, err = NewPrivateKey(bytes.Repeat([]byte{0xff}, PrivateKeySize))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "non-reduced private key accepted")
+}
+
+func TestPublicKeyEncoding(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		sk := generateKeyForTest(rnd)
+		pk := sk.PublicKey()
+		testutils.FatalUnless(t, sk.Public().(*PublicKey).Equal(pk), "Public and PublicKey differ")
+		encoded := pk.Bytes()
+		testutils.FatalUnless(t, len(encoded) == PublicKeySize, "public key has wrong length")
+		decoded, err := NewPublicKey(encoded)
+		testutils.FatalUnless(t, err == nil, "could not decode public key: %v", err)
+		testutils.FatalUnless(t, decoded.Equal(pk), "public key roundtrip failed")
+		point := decoded.Point()
+		expected := pk.Point()
+		testutils.FatalUnless(t, point.IsEqual(&expected), "decoded public key has wrong point")
+
+		// modifying the output of Bytes must not modify the key
+		encoded[0] ^= 1
+		testutils.FatalUnless(t, !bytes.Equal(encoded, pk.Bytes()), "Bytes returned internal buffer")
+		var foreign crypto.PublicKey = encoded
+		testutils.FatalUnless(t, !pk.Equal(foreign), "public key equal to foreign type")
+	}
+
+	_, err := NewPublicKey(make([]byte, PublicKeySize+1))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "public key of wrong length accepted")
+	// neutral element
+	neutral := keypair.EncodePoint(&curvePoints.NeutralElement_xtw_subgroup)
+	_, err = NewPublicKey(neutral[:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "neutral element accepted as public key")
+	// invalid encodings
+	_, err = NewPublicKey(bytes.Repeat([]byte{0xff}, PublicKeySize))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "invalid encoding accepted as public key")
+}
+
+func TestNewPublicKeyFromPoint(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var A, neutral curvePoints.Point_xtw_full
+	A.SetFrom(&curvePoints.AffineOrderTwoPoint_xtw)
+	neutral.SetFrom(&curvePoints.NeutralElement_xtw_full)
+	for _, p := range []*curvePoints.Point_xtw_full{&A, &neutral, {}} {
+		_, err := NewPublicKeyFromPoint(p)
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "invalid point accepted as public key")
+	}
+
+	for i := 0; i < 20; i++ {
+		sk := generateKeyForTest(rnd)
+		expected := sk.PublicKey().Point()
+
+		// P and P+A, obtained via a deserializer for _full points, which accepts points outside the subgroup.
+		var p, pPlusA curvePoints.Point_xtw_full
+		p.SetFrom(&expected)
+		pPlusA.Add(&p, &A)
+		var buf, bufPlusA bytes.Buffer
+		_, err := pointserializer.EdwardsCompressed.SerializeCurvePoint(&buf, &p)
+		testutils.FatalUnless(t, err == nil, "serialization failed: %v", err)
+		_, err = pointserializer.EdwardsCompressed.SerializeCurvePoint(&bufPlusA, &pPlusA)
+		testutils.FatalUnless(t, err == nil, "serialization failed: %v", err)
+		var decoded, decodedPlusA curvePoints.Point_xtw_full
+		_, errDeserialize := pointserializer.EdwardsCompressed.DeserializeCurvePoint(&buf, common.UntrustedInput, &decoded)
+		testutils.FatalUnless(t, errDeserialize == nil, "deserialization failed: %v", errDeserialize)
+		_, errDeserialize = pointserializer.EdwardsCompressed.DeserializeCurvePoint(&bufPlusA, common.UntrustedInput, &decodedPlusA)
+		testutils.FatalUnless(t, errDeserialize == nil, "deserialization failed: %v", errDeserialize)
+
+		pk, errKey := NewPublicKeyFromPoint(&decoded)
+		testutils.FatalUnless(t, errKey == nil, "valid point rejected: %v", errKey)
+		testutils.FatalUnless(t, pk.Equal(sk.PublicKey()), "NewPublicKeyFromPoint gave wrong key")
+		_, errKey = NewPublicKeyFromPoint(&decodedPlusA)
+		testutils.FatalUnless(t, errors.Is(errKey, ErrInvalidPublicKey), "point outside the subgroup accepted as public key")
+	}
+}
+
+func TestECDHInvalidInputs(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	sk := generateKeyForTest(rnd)
+	_, err := sk.ECDH(&PublicKey{})
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "zero value public key accepted")
+	_, err = sk.DeriveSharedKey(&PublicKey{}, nil, nil, 32)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "zero value public key accepted")
+	_, err = sk.DeriveSharedKey(sk.PublicKey(), nil, nil, 0)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidKeyLength), "invalid key length accepted")
+
+	designatedErr := errors.New("designated error")
+	_, err = GenerateKey(iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "GenerateKey did not report randomness failure")
+}
+
+func BenchmarkECDH(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	sk, remote := generateKeyForTest(rnd), generateKeyForTest(rnd).PublicKey()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sk.ECDH(remote)
+	}
+}
@@ -0,0 +1,84 @@
+package ecdh
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// This file contains key derivation from ECDH shared secrets via HKDF (RFC 5869) with SHA-512.
+//
+// DeriveKey is plain HKDF-SHA512. PrivateKey.DeriveSharedKey additionally binds the derived key to both public keys and a fixed label, by using
+//
+//	info' = SharedKeyLabel || pk_1 || pk_2 || info
+//
+// as HKDF info, where pk_1, pk_2 are the encodings of both parties' public keys in lexicographic order (so both parties derive the same key).
+
+// SharedKeyLabel is the label prepended to the HKDF info by PrivateKey.DeriveSharedKey.
+const SharedKeyLabel = "BANDERSNATCH-ECDH-V01"
+
+// MaxDerivedKeyLength is the maximal length of keys output by HKDF-SHA512.
+const MaxDerivedKeyLength = 255 * sha512.Size
+
+var ErrInvalidKeyLength = errors.New(ErrorPrefix + "invalid length of derived key")
+
+// hkdfExtract computes HKDF-Extract(salt, ikm) = HMAC(salt, ikm) with the given hash function. An empty salt means a string of zeros of length equal to the hash output.
+func hkdfExtract(newHash func() hash.Hash, salt, ikm []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, newHash().Size())
+	}
+	mac := hmac.New(newHash, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// hkdfExpand computes HKDF-Expand(prk, info, length) with the given hash function. length must be at most 255 times the hash output length.
+func hkdfExpand(newHash func() hash.Hash, prk, info []byte, length int) []byte {
+	mac := hmac.New(newHash, prk)
+	ret := make([]byte, 0, length+mac.Size())
+	var previous []byte
+	for counter := byte(1); len(ret) < length; counter++ {
+		mac.Reset()
+		mac.Write(previous)
+		mac.Write(info)
+		mac.Write([]byte{counter})
+		previous = mac.Sum(nil)
+		ret = append(ret, previous...)
+	}
+	return ret[:length]
+}
+
+// DeriveKey derives a key of the given length from secret via HKDF-SHA512 with the given salt and info, both of which may be empty.
+//
+// We return an error wrapping ErrInvalidKeyLength unless 0 < length <= MaxDerivedKeyLength.
+func DeriveKey(secret, salt, info []byte, length int) ([]byte, error) {
+	if length <= 0 || length > MaxDerivedKeyLength {
+		return nil, fmt.Errorf("%w: requested %v bytes, must be between 1 and %v", ErrInvalidKeyLength, length, MaxDerivedKeyLength)
+	}
+	prk := hkdfExtract(sha512.New, salt, secret)
+	return hkdfExpand(sha512.New, prk, info, length), nil
+}
+
+// DeriveSharedKey performs the key agreement with remote and derives a key of the given length from the shared secret, bound to both public keys.
+// See the documentation at the top of this file for the exact construction. Both parties obtain the same key.
+//
+// We return an error wrapping ErrInvalidPublicKey if remote is not a valid public key and an error wrapping ErrInvalidKeyLength unless 0 < length <= MaxDerivedKeyLength.
+func (sk *PrivateKey) DeriveSharedKey(remote *PublicKey, salt, info []byte, length int) ([]byte, error) {
+	secret, err := sk.ECDH(remote)
+	if err != nil {
+		return nil, err
+	}
+	pk1, pk2 := sk.key.PublicKey().Bytes(), remote.key.Bytes()
+	if bytes.Compare(pk1, pk2) > 0 {
+		pk1, pk2 = pk2, pk1
+	}
+	fullInfo := make([]byte, 0, len(SharedKeyLabel)+2*PublicKeySize+len(info))
+	fullInfo = append(fullInfo, SharedKeyLabel...)
+	fullInfo = append(fullInfo, pk1...)
+	fullInfo = append(fullInfo, pk2...)
+	fullInfo = append(fullInfo, info...)
+	return DeriveKey(secret, salt, fullInfo, length)
+}
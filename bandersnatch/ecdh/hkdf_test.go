@@ -0,0 +1,55 @@
+package ecdh
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func mustDecodeHex(s string) []byte {
+	ret, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// RFC 5869, test case 1
+func TestHKDFRFC5869(t *testing.T) {
+	ikm := mustDecodeHex("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt := mustDecodeHex("000102030405060708090a0b0c")
+	info := mustDecodeHex("f0f1f2f3f4f5f6f7f8f9")
+	prk := hkdfExtract(sha256.New, salt, ikm)
+	testutils.FatalUnless(t, hex.EncodeToString(prk) == "077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5", "HKDF-Extract wrong: %x", prk)
+	okm := hkdfExpand(sha256.New, prk, info, 42)
+	testutils.FatalUnless(t, hex.EncodeToString(okm) == "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865", "HKDF-Expand wrong: %x", okm)
+}
+
+func TestDeriveKey(t *testing.T) {
+	ikm := mustDecodeHex("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt := mustDecodeHex("000102030405060708090a0b0c")
+	info := mustDecodeHex("f0f1f2f3f4f5f6f7f8f9")
+	key, err := DeriveKey(ikm, salt, info, 42)
+	testutils.FatalUnless(t, err == nil, "DeriveKey failed: %v", err)
+	testutils.FatalUnless(t, hex.EncodeToString(key) == "832390086cda71fb47625bb5ceb168e4c8e26a1a16ed34d9fc7fe92c1481579338da362cb8d9f925d7cb", "DeriveKey wrong: %x", key)
+	key, err = DeriveKey(ikm, nil, nil, 42)
+	testutils.FatalUnless(t, err == nil, "DeriveKey failed: %v", err)
+	testutils.FatalUnless(t, hex.EncodeToString(key) == "f5fa02b18298a72a8c23898a8703472c6eb179dc204c03425c970e3b164bf90fff22d04836d0e2343bac", "DeriveKey with empty salt wrong: %x", key)
+	prk := hkdfExtract(sha512.New, nil, ikm)
+	testutils.FatalUnless(t, hex.EncodeToString(prk) == "fd200c4987ac491313bd4a2a13287121247239e11c9ef82802044b66ef357e5b194498d0682611382348572a7b1611de54764094286320578a863f36562b0df6", "HKDF-Extract with empty salt wrong: %x", prk)
+
+	// prefixes are consistent and the maximal length is supported
+	long, err := DeriveKey(ikm, salt, info, MaxDerivedKeyLength)
+	testutils.FatalUnless(t, err == nil && len(long) == MaxDerivedKeyLength, "DeriveKey failed for maximal length: %v", err)
+	short, _ := DeriveKey(ikm, salt, info, 42)
+	testutils.FatalUnless(t, string(long[:42]) == string(short), "outputs of different lengths are inconsistent")
+
+	for _, length := range []int{-1, 0, MaxDerivedKeyLength + 1} {
+		_, err = DeriveKey(ikm, salt, info, length)
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidKeyLength), "invalid length %v accepted", length)
+	}
+}
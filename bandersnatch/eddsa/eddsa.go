@@ -0,0 +1,276 @@
+package eddsa
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/pointserializer"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+)
+
+// This file contains EdDSA-style signatures (Bandersnatch-EdDSA) over the Bandersnatch curve.
+//
+// Notation: B is the generator curvePoints.SubgroupGenerator_xtw_subgroup of the prime-order subgroup. A private key is a 32-byte seed,
+// from which we derive a secret exponent s and a secret nonce prefix. The public key is A = s*B.
+// A signature on a message M is a pair (R, S) with
+//
+//	r = H_nonce(prefix || ID || M), R = r*B, c = H_challenge(R, A, M), S = r + c*s (modulo p253).
+//
+// Here, ID identifies the challenge hash (see ChallengeHasher). Verification checks the equation S*B == R + c*A, either exactly (cofactorless) or after multiplying by the cofactor 4 (cofactored), see VerificationMode.
+// Since public keys and R are decoded from the EdwardsCompressed encoding, which can represent points outside the prime-order subgroup,
+// the distinction matters for maliciously chosen keys or signatures; honestly generated keys and signatures verify in either mode.
+//
+// Unlike Ed25519, the challenge hash H_challenge is pluggable (see ChallengeHasher). This allows to use a hash function that is efficient inside SNARK circuits over
+// the base field of Bandersnatch (such as Poseidon), via FieldChallengeHasher. The nonce derivation H_nonce is always done outside the circuit and uses SHA-512.
+//
+// Encodings: Points are encoded via pointserializer.EdwardsCompressed (32 bytes), S is encoded as a 32-byte number in 0 <= . < p253 with the same endianness.
+// A signature is enc(R) || enc(S), i.e. 64 bytes.
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / eddsa: "
+
+const (
+	SeedSize      = 32                // size in bytes of private key seeds
+	PublicKeySize = 32                // size in bytes of encoded public keys
+	SignatureSize = 2 * PublicKeySize // size in bytes of encoded signatures
+)
+
+// Domain separation tags for deriving the secret exponent and nonce prefix from the seed and for deriving nonces.
+const (
+	SecretScalarDST = "BANDERSNATCH-EDDSA-V01-SECRET-SCALAR"
+	NoncePrefixDST  = "BANDERSNATCH-EDDSA-V01-NONCE-PREFIX"
+	NonceDST        = "BANDERSNATCH-EDDSA-V01-NONCE"
+)
+
+// noncePrefixSize is the size in bytes of the secret nonce prefix derived from the seed.
+const noncePrefixSize = 32
+
+var (
+	ErrInvalidSeed              = errors.New(ErrorPrefix + "invalid private key seed")
+	ErrInvalidPublicKey         = errors.New(ErrorPrefix + "invalid public key")
+	ErrInvalidSignatureEncoding = errors.New(ErrorPrefix + "invalid signature encoding")
+)
+
+// pointSerializer is the serializer used for all curve points (public keys and the R part of signatures).
+var pointSerializer = pointserializer.EdwardsCompressed
+
+// exponentEndianness is the endianness used for encoding the S part of signatures. We use the same endianness as pointSerializer.
+var exponentEndianness common.FieldElementEndianness = pointSerializer.GetFieldElementEndianness()
+
+// PrivateKey is an EdDSA private key. Use GenerateKey or NewKeyFromSeed to create one.
+type PrivateKey struct {
+	seed        [SeedSize]byte
+	scalar      exponents.Exponent // secret exponent s, non-zero and fully reduced
+	noncePrefix [noncePrefixSize]byte
+	publicKey   PublicKey
+}
+
+// PublicKey is an EdDSA public key A. Note that A need not be in the prime-order subgroup if it was decoded from untrusted input. Use PublicKeyFromBytes or PrivateKey.PublicKey to obtain one.
+type PublicKey struct {
+	point              curvePoints.Point_xtw_full     // A, never of small order
+	encoded            [PublicKeySize]byte            // cached encoding of A
+	cofactorTimesPoint curvePoints.Point_xtw_subgroup // cached 4*A, which is always in the prime-order subgroup. This is used for cofactored verification
+}
+
+// Signature is an EdDSA signature (R, S). Use SignatureFromBytes to decode one.
+type Signature struct {
+	r              curvePoints.Point_xtw_full
+	encodedR       [PublicKeySize]byte
+	cofactorTimesR curvePoints.Point_xtw_subgroup // cached 4*R
+	s              exponents.Exponent             // fully reduced
+}
+
+// encodePoint returns the EdwardsCompressed encoding of p.
+func encodePoint(p curvePoints.CurvePointPtrInterfaceRead) (ret [PublicKeySize]byte) {
+	var buf bytes.Buffer
+	bytesWritten, err := pointSerializer.SerializeCurvePoint(&buf, p)
+	if err != nil || bytesWritten != PublicKeySize {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when serializing curve point: %w", err))
+	}
+	copy(ret[:], buf.Bytes())
+	return
+}
+
+// decodePoint decodes an EdwardsCompressed-encoded curve point from buf. The result need not be in the prime-order subgroup.
+func decodePoint(buf []byte) (ret curvePoints.Point_xtw_full, err error) {
+	if len(buf) != PublicKeySize {
+		err = fmt.Errorf(ErrorPrefix+"encoded curve point has length %v, expected %v", len(buf), PublicKeySize)
+		return
+	}
+	_, errDeserialize := pointSerializer.DeserializeCurvePoint(bytes.NewReader(buf), common.UntrustedInput, &ret)
+	if errDeserialize != nil {
+		err = errDeserialize
+	}
+	return
+}
+
+// cofactorTimes returns 4*p, which is always in the prime-order subgroup.
+func cofactorTimes(p *curvePoints.Point_xtw_full) (ret curvePoints.Point_xtw_subgroup) {
+	var temp curvePoints.Point_xtw_full = *p
+	temp.DoubleEq()
+	temp.DoubleEq()
+	if !ret.SetFromSubgroupPoint(&temp, common.TrustedInput) {
+		panic(ErrorPrefix + "4*P is not in the prime-order subgroup. This is not supposed to be possible")
+	}
+	return
+}
+
+// GenerateKey generates a new private key, reading the seed from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// We return an error if reading from rnd fails.
+func GenerateKey(rnd io.Reader) (*PrivateKey, error) {
+	var seed [SeedSize]byte
+	if err := common.ReadRandomBytes(rnd, seed[:]); err != nil {
+		return nil, err
+	}
+	return NewKeyFromSeed(seed[:])
+}
+
+// NewKeyFromSeed derives the private key from a 32-byte seed. The seed must be uniformly random and secret.
+//
+// We return an error wrapping ErrInvalidSeed if the seed has the wrong length.
+func NewKeyFromSeed(seed []byte) (*PrivateKey, error) {
+	if len(seed) != SeedSize {
+		return nil, fmt.Errorf("%w: seed has length %v, expected %v", ErrInvalidSeed, len(seed), SeedSize)
+	}
+	var ret PrivateKey
+	copy(ret.seed[:], seed)
+	var err error
+	ret.scalar, err = exponents.HashToExponent(seed, []byte(SecretScalarDST))
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when deriving secret exponent: %w", err))
+	}
+	if ret.scalar.IsZero_Subgroup() {
+		// This happens with probability about 2^-253.
+		return nil, fmt.Errorf("%w: seed gives zero secret exponent", ErrInvalidSeed)
+	}
+	noncePrefix, err := common.ExpandMessageXMD(crypto.SHA512, seed, []byte(NoncePrefixDST), noncePrefixSize)
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when deriving nonce prefix: %w", err))
+	}
+	copy(ret.noncePrefix[:], noncePrefix)
+
+	var a curvePoints.Point_xtw_subgroup
+	a.ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, &ret.scalar)
+	ret.publicKey.point.SetFrom(&a)
+	ret.publicKey.encoded = encodePoint(&ret.publicKey.point)
+	ret.publicKey.cofactorTimesPoint = cofactorTimes(&ret.publicKey.point)
+	return &ret, nil
+}
+
+// Seed returns the seed of the private key. Note that the result is secret.
+func (sk *PrivateKey) Seed() []byte {
+	ret := sk.seed
+	return ret[:]
+}
+
+// PublicKey returns the public key corresponding to sk.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	ret := sk.publicKey
+	return &ret
+}
+
+// Equal checks whether sk and other are the same private key.
+func (sk *PrivateKey) Equal(other *PrivateKey) bool {
+	return sk.seed == other.seed
+}
+
+// PublicKeyFromBytes decodes a public key in the format written by PublicKey.Bytes.
+//
+// The public key need not be in the prime-order subgroup. We return an error wrapping ErrInvalidPublicKey if buf is not a valid encoding of a rational curve point or
+// if the point has small order (i.e. 4*A is the neutral element): For such keys, (cofactored) verification would accept signatures for any message.
+func PublicKeyFromBytes(buf []byte) (*PublicKey, error) {
+	point, err := decodePoint(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	ret := PublicKey{point: point, cofactorTimesPoint: cofactorTimes(&point)}
+	if ret.cofactorTimesPoint.IsNeutralElement() {
+		return nil, fmt.Errorf("%w: public key has small order", ErrInvalidPublicKey)
+	}
+	copy(ret.encoded[:], buf)
+	return &ret, nil
+}
+
+// Bytes returns the encoding of the public key.
+func (pk *PublicKey) Bytes() []byte {
+	ret := pk.encoded
+	return ret[:]
+}
+
+// Point returns the public key as a curve point.
+func (pk *PublicKey) Point() curvePoints.Point_xtw_full {
+	return pk.point
+}
+
+// IsInSubgroup checks whether the public key is in the prime-order subgroup. This is always the case for honestly generated keys.
+func (pk *PublicKey) IsInSubgroup() bool {
+	return pk.point.IsInSubgroup()
+}
+
+// Equal checks whether pk and other are the same public key.
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	return pk.encoded == other.encoded
+}
+
+// SignatureFromBytes decodes a signature in the format written by Signature.Bytes.
+//
+// We return an error wrapping ErrInvalidSignatureEncoding if buf has the wrong length, R is not a valid encoding of a rational curve point or S encodes a number >= p253.
+// Note that R need not be in the prime-order subgroup and that succesfully decoding a signature says nothing about its validity.
+func SignatureFromBytes(buf []byte) (*Signature, error) {
+	if len(buf) != SignatureSize {
+		return nil, fmt.Errorf("%w: signature has length %v, expected %v", ErrInvalidSignatureEncoding, len(buf), SignatureSize)
+	}
+	var ret Signature
+	var err error
+	ret.r, err = decodePoint(buf[0:PublicKeySize])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignatureEncoding, err)
+	}
+	copy(ret.encodedR[:], buf[0:PublicKeySize])
+	ret.cofactorTimesR = cofactorTimes(&ret.r)
+	if err = ret.s.SetCanonicalBytes(buf[PublicKeySize:], exponentEndianness); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignatureEncoding, err)
+	}
+	return &ret, nil
+}
+
+// Bytes returns the encoding of the signature.
+func (sig *Signature) Bytes() []byte {
+	ret := make([]byte, SignatureSize)
+	copy(ret[0:PublicKeySize], sig.encodedR[:])
+	sBytes := sig.s.ToCanonicalBytes(exponentEndianness)
+	copy(ret[PublicKeySize:], sBytes[:])
+	return ret
+}
+
+// R returns the commitment part R of the signature.
+func (sig *Signature) R() curvePoints.Point_xtw_full {
+	return sig.r
+}
+
+// S returns the response part S of the signature.
+func (sig *Signature) S() exponents.Exponent {
+	return sig.s
+}
+
+// deriveNonce computes the nonce r = H_nonce(prefix || len(hasherID) || hasherID || msg), where len(hasherID) is encoded as a 2-byte big-endian number.
+//
+// Note that including the hasher's ID is crucial: Signing the same message with the same nonce, but different challenge hashes would reveal the secret exponent.
+func (sk *PrivateKey) deriveNonce(hasherID string, msg []byte) exponents.Exponent {
+	if len(hasherID) > 0xFFFF {
+		panic(ErrorPrefix + "hasher ID is too long")
+	}
+	input := make([]byte, 0, noncePrefixSize+2+len(hasherID)+len(msg))
+	input = append(input, sk.noncePrefix[:]...)
+	input = append(input, byte(len(hasherID)>>8), byte(len(hasherID)))
+	input = append(input, hasherID...)
+	input = append(input, msg...)
+	return keypair.DeriveNonce(input, NonceDST)
+}
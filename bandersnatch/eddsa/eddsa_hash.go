@@ -0,0 +1,121 @@
+package eddsa
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/fieldElements"
+)
+
+// This file contains the pluggable challenge hashes c = H_challenge(R, A, M) for EdDSA.
+
+// ChallengeHasher computes the challenge c = H_challenge(R, A, msg) for EdDSA.
+//
+// HasherID must return a string that uniquely identifies the hash function (including all its parameters).
+// It is used to separate the nonces derived for different challenge hashes; two ChallengeHashers with the same ID must compute the same challenges.
+type ChallengeHasher interface {
+	Challenge(r *curvePoints.Point_xtw_full, a *curvePoints.Point_xtw_full, msg []byte) exponents.Exponent
+	HasherID() string
+}
+
+// ByteChallengeHasher is a ChallengeHasher that hashes enc(R) || enc(A) || msg to an exponent via exponents.HashToExponent (i.e. hash_to_field from RFC 9380 with SHA-512)
+// with domain separation tag DST, where enc is the EdwardsCompressed encoding. DST must be non-empty.
+type ByteChallengeHasher struct {
+	DST string
+}
+
+// DefaultChallengeHasher is the ChallengeHasher used by DefaultScheme.
+var DefaultChallengeHasher = ByteChallengeHasher{DST: "BANDERSNATCH-EDDSA-V01-CHALLENGE"}
+
+// Challenge computes the challenge hash from R, A and msg. It implements ChallengeHasher.
+func (h ByteChallengeHasher) Challenge(r *curvePoints.Point_xtw_full, a *curvePoints.Point_xtw_full, msg []byte) exponents.Exponent {
+	encodedR := encodePoint(r)
+	encodedA := encodePoint(a)
+	input := make([]byte, 0, 2*PublicKeySize+len(msg))
+	input = append(input, encodedR[:]...)
+	input = append(input, encodedA[:]...)
+	input = append(input, msg...)
+	c, err := exponents.HashToExponent(input, []byte(h.DST))
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when computing challenge: %w", err))
+	}
+	return c
+}
+
+// HasherID returns "bytes:" followed by the DST. It implements ChallengeHasher.
+func (h ByteChallengeHasher) HasherID() string {
+	return "bytes:" + h.DST
+}
+
+// FieldHashFunction is a hash function whose inputs and outputs are elements of the base field of Bandersnatch.
+// This is meant for hash functions that are efficient inside SNARK circuits over BLS12-381, such as Poseidon. Note that we do not provide any implementation.
+//
+// HashFieldElements must be deterministic. ID must uniquely identify the hash function including all its parameters (e.g. the Poseidon instance).
+type FieldHashFunction interface {
+	HashFieldElements(inputs []fieldElements.FieldElement) fieldElements.FieldElement
+	ID() string
+}
+
+// FieldChallengeHasher is a ChallengeHasher built from a FieldHashFunction.
+//
+// The challenge is computed as the output of Hash.HashFieldElements on the inputs
+//
+//	R.x, R.y, A.x, A.y, len(msg), m_0, m_1, ..., m_{k-1}
+//
+// (where the coordinates are affine twisted Edwards coordinates) and then reduced modulo p253.
+// Here, the message is split into chunks of MessageChunkSize = 31 bytes (the last chunk may be shorter) and each chunk m_i is interpreted as a little-endian number.
+// len(msg) is the length of msg in bytes. Since 31 byte numbers are smaller than BaseFieldSize, this encoding is injective.
+//
+// Note that the reduction modulo p253 introduces a bias: BaseFieldSize == 4*p253 + eps with eps/p253 about 2^-124.4,
+// so residues in [0, eps) have 5 preimages rather than 4. For a uniform hash output, the statistical distance of the challenge from uniform is
+// eps*(p253-eps)/(p253*BaseFieldSize), which is about eps/BaseFieldSize, i.e. about 2^-126.4. This is negligible.
+type FieldChallengeHasher struct {
+	Hash FieldHashFunction
+}
+
+// MessageChunkSize is the number of bytes of the message that FieldChallengeHasher packs into a single field element.
+const MessageChunkSize = 31
+
+// Challenge computes the challenge hash from R, A and msg. It implements ChallengeHasher.
+func (h FieldChallengeHasher) Challenge(r *curvePoints.Point_xtw_full, a *curvePoints.Point_xtw_full, msg []byte) exponents.Exponent {
+	inputs := make([]fieldElements.FieldElement, 0, 5+(len(msg)+MessageChunkSize-1)/MessageChunkSize)
+	rx, ry := r.XY_affine()
+	ax, ay := a.XY_affine()
+	inputs = append(inputs, rx, ry, ax, ay)
+	inputs = append(inputs, MessageToFieldElements(msg)...)
+	output := h.Hash.HashFieldElements(inputs)
+	var c exponents.Exponent
+	c.SetBigInt(output.ToBigInt())
+	return c.ModuloP253()
+}
+
+// HasherID returns "field:" followed by the ID of the underlying FieldHashFunction. It implements ChallengeHasher.
+func (h FieldChallengeHasher) HasherID() string {
+	return "field:" + h.Hash.ID()
+}
+
+// MessageToFieldElements encodes msg as a sequence of field elements len(msg), m_0, m_1, ..., as described in the documentation of FieldChallengeHasher.
+// This is exported so users can replicate the encoding inside circuits.
+func MessageToFieldElements(msg []byte) []fieldElements.FieldElement {
+	numChunks := (len(msg) + MessageChunkSize - 1) / MessageChunkSize
+	ret := make([]fieldElements.FieldElement, numChunks+1)
+	ret[0].SetUint64(uint64(len(msg)))
+	var chunkInt big.Int
+	for i := 0; i < numChunks; i++ {
+		chunkEnd := (i + 1) * MessageChunkSize
+		if chunkEnd > len(msg) {
+			chunkEnd = len(msg)
+		}
+		chunk := msg[i*MessageChunkSize : chunkEnd]
+		// big.Int uses big-endian byte order, so we need to reverse.
+		reversed := make([]byte, len(chunk))
+		for j := range chunk {
+			reversed[len(chunk)-1-j] = chunk[j]
+		}
+		chunkInt.SetBytes(reversed)
+		ret[i+1].SetBigInt(&chunkInt)
+	}
+	return ret
+}
@@ -0,0 +1,325 @@
+package eddsa
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/fieldElements"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// toyFieldHash is a (cryptographically useless) FieldHashFunction used to test FieldChallengeHasher.
+// It evaluates the polynomial with coefficients given by the inputs at a fixed point via Horner's rule.
+type toyFieldHash struct {
+	point uint64
+}
+
+func (h toyFieldHash) HashFieldElements(inputs []fieldElements.FieldElement) (ret fieldElements.FieldElement) {
+	var x fieldElements.FieldElement
+	x.SetUint64(h.point)
+	for i := range inputs {
+		ret.Mul(&ret, &x)
+		ret.Add(&ret, &inputs[i])
+	}
+	return
+}
+
+func (h toyFieldHash) ID() string {
+	return "toy-horner"
+}
+
+var testSchemes = []Scheme{
+	DefaultScheme,
+	{Hasher: DefaultChallengeHasher, Mode: Cofactorless},
+	{Hasher: FieldChallengeHasher{Hash: toyFieldHash{point: 12345}}, Mode: Cofactored},
+	{Hasher: FieldChallengeHasher{Hash: toyFieldHash{point: 12345}}, Mode: Cofactorless},
+}
+
+// signWithCommitment creates a signature on msg for the key sk, using the nonce r and the commitment R (which need not be r*B).
+func signWithCommitment(sc *Scheme, sk *PrivateKey, pk *PublicKey, msg []byte, r *exponents.Exponent, R *curvePoints.Point_xtw_full) *Signature {
+	var ret Signature
+	ret.r = *R
+	ret.encodedR = encodePoint(R)
+	ret.cofactorTimesR = cofactorTimes(R)
+	c := sc.Hasher.Challenge(R, &pk.point, msg)
+	ret.s.Mul(&c, &sk.scalar)
+	ret.s.Add(&ret.s, r)
+	ret.s = ret.s.ModuloP253()
+	return &ret
+}
+
+func TestSignVerify(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	for _, sc := range testSchemes {
+		for i := 0; i < 10; i++ {
+			sk, err := GenerateKey(drng)
+			testutils.FatalUnless(t, err == nil, "GenerateKey failed: %v", err)
+			pk := sk.PublicKey()
+			testutils.FatalUnless(t, pk.IsInSubgroup(), "Honest public key not in subgroup")
+			msg := make([]byte, drng.Intn(100))
+			drng.Read(msg)
+
+			sig := sc.Sign(sk, msg)
+			testutils.FatalUnless(t, sc.Verify(pk, msg, sig), "Valid signature did not verify (mode %v)", sc.Mode)
+			testutils.FatalUnless(t, bytes.Equal(sig.Bytes(), sc.Sign(sk, msg).Bytes()), "Signing is not deterministic")
+			r := sig.R()
+			testutils.FatalUnless(t, r.IsInSubgroup(), "Honest R not in subgroup")
+
+			testutils.FatalUnless(t, !sc.Verify(pk, append(msg, 0), sig), "Signature verified for wrong message")
+			otherSk, _ := GenerateKey(drng)
+			testutils.FatalUnless(t, !sc.Verify(otherSk.PublicKey(), msg, sig), "Signature verified for wrong key")
+			modified := *sig
+			var one exponents.Exponent
+			one.SetOne()
+			modified.s.Add(&modified.s, &one)
+			modified.s = modified.s.ModuloP253()
+			testutils.FatalUnless(t, !sc.Verify(pk, msg, &modified), "Modified signature verified")
+		}
+	}
+}
+
+func TestDefaultFunctions(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk, _ := GenerateKey(drng)
+	msg := []byte("abc")
+	sig := sk.Sign(msg)
+	testutils.FatalUnless(t, bytes.Equal(sig.Bytes(), DefaultScheme.Sign(sk, msg).Bytes()), "PrivateKey.Sign does not use DefaultScheme")
+	testutils.FatalUnless(t, Verify(sk.PublicKey(), msg, sig), "Verify failed")
+}
+
+func TestNoncesDifferAcrossHashers(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk, _ := GenerateKey(drng)
+	msg := []byte("abc")
+	sig1 := testSchemes[0].Sign(sk, msg)
+	sig2 := testSchemes[2].Sign(sk, msg)
+	testutils.FatalUnless(t, sig1.encodedR != sig2.encodedR, "Nonce was reused across different challenge hashes")
+	otherDST := Scheme{Hasher: ByteChallengeHasher{DST: "OTHER-DST"}, Mode: Cofactored}
+	sig3 := otherDST.Sign(sk, msg)
+	testutils.FatalUnless(t, sig1.encodedR != sig3.encodedR, "Nonce was reused across different DSTs")
+	// The verification mode must not affect signing.
+	sig4 := testSchemes[1].Sign(sk, msg)
+	testutils.FatalUnless(t, bytes.Equal(sig1.Bytes(), sig4.Bytes()), "Signing depends on verification mode")
+}
+
+func TestTorsionComponents(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	for _, sc := range testSchemes {
+		sk, _ := GenerateKey(drng)
+		pk := sk.PublicKey()
+		msg := []byte("abc")
+
+		// R' = r*B + T for T of order 2. Then S*B == R' - T + c*A, so only cofactored verification accepts.
+		var r exponents.Exponent
+		r.SetRandom(drng)
+		var R curvePoints.Point_xtw_full
+		R.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &r)
+		R.AddEq(&curvePoints.AffineOrderTwoPoint_xtw)
+		testutils.FatalUnless(t, !R.IsInSubgroup(), "R' is in subgroup")
+		sig := signWithCommitment(&sc, sk, pk, msg, &r, &R)
+		decoded, err := SignatureFromBytes(sig.Bytes())
+		testutils.FatalUnless(t, err == nil, "Could not decode signature with torsion component: %v", err)
+		testutils.FatalUnless(t, sc.Verify(pk, msg, decoded) == (sc.Mode == Cofactored), "Wrong verification result for R with torsion component (mode %v)", sc.Mode)
+
+		// A' = A + T. Signatures with respect to A' verify cofactored, but cofactorless only if c is even.
+		var A curvePoints.Point_xtw_full = pk.point
+		A.AddEq(&curvePoints.AffineOrderTwoPoint_xtw)
+		encodedA := encodePoint(&A)
+		pkTorsion, err := PublicKeyFromBytes(encodedA[:])
+		testutils.FatalUnless(t, err == nil, "Could not decode public key with torsion component: %v", err)
+		testutils.FatalUnless(t, !pkTorsion.IsInSubgroup(), "Public key with torsion component reported as in subgroup")
+		R.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &r)
+		sig = signWithCommitment(&sc, sk, pkTorsion, msg, &r, &R)
+		c := sc.Hasher.Challenge(&R, &A, msg)
+		_, cParity := c.DivModTwo()
+		expected := sc.Mode == Cofactored || cParity == 0
+		testutils.FatalUnless(t, sc.Verify(pkTorsion, msg, sig) == expected, "Wrong verification result for A with torsion component (mode %v)", sc.Mode)
+	}
+}
+
+func TestEncodingRoundtrip(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk, _ := GenerateKey(drng)
+	sk2, err := NewKeyFromSeed(sk.Seed())
+	testutils.FatalUnless(t, err == nil && sk.Equal(sk2), "Seed roundtrip failed")
+	testutils.FatalUnless(t, sk2.PublicKey().Equal(sk.PublicKey()), "Seed roundtrip changed public key")
+
+	pk, err := PublicKeyFromBytes(sk.PublicKey().Bytes())
+	testutils.FatalUnless(t, err == nil && pk.Equal(sk.PublicKey()), "Public key roundtrip failed")
+	pkPoint := pk.Point()
+	skPkPoint := sk.PublicKey().Point()
+	testutils.FatalUnless(t, pkPoint.IsEqual(&skPkPoint), "Public key roundtrip changed point")
+
+	msg := []byte("abc")
+	sig, err := SignatureFromBytes(sk.Sign(msg).Bytes())
+	testutils.FatalUnless(t, err == nil, "Signature roundtrip failed: %v", err)
+	testutils.FatalUnless(t, len(sig.Bytes()) == SignatureSize, "Wrong signature size")
+	testutils.FatalUnless(t, Verify(pk, msg, sig), "Decoded signature does not verify")
+}
+
+func TestInvalidEncodings(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk, _ := GenerateKey(drng)
+	sigBytes := sk.Sign([]byte("abc")).Bytes()
+
+	_, err := NewKeyFromSeed(make([]byte, SeedSize+1))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSeed), "NewKeyFromSeed accepted wrong length")
+	_, err = PublicKeyFromBytes(make([]byte, PublicKeySize-1))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "PublicKeyFromBytes accepted wrong length")
+	_, err = SignatureFromBytes(sigBytes[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureEncoding), "SignatureFromBytes accepted wrong length")
+
+	// small-order public keys
+	var smallOrder curvePoints.Point_xtw_full
+	for _, p := range []curvePoints.CurvePointPtrInterfaceRead{&curvePoints.NeutralElement_xtw_full, &curvePoints.AffineOrderTwoPoint_xtw} {
+		smallOrder.SetFrom(p)
+		encoded := encodePoint(&smallOrder)
+		_, err = PublicKeyFromBytes(encoded[:])
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "PublicKeyFromBytes accepted small-order point")
+	}
+
+	// find some encoding that does not decode to a rational point
+	var invalidPoint []byte
+	for i := 0; ; i++ {
+		candidate := make([]byte, PublicKeySize)
+		candidate[0] = byte(i)
+		if _, errDecode := decodePoint(candidate); errDecode != nil {
+			invalidPoint = candidate
+			break
+		}
+	}
+	_, err = PublicKeyFromBytes(invalidPoint)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "PublicKeyFromBytes accepted invalid point")
+	modifiedSig := append(append([]byte{}, invalidPoint...), sigBytes[PublicKeySize:]...)
+	_, err = SignatureFromBytes(modifiedSig)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureEncoding), "SignatureFromBytes accepted invalid R")
+
+	// non-canonical S
+	for i := PublicKeySize; i < SignatureSize; i++ {
+		modifiedSig[i] = 0xFF
+	}
+	copy(modifiedSig, sigBytes[:PublicKeySize])
+	_, err = SignatureFromBytes(modifiedSig)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureEncoding), "SignatureFromBytes accepted non-canonical S")
+}
+
+func TestBatchVerify(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	const batchSize = 8
+	for _, sc := range testSchemes {
+		pks := make([]*PublicKey, batchSize)
+		msgs := make([][]byte, batchSize)
+		sigs := make([]*Signature, batchSize)
+		for i := 0; i < batchSize; i++ {
+			sk, _ := GenerateKey(drng)
+			pks[i] = sk.PublicKey()
+			msgs[i] = []byte{byte(i)}
+			sigs[i] = sc.Sign(sk, msgs[i])
+		}
+		invalid, err := sc.BatchVerify(drng, pks, msgs, sigs)
+		testutils.FatalUnless(t, err == nil && invalid == nil, "Batch of valid signatures did not verify (mode %v)", sc.Mode)
+		invalid, err = sc.BatchVerify(drng, nil, nil, nil)
+		testutils.FatalUnless(t, err == nil && invalid == nil, "Empty batch did not verify")
+
+		// swap two messages, making signatures 2 and 3 invalid
+		msgs[2], msgs[3] = msgs[3], msgs[2]
+		invalid, err = sc.BatchVerify(drng, pks, msgs, sigs)
+		testutils.FatalUnless(t, err == nil, "BatchVerify failed: %v", err)
+		testutils.FatalUnless(t, len(invalid) == 2 && invalid[0] == 2 && invalid[1] == 3, "BatchVerify reported wrong invalid signatures %v (mode %v)", invalid, sc.Mode)
+		msgs[2], msgs[3] = msgs[3], msgs[2]
+
+		testutils.FatalUnless(t, testutils.CheckPanic(sc.BatchVerify, drng, pks[1:], msgs, sigs), "BatchVerify did not panic on length mismatch")
+	}
+
+	// Signatures with torsion components in R pass cofactored batch verification, consistent with individual verification.
+	sk, _ := GenerateKey(drng)
+	var r exponents.Exponent
+	r.SetRandom(drng)
+	var R curvePoints.Point_xtw_full
+	R.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &r)
+	R.AddEq(&curvePoints.AffineOrderTwoPoint_xtw)
+	sig := signWithCommitment(&DefaultScheme, sk, sk.PublicKey(), nil, &r, &R)
+	invalid, err := BatchVerify(drng, []*PublicKey{sk.PublicKey()}, [][]byte{nil}, []*Signature{sig})
+	testutils.FatalUnless(t, err == nil && invalid == nil, "Cofactored batch verification is inconsistent with individual verification")
+
+	designatedErr := errors.New("designated error")
+	_, err = BatchVerify(iotest.ErrReader(designatedErr), []*PublicKey{sk.PublicKey()}, [][]byte{nil}, []*Signature{sk.Sign(nil)})
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "BatchVerify did not report randomness error")
+}
+
+func TestMessageToFieldElements(t *testing.T) {
+	for _, length := range []int{0, 1, MessageChunkSize - 1, MessageChunkSize, MessageChunkSize + 1, 3 * MessageChunkSize} {
+		msg := make([]byte, length)
+		for i := range msg {
+			msg[i] = 0xFF
+		}
+		elements := MessageToFieldElements(msg)
+		testutils.FatalUnless(t, len(elements) == 1+(length+MessageChunkSize-1)/MessageChunkSize, "Wrong number of field elements for length %v", length)
+		var expectedLength fieldElements.FieldElement
+		expectedLength.SetUint64(uint64(length))
+		testutils.FatalUnless(t, elements[0].IsEqual(&expectedLength), "First element is not the length")
+	}
+	elements := MessageToFieldElements([]byte{1, 2})
+	var expected fieldElements.FieldElement
+	expected.SetUint64(0x0201)
+	testutils.FatalUnless(t, elements[1].IsEqual(&expected), "Message chunks are not little endian")
+}
+
+func TestRandomnessErrors(t *testing.T) {
+	designatedErr := errors.New("designated error")
+	_, err := GenerateKey(iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "GenerateKey did not report error")
+	sk, err := GenerateKey(nil)
+	testutils.FatalUnless(t, err == nil && Verify(sk.PublicKey(), nil, sk.Sign(nil)), "GenerateKey(nil) failed: %v", err)
+}
+
+func TestInvalidScheme(t *testing.T) {
+	sk, _ := GenerateKey(rand.New(rand.NewSource(1)))
+	noHasher := Scheme{}
+	testutils.FatalUnless(t, testutils.CheckPanic(noHasher.Sign, sk, []byte("abc")), "Scheme without hasher did not panic")
+	badMode := Scheme{Hasher: DefaultChallengeHasher, Mode: VerificationMode(5)}
+	testutils.FatalUnless(t, testutils.CheckPanic(badMode.Verify, sk.PublicKey(), []byte("abc"), sk.Sign(nil)), "Scheme with invalid mode did not panic")
+}
+
+func BenchmarkSign(b *testing.B) {
+	sk, _ := GenerateKey(rand.New(rand.NewSource(1)))
+	msg := []byte("abc")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sk.Sign(msg)
+	}
+}
+
+func BenchmarkBatchVerify64(b *testing.B) {
+	drng := rand.New(rand.NewSource(1))
+	const batchSize = 64
+	pks := make([]*PublicKey, batchSize)
+	msgs := make([][]byte, batchSize)
+	sigs := make([]*Signature, batchSize)
+	for i := 0; i < batchSize; i++ {
+		sk, _ := GenerateKey(drng)
+		pks[i] = sk.PublicKey()
+		msgs[i] = []byte{byte(i)}
+		sigs[i] = sk.Sign(msgs[i])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(drng, pks, msgs, sigs)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	sk, _ := GenerateKey(rand.New(rand.NewSource(1)))
+	msg := []byte("abc")
+	sig := sk.Sign(msg)
+	pk := sk.PublicKey()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Verify(pk, msg, sig)
+	}
+}
@@ -0,0 +1,165 @@
+package eddsa
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/batchverify"
+)
+
+// This file contains signing and (batch) verification of EdDSA signatures.
+//
+// Since public keys A and commitments R are decoded from an encoding that can represent any rational curve point, they may have a component of small order
+// (i.e. A = A' + T with A' in the prime-order subgroup and 4*T == neutral element). Honest signers never produce such keys or signatures.
+// The VerificationMode determines how such components are treated:
+//
+// Cofactored verification checks 4*S*B == 4*R + 4*c*A. This ignores any small-order components of A and R and is the mode that batch verification is consistent with.
+// Cofactorless verification checks S*B == R + c*A exactly on the full curve. This rejects signatures where the small-order components do not cancel.
+//
+// For honestly generated keys and signatures, both modes agree. In-circuit verifiers should make sure to implement the same mode as the out-of-circuit verifier.
+
+// VerificationMode selects cofactored or cofactorless verification. See the file-level documentation.
+type VerificationMode int
+
+const (
+	Cofactored   VerificationMode = iota // check 4*S*B == 4*R + 4*c*A
+	Cofactorless                         // check S*B == R + c*A
+)
+
+// String returns the name of the verification mode.
+func (mode VerificationMode) String() string {
+	switch mode {
+	case Cofactored:
+		return "cofactored"
+	case Cofactorless:
+		return "cofactorless"
+	default:
+		return fmt.Sprintf("invalid verification mode %d", int(mode))
+	}
+}
+
+// Scheme is an instantiation of EdDSA, given by a challenge hash and a verification mode.
+//
+// A Scheme is a plain value type; the zero value is not usable (Hasher must be set).
+type Scheme struct {
+	Hasher ChallengeHasher
+	Mode   VerificationMode
+}
+
+// DefaultScheme is the Scheme used by the package-level functions and PrivateKey.Sign. It uses the SHA-512 based DefaultChallengeHasher and cofactored verification.
+var DefaultScheme = Scheme{Hasher: DefaultChallengeHasher, Mode: Cofactored}
+
+// Sign signs msg with sk under DefaultScheme.
+func (sk *PrivateKey) Sign(msg []byte) *Signature {
+	return DefaultScheme.Sign(sk, msg)
+}
+
+// Verify verifies the signature sig on msg under the public key pk with DefaultScheme.
+func Verify(pk *PublicKey, msg []byte, sig *Signature) bool {
+	return DefaultScheme.Verify(pk, msg, sig)
+}
+
+// BatchVerify verifies the signatures sigs[i] on msgs[i] under the public keys pks[i] with DefaultScheme and returns the indices of the invalid signatures. See Scheme.BatchVerify.
+func BatchVerify(rnd io.Reader, pks []*PublicKey, msgs [][]byte, sigs []*Signature) (invalid []int, err error) {
+	return DefaultScheme.BatchVerify(rnd, pks, msgs, sigs)
+}
+
+// Sign signs msg with sk. Signing is deterministic; the result does not depend on sc.Mode.
+//
+// Note that signatures for the same message under different challenge hashes use different nonces.
+func (sc *Scheme) Sign(sk *PrivateKey, msg []byte) *Signature {
+	sc.checkValid()
+	r := sk.deriveNonce(sc.Hasher.HasherID(), msg)
+	var rSubgroup curvePoints.Point_xtw_subgroup
+	rSubgroup.ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, &r)
+
+	var ret Signature
+	ret.r.SetFrom(&rSubgroup)
+	ret.encodedR = encodePoint(&ret.r)
+	ret.cofactorTimesR = cofactorTimes(&ret.r)
+
+	c := sc.Hasher.Challenge(&ret.r, &sk.publicKey.point, msg)
+	ret.s.Mul(&c, &sk.scalar)
+	ret.s.Add(&ret.s, &r)
+	ret.s = ret.s.ModuloP253()
+	return &ret
+}
+
+// Verify verifies the signature sig on msg under the public key pk, using the verification mode sc.Mode.
+func (sc *Scheme) Verify(pk *PublicKey, msg []byte, sig *Signature) bool {
+	sc.checkValid()
+	c := sc.Hasher.Challenge(&sig.r, &pk.point, msg)
+	switch sc.Mode {
+	case Cofactored:
+		// Check (4*S)*B - c*(4*A) == 4*R. All points involved are in the prime-order subgroup.
+		var fourS exponents.Exponent
+		fourS.Add(&sig.s, &sig.s)
+		fourS.Add(&fourS, &fourS)
+		c.Neg(&c)
+		points := curvePoints.CurvePointSlice_xtw_subgroup{curvePoints.SubgroupGenerator_xtw_subgroup, pk.cofactorTimesPoint}
+		result := curvePoints.MultiExponentiate(points, []exponents.Exponent{fourS, c})
+		return result.IsEqual(&sig.cofactorTimesR)
+	case Cofactorless:
+		// Check S*B == R + c*A on the full curve. Note that c < p253, so the exponentiation below is by the integer c, even if A is not in the subgroup.
+		var lhs, rhs curvePoints.Point_xtw_full
+		lhs.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &sig.s)
+		rhs.Exponentiate(&pk.point, &c)
+		rhs.AddEq(&sig.r)
+		return lhs.IsEqual(&rhs)
+	default:
+		panic(ErrorPrefix + "invalid verification mode")
+	}
+}
+
+// BatchVerify verifies the signatures sigs[i] on msgs[i] under the public keys pks[i] and returns the (sorted) indices of the invalid signatures.
+// All signatures are valid iff invalid == nil and err == nil; if some signature is invalid, invalid is non-empty. This includes the case of an empty batch.
+//
+// For cofactored verification, this checks a random linear combination of the verification equations with a single multi-exponentiation, which is
+// considerably faster than verifying individually. If that check fails, we bisect to find the invalid signatures.
+// The random weights are read from rnd; if rnd is nil, we use crypto/rand.Reader.
+// The probability that an invalid signature is not reported is at most 2^-128.
+// For cofactorless verification, there is no sound batch verification (small-order components need not cancel in a random linear combination),
+// so we verify each signature individually and rnd is not used.
+//
+// We return an error if reading from rnd fails. pks, msgs and sigs must have the same length (we panic otherwise).
+func (sc *Scheme) BatchVerify(rnd io.Reader, pks []*PublicKey, msgs [][]byte, sigs []*Signature) (invalid []int, err error) {
+	sc.checkValid()
+	n := len(sigs)
+	if len(pks) != n || len(msgs) != n {
+		panic(fmt.Errorf(ErrorPrefix+"BatchVerify called with mismatched lengths: %v public keys, %v messages, %v signatures", len(pks), len(msgs), n))
+	}
+	if sc.Mode == Cofactorless {
+		for i := 0; i < n; i++ {
+			if !sc.Verify(pks[i], msgs[i], sigs[i]) {
+				invalid = append(invalid, i)
+			}
+		}
+		return invalid, nil
+	}
+
+	// The verification equation for signature i is (4*S_i)*B - c_i*(4*A_i) - (4*R_i) == neutral element.
+	equations := make([]batchverify.Equation, n)
+	var minusOne exponents.Exponent
+	minusOne.SetInt(-1)
+	for i := 0; i < n; i++ {
+		c := sc.Hasher.Challenge(&sigs[i].r, &pks[i].point, msgs[i])
+		c.Neg(&c)
+		equations[i].GeneratorScalar.Add(&sigs[i].s, &sigs[i].s)
+		equations[i].GeneratorScalar.Add(&equations[i].GeneratorScalar, &equations[i].GeneratorScalar)
+		equations[i].Points = []curvePoints.Point_xtw_subgroup{pks[i].cofactorTimesPoint, sigs[i].cofactorTimesR}
+		equations[i].Scalars = []exponents.Exponent{c, minusOne}
+	}
+	return batchverify.FindInvalid(rnd, equations)
+}
+
+// checkValid panics if sc is not usable.
+func (sc *Scheme) checkValid() {
+	if sc.Hasher == nil {
+		panic(ErrorPrefix + "Scheme has no ChallengeHasher set")
+	}
+	if sc.Mode != Cofactored && sc.Mode != Cofactorless {
+		panic(ErrorPrefix + "Scheme has invalid verification mode")
+	}
+}
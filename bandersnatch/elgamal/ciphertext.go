@@ -0,0 +1,141 @@
+package elgamal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/pointserializer"
+)
+
+// This file contains the Ciphertext type with its homomorphic operations and serialization.
+//
+// A ciphertext (C1, C2) is serialized as CiphertextHeader || enc(C1) || enc(C2), where the points are written via pointSerializer.SerializeCurvePoints.
+// The header identifies the data as an ElGamal ciphertext (and the version of the format).
+
+// pointSerializer is the serializer used for the points of ciphertexts. This is the same encoding as for public keys.
+var pointSerializer = pointserializer.BanderwagonShort
+
+// CiphertextHeader is written in front of every serialized ciphertext.
+var CiphertextHeader = [4]byte{'E', 'G', 0x00, 0x01}
+
+// CiphertextSize is the size in bytes of serialized ciphertexts.
+const CiphertextSize = len(CiphertextHeader) + 2*PublicKeySize
+
+var ErrInvalidCiphertextEncoding = errors.New(ErrorPrefix + "invalid ciphertext encoding")
+
+// Ciphertext is an ElGamal ciphertext (C1, C2). The zero value is not a valid ciphertext; use PublicKey.Encrypt or CiphertextFromBytes or Deserialize to obtain one.
+type Ciphertext struct {
+	c1 curvePoints.Point_xtw_subgroup
+	c2 curvePoints.Point_xtw_subgroup
+}
+
+// CiphertextFromPoints creates the ciphertext (c1, c2). The points must not be NaPs, else we panic.
+func CiphertextFromPoints(c1, c2 *curvePoints.Point_xtw_subgroup) (ret Ciphertext) {
+	if c1.IsNaP() || c2.IsNaP() {
+		panic(ErrorPrefix + "CiphertextFromPoints called with NaP")
+	}
+	ret.c1, ret.c2 = *c1, *c2
+	return
+}
+
+// Points returns the components C1, C2 of the ciphertext.
+func (z *Ciphertext) Points() (c1, c2 curvePoints.Point_xtw_subgroup) {
+	return z.c1, z.c2
+}
+
+// Add sets z = x + y (componentwise). This is an encryption of the sum of the plaintexts.
+func (z *Ciphertext) Add(x, y *Ciphertext) {
+	z.c1.Add(&x.c1, &y.c1)
+	z.c2.Add(&x.c2, &y.c2)
+}
+
+// Sub sets z = x - y (componentwise). This is an encryption of the difference of the plaintexts.
+func (z *Ciphertext) Sub(x, y *Ciphertext) {
+	z.c1.Sub(&x.c1, &y.c1)
+	z.c2.Sub(&x.c2, &y.c2)
+}
+
+// ScalarMul sets z = factor * x (componentwise). This is an encryption of factor times the plaintext.
+//
+// NOTE: The running time depends on factor.
+func (z *Ciphertext) ScalarMul(x *Ciphertext, factor *exponents.Exponent) {
+	z.c1.Exponentiate(&x.c1, factor)
+	z.c2.Exponentiate(&x.c2, factor)
+}
+
+// IsEqual checks whether z and x are the same ciphertext. Note that different encryptions of the same plaintext are not equal.
+func (z *Ciphertext) IsEqual(x *Ciphertext) bool {
+	return z.c1.IsEqual(&x.c1) && z.c2.IsEqual(&x.c2)
+}
+
+// Serialize writes the ciphertext to output. It returns the number of bytes written and an error (nil if ok), which can only come from output.
+func (z *Ciphertext) Serialize(output io.Writer) (bytesWritten int, err error) {
+	bytesWritten, err = output.Write(CiphertextHeader[:])
+	if err != nil {
+		return
+	}
+	bytesJustWritten, errSerialize := pointSerializer.SerializeCurvePoints(output, curvePoints.CurvePointSlice_xtw_subgroup{z.c1, z.c2})
+	bytesWritten += bytesJustWritten
+	if errSerialize != nil {
+		err = errSerialize
+	}
+	return
+}
+
+// Deserialize reads a ciphertext in the format written by Serialize from input.
+//
+// It returns the number of bytes read and an error (nil if ok). We return an error wrapping ErrInvalidCiphertextEncoding if the header does not match
+// and the error from the deserializer otherwise. On error, z is unchanged.
+func (z *Ciphertext) Deserialize(input io.Reader) (bytesRead int, err error) {
+	var header [len(CiphertextHeader)]byte
+	bytesRead, err = io.ReadFull(input, header[:])
+	if err != nil {
+		return
+	}
+	if header != CiphertextHeader {
+		err = fmt.Errorf("%w: unexpected header %x", ErrInvalidCiphertextEncoding, header)
+		return
+	}
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, 2)
+	bytesJustRead, errDeserialize := pointSerializer.DeserializeCurvePoints(input, common.UntrustedInput, points)
+	bytesRead += bytesJustRead
+	if errDeserialize != nil {
+		err = errDeserialize
+		return
+	}
+	z.c1, z.c2 = points[0], points[1]
+	return
+}
+
+// Bytes returns the serialization of the ciphertext.
+func (z *Ciphertext) Bytes() []byte {
+	var buf bytes.Buffer
+	bytesWritten, err := z.Serialize(&buf)
+	if err != nil || bytesWritten != CiphertextSize {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when serializing ciphertext: %w", err))
+	}
+	return buf.Bytes()
+}
+
+// CiphertextFromBytes decodes a ciphertext in the format written by Bytes.
+//
+// We return an error wrapping ErrInvalidCiphertextEncoding if buf has the wrong length, the wrong header or does not encode two points in the prime-order subgroup.
+func CiphertextFromBytes(buf []byte) (ret Ciphertext, err error) {
+	if len(buf) != CiphertextSize {
+		err = fmt.Errorf("%w: ciphertext has length %v, expected %v", ErrInvalidCiphertextEncoding, len(buf), CiphertextSize)
+		return
+	}
+	if _, errDeserialize := ret.Deserialize(bytes.NewReader(buf)); errDeserialize != nil {
+		if errors.Is(errDeserialize, ErrInvalidCiphertextEncoding) {
+			err = errDeserialize
+		} else {
+			err = fmt.Errorf("%w: %v", ErrInvalidCiphertextEncoding, errDeserialize)
+		}
+	}
+	return
+}
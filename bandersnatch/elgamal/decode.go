@@ -0,0 +1,113 @@
+package elgamal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/fieldElements"
+)
+
+// This file contains a baby-step-giant-step decoder that recovers small plaintexts m from m*B.
+//
+// For a bound maxValue, we set n = ceil(sqrt(maxValue + 1)) and precompute a table of the baby steps j*B for 0 <= j < n.
+// To decode a point M, we compute the giant steps M - i*n*B for i = 0, 1, ..., maxValue/n and look them up in the table.
+// This takes O(sqrt(maxValue)) memory and time.
+//
+// Table lookups use the key x/y for affine coordinates (x, y). This is injective on the prime-order subgroup (it only identifies P with P+A, which is outside the subgroup)
+// and independent of the internal representation of points. To amortize the field inversions, we normalize points in batches.
+
+// MaxDecodableValue is the largest maxValue supported by NewDecodingTable. The table for this value has 2^20 entries.
+const MaxDecodableValue = 1<<40 - 1
+
+// giantStepBatchSize is the number of giant steps that are normalized together.
+const giantStepBatchSize = 64
+
+var ErrPlaintextOutOfRange = errors.New(ErrorPrefix + "plaintext is out of range of the decoding table")
+
+// DecodingTable is a precomputed table to recover plaintexts in [0, MaxValue()] from m*B. Use NewDecodingTable to create one.
+//
+// A DecodingTable is safe for concurrent use.
+type DecodingTable struct {
+	maxValue   uint64
+	babySteps  map[fieldElements.Uint256]uint32 // maps the key of j*B to j
+	numBaby    uint64                           // number of baby steps n
+	giantStep  curvePoints.Point_xtw_subgroup   // -n*B
+	giantSteps uint64                           // number of giant steps
+}
+
+// pointKeys returns the lookup keys of the given points. This modifies the internal representation of the points.
+func pointKeys(points curvePoints.CurvePointSlice_xtw_subgroup) []fieldElements.Uint256 {
+	points.BatchNormalizeForY() // cannot fail for points in the subgroup
+	ret := make([]fieldElements.Uint256, len(points))
+	for i := range points {
+		x := points[i].X_decaf_projective() // x/y, since the Y coordinate is now 1
+		x.ToUint256(&ret[i])
+	}
+	return ret
+}
+
+// NewDecodingTable creates a decoding table for plaintexts in the range [0, maxValue].
+//
+// maxValue must be at most MaxDecodableValue, else we panic.
+func NewDecodingTable(maxValue uint64) *DecodingTable {
+	if maxValue > MaxDecodableValue {
+		panic(fmt.Errorf(ErrorPrefix+"NewDecodingTable called with maxValue %v > MaxDecodableValue", maxValue))
+	}
+	numBaby := uint64(math.Ceil(math.Sqrt(float64(maxValue + 1))))
+	for numBaby*numBaby < maxValue+1 {
+		numBaby++ // guard against rounding errors of floating-point arithmetic
+	}
+	ret := &DecodingTable{maxValue: maxValue, numBaby: numBaby, giantSteps: maxValue/numBaby + 1}
+
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, numBaby)
+	points[0] = curvePoints.NeutralElement_xtw_subgroup
+	for j := 1; j < len(points); j++ {
+		points[j].Add(&points[j-1], &curvePoints.SubgroupGenerator_xtw_subgroup)
+	}
+	ret.giantStep.Add(&points[numBaby-1], &curvePoints.SubgroupGenerator_xtw_subgroup)
+	ret.giantStep.NegEq()
+	ret.babySteps = make(map[fieldElements.Uint256]uint32, numBaby)
+	for j, key := range pointKeys(points) {
+		ret.babySteps[key] = uint32(j)
+	}
+	return ret
+}
+
+// MaxValue returns the largest value that the table can decode.
+func (table *DecodingTable) MaxValue() uint64 {
+	return table.maxValue
+}
+
+// Decode returns the value m in [0, table.MaxValue()] with m*B == point.
+//
+// We return an error wrapping ErrPlaintextOutOfRange if there is no such m. point must not be a NaP, else we panic.
+// NOTE: The running time depends on m.
+func (table *DecodingTable) Decode(point *curvePoints.Point_xtw_subgroup) (uint64, error) {
+	if point.IsNaP() {
+		panic(ErrorPrefix + "trying to decode a NaP")
+	}
+	batch := make(curvePoints.CurvePointSlice_xtw_subgroup, giantStepBatchSize)
+	current := *point // M - i*n*B for the next i to be processed
+	for i := uint64(0); i < table.giantSteps; i += giantStepBatchSize {
+		batchLen := table.giantSteps - i
+		if batchLen > giantStepBatchSize {
+			batchLen = giantStepBatchSize
+		}
+		for k := uint64(0); k < batchLen; k++ {
+			batch[k] = current
+			current.AddEq(&table.giantStep)
+		}
+		for k, key := range pointKeys(batch[0:batchLen]) {
+			if j, ok := table.babySteps[key]; ok {
+				value := (i+uint64(k))*table.numBaby + uint64(j)
+				if value > table.maxValue {
+					break
+				}
+				return value, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w: maximal value is %v", ErrPlaintextOutOfRange, table.maxValue)
+}
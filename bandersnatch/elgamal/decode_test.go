@@ -0,0 +1,61 @@
+package elgamal
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func pointForValue(m uint64) (ret curvePoints.Point_xtw_subgroup) {
+	var exponent exponents.Exponent
+	exponent.SetUInt(m)
+	ret.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &exponent)
+	return
+}
+
+func TestDecodingTable(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	// include maximal values that are (one less than) perfect squares and values that require a partial last batch of giant steps.
+	for _, maxValue := range []uint64{0, 1, 2, 15, 16, 99, 1000, 5000, 100000} {
+		table := NewDecodingTable(maxValue)
+		testutils.FatalUnless(t, table.MaxValue() == maxValue, "MaxValue wrong")
+		candidates := []uint64{0, maxValue, maxValue / 2}
+		for i := 0; i < 10; i++ {
+			candidates = append(candidates, uint64(rnd.Int63n(int64(maxValue)+1)))
+		}
+		for _, m := range candidates {
+			point := pointForValue(m)
+			decoded, err := table.Decode(&point)
+			testutils.FatalUnless(t, err == nil && decoded == m, "decoding %v with maxValue %v failed: got %v, error %v", m, maxValue, decoded, err)
+		}
+		for _, m := range []uint64{maxValue + 1, maxValue + 2, 2*maxValue + 100, table.numBaby*table.giantSteps + 1} {
+			point := pointForValue(m)
+			_, err := table.Decode(&point)
+			testutils.FatalUnless(t, errors.Is(err, ErrPlaintextOutOfRange), "value %v out of range %v was decoded", m, maxValue)
+		}
+		// negative values
+		var minusOne exponents.Exponent
+		minusOne.SetUInt(1)
+		minusOne.Neg(&minusOne)
+		var point curvePoints.Point_xtw_subgroup
+		point.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &minusOne)
+		_, err := table.Decode(&point)
+		testutils.FatalUnless(t, errors.Is(err, ErrPlaintextOutOfRange), "-1 was decoded")
+	}
+	testutils.FatalUnless(t, testutils.CheckPanic(NewDecodingTable, uint64(MaxDecodableValue+1)), "NewDecodingTable did not panic on too large value")
+	testutils.FatalUnless(t, testutils.CheckPanic(NewDecodingTable(10).Decode, &curvePoints.Point_xtw_subgroup{}), "Decode did not panic on NaP")
+}
+
+func BenchmarkDecode(b *testing.B) {
+	const maxValue = 1 << 24
+	table := NewDecodingTable(maxValue)
+	point := pointForValue(maxValue - 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = table.Decode(&point)
+	}
+}
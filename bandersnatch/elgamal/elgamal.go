@@ -0,0 +1,172 @@
+package elgamal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+)
+
+// This file contains keys and encryption / decryption for additive (a.k.a. exponential) ElGamal encryption on the prime-order subgroup of Bandersnatch.
+//
+// Notation: B is the generator curvePoints.SubgroupGenerator_xtw_subgroup, the private key is an exponent x != 0, the public key is Y = x*B.
+// A plaintext is an exponent m (modulo p253), which is encrypted with randomness r as
+//
+//	Enc(m; r) = (C1, C2) = (r*B, m*B + r*Y).
+//
+// Decryption computes C2 - x*C1 = m*B. Recovering m from m*B requires solving a discrete logarithm, which is only feasible if m is known to be small;
+// we provide a baby-step-giant-step decoder (see DecodingTable) for that purpose.
+//
+// The scheme is additively homomorphic: Enc(m; r) + Enc(m'; r') == Enc(m + m'; r + r') and c * Enc(m; r) == Enc(c*m; c*r),
+// where addition and scalar multiplication of ciphertexts is componentwise. Ciphertexts can be re-randomized by adding Enc(0; r').
+//
+// NOTE: Encryption is IND-CPA secure under the DDH assumption, but (by design) malleable. Protocols that need more must add proofs of well-formedness.
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / elgamal: "
+
+const (
+	PublicKeySize  = keypair.PointSize  // size in bytes of encoded public keys
+	PrivateKeySize = keypair.ScalarSize // size in bytes of encoded private keys
+)
+
+var (
+	ErrInvalidPrivateKey = errors.New(ErrorPrefix + "invalid private key")
+	ErrInvalidPublicKey  = errors.New(ErrorPrefix + "invalid public key")
+)
+
+// PrivateKey is an ElGamal private key. The zero value is not a valid private key; use GenerateKey or PrivateKeyFromBytes to create one.
+type PrivateKey struct {
+	key keypair.PrivateKey // private exponent x and public key x*B
+}
+
+// PublicKey is an ElGamal public key. The zero value is not a valid public key; use PublicKeyFromBytes or PrivateKey.PublicKey to obtain one.
+type PublicKey struct {
+	key keypair.PublicKey // Y and its encoding
+}
+
+// GenerateKey generates a new private key, reading randomness from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// We return an error if reading from rnd fails.
+func GenerateKey(rnd io.Reader) (*PrivateKey, error) {
+	key, err := keypair.GenerateKey(rnd)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{key: key}, nil
+}
+
+// PrivateKeyFromBytes decodes a private key in the format written by PrivateKey.Bytes.
+//
+// We return an error wrapping ErrInvalidPrivateKey if buf has the wrong length, encodes a number >= p253 or encodes 0.
+func PrivateKeyFromBytes(buf []byte) (*PrivateKey, error) {
+	key, err := keypair.PrivateKeyFromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+	return &PrivateKey{key: key}, nil
+}
+
+// Bytes returns the encoding of the private key. Note that the result is secret.
+func (sk *PrivateKey) Bytes() []byte {
+	return sk.key.Bytes()
+}
+
+// PublicKey returns the public key corresponding to sk.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{key: *sk.key.PublicKey()}
+}
+
+// Equal checks whether sk and other are the same private key.
+func (sk *PrivateKey) Equal(other *PrivateKey) bool {
+	return sk.key.Equal(&other.key)
+}
+
+// PublicKeyFromBytes decodes a public key in the format written by PublicKey.Bytes.
+//
+// We return an error wrapping ErrInvalidPublicKey if buf is not a valid encoding of a point in the prime-order subgroup or encodes the neutral element.
+func PublicKeyFromBytes(buf []byte) (*PublicKey, error) {
+	key, err := keypair.PublicKeyFromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return &PublicKey{key: key}, nil
+}
+
+// Bytes returns the encoding of the public key.
+func (pk *PublicKey) Bytes() []byte {
+	return pk.key.Bytes()
+}
+
+// Point returns the public key as a curve point.
+func (pk *PublicKey) Point() curvePoints.Point_xtw_subgroup {
+	return *pk.key.Point()
+}
+
+// Equal checks whether pk and other are the same public key.
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	return pk.key.Equal(&other.key)
+}
+
+// EncryptWithRandomness returns the encryption Enc(m; r) = (r*B, m*B + r*Y) of m under pk with the given randomness r.
+//
+// This is meant for protocols that prove statements about ciphertexts and hence need to know r. r must be uniformly random and secret for the encryption to be secure.
+// The running time does not depend on m or r.
+func (pk *PublicKey) EncryptWithRandomness(m *exponents.Exponent, r *exponents.Exponent) (ret Ciphertext) {
+	var mB curvePoints.Point_xtw_subgroup
+	ret.c1.ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, r)
+	ret.c2.ExponentiateConstantTime(pk.key.Point(), r)
+	mB.ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, m)
+	ret.c2.AddEq(&mB)
+	return
+}
+
+// Encrypt returns an encryption of m under pk with fresh randomness read from rnd (crypto/rand.Reader if rnd is nil).
+//
+// We return an error if reading from rnd fails.
+func (pk *PublicKey) Encrypt(m *exponents.Exponent, rnd io.Reader) (ret Ciphertext, err error) {
+	var r exponents.Exponent
+	if err = r.SetRandom(rnd); err != nil {
+		return
+	}
+	ret = pk.EncryptWithRandomness(m, &r)
+	return
+}
+
+// Rerandomize sets z to a fresh encryption of the same plaintext as x by adding an encryption of 0 with randomness read from rnd (crypto/rand.Reader if rnd is nil).
+//
+// We return an error if reading from rnd fails; in this case, z is unchanged.
+func (pk *PublicKey) Rerandomize(z, x *Ciphertext, rnd io.Reader) error {
+	var zero exponents.Exponent
+	encryptedZero, err := pk.Encrypt(&zero, rnd)
+	if err != nil {
+		return err
+	}
+	z.Add(x, &encryptedZero)
+	return nil
+}
+
+// Decrypt decrypts c and returns the point m*B, where m is the plaintext.
+//
+// To recover m, use DecryptSmall (if m is known to be small) or compare the result with candidate values m'*B.
+// c must not be the zero value, else we panic. Decryption takes constant time.
+func (sk *PrivateKey) Decrypt(c *Ciphertext) (ret curvePoints.Point_xtw_subgroup) {
+	if c.c1.IsNaP() || c.c2.IsNaP() {
+		panic(ErrorPrefix + "trying to decrypt an uninitialized ciphertext")
+	}
+	ret.ExponentiateConstantTime(&c.c1, sk.key.Scalar())
+	ret.Sub(&c.c2, &ret)
+	return
+}
+
+// DecryptSmall decrypts c and recovers the plaintext m, which must be in the range [0, table.MaxValue()], via the decoding table.
+//
+// We return an error wrapping ErrPlaintextOutOfRange if the plaintext is not in this range.
+// NOTE: Unlike Decrypt, the running time depends on the plaintext.
+func (sk *PrivateKey) DecryptSmall(c *Ciphertext, table *DecodingTable) (uint64, error) {
+	point := sk.Decrypt(c)
+	return table.Decode(&point)
+}
@@ -0,0 +1,191 @@
+package elgamal
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func generateKeyForTest(rnd *rand.Rand) *PrivateKey {
+	sk, err := GenerateKey(rnd)
+	if err != nil {
+		panic(err)
+	}
+	return sk
+}
+
+func encryptForTest(pk *PublicKey, m uint64, rnd *rand.Rand) Ciphertext {
+	var exponent exponents.Exponent
+	exponent.SetUInt(m)
+	c, err := pk.Encrypt(&exponent, rnd)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	sk := generateKeyForTest(rnd)
+	pk := sk.PublicKey()
+	for i := 0; i < 20; i++ {
+		var m, r exponents.Exponent
+		_ = m.SetRandom(rnd)
+		_ = r.SetRandom(rnd)
+		c := pk.EncryptWithRandomness(&m, &r)
+
+		// C1 == r*B, C2 == m*B + r*Y
+		var expected1, expected2, mB curvePoints.Point_xtw_subgroup
+		expected1.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &r)
+		expected2.Exponentiate(pk.key.Point(), &r)
+		mB.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &m)
+		expected2.AddEq(&mB)
+		c1, c2 := c.Points()
+		testutils.FatalUnless(t, c1.IsEqual(&expected1) && c2.IsEqual(&expected2), "EncryptWithRandomness is wrong")
+
+		decrypted := sk.Decrypt(&c)
+		testutils.FatalUnless(t, decrypted.IsEqual(&mB), "Decrypt did not return m*B")
+
+		c, err := pk.Encrypt(&m, rnd)
+		testutils.FatalUnless(t, err == nil, "Encrypt failed: %v", err)
+		decrypted = sk.Decrypt(&c)
+		testutils.FatalUnless(t, decrypted.IsEqual(&mB), "Decrypt did not return m*B")
+
+		other := generateKeyForTest(rnd)
+		decrypted = other.Decrypt(&c)
+		testutils.FatalUnless(t, !decrypted.IsEqual(&mB), "decryption with wrong key succeeded")
+	}
+	testutils.FatalUnless(t, testutils.CheckPanic(sk.Decrypt, &Ciphertext{}), "decrypting the zero value did not panic")
+}
+
+func TestHomomorphicOperations(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	sk := generateKeyForTest(rnd)
+	pk := sk.PublicKey()
+	table := NewDecodingTable(1000)
+	for i := 0; i < 20; i++ {
+		a, b := uint64(rnd.Intn(250)), uint64(rnd.Intn(250))
+		ca, cb := encryptForTest(pk, a, rnd), encryptForTest(pk, b, rnd)
+
+		var sum, diff, scaled, rerandomized Ciphertext
+		sum.Add(&ca, &cb)
+		m, err := sk.DecryptSmall(&sum, table)
+		testutils.FatalUnless(t, err == nil && m == a+b, "Add is not homomorphic: got %v, expected %v (error %v)", m, a+b, err)
+
+		diff.Sub(&sum, &cb)
+		m, err = sk.DecryptSmall(&diff, table)
+		testutils.FatalUnless(t, err == nil && m == a, "Sub is not homomorphic")
+
+		var factor exponents.Exponent
+		factor.SetUInt(3)
+		scaled.ScalarMul(&ca, &factor)
+		m, err = sk.DecryptSmall(&scaled, table)
+		testutils.FatalUnless(t, err == nil && m == 3*a, "ScalarMul is not homomorphic")
+
+		err = pk.Rerandomize(&rerandomized, &ca, rnd)
+		testutils.FatalUnless(t, err == nil, "Rerandomize failed: %v", err)
+		testutils.FatalUnless(t, !rerandomized.IsEqual(&ca), "Rerandomize did not change the ciphertext")
+		m, err = sk.DecryptSmall(&rerandomized, table)
+		testutils.FatalUnless(t, err == nil && m == a, "Rerandomize changed the plaintext")
+
+		// aliasing
+		sum.Add(&sum, &sum)
+		m, _ = sk.DecryptSmall(&sum, table)
+		testutils.FatalUnless(t, m == 2*(a+b), "Add with aliasing arguments failed")
+		_ = pk.Rerandomize(&ca, &ca, rnd)
+		m, _ = sk.DecryptSmall(&ca, table)
+		testutils.FatalUnless(t, m == a, "Rerandomize with aliasing arguments failed")
+	}
+
+	c := encryptForTest(pk, 5, rnd)
+	designatedErr := errors.New("designated error")
+	rerandomized := c
+	err := pk.Rerandomize(&rerandomized, &c, iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "Rerandomize did not report randomness failure")
+	testutils.FatalUnless(t, rerandomized.IsEqual(&c), "failed Rerandomize modified receiver")
+	var m exponents.Exponent
+	_, err = pk.Encrypt(&m, iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "Encrypt did not report randomness failure")
+	_, err = GenerateKey(iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "GenerateKey did not report randomness failure")
+}
+
+func TestKeyEncoding(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		sk := generateKeyForTest(rnd)
+		decodedSk, err := PrivateKeyFromBytes(sk.Bytes())
+		testutils.FatalUnless(t, err == nil && decodedSk.Equal(sk), "private key roundtrip failed: %v", err)
+		encoded := sk.PublicKey().Bytes()
+		testutils.FatalUnless(t, len(encoded) == PublicKeySize, "public key has wrong length")
+		decodedPk, err := PublicKeyFromBytes(encoded)
+		testutils.FatalUnless(t, err == nil && decodedPk.Equal(sk.PublicKey()), "public key roundtrip failed: %v", err)
+		point := decodedPk.Point()
+		testutils.FatalUnless(t, point.IsEqual(sk.key.PublicKey().Point()), "Point() wrong")
+	}
+	_, err := PrivateKeyFromBytes(make([]byte, PrivateKeySize))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "zero private key accepted")
+	_, err = PrivateKeyFromBytes(bytes.Repeat([]byte{0xff}, PrivateKeySize))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "non-reduced private key accepted")
+	_, err = PublicKeyFromBytes(make([]byte, PublicKeySize-1))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "public key of wrong length accepted")
+	neutral := keypair.EncodePoint(&curvePoints.NeutralElement_xtw_subgroup)
+	_, err = PublicKeyFromBytes(neutral[:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "neutral element accepted as public key")
+}
+
+func TestCiphertextSerialization(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	pk := generateKeyForTest(rnd).PublicKey()
+	for i := 0; i < 10; i++ {
+		c := encryptForTest(pk, uint64(i), rnd)
+		encoded := c.Bytes()
+		testutils.FatalUnless(t, len(encoded) == CiphertextSize, "ciphertext has wrong length")
+		testutils.FatalUnless(t, bytes.Equal(encoded[0:len(CiphertextHeader)], CiphertextHeader[:]), "ciphertext does not start with header")
+		decoded, err := CiphertextFromBytes(encoded)
+		testutils.FatalUnless(t, err == nil && decoded.IsEqual(&c), "ciphertext roundtrip failed: %v", err)
+
+		c1, c2 := c.Points()
+		fromPoints := CiphertextFromPoints(&c1, &c2)
+		testutils.FatalUnless(t, fromPoints.IsEqual(&c), "CiphertextFromPoints roundtrip failed")
+
+		// several ciphertexts in a stream
+		var buf bytes.Buffer
+		bytesWritten, err := c.Serialize(&buf)
+		testutils.FatalUnless(t, err == nil && bytesWritten == CiphertextSize, "Serialize failed: %v", err)
+		_, _ = decoded.Serialize(&buf)
+		var first, second Ciphertext
+		bytesRead, err := first.Deserialize(&buf)
+		testutils.FatalUnless(t, err == nil && bytesRead == CiphertextSize && first.IsEqual(&c), "Deserialize failed: %v", err)
+		_, err = second.Deserialize(&buf)
+		testutils.FatalUnless(t, err == nil && second.IsEqual(&c), "Deserialize failed: %v", err)
+	}
+
+	c := encryptForTest(pk, 1, rnd)
+	encoded := c.Bytes()
+	_, err := CiphertextFromBytes(encoded[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCiphertextEncoding), "ciphertext of wrong length accepted")
+	bad := append([]byte(nil), encoded...)
+	bad[0] ^= 1
+	_, err = CiphertextFromBytes(bad)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCiphertextEncoding), "wrong header accepted")
+	bad = append(bad[:0], encoded...)
+	for i := len(CiphertextHeader); i < len(CiphertextHeader)+PublicKeySize; i++ {
+		bad[i] = 0xff
+	}
+	_, err = CiphertextFromBytes(bad)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCiphertextEncoding), "invalid point accepted")
+
+	var z Ciphertext
+	_, err = z.Deserialize(bytes.NewReader(encoded[0 : CiphertextSize-1]))
+	testutils.FatalUnless(t, err != nil, "truncated input accepted")
+	testutils.FatalUnless(t, z.c1.IsNaP(), "failed Deserialize modified receiver")
+	testutils.FatalUnless(t, testutils.CheckPanic(CiphertextFromPoints, &z.c1, &z.c2), "CiphertextFromPoints accepted NaPs")
+}
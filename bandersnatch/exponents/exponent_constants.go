@@ -7,6 +7,9 @@ import (
 	"github.com/GottfriedHerold/Bandersnatch/internal/utils"
 )
 
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / exponents: "
+
 const CurveExponent = common.CurveExponent
 const CurveOrder = common.CurveOrder
 const GroupOrder = common.GroupOrder
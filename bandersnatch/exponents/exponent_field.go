@@ -0,0 +1,263 @@
+package exponents
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// This file contains multiplication of exponents and the field operations of GF(p253) (Inv, Divide, Exp, IsEqual, batch inversion).
+//
+// Internally, we multiply via Montgomery multiplication modulo p253 with Montgomery constant R = 2^256.
+// Since Exponent works modulo 2*p253 and 2*p253 is even (so Montgomery multiplication modulo 2*p253 is not possible),
+// Mul computes the product modulo p253 via Montgomery multiplication and recovers the result modulo 2*p253 from its parity via the CRT.
+//
+// The field operations Inv, Divide and Exp are only meaningful modulo p253. Their results are always fully reduced, i.e. in the range 0 <= . < p253.
+// Note that this is consistent with the interpretation of exponents modulo 2*p253 as long as we only apply them to points in the prime-order subgroup.
+// However, since Add, Sub, Neg and Mul work modulo 2*p253, identities such as x * (1/x) == 1 only hold modulo p253 (i.e. for IsEqual, not for ==).
+// Use ScalarField (see scalar_field.go) for field arithmetic where every result is fully reduced.
+
+// ErrDivisionByZero is the error (possibly wrapped) reported if we attempt to invert zero modulo p253.
+var ErrDivisionByZero = errors.New(ErrorPrefix + "division by zero")
+
+// p253 as a little-endian array of 64-bit words.
+var p253Words = [4]uint64{groupOrder_0, groupOrder_1, groupOrder_2, groupOrder_3}
+
+// montgomeryNegInverse is -1/p253 mod 2^64.
+const montgomeryNegInverse = 0xf19f2229_5cc063df
+
+// montgomeryRSquared is R^2 mod p253 with R = 2^256. Montgomery-multiplying by this converts into Montgomery form.
+var montgomeryRSquared = [4]uint64{0xdbb4f5d6_58db47cb, 0x40fa7ca2_7fecb938, 0xaa9e6dae_c0055cea, 0x0ae793dd_b14aec7d}
+
+// montgomeryOne is R mod p253 with R = 2^256, i.e. the Montgomery form of 1.
+var montgomeryOne = [4]uint64{0x5817ca56_bc48c0f8, 0x0383c7fc_5f37dc74, 0x998c4fef_ecbc4ff8, 0x1824b159_acc5056f}
+
+// p253MinusTwo_Int is p253 - 2, which is the exponent used for inversion via Fermat's little theorem.
+var p253MinusTwo_Int *big.Int = new(big.Int).Sub(GroupOrder_Int, big.NewInt(2))
+
+// montgomeryMul computes x * y / R modulo p253, where R = 2^256.
+//
+// x and y must be smaller than 2*p253 (so the internal values of Exponent are fine). The result is fully reduced, i.e. in 0 <= . < p253.
+// The running time does not depend on the values of x and y.
+func montgomeryMul(x *[4]uint64, y *[4]uint64) (z [4]uint64) {
+	// CIOS (coarsely integrated operand scanning) Montgomery multiplication.
+	// Since x, y < 2*p253 and 4*p253 < R, the intermediate value t stays below 2*p253 after each outer iteration.
+	var t [5]uint64
+	var hi, lo, carry, c uint64
+	for i := 0; i < 4; i++ {
+		// t += x * y[i]
+		c = 0
+		for j := 0; j < 4; j++ {
+			hi, lo = bits.Mul64(x[j], y[i])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			t[j], carry = bits.Add64(lo, c, 0)
+			c = hi + carry
+		}
+		t[4] += c // cannot overflow
+
+		// t = (t + m * p253) / 2^64, where m is chosen such that the division is exact.
+		m := t[0] * montgomeryNegInverse
+		hi, lo = bits.Mul64(m, p253Words[0])
+		_, carry = bits.Add64(lo, t[0], 0)
+		c = hi + carry
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(m, p253Words[j])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			t[j-1], carry = bits.Add64(lo, c, 0)
+			c = hi + carry
+		}
+		t[3], carry = bits.Add64(t[4], c, 0)
+		t[4] = carry
+	}
+
+	// t < 2*p253 < 2^256, so t[4] == 0. We conditionally subtract p253 without branching.
+	var borrow uint64
+	z[0], borrow = bits.Sub64(t[0], p253Words[0], 0)
+	z[1], borrow = bits.Sub64(t[1], p253Words[1], borrow)
+	z[2], borrow = bits.Sub64(t[2], p253Words[2], borrow)
+	z[3], borrow = bits.Sub64(t[3], p253Words[3], borrow)
+	mask := -borrow // all-ones if t < p253, i.e. we must keep t.
+	z[0] ^= (z[0] ^ t[0]) & mask
+	z[1] ^= (z[1] ^ t[1]) & mask
+	z[2] ^= (z[2] ^ t[2]) & mask
+	z[3] ^= (z[3] ^ t[3]) & mask
+	return
+}
+
+// mulModP253 computes x * y modulo p253. The result is fully reduced.
+func mulModP253(x *[4]uint64, y *[4]uint64) [4]uint64 {
+	// montgomeryMul(x,y) == x*y/R. Multiplying by R^2 in Montgomery form gives x*y.
+	temp := montgomeryMul(x, y)
+	return montgomeryMul(&temp, &montgomeryRSquared)
+}
+
+// Mul performs multiplication of exponents
+//
+// Use z.Mul(&x, &y) to compute z = x * y (modulo 2*p253)
+//
+// The running time does not depend on the values of x and y.
+func (z *Exponent) Mul(x *Exponent, y *Exponent) {
+	// We compute x*y mod p253 and then fix the parity to obtain x*y mod 2*p253 (which works, because p253 is odd).
+	// The fix-up adds p253 if the parity is wrong; we select this via bit-masking rather than with an if, since x and y may be secret.
+	parity := x.value[0] & y.value[0] & 1
+	z.value = mulModP253(&x.value, &y.value)
+	mask := -((z.value[0] & 1) ^ parity) // all-ones iff we need to add p253
+	var carry uint64
+	z.value[0], carry = bits.Add64(z.value[0], groupOrder_0&mask, 0)
+	z.value[1], carry = bits.Add64(z.value[1], groupOrder_1&mask, carry)
+	z.value[2], carry = bits.Add64(z.value[2], groupOrder_2&mask, carry)
+	z.value[3], _ = bits.Add64(z.value[3], groupOrder_3&mask, carry)
+}
+
+// Square computes the square of an exponent.
+//
+// Use z.Square(&x) to compute z = x * x (modulo 2*p253)
+func (z *Exponent) Square(x *Exponent) {
+	z.Mul(x, x)
+}
+
+// IsEqual compares two exponents for equality modulo p253.
+//
+// NOTE: This is the appropriate notion of equality for elements of the scalar field GF(p253). Exponents that only agree modulo p253 act differently on points outside the prime-order subgroup;
+// compare the values of ModuloP253 resp. ToBigInt_Full if this distinction matters.
+func (z *Exponent) IsEqual(x *Exponent) bool {
+	return z.ModuloP253() == x.ModuloP253()
+}
+
+// montgomeryExp computes base^exponent in Montgomery form, i.e. both baseMontgomery and the result are in Montgomery form.
+// exponent must be non-negative.
+//
+// The running time depends on the exponent, but not on the base.
+func montgomeryExp(baseMontgomery *[4]uint64, exponent *big.Int) (result [4]uint64) {
+	result = montgomeryOne
+	for i := exponent.BitLen() - 1; i >= 0; i-- {
+		result = montgomeryMul(&result, &result)
+		if exponent.Bit(i) == 1 {
+			result = montgomeryMul(&result, baseMontgomery)
+		}
+	}
+	return
+}
+
+// invModP253 computes 1/x modulo p253 via Fermat's little theorem. The result is fully reduced. For x == 0 mod p253, the result is 0.
+//
+// The running time does not depend on x.
+func invModP253(x *[4]uint64) [4]uint64 {
+	var one = [4]uint64{1, 0, 0, 0}
+	xMontgomery := montgomeryMul(x, &montgomeryRSquared)
+	resultMontgomery := montgomeryExp(&xMontgomery, p253MinusTwo_Int)
+	return montgomeryMul(&resultMontgomery, &one)
+}
+
+// Inv computes the multiplicative inverse modulo p253.
+//
+// z.Inv(&x) sets z = 1/x (modulo p253). The result is fully reduced, i.e. in 0 <= z < p253.
+// If x == 0 modulo p253, we panic with ErrDivisionByZero.
+func (z *Exponent) Inv(x *Exponent) {
+	if x.IsZero_Subgroup() {
+		panic(ErrDivisionByZero)
+	}
+	z.value = invModP253(&x.value)
+}
+
+// InvEq replaces z by its multiplicative inverse modulo p253.
+//
+// z.InvEq() is equivalent to z.Inv(&z).
+func (z *Exponent) InvEq() {
+	z.Inv(z)
+}
+
+// Divide performs division modulo p253.
+//
+// z.Divide(&num, &denom) sets z = num/denom (modulo p253). The result is fully reduced, i.e. in 0 <= z < p253.
+// If denom == 0 modulo p253, we panic with ErrDivisionByZero.
+func (z *Exponent) Divide(num *Exponent, denom *Exponent) {
+	if denom.IsZero_Subgroup() {
+		panic(ErrDivisionByZero)
+	}
+	denomInv := invModP253(&denom.value)
+	z.value = mulModP253(&num.value, &denomInv)
+}
+
+// Exp performs exponentiation modulo p253.
+//
+// z.Exp(&base, exponent) sets z = base^exponent (modulo p253), with 0^0 == 1. The result is fully reduced, i.e. in 0 <= z < p253.
+// Negative exponents are allowed and mean exponentiation of the inverse; in that case, we panic with ErrDivisionByZero if base == 0 modulo p253.
+//
+// NOTE: The running time depends on exponent (but not on base).
+func (z *Exponent) Exp(base *Exponent, exponent *big.Int) {
+	var baseMontgomery [4]uint64 = montgomeryMul(&base.value, &montgomeryRSquared)
+	if exponent.Sign() < 0 {
+		if base.IsZero_Subgroup() {
+			panic(ErrDivisionByZero)
+		}
+		// base^exponent == (1/base)^(-exponent)
+		baseInv := invModP253(&base.value)
+		baseMontgomery = montgomeryMul(&baseInv, &montgomeryRSquared)
+		exponent = new(big.Int).Neg(exponent)
+	}
+	var one = [4]uint64{1, 0, 0, 0}
+	resultMontgomery := montgomeryExp(&baseMontgomery, exponent)
+	z.value = montgomeryMul(&resultMontgomery, &one)
+}
+
+// MultiInvertEq replaces every argument by its multiplicative inverse modulo p253, using Montgomery's batch inversion trick (i.e. a single inversion plus 3 multiplications per element).
+// The results are fully reduced.
+//
+// If any argument is zero modulo p253, we return an error wrapping ErrDivisionByZero without modifying any of the args.
+// Use MultiInvertEqSlice instead if the arguments are contained in a slice.
+//
+// NOTE: We do not guarantee correct behaviour if any args alias.
+func MultiInvertEq(args ...*Exponent) error {
+	L := len(args)
+	if L == 0 {
+		return nil
+	}
+
+	// productOfFirstN[i] == args[0] * ... * args[i] modulo p253
+	var productOfFirstN []Exponent = make([]Exponent, L)
+	productOfFirstN[0].value = args[0].ModuloP253().value
+	for i := 1; i < L; i++ {
+		productOfFirstN[i].value = mulModP253(&productOfFirstN[i-1].value, &args[i].value)
+	}
+	if productOfFirstN[L-1].IsZero_Full() {
+		return multiInversionError(args)
+	}
+
+	// invariant: temp == 1 / (args[0] * ... * args[i]) at the beginning of the loop
+	var temp Exponent
+	temp.value = invModP253(&productOfFirstN[L-1].value)
+	for i := L - 1; i >= 1; i-- {
+		newTemp := mulModP253(&temp.value, &args[i].value)
+		args[i].value = mulModP253(&temp.value, &productOfFirstN[i-1].value)
+		temp.value = newTemp
+	}
+	*args[0] = temp
+	return nil
+}
+
+// MultiInvertEqSlice replaces every element in args by its multiplicative inverse modulo p253.
+// The results are fully reduced.
+//
+// If any argument is zero modulo p253, we return an error wrapping ErrDivisionByZero without modifying any of the args.
+func MultiInvertEqSlice(args []Exponent) error {
+	argPtrs := make([]*Exponent, len(args))
+	for i := range args {
+		argPtrs[i] = &args[i]
+	}
+	return MultiInvertEq(argPtrs...)
+}
+
+// multiInversionError creates the error returned by MultiInvertEq if some args are zero. The error message contains the indices of the zero args.
+func multiInversionError(args []*Exponent) error {
+	var zeroIndices []int
+	for i, arg := range args {
+		if arg.IsZero_Subgroup() {
+			zeroIndices = append(zeroIndices, i)
+		}
+	}
+	return fmt.Errorf("%w: batch inversion of %v exponents encountered zeros at positions %v", ErrDivisionByZero, len(args), zeroIndices)
+}
@@ -0,0 +1,174 @@
+package exponents
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// sampleExponentsForTest returns a list of exponents for testing, covering the whole range 0 <= . < 2*p253, including some special values.
+func sampleExponentsForTest(drng *rand.Rand, num int) (ret []Exponent) {
+	var xInt *big.Int = new(big.Int)
+	specialValues := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2), new(big.Int).Sub(GroupOrder_Int, big.NewInt(1)), GroupOrder_Int,
+		new(big.Int).Add(GroupOrder_Int, big.NewInt(1)), new(big.Int).Sub(CurveExponent_Int, big.NewInt(1))}
+	ret = make([]Exponent, num+len(specialValues))
+	for i, special := range specialValues {
+		ret[i].SetBigInt(special)
+	}
+	for i := len(specialValues); i < len(ret); i++ {
+		xInt.Rand(drng, CurveExponent_Int)
+		ret[i].SetBigInt(xInt)
+	}
+	return
+}
+
+func TestNegModuloP253(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1000))
+	for _, x := range sampleExponentsForTest(drng, 1000) {
+		var z Exponent
+		z.Neg(&x)
+		expected := new(big.Int).Neg(x.ToBigInt_Full())
+		expected.Mod(expected, CurveExponent_Int)
+		testutils.FatalUnless(t, z.ToBigInt_Full().Cmp(expected) == 0, "Neg does not match big.Int computation for %v", x)
+
+		reduced := x.ModuloP253()
+		expected.Mod(x.ToBigInt_Full(), GroupOrder_Int)
+		testutils.FatalUnless(t, reduced.ToBigInt_Full().Cmp(expected) == 0, "ModuloP253 does not match big.Int computation for %v", x)
+		testutils.FatalUnless(t, x.ToBigInt_Subgroup().Cmp(expected) == 0, "ToBigInt_Subgroup does not match big.Int computation for %v", x)
+	}
+}
+
+func TestMontgomeryMul(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1000))
+	xs := sampleExponentsForTest(drng, 100)
+	ys := sampleExponentsForTest(drng, 100)
+	rInverse := new(big.Int).Lsh(big.NewInt(1), 256)
+	rInverse.ModInverse(rInverse, GroupOrder_Int)
+	for _, x := range xs {
+		for _, y := range ys {
+			z := montgomeryMul(&x.value, &y.value)
+			expected := new(big.Int).Mul(x.ToBigInt_Full(), y.ToBigInt_Full())
+			expected.Mul(expected, rInverse)
+			expected.Mod(expected, GroupOrder_Int)
+			zExp := Exponent{value: z}
+			testutils.FatalUnless(t, zExp.ToBigInt_Full().Cmp(expected) == 0, "montgomeryMul does not match big.Int computation for %v, %v", x, y)
+
+			var zMul, zSquare Exponent
+			zMul.Mul(&x, &y)
+			expected.Mul(x.ToBigInt_Full(), y.ToBigInt_Full())
+			expected.Mod(expected, CurveExponent_Int)
+			testutils.FatalUnless(t, zMul.ToBigInt_Full().Cmp(expected) == 0, "Mul does not match big.Int computation for %v, %v", x, y)
+
+			zSquare.Square(&x)
+			zMul.Mul(&x, &x)
+			testutils.FatalUnless(t, zSquare == zMul, "Square does not match Mul")
+		}
+	}
+	// check constants
+	var one Exponent
+	one.SetOne()
+	testutils.FatalUnless(t, montgomeryMul(&montgomeryOne, &one.value) == one.value, "montgomeryOne is wrong")
+	testutils.FatalUnless(t, montgomeryMul(&montgomeryRSquared, &one.value) == montgomeryOne, "montgomeryRSquared is wrong")
+}
+
+func TestInvDivideExp(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1000))
+	xs := sampleExponentsForTest(drng, 200)
+	for i, x := range xs {
+		y := xs[(i+1)%len(xs)]
+		if !x.IsZero_Subgroup() {
+			var z Exponent
+			z.Inv(&x)
+			expected := new(big.Int).ModInverse(x.ToBigInt_Full(), GroupOrder_Int)
+			testutils.FatalUnless(t, z.ToBigInt_Full().Cmp(expected) == 0, "Inv does not match big.Int computation for %v", x)
+			z.InvEq()
+			testutils.FatalUnless(t, z.IsEqual(&x), "Inverting twice does not give back original")
+
+			z.Divide(&y, &x)
+			expected.Mul(expected, y.ToBigInt_Full())
+			expected.Mod(expected, GroupOrder_Int)
+			testutils.FatalUnless(t, z.ToBigInt_Full().Cmp(expected) == 0, "Divide does not match big.Int computation for %v / %v", y, x)
+		} else {
+			var z Exponent
+			testutils.FatalUnless(t, testutils.CheckPanic(z.Inv, &x), "Inv did not panic for 0")
+			testutils.FatalUnless(t, testutils.CheckPanic(z.Divide, &y, &x), "Divide did not panic for 0")
+			testutils.FatalUnless(t, testutils.CheckPanic(z.Exp, &x, big.NewInt(-1)), "Exp did not panic for 0 with negative exponent")
+		}
+
+		for _, e := range []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(5), big.NewInt(-3), y.ToBigInt_Full(), new(big.Int).Neg(y.ToBigInt_Full())} {
+			if e.Sign() < 0 && x.IsZero_Subgroup() {
+				continue
+			}
+			var z Exponent
+			z.Exp(&x, e)
+			expected := new(big.Int).Exp(x.ToBigInt_Full(), new(big.Int).Abs(e), GroupOrder_Int)
+			if e.Sign() < 0 {
+				expected.ModInverse(expected, GroupOrder_Int)
+			}
+			testutils.FatalUnless(t, z.ToBigInt_Full().Cmp(expected) == 0, "Exp does not match big.Int computation for %v^%v", x, e)
+		}
+	}
+}
+
+func TestIsEqual(t *testing.T) {
+	var x, y Exponent
+	x.SetInt(5)
+	y.SetInt(5)
+	testutils.FatalUnless(t, x.IsEqual(&y), "IsEqual failed")
+	y.Add(&y, &p253Exponent)
+	testutils.FatalUnless(t, x != y, "Adding p253 did not change the value")
+	testutils.FatalUnless(t, x.IsEqual(&y), "IsEqual does not work modulo p253")
+	y.SetInt(6)
+	testutils.FatalUnless(t, !x.IsEqual(&y), "IsEqual failed")
+}
+
+func TestMultiInvertEq(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1000))
+	for _, L := range []int{0, 1, 2, 3, 10, 100} {
+		args := make([]Exponent, L)
+		for i := range args {
+			for args[i].IsZero_Subgroup() {
+				args[i].SetRandom(drng)
+			}
+			if i%3 == 1 {
+				args[i].Add(&args[i], &p253Exponent)
+			}
+		}
+		argsCopy := make([]Exponent, L)
+		copy(argsCopy, args)
+		err := MultiInvertEqSlice(args)
+		testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+		for i := range args {
+			var expected Exponent
+			expected.Inv(&argsCopy[i])
+			testutils.FatalUnless(t, args[i] == expected, "MultiInvertEqSlice differs from Inv")
+		}
+
+		if L > 0 {
+			copy(args, argsCopy)
+			args[L/2].SetZero()
+			if L > 2 {
+				args[L-1] = p253Exponent
+			}
+			copy(argsCopy, args)
+			err = MultiInvertEqSlice(args)
+			testutils.FatalUnless(t, errors.Is(err, ErrDivisionByZero), "MultiInvertEqSlice did not report division by zero")
+			for i := range args {
+				testutils.FatalUnless(t, args[i] == argsCopy[i], "MultiInvertEqSlice modified args on error")
+			}
+		}
+	}
+	var x, y Exponent
+	x.SetInt(2)
+	y.SetInt(-3)
+	err := MultiInvertEq(&x, &y)
+	testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+	var z Exponent
+	z.Mul(&x, &y)
+	z.Inv(&z)
+	z.Neg(&z)
+	testutils.FatalUnless(t, z.ToBigInt_Subgroup().Int64() == 6, "MultiInvertEq gave wrong result")
+}
@@ -0,0 +1,58 @@
+package exponents
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func TestExponentSetRandom(t *testing.T) {
+	var x, y Exponent
+	testutils.FatalUnless(t, x.SetRandom(nil) == nil, "SetRandom(nil) failed")
+	testutils.FatalUnless(t, y.SetRandom(nil) == nil, "SetRandom(nil) failed")
+	testutils.FatalUnless(t, x != y, "SetRandom(nil) gave identical outputs")
+	testutils.FatalUnless(t, x.isNormalized_Subgroup(), "SetRandom gave output >= p253")
+
+	// compare with big.Int computation
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	buf := make([]byte, HashToExponentBytesPerElement)
+	drng.Read(buf)
+	testutils.FatalUnless(t, x.SetRandom(bytes.NewReader(buf)) == nil, "Unexpected error")
+	expected := new(big.Int).SetBytes(buf)
+	expected.Mod(expected, GroupOrder_Int)
+	testutils.FatalUnless(t, x.ToBigInt_Full().Cmp(expected) == 0, "SetRandom differs from big.Int computation")
+
+	// errors
+	designatedErr := errors.New("designated error")
+	y = x
+	err := x.SetRandom(iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "SetRandom did not report error")
+	testutils.FatalUnless(t, x == y, "SetRandom modified receiver on error")
+	err = x.SetRandom(bytes.NewReader(make([]byte, HashToExponentBytesPerElement-1)))
+	testutils.FatalUnless(t, errors.Is(err, io.ErrUnexpectedEOF), "SetRandom did not report error on short read")
+}
+
+// TestExponentSetRandomUniformity performs chi-squared tests on small projections of the output of SetRandom.
+func TestExponentSetRandomUniformity(t *testing.T) {
+	const numBuckets = 16
+	const numSamples = 16000
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	var lowBits, highBits [numBuckets]int
+	var x Exponent
+	for i := 0; i < numSamples; i++ {
+		err := x.SetRandom(drng)
+		testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+		lowBits[x.value[0]%numBuckets]++
+		bucket := new(big.Int).Mul(x.ToBigInt_Full(), big.NewInt(numBuckets))
+		bucket.Div(bucket, GroupOrder_Int)
+		highBits[bucket.Int64()]++
+	}
+	testutils.FatalUnless(t, testutils.ChiSquaredIsUniform(lowBits[:]), "Low bits of SetRandom fail chi-squared test: %v", lowBits)
+	testutils.FatalUnless(t, testutils.ChiSquaredIsUniform(highBits[:]), "High bits of SetRandom fail chi-squared test: %v", highBits)
+}
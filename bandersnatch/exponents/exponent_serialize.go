@@ -0,0 +1,74 @@
+package exponents
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+)
+
+// This file contains (de)serialization of exponents, viewed as elements of GF(p253).
+//
+// We always serialize the canonical representative in 0 <= . < p253 as a 32-byte number; the byte order is determined by a [common.FieldElementEndianness].
+// Note that this loses information for exponents that are only defined modulo 2*p253 (i.e. when acting on points outside the prime-order subgroup).
+//
+// Deserialization is strict: We reject encodings of numbers >= p253. This is important for signature schemes, where accepting non-canonical encodings makes signatures malleable.
+
+// ExponentBytesLength is the length in bytes of serialized exponents.
+const ExponentBytesLength = 32
+
+// ErrNonCanonicalExponent is the error (possibly wrapped) returned if we try to deserialize a number that is not in the range 0 <= . < p253.
+var ErrNonCanonicalExponent = errors.New(ErrorPrefix + "deserialized exponent is not in the range 0 <= . < p253")
+
+// ErrExponentBytesLength is the error (possibly wrapped) returned by SetCanonicalBytes if the input does not have length ExponentBytesLength.
+var ErrExponentBytesLength = errors.New(ErrorPrefix + "byte slice for exponent has wrong length")
+
+// ToCanonicalBytes returns the serialization of z modulo p253, i.e. the 32-byte representation of the unique number 0 <= . < p253 that agrees with z modulo p253.
+// byteOrder determines the order of the output bytes.
+func (z *Exponent) ToCanonicalBytes(byteOrder common.FieldElementEndianness) (ret [ExponentBytesLength]byte) {
+	reduced := z.ModuloP253()
+	byteOrder.PutUint256_array(&ret, &reduced.value)
+	return
+}
+
+// SetCanonicalBytes sets z from the 32-byte slice buf written by ToCanonicalBytes (or Serialize) with the same byteOrder.
+//
+// We return an error wrapping ErrExponentBytesLength if buf does not have length ExponentBytesLength and an error wrapping ErrNonCanonicalExponent if buf encodes a number >= p253.
+// On error, z is unchanged.
+func (z *Exponent) SetCanonicalBytes(buf []byte, byteOrder common.FieldElementEndianness) error {
+	if len(buf) != ExponentBytesLength {
+		return fmt.Errorf("%w: got %v bytes, expected %v", ErrExponentBytesLength, len(buf), ExponentBytesLength)
+	}
+	var temp Exponent
+	byteOrder.Uint256_indirect(buf, &temp.value)
+	if !temp.isNormalized_Subgroup() {
+		return ErrNonCanonicalExponent
+	}
+	*z = temp
+	return nil
+}
+
+// Serialize writes the serialization of z modulo p253 (as given by ToCanonicalBytes) to output.
+//
+// It returns the number of bytes written and an error (nil if ok), which can only come from output.
+// If no error happened, bytesWritten == ExponentBytesLength.
+func (z *Exponent) Serialize(output io.Writer, byteOrder common.FieldElementEndianness) (bytesWritten int, err error) {
+	buf := z.ToCanonicalBytes(byteOrder)
+	return output.Write(buf[:])
+}
+
+// Deserialize reads ExponentBytesLength many bytes from input and sets z to the exponent encoded therein (in the format written by Serialize).
+//
+// It returns the number of bytes read and an error (nil if ok). Possible errors are io errors from input and (a wrapper of) ErrNonCanonicalExponent
+// if the bytes read encode a number >= p253. If the input ends prematurely, we return [io.ErrUnexpectedEOF] resp. [io.EOF] (if nothing was read).
+// On error, z is unchanged.
+func (z *Exponent) Deserialize(input io.Reader, byteOrder common.FieldElementEndianness) (bytesRead int, err error) {
+	var buf [ExponentBytesLength]byte
+	bytesRead, err = io.ReadFull(input, buf[:])
+	if err != nil {
+		return
+	}
+	err = z.SetCanonicalBytes(buf[:], byteOrder)
+	return
+}
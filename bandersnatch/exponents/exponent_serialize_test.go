@@ -0,0 +1,58 @@
+package exponents
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func TestExponentSerializeRoundtrip(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1000))
+	for _, endianness := range []common.FieldElementEndianness{common.LittleEndian, common.BigEndian} {
+		for _, x := range sampleExponentsForTest(drng, 100) {
+			var buf bytes.Buffer
+			bytesWritten, err := x.Serialize(&buf, endianness)
+			testutils.FatalUnless(t, err == nil && bytesWritten == ExponentBytesLength, "Serialize failed: %v", err)
+			canonical := x.ToCanonicalBytes(endianness)
+			testutils.FatalUnless(t, bytes.Equal(buf.Bytes(), canonical[:]), "Serialize differs from ToCanonicalBytes")
+
+			var y Exponent
+			bytesRead, err := y.Deserialize(&buf, endianness)
+			testutils.FatalUnless(t, err == nil && bytesRead == ExponentBytesLength, "Deserialize failed: %v", err)
+			testutils.FatalUnless(t, y == x.ModuloP253(), "Roundtrip failure")
+		}
+	}
+	var x Exponent
+	x.SetInt(0x0102)
+	littleEndian := x.ToCanonicalBytes(common.LittleEndian)
+	bigEndian := x.ToCanonicalBytes(common.BigEndian)
+	testutils.FatalUnless(t, littleEndian[0] == 0x02 && littleEndian[1] == 0x01, "Unexpected little endian serialization")
+	testutils.FatalUnless(t, bigEndian[31] == 0x02 && bigEndian[30] == 0x01, "Unexpected big endian serialization")
+}
+
+func TestExponentDeserializeErrors(t *testing.T) {
+	var x, y Exponent
+	x.SetInt(42)
+	y = x
+
+	// p253 itself is non-canonical
+	var buf [ExponentBytesLength]byte
+	common.BigEndian.PutUint256_array(&buf, &p253Exponent.value)
+	err := x.SetCanonicalBytes(buf[:], common.BigEndian)
+	testutils.FatalUnless(t, errors.Is(err, ErrNonCanonicalExponent), "SetCanonicalBytes accepted p253")
+	testutils.FatalUnless(t, x == y, "SetCanonicalBytes modified receiver on error")
+	_, err = x.Deserialize(bytes.NewReader(buf[:]), common.BigEndian)
+	testutils.FatalUnless(t, errors.Is(err, ErrNonCanonicalExponent), "Deserialize accepted p253")
+	testutils.FatalUnless(t, x == y, "Deserialize modified receiver on error")
+
+	err = x.SetCanonicalBytes(buf[:31], common.BigEndian)
+	testutils.FatalUnless(t, errors.Is(err, ErrExponentBytesLength), "SetCanonicalBytes accepted wrong length")
+	bytesRead, err := x.Deserialize(bytes.NewReader(buf[:31]), common.BigEndian)
+	testutils.FatalUnless(t, errors.Is(err, io.ErrUnexpectedEOF) && bytesRead == 31, "Deserialize did not report short read")
+	testutils.FatalUnless(t, x == y, "Deserialize modified receiver on error")
+}
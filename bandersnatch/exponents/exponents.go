@@ -3,9 +3,11 @@ package exponents
 import (
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math/big"
 	"math/bits"
 
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
 	"github.com/GottfriedHerold/Bandersnatch/internal/utils"
 )
 
@@ -21,16 +23,13 @@ import (
 // Note: The implementation for Exponents is quite different from the implementation FieldElement of the field of definition GF(BaseFieldSize) of the curve.
 // For FieldElement, we internally use Montgomery representation to speed up multiplication. For Exponents, we do not multiply often,
 // So we use a "plain" representation.
-// If needed, we can implement a "mixed" multiplication -- the main thing we need to do inside the library is compute A * n for exponents n and constant A, so
-// we could use Montgomery multiplication anyway.
+// Multiplication converts to Montgomery form on the fly (modulo p253) and recovers the result modulo 2*p253 via the CRT, see exponent_field.go.
 
 // Exponent stores an integer value used as an exponent for exponentiation algorithms.
 type Exponent struct {
 	value [4]uint64 // low-endian, between 0 and curveExponent-1
 }
 
-type ScalarField = Exponent
-
 // p253Exponent is the value p253 of type Exponent (which works modulo 2*p253)
 var p253Exponent Exponent = Exponent{value: [4]uint64{groupOrder_0, groupOrder_1, groupOrder_2, groupOrder_3}}
 
@@ -55,7 +54,7 @@ func (z *Exponent) ModuloP253() (ret Exponent) {
 	if z.isNormalized_Subgroup() {
 		ret = *z
 	} else {
-		ret.Sub(&p253Exponent, z)
+		ret.Sub(z, &p253Exponent)
 	}
 	return
 }
@@ -91,6 +90,19 @@ func (z *Exponent) SetBigInt(x *big.Int) {
 	z.value = utils.BigIntToUIntArray(xReduced)
 }
 
+// SetRandom sets z to a uniformly random exponent modulo p253, using randomness read from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// We read HashToExponentBytesPerElement many bytes and reduce them modulo p253, so the output distribution is statistically close (distance < 2^-128) to uniform in [0, p253).
+// This is what is needed for exponents of points in the prime-order subgroup. If reading from rnd fails, we return an error (wrapping the error from rnd) and z is unchanged.
+func (z *Exponent) SetRandom(rnd io.Reader) error {
+	var buf [HashToExponentBytesPerElement]byte
+	if err := common.ReadRandomBytes(rnd, buf[:]); err != nil {
+		return err
+	}
+	z.setFromUniformBytes(buf[:])
+	return nil
+}
+
 // SetUInt sets the value of z to the given unsigned integer.
 func (z *Exponent) SetUInt(x uint64) {
 	z.value[0] = x
@@ -204,19 +216,6 @@ func (z *Exponent) Sub(x *Exponent, y *Exponent) {
 	}
 }
 
-// This is not very efficient
-
-// Mul performs multiplication of exponents
-//
-// Use z.Mul(&x, &y) to compute z = x * y (modulo 2*p253)
-func (z *Exponent) Mul(x *Exponent, y *Exponent) {
-	xInt := x.ToBigInt_Full()
-	yInt := y.ToBigInt_Full()
-	zInt := big.NewInt(0)
-	zInt.Mul(xInt, yInt)
-	z.SetBigInt(zInt)
-}
-
 // Neg performs negation of exponents
 //
 // Use z.Neg(&x) to compute z = -x (modulo 2*p253)
@@ -227,10 +226,23 @@ func (z *Exponent) Neg(x *Exponent) {
 		return
 	}
 	var borrow uint64
-	z.value[0], borrow = bits.Sub64(curveExponent_0, z.value[0], 0)
-	z.value[1], borrow = bits.Sub64(curveExponent_1, z.value[1], borrow)
-	z.value[2], borrow = bits.Sub64(curveExponent_2, z.value[2], borrow)
-	z.value[3], _ = bits.Sub64(curveExponent_3, z.value[3], borrow)
+	z.value[0], borrow = bits.Sub64(curveExponent_0, x.value[0], 0)
+	z.value[1], borrow = bits.Sub64(curveExponent_1, x.value[1], borrow)
+	z.value[2], borrow = bits.Sub64(curveExponent_2, x.value[2], borrow)
+	z.value[3], _ = bits.Sub64(curveExponent_3, x.value[3], borrow)
+}
+
+// DivModTwo returns the quotient and remainder of z when divided by 2, where z is taken as an integer in 0 <= z < 2*p253.
+//
+// This is used by exponentiation algorithms for points that might be outside the prime-order subgroup:
+// We compute z*P = (z div 2) * (2P) + (z mod 2) * P, where 2P is guaranteed to be in the subgroup.
+func (z *Exponent) DivModTwo() (quotient Exponent, remainder uint) {
+	remainder = uint(z.value[0] & 1)
+	quotient.value[0] = (z.value[0] >> 1) | (z.value[1] << 63)
+	quotient.value[1] = (z.value[1] >> 1) | (z.value[2] << 63)
+	quotient.value[2] = (z.value[2] >> 1) | (z.value[3] << 63)
+	quotient.value[3] = z.value[3] >> 1
+	return
 }
 
 // SetZero sets the exponent value to 0
@@ -129,6 +129,28 @@ func TestRoundTripBigIntScalarField(t *testing.T) {
 
 }
 
+func TestDivModTwo(t *testing.T) {
+	const iterations = 10000
+	var drng *rand.Rand = rand.New(rand.NewSource(1000))
+	var xInt = big.NewInt(0)
+	var qInt = big.NewInt(0)
+	var rInt = big.NewInt(0)
+	var two = big.NewInt(2)
+	var x Exponent
+	for i := 0; i < iterations; i++ {
+		xInt.Rand(drng, CurveExponent_Int)
+		x.SetBigInt(xInt)
+		quotient, remainder := x.DivModTwo()
+		qInt.DivMod(xInt, two, rInt)
+		if quotient.ToBigInt_Full().Cmp(qInt) != 0 {
+			t.Fatal("DivModTwo returned wrong quotient")
+		}
+		if uint64(remainder) != rInt.Uint64() {
+			t.Fatal("DivModTwo returned wrong remainder")
+		}
+	}
+}
+
 func TestAdd128(t *testing.T) {
 	const iterations = 10000
 	var drng *rand.Rand = rand.New(rand.NewSource(1000))
@@ -236,22 +236,22 @@ func GLV_representation(exponent *Exponent) (ret glvExponents) {
 	return
 }
 
-type decompositionCoefficient struct {
-	position uint
-	coeff    uint
-	sign     int
+type DecompositionCoefficient struct {
+	Position uint
+	Coeff    uint
+	Sign     int
 }
 
-// decomposeUnalignedSignedAdic(input, maxbits) outputs a list of exponents e_i and coeffs c_i of the same length s.t.
+// DecomposeUnalignedSignedAdic(input, maxbits) outputs a list of exponents e_i and coeffs c_i of the same length s.t.
 // a) input = \sum_i c_i * 2^{e_i} for the original value of input
 // b) the e_i are ascending (this might change)
 // c) All c_i are odd with |c_i| having at most maxbits bits. Note that both input and the c_i carry signs.
 // The function is allowed to write to input. If the caller needs to re-use input, make a copy first.
-func decomposeUnalignedSignedAdic(input glvExponent, maxbits uint) (decomposition []decompositionCoefficient) {
+func DecomposeUnalignedSignedAdic(input glvExponent, maxbits uint) (decomposition []DecompositionCoefficient) {
 	var globalSign int = input.Sign() // big.Int internally stores sign bit + Abs(input). We only read the latter, so we need to correct the sign. globalSign is in {-1,0,+1}
 	const inputBitLen = 128
 	// 1 + inputBitLen / maxbits is a reasonable estimate for the capacity (it is in fact a upper bound, but we just need an estimate)
-	decomposition = make([]decompositionCoefficient, 0, int(1+inputBitLen/maxbits))
+	decomposition = make([]DecompositionCoefficient, 0, int(1+inputBitLen/maxbits))
 	// exponents = make([]uint, 0, 1+inputBitLen/maxbits)
 	// coeffs = make([]int, 0, 1+inputBitLen/maxbits)
 	var carry uint // bool? uint?
@@ -270,20 +270,20 @@ func decomposeUnalignedSignedAdic(input glvExponent, maxbits uint) (decompositio
 		carry = input.Bit(i + maxbits)
 		if carry == 1 {
 			// change v to v - (2 << maxbits).
-			decomposition = append(decomposition, decompositionCoefficient{position: uint(i), coeff: (1 << maxbits) - v, sign: -globalSign})
+			decomposition = append(decomposition, DecompositionCoefficient{Position: uint(i), Coeff: (1 << maxbits) - v, Sign: -globalSign})
 		} else {
-			decomposition = append(decomposition, decompositionCoefficient{position: uint(i), coeff: v, sign: globalSign})
+			decomposition = append(decomposition, DecompositionCoefficient{Position: uint(i), Coeff: v, Sign: globalSign})
 		}
 		i += maxbits + 1 // Note: The +1 comes from the sign ambiguity
 	}
 	if carry == 1 {
-		decomposition = append(decomposition, decompositionCoefficient{position: uint(i), coeff: 1, sign: globalSign})
+		decomposition = append(decomposition, DecompositionCoefficient{Position: uint(i), Coeff: 1, Sign: globalSign})
 	}
 	return
 }
 
 // getBitRange(x, low, high) interprets Abs(x) as a slice of bits in low-endian order and retuns the value of x[low:high], interpreted as a (usual) int.
-// We only require this to be correct if low <= high and high - low <= 8, say (not sure what bound we need)
+// We only require this to be correct if low <= high and high - low <= 16, say (not sure what bound we need)
 func getBitRange(input uint128, lowend uint, highend uint) uint {
 	// naive implementation:
 	var result uint = 0
@@ -0,0 +1,151 @@
+package exponents
+
+import "math/bits"
+
+// This file contains a variant of the GLV decomposition and of the subsequent recoding of the GLV halves that is intended for constant-time exponentiation algorithms.
+//
+// GLV_representation uses big.Int, whose running time depends on the values involved; furthermore, it performs some data-dependent post-processing to find the optimal (u,v).
+// DecomposeUnalignedSignedAdic outputs a data-dependent number of coefficients at data-dependent positions.
+// Both are fine for public exponents, but not for secret ones.
+//
+// For secret exponents, we instead use the following:
+//   - GLV_representation_ConstantTime only uses fixed-width arithmetic on uint64-words without any branches depending on the exponent.
+//     It only performs the Babai rounding step (without looking for a better solution afterwards), so |u|,|v| are not guaranteed to be optimal.
+//     We still have |u|,|v| < 2^127.
+//   - DecomposeSignedFixedWindow outputs a fixed number of signed digits (some of which may be zero) for a fixed window size.
+
+// We approximate the rational numbers lBasis_22 / p253 and lBasis_12 / p253 (which appear in the Babai rounding step) by
+// glvRoundingFactor_1 / 2^glvRoundingShift and glvRoundingFactor_2 / 2^glvRoundingShift.
+//
+// Note: The glvRoundingFactor_i are floor(2^glvRoundingShift * lBasis_22 / p253) resp. floor(2^glvRoundingShift * lBasis_12 / p253) in low-endian 64-bit words.
+// The error introduced by this approximation is tiny and at worst causes the Babai rounding to be off by one, which still gives a short (u,v).
+const glvRoundingShift = 384
+
+var (
+	glvRoundingFactor_1 = [5]uint64{0xA9A789475436DFBA, 0xB896E1904DEBA64F, 0xDEBAC77A3F4747C1, 0xF21DF5B0541CF632, 0x0000000000000002}
+	glvRoundingFactor_2 = [4]uint64{0xDEC972CF0A58CC2A, 0xBCB69F852DCABF60, 0x993B75E7547768AA, 0x4760F127D8767BDE}
+)
+
+// lBasis_11 (which equals lBasis_22) and lBasis_12 as low-endian 64-bit words. Recall that lBasis_21 == -2 * lBasis_12.
+var (
+	lBasis_11_words = [2]uint64{0x4B02F94A9789181F, 0x555FE2004BE6928E}
+	lBasis_12_words = [2]uint64{0xF8E2591A23D61F44, 0x0814B3EEE55E8F5D}
+)
+
+// mulWordsTruncated computes out = x * y modulo 2^(64*len(out)) for numbers given as low-endian slices of uint64's.
+// The running time only depends on the lengths of the slices.
+func mulWordsTruncated(out []uint64, x []uint64, y []uint64) {
+	for i := range out {
+		out[i] = 0
+	}
+	for i := 0; i < len(x) && i < len(out); i++ {
+		var carry uint64
+		for j := 0; j < len(y) && i+j < len(out); j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			var c uint64
+			lo, c = bits.Add64(lo, out[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			out[i+j] = lo
+			carry = hi
+		}
+		if i+len(y) < len(out) {
+			out[i+len(y)] = carry
+		}
+	}
+}
+
+// glvRound computes round(t * factor / 2^glvRoundingShift), which is guaranteed to be < 2^128 for the values of factor that we use.
+func glvRound(t *[4]uint64, factor []uint64) (ret [2]uint64) {
+	var product [9]uint64
+	mulWordsTruncated(product[:], t[:], factor)
+	// add 2^(glvRoundingShift-1) to turn truncation into rounding.
+	var carry uint64
+	product[5], carry = bits.Add64(product[5], 1<<63, 0)
+	product[6], carry = bits.Add64(product[6], 0, carry)
+	product[7], _ = bits.Add64(product[7], 0, carry)
+	ret[0] = product[glvRoundingShift/64]
+	ret[1] = product[glvRoundingShift/64+1]
+	return
+}
+
+// GLV_representation_ConstantTime(t) outputs a pair u,v such that t*P = u*P + v*Psi(P) for the endomorphism Psi for any P in the subgroup.
+//
+// As opposed to GLV_representation, the running time of this function does not depend on the value of t.
+// We guarantee that |u|, |v| both have at most 127 bits, but (u,v) is not necessarily the shortest possible solution.
+func GLV_representation_ConstantTime(exponent *Exponent) (ret glvExponents) {
+	// We write (t,0) = alpha * lBasis_1 + beta * lBasis_2, with alpha = t * lBasis_22 / p253 and beta = -t * lBasis_12 / p253 (as det(lBasis) == p253)
+	// and then set c1 = round(alpha), c2 = round(-beta).
+	// The result is (u,v) = (t,0) - c1 * lBasis_1 + c2 * lBasis_2 = (t - c1*lBasis_11 - 2*c2*lBasis_12, c2 * lBasis_11 - c1 * lBasis_12)
+	//
+	// Since we know that |u|,|v| < 2^127, we can just compute everything modulo 2^192 in two's complement representation.
+	c1 := glvRound(&exponent.value, glvRoundingFactor_1[:])
+	c2 := glvRound(&exponent.value, glvRoundingFactor_2[:])
+
+	var u, v, temp [3]uint64
+	var borrow uint64
+	u[0], u[1], u[2] = exponent.value[0], exponent.value[1], exponent.value[2]
+
+	mulWordsTruncated(temp[:], c1[:], lBasis_11_words[:])
+	u[0], borrow = bits.Sub64(u[0], temp[0], 0)
+	u[1], borrow = bits.Sub64(u[1], temp[1], borrow)
+	u[2], _ = bits.Sub64(u[2], temp[2], borrow)
+
+	mulWordsTruncated(temp[:], c2[:], lBasis_12_words[:])
+	// multiply temp by 2
+	temp[2] = (temp[2] << 1) | (temp[1] >> 63)
+	temp[1] = (temp[1] << 1) | (temp[0] >> 63)
+	temp[0] = temp[0] << 1
+	u[0], borrow = bits.Sub64(u[0], temp[0], 0)
+	u[1], borrow = bits.Sub64(u[1], temp[1], borrow)
+	u[2], _ = bits.Sub64(u[2], temp[2], borrow)
+
+	mulWordsTruncated(v[:], c2[:], lBasis_11_words[:])
+	mulWordsTruncated(temp[:], c1[:], lBasis_12_words[:])
+	v[0], borrow = bits.Sub64(v[0], temp[0], 0)
+	v[1], borrow = bits.Sub64(v[1], temp[1], borrow)
+	v[2], _ = bits.Sub64(v[2], temp[2], borrow)
+
+	ret.U = glvExponentFromTwosComplement(&u)
+	ret.V = glvExponentFromTwosComplement(&v)
+	return
+}
+
+// glvExponentFromTwosComplement converts a signed number with absolute value < 2^128, given in two's complement modulo 2^192, into a glvExponent.
+//
+// Note that this sets the sign to +1 for a zero input.
+func glvExponentFromTwosComplement(x *[3]uint64) (ret glvExponent) {
+	negative := x[2] >> 63
+	mask := -negative
+	var carry uint64
+	ret.value[0], carry = bits.Add64(x[0]^mask, negative, 0)
+	ret.value[1], _ = bits.Add64(x[1]^mask, 0, carry)
+	ret.sign = 1 - 2*int(negative)
+	return
+}
+
+// DecomposeSignedFixedWindow(input, windowSize) outputs a list of signed digits d_i such that
+// a) input = \sum_i d_i * 2^{i * windowSize} (note that input carries a sign)
+// b) |d_i| <= 2^{windowSize-1} for all i. Digits may be zero.
+// c) The number of digits only depends on windowSize; it is ceil(128 / windowSize) + 1.
+//
+// The running time does not depend on the value of input. windowSize must be between 1 and 16.
+func DecomposeSignedFixedWindow(input glvExponent, windowSize uint) (digits []int) {
+	if windowSize == 0 || windowSize > 16 {
+		panic(ErrorPrefix + "DecomposeSignedFixedWindow called with invalid window size")
+	}
+	const inputBitLen = 128
+	numDigits := (inputBitLen+windowSize-1)/windowSize + 1
+	digits = make([]int, numDigits)
+	var carry int // either 0 or 1
+	for i := uint(0); i < numDigits-1; i++ {
+		d := int(getBitRange(input.value, i*windowSize, (i+1)*windowSize)) + carry // 0 <= d <= 2^windowSize
+		// set carry to 1 iff d >= 2^(windowSize-1) and reduce d accordingly
+		carry = (d + (1 << (windowSize - 1))) >> windowSize
+		d -= carry << windowSize
+		digits[i] = d * input.sign
+	}
+	digits[numDigits-1] = carry * input.sign
+	return
+}
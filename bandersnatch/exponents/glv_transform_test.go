@@ -52,7 +52,7 @@ func BenchmarkGLVDecomposition(b *testing.B) {
 	}
 }
 
-// BenchmarkBitDecomposition benchmarks decomposeUnalignedSignedAdic
+// BenchmarkBitDecomposition benchmarks DecomposeUnalignedSignedAdic
 func BenchmarkBitDecomposition(b *testing.B) {
 	var drng *rand.Rand = rand.New(rand.NewSource(int64(1000 + b.N)))
 	var exponents []glvExponent = make([]glvExponent, b.N)
@@ -68,7 +68,7 @@ func BenchmarkBitDecomposition(b *testing.B) {
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = decomposeUnalignedSignedAdic(exponents[i], 4)
+		_ = DecomposeUnalignedSignedAdic(exponents[i], 4)
 	}
 }
 
@@ -158,25 +158,25 @@ func TestGLV(t *testing.T) {
 	}
 }
 
-func test_decomposition_correctness(x *glvExponent, decomposition []decompositionCoefficient) bool {
+func test_decomposition_correctness(x *glvExponent, decomposition []DecompositionCoefficient) bool {
 	var xBigInt *big.Int = x.ToBigInt()
 	var accumulator *big.Int = big.NewInt(0)
 	var toAdd *big.Int = big.NewInt(0)
 	for _, comp := range decomposition {
-		toAdd.SetUint64(uint64(comp.coeff))
-		toAdd.Lsh(toAdd, comp.position)
-		if comp.sign == 1 {
+		toAdd.SetUint64(uint64(comp.Coeff))
+		toAdd.Lsh(toAdd, comp.Position)
+		if comp.Sign == 1 {
 			accumulator.Add(accumulator, toAdd)
-		} else if comp.sign == -1 {
+		} else if comp.Sign == -1 {
 			accumulator.Sub(accumulator, toAdd)
 		} else {
-			panic("decompositionCoefficient::sign not +/- 1")
+			panic("DecompositionCoefficient::Sign not +/- 1")
 		}
 	}
 	return accumulator.Cmp(xBigInt) == 0 // This is true iff x and accumulator hold the same value
 }
 
-// TestDecomposition checks correctness of decomposeUnalignedSignedAdic
+// TestDecomposition checks correctness of DecomposeUnalignedSignedAdic
 func TestDecomposition(t *testing.T) {
 	const iterations = 10000
 	var drng *rand.Rand = rand.New(rand.NewSource(141152))
@@ -194,7 +194,7 @@ func TestDecomposition(t *testing.T) {
 		var x glvExponent
 		x.SetBigInt(x_Int)
 
-		decomp := decomposeUnalignedSignedAdic(x, 5)
+		decomp := DecomposeUnalignedSignedAdic(x, 5)
 		// fmt.Println(i)
 		// fmt.Println(decomp)
 		// fmt.Printf("%b\n", x)
@@ -204,3 +204,84 @@ func TestDecomposition(t *testing.T) {
 	}
 
 }
+
+// TestGLVConstantTime tests whether GLV_representation_ConstantTime(n) outputs values u,v satisfying n = u+EndoEV * v with |u|,|v| < 2^127
+func TestGLVConstantTime(t *testing.T) {
+	const iterations = 10000
+	var exponent *big.Int = big.NewInt(0)
+	var exponent_ScalarField Exponent
+	var temp *big.Int = big.NewInt(0)
+	var temp2 *big.Int = big.NewInt(0)
+	var bound *big.Int = big.NewInt(0)
+	bound.Lsh(big.NewInt(1), 127)
+
+	var drng *rand.Rand = rand.New(rand.NewSource(141152))
+	for i := 0; i < iterations; i++ {
+		switch {
+		case i < 64:
+			exponent.SetInt64(int64(i - 32))
+		case i < 128:
+			exponent.SetInt64(int64(i - 96))
+			exponent.Add(exponent, GroupOrder_Int)
+		default:
+			exponent.Rand(drng, CurveExponent_Int)
+		}
+		exponent_ScalarField.SetBigInt(exponent)
+		glv := GLV_representation_ConstantTime(&exponent_ScalarField)
+		var u *big.Int = glv.U.ToBigInt()
+		var v *big.Int = glv.V.ToBigInt()
+
+		temp.Sub(u, exponent)
+		temp2.Mul(v, EndomorphismEigenvalue_Int)
+		temp.Add(temp, temp2)
+		temp.Mod(temp, GroupOrder_Int)
+		if temp.Sign() != 0 {
+			t.Fatalf("GLV_representation_ConstantTime does not output pair of exponents that gives correct result for exponent %v", exponent)
+		}
+		if u.CmpAbs(bound) >= 0 || v.CmpAbs(bound) >= 0 {
+			t.Fatalf("GLV_representation_ConstantTime outputs too large pair of exponents for exponent %v", exponent)
+		}
+	}
+}
+
+// TestDecompositionFixedWindow checks correctness of DecomposeSignedFixedWindow
+func TestDecompositionFixedWindow(t *testing.T) {
+	const iterations = 2000
+	var drng *rand.Rand = rand.New(rand.NewSource(141152))
+	var bigrange *big.Int = big.NewInt(0)
+	bigrange.Set(twoTo128_Int)
+	for i := 0; i < iterations; i++ {
+		var x_Int *big.Int = big.NewInt(0)
+		switch {
+		case i < 32:
+			x_Int.SetInt64(int64(i - 16))
+		case i < 64:
+			x_Int.SetInt64(int64(i - 31))
+			x_Int.Sub(bigrange, x_Int) // values slightly below 2^128
+		default:
+			x_Int.Rand(drng, bigrange)
+		}
+		if i%2 == 1 {
+			x_Int.Neg(x_Int)
+		}
+		var x glvExponent
+		x.SetBigInt(x_Int)
+		for windowSize := uint(1); windowSize <= 16; windowSize++ {
+			digits := DecomposeSignedFixedWindow(x, windowSize)
+			if uint(len(digits)) != (128+windowSize-1)/windowSize+1 {
+				t.Fatalf("DecomposeSignedFixedWindow output unexpected number of digits for window size %v", windowSize)
+			}
+			var accumulator *big.Int = big.NewInt(0)
+			for j := len(digits) - 1; j >= 0; j-- {
+				if digits[j] > 1<<(windowSize-1) || digits[j] < -(1<<(windowSize-1)) {
+					t.Fatalf("DecomposeSignedFixedWindow output digit %v out of range for window size %v", digits[j], windowSize)
+				}
+				accumulator.Lsh(accumulator, windowSize)
+				accumulator.Add(accumulator, big.NewInt(int64(digits[j])))
+			}
+			if accumulator.Cmp(x.ToBigInt()) != 0 {
+				t.Fatalf("DecomposeSignedFixedWindow does not work with x==%v and window size %v. Digits were %v", x_Int, windowSize, digits)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package exponents
+
+import (
+	"crypto"
+	_ "crypto/sha512" // links in SHA-512 for defaultHashToExponentExpander
+	"math/bits"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+)
+
+// This file contains functions to derive exponents from arbitrary byte strings without (noticeable) modular bias.
+// This is the analogue of hash_to_field from RFC 9380 for the scalar field GF(p253).
+
+// HashToExponentBytesPerElement is the number L of uniform bytes used to derive an exponent.
+//
+// This is L = ceil((ceil(log2(p253)) + k) / 8) in the notation of RFC 9380 for security parameter k = 128, giving a statistical distance to uniform of about 2^-128.
+const HashToExponentBytesPerElement = 48
+
+// defaultHashToExponentExpander is the [common.MessageExpander] used by HashToExponent, namely expand_message_xmd with SHA-512.
+var defaultHashToExponentExpander = common.ExpanderXMD{Hash: crypto.SHA512}
+
+// HashToExponent derives an exponent from msg with domain separation tag dst.
+//
+// The output is (indistinguishable from) uniform modulo p253. It is computed by reducing HashToExponentBytesPerElement many bytes of
+// expand_message_xmd with SHA-512 (interpreted as big-endian integer) modulo p253.
+// Note that this means the output is always in the range [0, p253), even though Exponent can store values modulo 2*p253.
+//
+// dst must be non-empty and should be unique to the application and use. We return an error if dst is empty.
+func HashToExponent(msg []byte, dst []byte) (Exponent, error) {
+	return HashToExponentWithExpander(defaultHashToExponentExpander, msg, dst)
+}
+
+// HashToExponentWithExpander is the same as [HashToExponent], but uses the given [common.MessageExpander] (e.g. expand_message_xof).
+func HashToExponentWithExpander(expander common.MessageExpander, msg []byte, dst []byte) (ret Exponent, err error) {
+	uniformBytes, err := expander.ExpandMessage(msg, dst, HashToExponentBytesPerElement)
+	if err != nil {
+		return
+	}
+	ret.setFromUniformBytes(uniformBytes)
+	return
+}
+
+// setFromUniformBytes sets z to the big-endian integer encoded in buf, reduced modulo p253.
+//
+// buf can have arbitrary length. We process the input bit-by-bit (MSB first) by computing z := 2*z + bit mod p253,
+// using a branch-free conditional subtraction. This is slow-ish, but simple and constant-time (in the content of buf).
+func (z *Exponent) setFromUniformBytes(buf []byte) {
+	var acc [4]uint64 // invariant: acc < p253 < 2^253
+	for _, b := range buf {
+		for i := 7; i >= 0; i-- {
+			bit := uint64(b>>uint(i)) & 1
+			// acc = 2*acc + bit. This cannot overflow, since acc < 2^253.
+			acc[3] = (acc[3] << 1) | (acc[2] >> 63)
+			acc[2] = (acc[2] << 1) | (acc[1] >> 63)
+			acc[1] = (acc[1] << 1) | (acc[0] >> 63)
+			acc[0] = (acc[0] << 1) | bit
+
+			// subtract p253 if acc >= p253
+			var reduced [4]uint64
+			var borrow uint64
+			reduced[0], borrow = bits.Sub64(acc[0], groupOrder_0, 0)
+			reduced[1], borrow = bits.Sub64(acc[1], groupOrder_1, borrow)
+			reduced[2], borrow = bits.Sub64(acc[2], groupOrder_2, borrow)
+			reduced[3], borrow = bits.Sub64(acc[3], groupOrder_3, borrow)
+			// borrow == 1 iff acc < p253, in which case we keep acc.
+			mask := borrow - 1 // all-ones iff acc >= p253
+			acc[0] = (acc[0] &^ mask) | (reduced[0] & mask)
+			acc[1] = (acc[1] &^ mask) | (reduced[1] & mask)
+			acc[2] = (acc[2] &^ mask) | (reduced[2] & mask)
+			acc[3] = (acc[3] &^ mask) | (reduced[3] & mask)
+		}
+	}
+	z.value = acc
+}
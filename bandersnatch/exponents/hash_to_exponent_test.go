@@ -0,0 +1,65 @@
+package exponents
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// TestExponentSetFromUniformBytes compares the wide reduction of uniform bytes modulo p253 against big.Int arithmetic.
+func TestExponentSetFromUniformBytes(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		buf := make([]byte, HashToExponentBytesPerElement)
+		drng.Read(buf)
+		switch i {
+		case 0: // all-zero
+			buf = make([]byte, HashToExponentBytesPerElement)
+		case 1: // all-one
+			for j := range buf {
+				buf[j] = 0xFF
+			}
+		case 2: // exactly p253
+			buf = GroupOrder_Int.FillBytes(make([]byte, 32))
+		case 3: // empty
+			buf = []byte{}
+		case 4: // long input
+			buf = make([]byte, 100)
+			drng.Read(buf)
+		}
+		var z Exponent
+		z.setFromUniformBytes(buf)
+		expected := new(big.Int).SetBytes(buf)
+		expected.Mod(expected, GroupOrder_Int)
+		testutils.FatalUnless(t, z.ToBigInt_Full().Cmp(expected) == 0, "setFromUniformBytes differs from big.Int computation")
+	}
+}
+
+func TestHashToExponent(t *testing.T) {
+	msg := []byte("abc")
+	dst := []byte("DST")
+	z, err := HashToExponent(msg, dst)
+	testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+	uniformBytes, _ := common.ExpandMessageXMD(crypto.SHA512, msg, dst, HashToExponentBytesPerElement)
+	expected := new(big.Int).SetBytes(uniformBytes)
+	expected.Mod(expected, GroupOrder_Int)
+	testutils.FatalUnless(t, z.ToBigInt_Full().Cmp(expected) == 0, "HashToExponent differs from big.Int computation")
+
+	z2, err := HashToExponentWithExpander(common.ExpanderXMD{Hash: crypto.SHA256}, msg, dst)
+	testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+	uniformBytes, _ = common.ExpandMessageXMD(crypto.SHA256, msg, dst, HashToExponentBytesPerElement)
+	expected.SetBytes(uniformBytes)
+	expected.Mod(expected, GroupOrder_Int)
+	testutils.FatalUnless(t, z2.ToBigInt_Full().Cmp(expected) == 0, "HashToExponentWithExpander differs from big.Int computation")
+
+	z2, _ = HashToExponent(msg, []byte("DST2"))
+	testutils.FatalUnless(t, z != z2, "HashToExponent ignores DST")
+	_, err = HashToExponent(msg, []byte{})
+	testutils.FatalUnless(t, errors.Is(err, common.ErrEmptyDST), "HashToExponent did not fail for empty DST")
+}
@@ -0,0 +1,241 @@
+package exponents
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/internal/utils"
+)
+
+// This file contains the ScalarField type, which represents elements of the scalar field GF(p253) of the prime-order subgroup.
+//
+// Contrary to Exponent, which works modulo 2*p253, the internal value of a ScalarField is always the unique representative in 0 <= . < p253.
+// Consequently, all operations (including Add, Sub, Neg) work modulo p253 and equal field elements are equal as Go values (i.e. under ==).
+// This is the type to use for field arithmetic in signature or commitment schemes, where identities such as x * (1/x) == 1 need to hold exactly.
+//
+// Multiplication uses Montgomery multiplication modulo p253 (see exponent_field.go). Add, Sub, Neg and Mul run in constant time; use SetExponent resp. Exponent to convert from resp. to Exponent.
+
+// ScalarField is an element of the field GF(p253). The zero value is the zero element.
+type ScalarField struct {
+	value [4]uint64 // low-endian, always in 0 <= . < p253
+}
+
+// SetExponent sets z to x modulo p253.
+func (z *ScalarField) SetExponent(x *Exponent) {
+	z.value = x.ModuloP253().value
+}
+
+// Exponent returns z as an Exponent. The result is fully reduced, i.e. in 0 <= . < p253.
+func (z *ScalarField) Exponent() Exponent {
+	return Exponent{value: z.value}
+}
+
+// SetZero sets z to 0.
+func (z *ScalarField) SetZero() {
+	z.value = [4]uint64{}
+}
+
+// SetOne sets z to 1.
+func (z *ScalarField) SetOne() {
+	z.value = [4]uint64{1, 0, 0, 0}
+}
+
+// SetUInt sets z to x modulo p253.
+func (z *ScalarField) SetUInt(x uint64) {
+	z.value = [4]uint64{x, 0, 0, 0} // x < 2^64 < p253
+}
+
+// SetInt sets z to x modulo p253.
+func (z *ScalarField) SetInt(x int64) {
+	var temp Exponent
+	temp.SetInt(x)
+	z.SetExponent(&temp)
+}
+
+// SetBigInt sets z to x modulo p253. x may be negative or larger than p253.
+func (z *ScalarField) SetBigInt(x *big.Int) {
+	var xReduced *big.Int = new(big.Int).Mod(x, GroupOrder_Int) // is in 0 <= . < p253, even if x is negative
+	z.value = utils.BigIntToUIntArray(xReduced)
+}
+
+// ToBigInt returns z as a *big.Int in the range 0 <= . < p253.
+func (z *ScalarField) ToBigInt() *big.Int {
+	return utils.UIntarrayToInt(&z.value)
+}
+
+// SetRandom sets z to a uniformly random element of GF(p253), using randomness read from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// The output distribution is the same as for Exponent.SetRandom. If reading from rnd fails, we return an error (wrapping the error from rnd) and z is unchanged.
+func (z *ScalarField) SetRandom(rnd io.Reader) error {
+	var temp Exponent
+	if err := temp.SetRandom(rnd); err != nil {
+		return err
+	}
+	z.SetExponent(&temp)
+	return nil
+}
+
+// String is provided to satisfy the fmt.Stringer interface. Note that this is defined on value receivers for convenience.
+func (z ScalarField) String() string {
+	return z.ToBigInt().String()
+}
+
+// Format is provided to satisfy the fmt.Formatter interface. Note that this is defined on value receivers for convenience.
+func (z ScalarField) Format(s fmt.State, ch rune) {
+	z.ToBigInt().Format(s, ch)
+}
+
+// IsZero checks whether z == 0.
+func (z *ScalarField) IsZero() bool {
+	return z.value == [4]uint64{}
+}
+
+// IsOne checks whether z == 1.
+func (z *ScalarField) IsOne() bool {
+	return z.value == [4]uint64{1, 0, 0, 0}
+}
+
+// IsEqual checks whether z == x. This is equivalent to *z == *x.
+func (z *ScalarField) IsEqual(x *ScalarField) bool {
+	return z.value == x.value
+}
+
+// Add computes z = x + y (modulo p253).
+//
+// The running time does not depend on the values of x and y.
+func (z *ScalarField) Add(x, y *ScalarField) {
+	// x + y < 2*p253 < 2^256, so this does not overflow.
+	var sum [4]uint64
+	var carry uint64
+	sum[0], carry = bits.Add64(x.value[0], y.value[0], 0)
+	sum[1], carry = bits.Add64(x.value[1], y.value[1], carry)
+	sum[2], carry = bits.Add64(x.value[2], y.value[2], carry)
+	sum[3], _ = bits.Add64(x.value[3], y.value[3], carry)
+
+	// subtract p253 unless this underflows, selecting the result via bit-masking rather than with an if.
+	var borrow uint64
+	z.value[0], borrow = bits.Sub64(sum[0], groupOrder_0, 0)
+	z.value[1], borrow = bits.Sub64(sum[1], groupOrder_1, borrow)
+	z.value[2], borrow = bits.Sub64(sum[2], groupOrder_2, borrow)
+	z.value[3], borrow = bits.Sub64(sum[3], groupOrder_3, borrow)
+	mask := -borrow // all-ones if sum < p253, i.e. we must keep sum.
+	z.value[0] ^= (z.value[0] ^ sum[0]) & mask
+	z.value[1] ^= (z.value[1] ^ sum[1]) & mask
+	z.value[2] ^= (z.value[2] ^ sum[2]) & mask
+	z.value[3] ^= (z.value[3] ^ sum[3]) & mask
+}
+
+// Sub computes z = x - y (modulo p253).
+//
+// The running time does not depend on the values of x and y.
+func (z *ScalarField) Sub(x, y *ScalarField) {
+	var borrow uint64
+	z.value[0], borrow = bits.Sub64(x.value[0], y.value[0], 0)
+	z.value[1], borrow = bits.Sub64(x.value[1], y.value[1], borrow)
+	z.value[2], borrow = bits.Sub64(x.value[2], y.value[2], borrow)
+	z.value[3], borrow = bits.Sub64(x.value[3], y.value[3], borrow)
+
+	// add p253 back if we underflowed, selecting via bit-masking rather than with an if.
+	mask := -borrow
+	var carry uint64
+	z.value[0], carry = bits.Add64(z.value[0], groupOrder_0&mask, 0)
+	z.value[1], carry = bits.Add64(z.value[1], groupOrder_1&mask, carry)
+	z.value[2], carry = bits.Add64(z.value[2], groupOrder_2&mask, carry)
+	z.value[3], _ = bits.Add64(z.value[3], groupOrder_3&mask, carry)
+}
+
+// Neg computes z = -x (modulo p253).
+//
+// The running time does not depend on the value of x.
+func (z *ScalarField) Neg(x *ScalarField) {
+	var zero ScalarField
+	z.Sub(&zero, x)
+}
+
+// Mul computes z = x * y (modulo p253).
+//
+// The running time does not depend on the values of x and y.
+func (z *ScalarField) Mul(x, y *ScalarField) {
+	z.value = mulModP253(&x.value, &y.value)
+}
+
+// Square computes z = x * x (modulo p253).
+func (z *ScalarField) Square(x *ScalarField) {
+	z.Mul(x, x)
+}
+
+// Inv computes z = 1/x (modulo p253). If x == 0, we panic with ErrDivisionByZero.
+//
+// The running time does not depend on x.
+func (z *ScalarField) Inv(x *ScalarField) {
+	if x.IsZero() {
+		panic(ErrDivisionByZero)
+	}
+	z.value = invModP253(&x.value)
+}
+
+// InvEq replaces z by its multiplicative inverse. z.InvEq() is equivalent to z.Inv(&z).
+func (z *ScalarField) InvEq() {
+	z.Inv(z)
+}
+
+// Divide computes z = num/denom (modulo p253). If denom == 0, we panic with ErrDivisionByZero.
+func (z *ScalarField) Divide(num, denom *ScalarField) {
+	if denom.IsZero() {
+		panic(ErrDivisionByZero)
+	}
+	denomInv := invModP253(&denom.value)
+	z.value = mulModP253(&num.value, &denomInv)
+}
+
+// Exp computes z = base^exponent (modulo p253), with 0^0 == 1.
+// Negative exponents are allowed and mean exponentiation of the inverse; in that case, we panic with ErrDivisionByZero if base == 0.
+//
+// NOTE: The running time depends on exponent (but not on base).
+func (z *ScalarField) Exp(base *ScalarField, exponent *big.Int) {
+	temp := base.Exponent()
+	temp.Exp(&temp, exponent)
+	z.value = temp.value // Exponent.Exp returns fully reduced results.
+}
+
+// ScalarFieldMultiInvertEqSlice replaces every element in args by its multiplicative inverse, using Montgomery's batch inversion trick.
+//
+// If any argument is zero, we return an error wrapping ErrDivisionByZero without modifying any of the args.
+func ScalarFieldMultiInvertEqSlice(args []ScalarField) error {
+	// The internal representation is shared with Exponent and MultiInvertEq returns fully reduced results.
+	asExponents := make([]Exponent, len(args))
+	for i := range args {
+		asExponents[i] = args[i].Exponent()
+	}
+	if err := MultiInvertEqSlice(asExponents); err != nil {
+		return err
+	}
+	for i := range args {
+		args[i].value = asExponents[i].value
+	}
+	return nil
+}
+
+// ToCanonicalBytes returns the 32-byte representation of z. byteOrder determines the order of the output bytes.
+//
+// This is the same format as Exponent.ToCanonicalBytes.
+func (z *ScalarField) ToCanonicalBytes(byteOrder common.FieldElementEndianness) (ret [ExponentBytesLength]byte) {
+	byteOrder.PutUint256_array(&ret, &z.value)
+	return
+}
+
+// SetCanonicalBytes sets z from the 32-byte slice buf written by ToCanonicalBytes with the same byteOrder.
+//
+// We return an error wrapping ErrExponentBytesLength if buf does not have length ExponentBytesLength and an error wrapping ErrNonCanonicalExponent if buf encodes a number >= p253.
+// On error, z is unchanged.
+func (z *ScalarField) SetCanonicalBytes(buf []byte, byteOrder common.FieldElementEndianness) error {
+	var temp Exponent
+	if err := temp.SetCanonicalBytes(buf, byteOrder); err != nil {
+		return err
+	}
+	z.value = temp.value
+	return nil
+}
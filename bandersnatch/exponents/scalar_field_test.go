@@ -0,0 +1,162 @@
+package exponents
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// sampleScalarFieldForTest returns a list of field elements for testing, obtained by reducing the output of sampleExponentsForTest.
+func sampleScalarFieldForTest(drng *rand.Rand, num int) (ret []ScalarField) {
+	exps := sampleExponentsForTest(drng, num)
+	ret = make([]ScalarField, len(exps))
+	for i := range exps {
+		ret[i].SetExponent(&exps[i])
+	}
+	return
+}
+
+func TestScalarFieldArithmetic(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1000))
+	xs := sampleScalarFieldForTest(drng, 50)
+	ys := sampleScalarFieldForTest(drng, 50)
+	var zero, one ScalarField
+	one.SetOne()
+	for _, x := range xs {
+		testutils.FatalUnless(t, x.ToBigInt().Cmp(GroupOrder_Int) < 0, "ScalarField is not fully reduced")
+
+		var negX, sum ScalarField
+		negX.Neg(&x)
+		sum.Add(&x, &negX)
+		testutils.FatalUnless(t, sum == zero, "x + (-x) != 0 for %v", x)
+
+		if !x.IsZero() {
+			var xInv, prod ScalarField
+			xInv.Inv(&x)
+			prod.Mul(&x, &xInv)
+			testutils.FatalUnless(t, prod == one, "x * (1/x) != 1 for %v", x)
+			testutils.FatalUnless(t, prod.ToCanonicalBytes(common.LittleEndian) == one.ToCanonicalBytes(common.LittleEndian), "x * (1/x) != 1 as bytes for %v", x)
+
+			var fermat ScalarField
+			fermat.Exp(&x, new(big.Int).Sub(GroupOrder_Int, big.NewInt(1)))
+			testutils.FatalUnless(t, fermat == one, "x^(p253-1) != 1 for %v", x)
+			fermat.Exp(&x, big.NewInt(-1))
+			testutils.FatalUnless(t, fermat == xInv, "x^-1 != 1/x for %v", x)
+		}
+
+		for _, y := range ys {
+			xInt, yInt := x.ToBigInt(), y.ToBigInt()
+			var z ScalarField
+			expected := new(big.Int)
+
+			z.Add(&x, &y)
+			expected.Add(xInt, yInt)
+			expected.Mod(expected, GroupOrder_Int)
+			testutils.FatalUnless(t, z.ToBigInt().Cmp(expected) == 0, "Add does not match big.Int computation for %v, %v", x, y)
+			z.Sub(&z, &y)
+			testutils.FatalUnless(t, z == x, "(x + y) - y != x for %v, %v", x, y)
+
+			z.Sub(&x, &y)
+			expected.Sub(xInt, yInt)
+			expected.Mod(expected, GroupOrder_Int)
+			testutils.FatalUnless(t, z.ToBigInt().Cmp(expected) == 0, "Sub does not match big.Int computation for %v, %v", x, y)
+
+			z.Mul(&x, &y)
+			expected.Mul(xInt, yInt)
+			expected.Mod(expected, GroupOrder_Int)
+			testutils.FatalUnless(t, z.ToBigInt().Cmp(expected) == 0, "Mul does not match big.Int computation for %v, %v", x, y)
+			if !y.IsZero() {
+				z.Divide(&z, &y)
+				testutils.FatalUnless(t, z == x, "(x * y) / y != x for %v, %v", x, y)
+			}
+		}
+	}
+
+	testutils.FatalUnless(t, testutils.CheckPanic(zero.InvEq), "Inverting 0 did not panic")
+	testutils.FatalUnless(t, testutils.CheckPanic(one.Divide, &one, &zero), "Division by 0 did not panic")
+}
+
+func TestScalarFieldExponentConversion(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1000))
+	xs := sampleExponentsForTest(drng, 50)
+	ys := sampleExponentsForTest(drng, 50)
+	for _, x := range xs {
+		var xField ScalarField
+		xField.SetExponent(&x)
+		back := xField.Exponent()
+		testutils.FatalUnless(t, back.IsEqual(&x) && back == x.ModuloP253(), "Conversion to ScalarField and back failed for %v", x)
+		for _, y := range ys {
+			var yField, zField, expected ScalarField
+			yField.SetExponent(&y)
+			var sum, prod Exponent
+			sum.Add(&x, &y)
+			prod.Mul(&x, &y)
+			zField.Add(&xField, &yField)
+			expected.SetExponent(&sum)
+			testutils.FatalUnless(t, zField == expected, "Add is not compatible with Exponent.Add for %v, %v", x, y)
+			zField.Mul(&xField, &yField)
+			expected.SetExponent(&prod)
+			testutils.FatalUnless(t, zField == expected, "Mul is not compatible with Exponent.Mul for %v, %v", x, y)
+		}
+	}
+
+	var a, b ScalarField
+	a.SetInt(-1)
+	b.SetBigInt(new(big.Int).Sub(GroupOrder_Int, big.NewInt(1)))
+	testutils.FatalUnless(t, a == b, "SetInt(-1) != p253 - 1")
+	b.SetUInt(5)
+	a.SetBigInt(new(big.Int).Add(GroupOrder_Int, big.NewInt(5)))
+	testutils.FatalUnless(t, a == b, "SetBigInt does not reduce modulo p253")
+}
+
+func TestScalarFieldMultiInvert(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1000))
+	var xs []ScalarField
+	for _, x := range sampleScalarFieldForTest(drng, 20) {
+		if !x.IsZero() {
+			xs = append(xs, x)
+		}
+	}
+	expected := make([]ScalarField, len(xs))
+	for i := range xs {
+		expected[i].Inv(&xs[i])
+	}
+	err := ScalarFieldMultiInvertEqSlice(xs)
+	testutils.FatalUnless(t, err == nil, "ScalarFieldMultiInvertEqSlice failed: %v", err)
+	for i := range xs {
+		testutils.FatalUnless(t, xs[i] == expected[i], "ScalarFieldMultiInvertEqSlice differs from Inv at index %v", i)
+	}
+
+	xs[3].SetZero()
+	old := make([]ScalarField, len(xs))
+	copy(old, xs)
+	err = ScalarFieldMultiInvertEqSlice(xs)
+	testutils.FatalUnless(t, errors.Is(err, ErrDivisionByZero), "ScalarFieldMultiInvertEqSlice did not report zero")
+	for i := range xs {
+		testutils.FatalUnless(t, xs[i] == old[i], "ScalarFieldMultiInvertEqSlice modified args on error")
+	}
+}
+
+func TestScalarFieldSerialization(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1000))
+	for _, byteOrder := range []common.FieldElementEndianness{common.LittleEndian, common.BigEndian} {
+		for _, x := range sampleScalarFieldForTest(drng, 50) {
+			buf := x.ToCanonicalBytes(byteOrder)
+			var y ScalarField
+			err := y.SetCanonicalBytes(buf[:], byteOrder)
+			testutils.FatalUnless(t, err == nil && y == x, "Serialization roundtrip failed for %v", x)
+		}
+		var buf [ExponentBytesLength]byte
+		p253 := p253Words
+		byteOrder.PutUint256_array(&buf, &p253)
+		var y ScalarField
+		err := y.SetCanonicalBytes(buf[:], byteOrder)
+		testutils.FatalUnless(t, errors.Is(err, ErrNonCanonicalExponent), "SetCanonicalBytes accepted p253")
+		err = y.SetCanonicalBytes(buf[1:], byteOrder)
+		testutils.FatalUnless(t, errors.Is(err, ErrExponentBytesLength), "SetCanonicalBytes accepted wrong length")
+	}
+}
@@ -20,6 +20,7 @@ import (
 func BenchmarkAllFieldElementTypes(b *testing.B) {
 	b.Log("NOTE: Benchmarking all field element implementations via generic benchmark. Being generic means some overhead. Take note if timings from non-generic benchmarks deviate.")
 	b.Run("MontgomeryNonUnique", benchmarkFE_all[bsFieldElement_MontgomeryNonUnique])
+	b.Run("MontgomeryUnique", benchmarkFE_all[bsFieldElement_MontgomeryUnique])
 	b.Run("big.Int Wrapper", benchmarkFE_all[bsFieldElement_BigInt])
 }
 
@@ -46,6 +47,7 @@ func benchmarkFE_all[FE any, FEPtr interface {
 
 	b.Run("Inv", benchmarkFE_Inv[FE, FEPtr])
 	b.Run("InvEq", benchmarkFE_InvEq[FE, FEPtr])
+	b.Run("InvConstantTime", benchmarkFE_InvConstantTime[FE, FEPtr])
 	b.Run("Divide", benchmarkFE_Divide[FE, FEPtr])
 	b.Run("DivideEq", benchmarkFE_DivideEq[FE, FEPtr])
 
@@ -57,6 +59,7 @@ func benchmarkFE_all[FE any, FEPtr interface {
 	// We have two separate benchmarks for the SquareRoot method, called only on squares or only on non-squares.
 	b.Run("SquareRoot (Squares)", benchmarkFE_SquareRootOnSquares[FE, FEPtr])
 	b.Run("SquareRoot (NonSquares)", benchmarkFE_SquareRootOnNonSquares[FE, FEPtr])
+	b.Run("SquareRootConstantTime", benchmarkFE_SquareRootConstantTime[FE, FEPtr])
 
 	b.Run("SetUint256", benchmarkFE_SetUint256[FE, FEPtr])
 	b.Run("ToUint256", benchmarkFE_ToUint256[FE, FEPtr])
@@ -269,6 +272,18 @@ func benchmarkFE_InvEq[FE any, FEPtr interface {
 	}
 }
 
+func benchmarkFE_InvConstantTime[FE any, FEPtr interface {
+	*FE
+	FieldElementInterface[FEPtr]
+}](b *testing.B) {
+	var bench_x []FE = GetPrecomputedFieldElementsNonZero[FE, FEPtr](10001, benchS)
+	var res [benchS]FE
+	prepareBenchmarkFieldElements(b)
+	for n := 0; n < b.N; n++ {
+		FEPtr(&res[n%benchS]).InvConstantTime(&bench_x[n%benchS])
+	}
+}
+
 func benchmarkFE_Divide[FE any, FEPtr interface {
 	*FE
 	FieldElementInterface[FEPtr]
@@ -356,6 +371,18 @@ func benchmarkFE_SquareRootOnSquares[FE any, FEPtr interface {
 	}
 }
 
+func benchmarkFE_SquareRootConstantTime[FE any, FEPtr interface {
+	*FE
+	FieldElementInterface[FEPtr]
+}](b *testing.B) {
+	var bench_x []FE = GetPrecomputedFieldElements[FE, FEPtr](10001, benchS)
+	var res [benchS]FE
+	prepareBenchmarkFieldElements(b)
+	for n := 0; n < b.N; n++ {
+		DumpBools_fe[n%benchS] = FEPtr(&res[n%benchS]).SquareRootConstantTime(&bench_x[n%benchS])
+	}
+}
+
 func benchmarkFE_SquareRootOnNonSquares[FE any, FEPtr interface {
 	*FE
 	FieldElementInterface[FEPtr]
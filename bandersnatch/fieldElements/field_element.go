@@ -42,6 +42,7 @@ type FieldElementInterface_common interface {
 	DoubleEq()  // z.DoubleEq() sets z = z + z == 2*z
 
 	SetRandomUnsafe(rnd *rand.Rand) // DEPRECATED
+	SetRandom(rnd io.Reader) error  // z.SetRandom(rnd) sets z to a uniformly random field element, reading randomness from rnd (crypto/rand.Reader if rnd == nil). Returns an error if reading from rnd fails.
 
 	fmt.Formatter // allows formatted output of field elements. -- Note that fmt.Formatter should be defined on value receivers TODO: Specify minimal accepted format verbs
 	fmt.Stringer  // allows output as string. -- Note that fmt.Stringer (i.e interface{String() string}) should be defined on value receivers.
@@ -103,6 +104,16 @@ type FieldElementInterface[FieldElementPointer any] interface {
 	DivideUint64(x FieldElementPointer, y uint64) // z.DivideIUint64(&x, y) performs z = x / y, where y is an uint64
 
 	Exp(base FieldElementPointer, exponent *Uint256) // z.Exp(&base,&exponent) performs z = base^exponent. Note that we only support exponent >=0 for simplicity. 0^0 == 1.
+
+	// Constant-time operations for secret data: The running time and memory access pattern of these is guaranteed not to depend on the values of the arguments
+	// (except for the return value of SquareRootConstantTime) for our main field element implementation. cond must be 0 or 1.
+	// Note that the methods above (notably Inv, SquareRoot, Exp and Jacobi) branch on the values of their arguments.
+	CondAssign(cond int, x FieldElementPointer)                  // z.CondAssign(cond, &x) sets z = x if cond == 1 and leaves z unchanged if cond == 0
+	CondSwap(cond int, x FieldElementPointer)                    // z.CondSwap(cond, &x) swaps z and x if cond == 1 and leaves both unchanged if cond == 0
+	CondNeg(cond int, x FieldElementPointer)                     // z.CondNeg(cond, &x) sets z = -x if cond == 1 and z = x if cond == 0
+	InvConstantTime(x FieldElementPointer)                       // z.InvConstantTime(&x) performs z = 1/x. For x == 0, we set z = 0 (rather than panic)
+	SquareRootConstantTime(x FieldElementPointer) bool           // z.SquareRootConstantTime(&x) behaves like SquareRoot
+	ExpConstantTime(base FieldElementPointer, exponent *Uint256) // z.ExpConstantTime(&base, &exponent) performs z = base^exponent with 0^0 == 1.
 }
 
 // TODO: MulInt64, MulUint64, DivideInt64, DivideUint64 not really optimized for main field element implementation at the moment.\
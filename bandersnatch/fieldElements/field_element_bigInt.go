@@ -3,9 +3,11 @@ package fieldElements
 import (
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math/big"
 	"math/rand"
 
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
 	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/errorsWithData"
 )
 
@@ -218,6 +220,16 @@ func (z *bsFieldElement_BigInt) SetRandomUnsafe(rnd *rand.Rand) {
 	z.SetBigInt(zInt)
 }
 
+func (z *bsFieldElement_BigInt) SetRandom(rnd io.Reader) error {
+	var buf [HashToFieldBytesPerElement]byte
+	if err := common.ReadRandomBytes(rnd, buf[:]); err != nil {
+		return err
+	}
+	zInt := new(big.Int).SetBytes(buf[:])
+	z.SetBigInt(zInt)
+	return nil
+}
+
 func (z *bsFieldElement_BigInt) Divide(x, y *bsFieldElement_BigInt) {
 	var yInv bsFieldElement_BigInt
 	yInv.Inv(y)
@@ -324,3 +336,42 @@ func (z *bsFieldElement_BigInt) Exp(base *bsFieldElement_BigInt, exponent *Uint2
 	baseInt.Exp(baseInt, exponentInt, baseFieldSize_Int)
 	z.SetBigInt(baseInt)
 }
+
+// NOTE: The following methods provide the semantics of the constant-time operations of the FieldElementInterface for differential testing.
+// Since they go through *big.Int, they are NOT actually constant-time.
+
+func (z *bsFieldElement_BigInt) CondAssign(cond int, x *bsFieldElement_BigInt) {
+	if cond == 1 {
+		*z = *x
+	}
+}
+
+func (z *bsFieldElement_BigInt) CondSwap(cond int, x *bsFieldElement_BigInt) {
+	if cond == 1 {
+		*z, *x = *x, *z
+	}
+}
+
+func (z *bsFieldElement_BigInt) CondNeg(cond int, x *bsFieldElement_BigInt) {
+	if cond == 1 {
+		z.Neg(x)
+	} else {
+		*z = *x
+	}
+}
+
+func (z *bsFieldElement_BigInt) InvConstantTime(x *bsFieldElement_BigInt) {
+	if x.IsZero() {
+		z.SetZero()
+	} else {
+		z.Inv(x)
+	}
+}
+
+func (z *bsFieldElement_BigInt) SquareRootConstantTime(x *bsFieldElement_BigInt) bool {
+	return z.SquareRoot(x)
+}
+
+func (z *bsFieldElement_BigInt) ExpConstantTime(base *bsFieldElement_BigInt, exponent *Uint256) {
+	z.Exp(base, exponent)
+}
@@ -105,6 +105,10 @@ var (
 	tonelliShanksExponent_uint256         = Uint256{tonelliShanksExponent_64_0, tonelliShanksExponent_64_1, tonelliShanksExponent_64_2, tonelliShanksExponent_64_3}
 )
 
+// fermatInverseExponent_uint256 is BaseFieldSize - 2. By Fermat's little theorem, x^(BaseFieldSize-2) == 1/x for x != 0.
+// This is used for constant-time inversion.
+var fermatInverseExponent_uint256 = Uint256{baseFieldSize_0 - 2, baseFieldSize_1, baseFieldSize_2, baseFieldSize_3}
+
 /***************************
  	uint 256 - related constants
 *****************************/
@@ -13,6 +13,8 @@ import (
 // runs differential tests for our field element implementations.
 func TestDifferentialFieldElements(t *testing.T) {
 	testFEDifferential[bsFieldElement_MontgomeryNonUnique, bsFieldElement_BigInt](10001, 10001, 10001, 1000, 100, 100)(t)
+	testFEDifferential[bsFieldElement_MontgomeryUnique, bsFieldElement_BigInt](10001, 10001, 10001, 1000, 100, 100)(t)
+	testFEDifferential[bsFieldElement_MontgomeryUnique, bsFieldElement_MontgomeryNonUnique](10001, 10001, 10001, 1000, 100, 100)(t)
 }
 
 // utility stuff to iterate over methods (and their names) in a type-safe way (i.e. without using reflection).
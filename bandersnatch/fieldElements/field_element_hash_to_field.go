@@ -0,0 +1,75 @@
+package fieldElements
+
+import (
+	"crypto"
+	_ "crypto/sha512" // links in SHA-512 for defaultHashToFieldExpander
+	"encoding/binary"
+	"fmt"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+)
+
+// This file is part of the fieldElements package. See the documentation of field_element.go for general remarks.
+
+// This file contains hash_to_field from RFC 9380, Section 5.2, which derives field elements from arbitrary byte strings without (noticeable) modular bias.
+// Note that hash_to_field (unlike hash_to_curve) is not specific to the curve; we follow the RFC's choice of parameters for the base field of Bandersnatch.
+
+// HashToFieldBytesPerElement is the number L of uniform bytes that hash_to_field uses per field element.
+//
+// This is L = ceil((ceil(log2(BaseFieldSize)) + k) / 8) from RFC 9380 for security parameter k = 128, giving a statistical distance to uniform of about 2^-128.
+const HashToFieldBytesPerElement = 48
+
+// defaultHashToFieldExpander is the [common.MessageExpander] used by HashToFieldElements, namely expand_message_xmd with SHA-512.
+var defaultHashToFieldExpander = common.ExpanderXMD{Hash: crypto.SHA512}
+
+// HashToFieldElements derives count many field elements from msg with domain separation tag dst.
+//
+// This is hash_to_field from RFC 9380, Section 5.2 using expand_message_xmd with SHA-512 and L = HashToFieldBytesPerElement bytes per field element;
+// the output is (indistinguishable from) uniform and independent for each distinct (msg, dst) pair.
+// Use [HashToFieldElementsWithExpander] to choose a different expand_message, e.g. expand_message_xof.
+//
+// dst must be non-empty and should be unique to the application and use. We return an error if dst is empty or count is too large (more than 340).
+func HashToFieldElements(msg []byte, dst []byte, count int) ([]FieldElement, error) {
+	return HashToFieldElementsWithExpander(defaultHashToFieldExpander, msg, dst, count)
+}
+
+// HashToFieldElementsWithExpander implements hash_to_field from RFC 9380, Section 5.2 with the given [common.MessageExpander].
+//
+// It returns count many field elements, derived from msg with domain separation tag dst. We return an error if expander does.
+func HashToFieldElementsWithExpander(expander common.MessageExpander, msg []byte, dst []byte, count int) ([]FieldElement, error) {
+	if count < 0 {
+		panic(fmt.Errorf(ErrorPrefix+"HashToFieldElementsWithExpander called with negative count %v", count))
+	}
+	uniformBytes, err := expander.ExpandMessage(msg, dst, count*HashToFieldBytesPerElement)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]FieldElement, count)
+	for i := range ret {
+		ret[i].setFromUniformBytes(uniformBytes[i*HashToFieldBytesPerElement : (i+1)*HashToFieldBytesPerElement])
+	}
+	return ret, nil
+}
+
+// setFromUniformBytes sets z to the big-endian integer encoded in buf, reduced modulo BaseFieldSize (i.e. OS2IP(buf) mod p in the notation of RFC 9380).
+//
+// buf must have length at most 64 and should have length HashToFieldBytesPerElement.
+func (z *bsFieldElement_MontgomeryNonUnique) setFromUniformBytes(buf []byte) {
+	var wide Uint512 = uint512FromBigEndianBytes(buf)
+	var reduced Uint256
+	reduced.ReduceUint512ToUint256_a(wide)
+	z.SetUint256(&reduced)
+}
+
+// uint512FromBigEndianBytes interprets buf as a big-endian integer. buf must have length at most 64.
+func uint512FromBigEndianBytes(buf []byte) (ret Uint512) {
+	if len(buf) > 64 {
+		panic(fmt.Errorf(ErrorPrefix+"uint512FromBigEndianBytes called with buffer of length %v > 64", len(buf)))
+	}
+	var padded [64]byte
+	copy(padded[64-len(buf):], buf)
+	for i := 0; i < 8; i++ {
+		ret[i] = binary.BigEndian.Uint64(padded[64-8*(i+1) : 64-8*i])
+	}
+	return
+}
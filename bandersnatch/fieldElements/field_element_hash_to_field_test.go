@@ -0,0 +1,77 @@
+package fieldElements
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// TestSetFromUniformBytes compares the reduction of uniform bytes to field elements against big.Int arithmetic.
+func TestSetFromUniformBytes(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		buf := make([]byte, HashToFieldBytesPerElement)
+		drng.Read(buf)
+		switch i {
+		case 0: // all-zero
+			buf = make([]byte, HashToFieldBytesPerElement)
+		case 1: // all-one
+			for j := range buf {
+				buf[j] = 0xFF
+			}
+		case 2: // 64 bytes
+			buf = make([]byte, 64)
+			drng.Read(buf)
+		}
+		var fe FieldElement
+		fe.setFromUniformBytes(buf)
+		expected := new(big.Int).SetBytes(buf)
+		expected.Mod(expected, baseFieldSize_Int)
+		testutils.FatalUnless(t, fe.ToBigInt().Cmp(expected) == 0, "setFromUniformBytes differs from big.Int computation")
+	}
+}
+
+func TestHashToFieldElementsWithExpander(t *testing.T) {
+	expander := common.ExpanderXMD{Hash: crypto.SHA256}
+	msg := []byte("abc")
+	dst := []byte("DST")
+	fes, err := HashToFieldElementsWithExpander(expander, msg, dst, 3)
+	testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+	testutils.FatalUnless(t, len(fes) == 3, "Wrong number of field elements")
+	uniformBytes, _ := common.ExpandMessageXMD(crypto.SHA256, msg, dst, 3*HashToFieldBytesPerElement)
+	for i := 0; i < 3; i++ {
+		expected := new(big.Int).SetBytes(uniformBytes[i*HashToFieldBytesPerElement : (i+1)*HashToFieldBytesPerElement])
+		expected.Mod(expected, baseFieldSize_Int)
+		testutils.FatalUnless(t, fes[i].ToBigInt().Cmp(expected) == 0, "HashToFieldElementsWithExpander differs from big.Int computation")
+	}
+	_, err = HashToFieldElementsWithExpander(expander, msg, []byte{}, 3)
+	testutils.FatalUnless(t, err != nil, "HashToFieldElementsWithExpander did not report error from expander")
+}
+
+func TestHashToFieldElements(t *testing.T) {
+	msg := []byte("abc")
+	dst := []byte("DST")
+	fes, err := HashToFieldElements(msg, dst, 2)
+	testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+	expected, _ := HashToFieldElementsWithExpander(common.ExpanderXMD{Hash: crypto.SHA512}, msg, dst, 2)
+	testutils.FatalUnless(t, fes[0].IsEqual(&expected[0]) && fes[1].IsEqual(&expected[1]), "HashToFieldElements does not use expand_message_xmd with SHA-512")
+	testutils.FatalUnless(t, !fes[0].IsEqual(&fes[1]), "HashToFieldElements gives identical outputs")
+	fes2, _ := HashToFieldElements(msg, []byte("DST2"), 2)
+	testutils.FatalUnless(t, !fes[0].IsEqual(&fes2[0]), "HashToFieldElements ignores DST")
+
+	fes, err = HashToFieldElements(msg, dst, 0)
+	testutils.FatalUnless(t, err == nil && len(fes) == 0, "HashToFieldElements failed for count 0")
+	_, err = HashToFieldElements(msg, dst, 340)
+	testutils.FatalUnless(t, err == nil, "HashToFieldElements failed for count 340")
+	_, err = HashToFieldElements(msg, dst, 341)
+	testutils.FatalUnless(t, errors.Is(err, common.ErrExpandMessageLength), "HashToFieldElements did not fail for count 341")
+	_, err = HashToFieldElements(msg, []byte{}, 1)
+	testutils.FatalUnless(t, errors.Is(err, common.ErrEmptyDST), "HashToFieldElements did not fail for empty DST")
+	testutils.FatalUnless(t, testutils.CheckPanic(HashToFieldElements, msg, dst, -1), "HashToFieldElements did not panic for negative count")
+}
@@ -3,10 +3,12 @@ package fieldElements
 import (
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math/big"
 	"math/bits"
 	"math/rand"
 
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
 	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/errorsWithData"
 	"github.com/GottfriedHerold/Bandersnatch/internal/callcounters"
 	"github.com/GottfriedHerold/Bandersnatch/internal/utils"
@@ -167,10 +169,12 @@ var _ = callcounters.CreateAttachedCallCounter("SubFromNeg", "Subtractions calle
 // Neg computes the additive inverse (i.e. -x)
 //
 // Use z.Neg(&x) to set z = -x
+//
+// The running time does not depend on x, since Sub (via SubAndReduce_c) selects its correction term by masking rather than branching. CondNeg relies on this.
 func (z *bsFieldElement_MontgomeryNonUnique) Neg(x *bsFieldElement_MontgomeryNonUnique) {
 	IncrementCallCounter("NegFe")
 	// IncrementCallCounter("SubFromNeg") -- done automatically
-	z.Sub(&bsFieldElement_64_zero_alt, x) // using alt here makes the if borrow!=0 in Sub unlikely.
+	z.Sub(&bsFieldElement_64_zero_alt, x) // any representation of zero works here; Sub is branch-free either way.
 
 }
 
@@ -207,6 +211,50 @@ func (z *bsFieldElement_MontgomeryNonUnique) SetZero() {
 	z.words = Uint256{}
 }
 
+// CondAssign sets z = x if cond == 1 and leaves z unchanged if cond == 0. Other values of cond are not allowed.
+//
+// The running time and memory access pattern do not depend on cond or on the values of z and x.
+// This is intended for constant-time algorithms (e.g. table lookups with secret indices).
+func (z *bsFieldElement_MontgomeryNonUnique) CondAssign(cond int, x *bsFieldElement_MontgomeryNonUnique) {
+	mask := -uint64(cond)
+	z.words[0] ^= mask & (z.words[0] ^ x.words[0])
+	z.words[1] ^= mask & (z.words[1] ^ x.words[1])
+	z.words[2] ^= mask & (z.words[2] ^ x.words[2])
+	z.words[3] ^= mask & (z.words[3] ^ x.words[3])
+}
+
+// CondSwap swaps z and x if cond == 1 and leaves both unchanged if cond == 0. Other values of cond are not allowed.
+//
+// The running time and memory access pattern do not depend on cond or on the values of z and x.
+func (z *bsFieldElement_MontgomeryNonUnique) CondSwap(cond int, x *bsFieldElement_MontgomeryNonUnique) {
+	mask := -uint64(cond)
+	for i := 0; i < 4; i++ {
+		diff := mask & (z.words[i] ^ x.words[i])
+		z.words[i] ^= diff
+		x.words[i] ^= diff
+	}
+}
+
+// CondNeg sets z = -x if cond == 1 and z = x if cond == 0. Other values of cond are not allowed.
+//
+// The running time and memory access pattern do not depend on cond or on the value of x.
+func (z *bsFieldElement_MontgomeryNonUnique) CondNeg(cond int, x *bsFieldElement_MontgomeryNonUnique) {
+	var negated bsFieldElement_MontgomeryNonUnique
+	negated.Neg(x) // Neg is constant-time for our type.
+	*z = *x
+	z.CondAssign(cond, &negated)
+}
+
+// isEqual_constantTime returns 1 if z == x (as field elements) and 0 otherwise.
+//
+// Unlike IsEqual, the running time does not depend on the values of z and x and neither z nor x is modified.
+func (z *bsFieldElement_MontgomeryNonUnique) isEqual_constantTime(x *bsFieldElement_MontgomeryNonUnique) int {
+	zWords, xWords := z.words, x.words
+	zWords.reduce_fb_constantTime()
+	xWords.reduce_fb_constantTime()
+	return int(zWords.isEqual_constantTime(&xWords))
+}
+
 // ToBigInt returns a *big.Int that stores a representation of (a copy of) the given field element.
 func (z *bsFieldElement_MontgomeryNonUnique) ToBigInt() *big.Int {
 	temp := z.words.ToNonMontgomery_fc()
@@ -337,6 +385,19 @@ func (z *bsFieldElement_MontgomeryNonUnique) SetRandomUnsafe(rnd *rand.Rand) {
 	z.SetBigInt(xInt)
 }
 
+// SetRandom sets z to a uniformly random field element, using randomness read from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// We read HashToFieldBytesPerElement many bytes and reduce them modulo BaseFieldSize, so the output distribution is statistically close (distance < 2^-128) to uniform.
+// If reading from rnd fails, we return an error (wrapping the error from rnd) and z is unchanged.
+func (z *bsFieldElement_MontgomeryNonUnique) SetRandom(rnd io.Reader) error {
+	var buf [HashToFieldBytesPerElement]byte
+	if err := common.ReadRandomBytes(rnd, buf[:]); err != nil {
+		return err
+	}
+	z.setFromUniformBytes(buf[:])
+	return nil
+}
+
 // SetUint256 sets the field element z from the Uint256 x. Note that we do not ask for x to be in the [0, BaseFieldSize) range; we reduce as needed.
 func (z *bsFieldElement_MontgomeryNonUnique) SetUint256(x *Uint256) {
 	z.words = *x
@@ -447,6 +508,16 @@ func (z *bsFieldElement_MontgomeryNonUnique) Inv(x *bsFieldElement_MontgomeryNon
 	z.SetBigInt(t)
 }
 
+// InvConstantTime computes the multiplicative inverse in constant time.
+//
+// z.InvConstantTime(&x) performs z := 1/x. Unlike Inv, this does not panic for x == 0; rather, we set z = 0 in that case.
+// The running time and memory access pattern do not depend on the value of x.
+// This is much slower than Inv and only intended for secret x.
+func (z *bsFieldElement_MontgomeryNonUnique) InvConstantTime(x *bsFieldElement_MontgomeryNonUnique) {
+	// By Fermat's little theorem, x^(BaseFieldSize-2) == 1/x for x != 0. For x == 0, this gives 0.
+	z.words.ModularExponentiationMontgomeryConstantTime_fa(&x.words, &fermatInverseExponent_uint256)
+}
+
 var _ = callcounters.CreateAttachedCallCounter("InvFromDivide", "Inversion in Divide", "InvFe").
 	AddToThisFromSource("DivideFe", +1).
 	AddThisToTarget("Divisions", -1)
@@ -531,6 +602,45 @@ func (z *bsFieldElement_MontgomeryNonUnique) SquareRoot(x *bsFieldElement_Montgo
 
 }
 
+// SquareRootConstantTime computes a square root in the field in constant time.
+//
+// Use ok := z.SquareRootConstantTime(&x). As for SquareRoot, if x is not a square, the return value is false and z is untouched.
+// The running time and memory access pattern do not depend on the value of x (apart from the return value, which tells whether x is a square).
+// This is slower than SquareRoot and only intended for secret x.
+func (z *bsFieldElement_MontgomeryNonUnique) SquareRootConstantTime(x *bsFieldElement_MontgomeryNonUnique) (ok bool) {
+	// We use the constant-time variant of Tonelli-Shanks from RFC 9380, Appendix I.4.
+	// Write BaseFieldSize - 1 == 2^BaseField2Adicity * q with q odd. Starting with candidate == x^((q+1)/2) and t == x^q (which is a 2^BaseField2Adicity'th root of unity),
+	// we maintain the invariant candidate^2 == t * x. In each iteration, we multiply candidate by a suitable root of unity c (and t by c^2), such that the order of t decreases,
+	// but unlike SquareRoot, we always perform all multiplications and select the results via CondAssign.
+	// If x is a square, we end with t == 1. This takes about BaseField2Adicity^2/2 additional squarings compared to SquareRoot.
+	var candidate, t feType_SquareRoot
+	xCopy := *x
+	xCopy.sqrtAlg_ComputeRelevantPowers(&candidate, &t) // addition chain that only depends on the (public) exponents
+
+	var b, temp feType_SquareRoot
+	c := dyadicRootOfUnity_fe // primitive 2^BaseField2Adicity'th root of unity
+	for i := BaseField2Adicity; i >= 2; i-- {
+		// Invariant: t is a 2^(i-1)'th root of unity iff x is a square. c is a primitive 2^i'th root of unity.
+		b = t
+		for j := 0; j < i-2; j++ {
+			b.SquareEq()
+		}
+		// b == t^(2^(i-2)) is in {1,-1} if x is a square. If b == -1, we multiply t by c^2, which is a primitive 2^(i-1)'th root of unity.
+		notOne := 1 - b.isEqual_constantTime(&bsFieldElement_64_one)
+		temp.Mul(&candidate, &c)
+		candidate.CondAssign(notOne, &temp)
+		c.SquareEq()
+		temp.Mul(&t, &c)
+		t.CondAssign(notOne, &temp)
+	}
+
+	// x is a square iff candidate is a square root. Note that this also works for x == 0.
+	temp.Square(&candidate)
+	isSquare := temp.isEqual_constantTime(x)
+	z.CondAssign(isSquare, &candidate)
+	return isSquare == 1
+}
+
 // Format is provided to satisfy the fmt.Formatter interface. Note that this is defined on value receivers.
 // We internally convert to big.Int and hence support the same formats as big.Int.
 func (z bsFieldElement_MontgomeryNonUnique) Format(s fmt.State, ch rune) {
@@ -797,3 +907,11 @@ func (z *bsFieldElement_MontgomeryNonUnique) DivideUint64(x *bsFieldElement_Mont
 func (z *bsFieldElement_MontgomeryNonUnique) Exp(base *bsFieldElement_MontgomeryNonUnique, exponent *Uint256) {
 	z.words.ModularExponentiationMontgomery_fa(&base.words, exponent)
 }
+
+// z.ExpConstantTime computes z := base^exponent in the field, with 0^0 == 1.
+//
+// Unlike Exp, the running time and memory access pattern do not depend on the values of base and exponent.
+// This is intended for secret base and/or exponent.
+func (z *bsFieldElement_MontgomeryNonUnique) ExpConstantTime(base *bsFieldElement_MontgomeryNonUnique, exponent *Uint256) {
+	z.words.ModularExponentiationMontgomeryConstantTime_fa(&base.words, exponent)
+}
@@ -0,0 +1,502 @@
+package fieldElements
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/errorsWithData"
+)
+
+// This file is part of the fieldElements package. See the documentation of field_element.go for general remarks.
+
+// This file gives an implementation of the FieldElementInterface using a Montgomery representation
+// WITH uniqueness of the internal representation: After every operation, the internal representation is fully reduced.
+//
+// The advantage over [bsFieldElement_MontgomeryNonUnique] is that the default == operator works, internal bytes (as obtained by ToBytes) are canonical
+// and field elements can be used as keys in maps without calling Normalize first.
+// Read-only methods never modify the internal representation, so sharing such field elements between goroutines for reading is safe.
+// The price is an extra (cheap) reduction after each arithmetic operation.
+//
+// The arithmetic itself is shared with bsFieldElement_MontgomeryNonUnique: The basic operations work directly on the internal Uint256 and
+// all other methods convert to bsFieldElement_MontgomeryNonUnique (which is free, since the internal representation is a valid one for that type as well).
+
+type bsFieldElement_MontgomeryUnique struct {
+	// field elements stored in low-endian 64-bit uints in Montgomery form, i.e.
+	// words encodes a field element x if words - x * 2^256 == 0 (mod BaseFieldSize).
+	//
+	// We maintain the invariant
+	//
+	// ********************************************
+	// *                                          *
+	// *     0 <= words < BaseFieldSize           *
+	// *                                          *
+	// ********************************************
+	//
+	// so the representation is unique. Note that we deliberately do NOT embed utils.MakeIncomparable, since == is meaningful for this type.
+	words Uint256
+}
+
+// nonUnique returns z as a bsFieldElement_MontgomeryNonUnique. This is free, as our internal representation is valid for that type.
+func (z *bsFieldElement_MontgomeryUnique) nonUnique() (ret bsFieldElement_MontgomeryNonUnique) {
+	ret.words = z.words
+	return
+}
+
+// setNonUnique sets z from a bsFieldElement_MontgomeryNonUnique, restoring our invariant.
+func (z *bsFieldElement_MontgomeryUnique) setNonUnique(x *bsFieldElement_MontgomeryNonUnique) {
+	z.words = x.words
+	z.words.Reduce_fb()
+}
+
+// unaryOp sets z = op(x) for an operation op of bsFieldElement_MontgomeryNonUnique.
+func (z *bsFieldElement_MontgomeryUnique) unaryOp(op func(*bsFieldElement_MontgomeryNonUnique, *bsFieldElement_MontgomeryNonUnique), x *bsFieldElement_MontgomeryUnique) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	op(&result, &xNonUnique)
+	z.setNonUnique(&result)
+}
+
+// binaryOp sets z = op(x, y) for an operation op of bsFieldElement_MontgomeryNonUnique.
+func (z *bsFieldElement_MontgomeryUnique) binaryOp(op func(*bsFieldElement_MontgomeryNonUnique, *bsFieldElement_MontgomeryNonUnique, *bsFieldElement_MontgomeryNonUnique), x, y *bsFieldElement_MontgomeryUnique) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique, yNonUnique := x.nonUnique(), y.nonUnique()
+	op(&result, &xNonUnique, &yNonUnique)
+	z.setNonUnique(&result)
+}
+
+// Normalize does nothing, since the internal representation is always unique. It is provided to satisfy the FieldElementInterface.
+func (z *bsFieldElement_MontgomeryUnique) Normalize() {
+	// do nothing
+}
+
+// RerandomizeRepresentation does nothing, since the internal representation is unique. It is provided to satisfy the FieldElementInterface.
+func (z *bsFieldElement_MontgomeryUnique) RerandomizeRepresentation(seed uint64) {
+	// do nothing
+}
+
+// Add is used to perform addition.
+//
+// Use z.Add(&x, &y) to add x + y and store the result in z.
+func (z *bsFieldElement_MontgomeryUnique) Add(x, y *bsFieldElement_MontgomeryUnique) {
+	IncrementCallCounter("AddFe")
+	z.words.addAndReduce_b_c(&x.words, &y.words)
+	z.words.Reduce_fb()
+}
+
+// Sub is used to perform subtraction.
+//
+// Use z.Sub(&x, &y) to compute x - y and store the result in z.
+func (z *bsFieldElement_MontgomeryUnique) Sub(x, y *bsFieldElement_MontgomeryUnique) {
+	IncrementCallCounter("SubFe")
+	z.words.SubAndReduce_c(&x.words, &y.words)
+	z.words.Reduce_fb()
+}
+
+// Mul computes multiplication in the field.
+//
+// Use z.Mul(&x, &y) to set z = x * y
+func (z *bsFieldElement_MontgomeryUnique) Mul(x, y *bsFieldElement_MontgomeryUnique) {
+	IncrementCallCounter("MulFe")
+	z.words.mulMontgomery_Unrolled_c(&x.words, &y.words)
+	z.words.Reduce_fb()
+}
+
+// Square squares the field element, computing z = x * x
+//
+// z.Square(&x) is equivalent to z.Mul(&x, &x)
+func (z *bsFieldElement_MontgomeryUnique) Square(x *bsFieldElement_MontgomeryUnique) {
+	IncrementCallCounter("Squarings")
+	z.words.mulMontgomery_Unrolled_c(&x.words, &x.words)
+	z.words.Reduce_fb()
+}
+
+// Divide performs division: z.Divide(num, denom) means z = num/denom
+//
+// Division by zero causes a panic.
+func (z *bsFieldElement_MontgomeryUnique) Divide(num, denom *bsFieldElement_MontgomeryUnique) {
+	z.binaryOp((*bsFieldElement_MontgomeryNonUnique).Divide, num, denom)
+}
+
+// Double computes z = 2*x == x + x
+func (z *bsFieldElement_MontgomeryUnique) Double(x *bsFieldElement_MontgomeryUnique) {
+	z.unaryOp((*bsFieldElement_MontgomeryNonUnique).Double, x)
+}
+
+// Neg computes the additive inverse, i.e. z.Neg(&x) sets z = -x
+func (z *bsFieldElement_MontgomeryUnique) Neg(x *bsFieldElement_MontgomeryUnique) {
+	z.unaryOp((*bsFieldElement_MontgomeryNonUnique).Neg, x)
+}
+
+// Inv computes the multiplicative inverse, i.e. z.Inv(&x) sets z = 1/x. We panic if x is zero.
+func (z *bsFieldElement_MontgomeryUnique) Inv(x *bsFieldElement_MontgomeryUnique) {
+	z.unaryOp((*bsFieldElement_MontgomeryNonUnique).Inv, x)
+}
+
+// MulFive computes z = 5*x.
+func (z *bsFieldElement_MontgomeryUnique) MulFive(x *bsFieldElement_MontgomeryUnique) {
+	z.unaryOp((*bsFieldElement_MontgomeryNonUnique).MulFive, x)
+}
+
+// SquareRoot computes a square root of x, i.e. ok := z.SquareRoot(&x) sets z such that z*z == x.
+//
+// If x is not a square, the return value is false and z is untouched.
+func (z *bsFieldElement_MontgomeryUnique) SquareRoot(x *bsFieldElement_MontgomeryUnique) (ok bool) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	if ok = result.SquareRoot(&xNonUnique); ok {
+		z.setNonUnique(&result)
+	}
+	return
+}
+
+// Exp computes z = base^exponent. 0^0 == 1.
+func (z *bsFieldElement_MontgomeryUnique) Exp(base *bsFieldElement_MontgomeryUnique, exponent *Uint256) {
+	var result bsFieldElement_MontgomeryNonUnique
+	baseNonUnique := base.nonUnique()
+	result.Exp(&baseNonUnique, exponent)
+	z.setNonUnique(&result)
+}
+
+// setNonUnique_constantTime sets z from a bsFieldElement_MontgomeryNonUnique, restoring our invariant.
+//
+// Unlike setNonUnique, the running time does not depend on x.
+func (z *bsFieldElement_MontgomeryUnique) setNonUnique_constantTime(x *bsFieldElement_MontgomeryNonUnique) {
+	z.words = x.words
+	z.words.reduce_fb_constantTime()
+}
+
+// CondAssign sets z = x if cond == 1 and leaves z unchanged if cond == 0. Other values of cond are not allowed.
+//
+// The running time and memory access pattern do not depend on cond or on the values of z and x.
+func (z *bsFieldElement_MontgomeryUnique) CondAssign(cond int, x *bsFieldElement_MontgomeryUnique) {
+	z.words.condAssign_constantTime(uint64(cond), &x.words)
+}
+
+// CondSwap swaps z and x if cond == 1 and leaves both unchanged if cond == 0. Other values of cond are not allowed.
+//
+// The running time and memory access pattern do not depend on cond or on the values of z and x.
+func (z *bsFieldElement_MontgomeryUnique) CondSwap(cond int, x *bsFieldElement_MontgomeryUnique) {
+	zNonUnique, xNonUnique := z.nonUnique(), x.nonUnique()
+	zNonUnique.CondSwap(cond, &xNonUnique)
+	z.words, x.words = zNonUnique.words, xNonUnique.words // no reduction needed, as we only swapped.
+}
+
+// CondNeg sets z = -x if cond == 1 and z = x if cond == 0. Other values of cond are not allowed.
+//
+// The running time and memory access pattern do not depend on cond or on the value of x.
+func (z *bsFieldElement_MontgomeryUnique) CondNeg(cond int, x *bsFieldElement_MontgomeryUnique) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	result.CondNeg(cond, &xNonUnique)
+	z.setNonUnique_constantTime(&result)
+}
+
+// InvConstantTime computes the multiplicative inverse in constant time, i.e. z.InvConstantTime(&x) sets z = 1/x. For x == 0, we set z = 0.
+func (z *bsFieldElement_MontgomeryUnique) InvConstantTime(x *bsFieldElement_MontgomeryUnique) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	result.InvConstantTime(&xNonUnique)
+	z.setNonUnique_constantTime(&result)
+}
+
+// SquareRootConstantTime computes a square root of x in constant time. See SquareRoot for the semantics.
+func (z *bsFieldElement_MontgomeryUnique) SquareRootConstantTime(x *bsFieldElement_MontgomeryUnique) (ok bool) {
+	result := z.nonUnique()
+	xNonUnique := x.nonUnique()
+	ok = result.SquareRootConstantTime(&xNonUnique) // leaves result unchanged if x is not a square
+	z.setNonUnique_constantTime(&result)
+	return
+}
+
+// ExpConstantTime computes z = base^exponent in constant time. 0^0 == 1.
+func (z *bsFieldElement_MontgomeryUnique) ExpConstantTime(base *bsFieldElement_MontgomeryUnique, exponent *Uint256) {
+	var result bsFieldElement_MontgomeryNonUnique
+	baseNonUnique := base.nonUnique()
+	result.ExpConstantTime(&baseNonUnique, exponent)
+	z.setNonUnique_constantTime(&result)
+}
+
+// AddEq implements +=, i.e. z.AddEq(&y) is equivalent to z.Add(&z, &y)
+func (z *bsFieldElement_MontgomeryUnique) AddEq(y *bsFieldElement_MontgomeryUnique) {
+	z.Add(z, y)
+}
+
+// SubEq implements -=, i.e. z.SubEq(&y) is equivalent to z.Sub(&z, &y)
+func (z *bsFieldElement_MontgomeryUnique) SubEq(y *bsFieldElement_MontgomeryUnique) {
+	z.Sub(z, y)
+}
+
+// MulEq implements *=, i.e. z.MulEq(&y) is equivalent to z.Mul(&z, &y)
+func (z *bsFieldElement_MontgomeryUnique) MulEq(y *bsFieldElement_MontgomeryUnique) {
+	z.Mul(z, y)
+}
+
+// DivideEq implements /=, i.e. z.DivideEq(&y) is equivalent to z.Divide(&z, &y). We panic if y is zero.
+func (z *bsFieldElement_MontgomeryUnique) DivideEq(y *bsFieldElement_MontgomeryUnique) {
+	z.Divide(z, y)
+}
+
+// SquareEq replaces z by its square, i.e. z.SquareEq() is equivalent to z.Square(&z)
+func (z *bsFieldElement_MontgomeryUnique) SquareEq() {
+	z.Square(z)
+}
+
+// NegEq replaces z by -z.
+func (z *bsFieldElement_MontgomeryUnique) NegEq() {
+	z.Neg(z)
+}
+
+// InvEq replaces z by 1/z. We panic if z is zero.
+func (z *bsFieldElement_MontgomeryUnique) InvEq() {
+	z.Inv(z)
+}
+
+// MulEqFive replaces z by 5*z.
+func (z *bsFieldElement_MontgomeryUnique) MulEqFive() {
+	z.MulFive(z)
+}
+
+// DoubleEq replaces z by 2*z.
+func (z *bsFieldElement_MontgomeryUnique) DoubleEq() {
+	z.Double(z)
+}
+
+// AddInt64 sets z = x + y, where y is an int64
+func (z *bsFieldElement_MontgomeryUnique) AddInt64(x *bsFieldElement_MontgomeryUnique, y int64) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	result.AddInt64(&xNonUnique, y)
+	z.setNonUnique(&result)
+}
+
+// AddUint64 sets z = x + y, where y is an uint64
+func (z *bsFieldElement_MontgomeryUnique) AddUint64(x *bsFieldElement_MontgomeryUnique, y uint64) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	result.AddUint64(&xNonUnique, y)
+	z.setNonUnique(&result)
+}
+
+// SubInt64 sets z = x - y, where y is an int64
+func (z *bsFieldElement_MontgomeryUnique) SubInt64(x *bsFieldElement_MontgomeryUnique, y int64) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	result.SubInt64(&xNonUnique, y)
+	z.setNonUnique(&result)
+}
+
+// SubUint64 sets z = x - y, where y is an uint64
+func (z *bsFieldElement_MontgomeryUnique) SubUint64(x *bsFieldElement_MontgomeryUnique, y uint64) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	result.SubUint64(&xNonUnique, y)
+	z.setNonUnique(&result)
+}
+
+// MulInt64 sets z = x * y, where y is an int64
+func (z *bsFieldElement_MontgomeryUnique) MulInt64(x *bsFieldElement_MontgomeryUnique, y int64) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	result.MulInt64(&xNonUnique, y)
+	z.setNonUnique(&result)
+}
+
+// MulUint64 sets z = x * y, where y is an uint64
+func (z *bsFieldElement_MontgomeryUnique) MulUint64(x *bsFieldElement_MontgomeryUnique, y uint64) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	result.MulUint64(&xNonUnique, y)
+	z.setNonUnique(&result)
+}
+
+// DivideInt64 sets z = x / y, where y is an int64. We panic if y == 0.
+func (z *bsFieldElement_MontgomeryUnique) DivideInt64(x *bsFieldElement_MontgomeryUnique, y int64) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	result.DivideInt64(&xNonUnique, y)
+	z.setNonUnique(&result)
+}
+
+// DivideUint64 sets z = x / y, where y is an uint64. We panic if y == 0.
+func (z *bsFieldElement_MontgomeryUnique) DivideUint64(x *bsFieldElement_MontgomeryUnique, y uint64) {
+	var result bsFieldElement_MontgomeryNonUnique
+	xNonUnique := x.nonUnique()
+	result.DivideUint64(&xNonUnique, y)
+	z.setNonUnique(&result)
+}
+
+// IsZero checks whether the field element is zero.
+func (z *bsFieldElement_MontgomeryUnique) IsZero() bool {
+	return z.words.IsZero()
+}
+
+// IsOne checks whether the field element is 1.
+func (z *bsFieldElement_MontgomeryUnique) IsOne() bool {
+	return z.words == bsFieldElement_64_one.words
+}
+
+// SetZero sets the field element to 0.
+func (z *bsFieldElement_MontgomeryUnique) SetZero() {
+	z.words = Uint256{}
+}
+
+// SetOne sets the field element to 1.
+func (z *bsFieldElement_MontgomeryUnique) SetOne() {
+	z.words = bsFieldElement_64_one.words
+}
+
+// IsEqual compares two field elements for equality, i.e. it checks whether z == x (mod BaseFieldSize).
+//
+// Due to the uniqueness of the representation, this is the same as *z == *x.
+func (z *bsFieldElement_MontgomeryUnique) IsEqual(x *bsFieldElement_MontgomeryUnique) bool {
+	return z.words == x.words
+}
+
+// CmpAbs compares the absolute values of two field elements: It checks whether z == +/- x (first return value) and whether z == x (second return value).
+func (z *bsFieldElement_MontgomeryUnique) CmpAbs(x *bsFieldElement_MontgomeryUnique) (absEqual bool, exactlyEqual bool) {
+	if z.words == x.words {
+		return true, true
+	}
+	var minusX bsFieldElement_MontgomeryUnique
+	minusX.Neg(x)
+	return z.words == minusX.words, false
+}
+
+// Sign outputs the "sign" of the field element, i.e. the sign of the integer representation of minimal absolute value. The return value is in {-1,0,+1}.
+func (z *bsFieldElement_MontgomeryUnique) Sign() int {
+	zNonUnique := z.nonUnique()
+	return zNonUnique.Sign()
+}
+
+// Jacobi computes the Legendre symbol of z, i.e. z.Jacobi() is +1 if z is a non-zero square, -1 if z is a non-square and 0 if z is zero.
+func (z *bsFieldElement_MontgomeryUnique) Jacobi() int {
+	zNonUnique := z.nonUnique()
+	return zNonUnique.Jacobi()
+}
+
+// ToBigInt returns a *big.Int that stores a representation of z in [0, BaseFieldSize).
+func (z *bsFieldElement_MontgomeryUnique) ToBigInt() *big.Int {
+	zNonUnique := z.nonUnique()
+	return zNonUnique.ToBigInt()
+}
+
+// SetBigInt converts from *big.Int to a field element. The input need not be reduced modulo the field size.
+func (z *bsFieldElement_MontgomeryUnique) SetBigInt(v *big.Int) {
+	var result bsFieldElement_MontgomeryNonUnique
+	result.SetBigInt(v)
+	z.setNonUnique(&result)
+}
+
+// ToUint256 sets x to the representation of z in [0, BaseFieldSize).
+func (z *bsFieldElement_MontgomeryUnique) ToUint256(x *Uint256) {
+	zNonUnique := z.nonUnique()
+	zNonUnique.ToUint256(x)
+}
+
+// SetUint256 sets z from the Uint256 x. The input need not be reduced modulo the field size.
+func (z *bsFieldElement_MontgomeryUnique) SetUint256(x *Uint256) {
+	var result bsFieldElement_MontgomeryNonUnique
+	result.SetUint256(x)
+	z.setNonUnique(&result)
+}
+
+// ToUint64 returns z as a uint64. If z cannot be represented by a uint64, we return an error wrapping [ErrCannotRepresentFieldElement].
+func (z *bsFieldElement_MontgomeryUnique) ToUint64() (uint64, error) {
+	zNonUnique := z.nonUnique()
+	result, err := zNonUnique.ToUint64()
+	if err != nil {
+		// The error from zNonUnique contains a bsFieldElement_MontgomeryNonUnique, so we need to create our own.
+		zCopy := *z
+		return 0, errorsWithData.NewErrorWithData_any_params(ErrCannotRepresentFieldElement, ErrorPrefix+"the field Element %v{FieldElement} cannot be represented as a uint64", "FieldElement", zCopy)
+	}
+	return result, nil
+}
+
+// SetUint64 sets z to the given value of type uint64.
+func (z *bsFieldElement_MontgomeryUnique) SetUint64(value uint64) {
+	var result bsFieldElement_MontgomeryNonUnique
+	result.SetUint64(value)
+	z.setNonUnique(&result)
+}
+
+// ToInt64 returns z as an int64. If z cannot be represented by an int64, we return an error wrapping [ErrCannotRepresentFieldElement].
+func (z *bsFieldElement_MontgomeryUnique) ToInt64() (int64, error) {
+	zNonUnique := z.nonUnique()
+	result, err := zNonUnique.ToInt64()
+	if err != nil {
+		// The error from zNonUnique contains a bsFieldElement_MontgomeryNonUnique, so we need to create our own.
+		zCopy := *z
+		return 0, errorsWithData.NewErrorWithData_any_params(ErrCannotRepresentFieldElement, ErrorPrefix+"the field Element %v{FieldElement} cannot be represented as an int64", "FieldElement", zCopy)
+	}
+	return result, nil
+}
+
+// SetInt64 sets z to the given value of type int64.
+func (z *bsFieldElement_MontgomeryUnique) SetInt64(value int64) {
+	var result bsFieldElement_MontgomeryNonUnique
+	result.SetInt64(value)
+	z.setNonUnique(&result)
+}
+
+// SetRandomUnsafe generates a random field element. This is used in unit-testing only.
+//
+// DEPRECATED
+func (z *bsFieldElement_MontgomeryUnique) SetRandomUnsafe(rnd *rand.Rand) {
+	var result bsFieldElement_MontgomeryNonUnique
+	result.SetRandomUnsafe(rnd)
+	z.setNonUnique(&result)
+}
+
+// SetRandom sets z to a uniformly random field element, using randomness read from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// If reading from rnd fails, we return an error (wrapping the error from rnd) and z is unchanged.
+func (z *bsFieldElement_MontgomeryUnique) SetRandom(rnd io.Reader) error {
+	var result bsFieldElement_MontgomeryNonUnique
+	if err := result.SetRandom(rnd); err != nil {
+		return err
+	}
+	z.setNonUnique(&result)
+	return nil
+}
+
+// Format is provided to satisfy the fmt.Formatter interface. Note that this is defined on value receivers.
+// We internally convert to big.Int and hence support the same formats as big.Int.
+func (z bsFieldElement_MontgomeryUnique) Format(s fmt.State, ch rune) {
+	z.ToBigInt().Format(s, ch)
+}
+
+// String is provided to satisfy the fmt.Stringer interface. Note that this is defined on a *value* receiver.
+func (z bsFieldElement_MontgomeryUnique) String() string {
+	return z.ToBigInt().String()
+}
+
+// ToBytes writes the internal representation of z to buf[0:32]. See [bsFieldElement_MontgomeryNonUnique.ToBytes] for caveats.
+//
+// Unlike for bsFieldElement_MontgomeryNonUnique, the output is unique for a given field element.
+func (z *bsFieldElement_MontgomeryUnique) ToBytes(buf []byte) {
+	binary.LittleEndian.PutUint64(buf[0:8], z.words[0])
+	binary.LittleEndian.PutUint64(buf[8:16], z.words[1])
+	binary.LittleEndian.PutUint64(buf[16:24], z.words[2])
+	binary.LittleEndian.PutUint64(buf[24:32], z.words[3])
+}
+
+// SetBytes sets z from buf[0:32] that was written by [ToBytes]. See [bsFieldElement_MontgomeryNonUnique.SetBytes] for caveats.
+//
+// We panic if buf does not contain a valid (i.e. fully reduced) internal representation.
+func (z *bsFieldElement_MontgomeryUnique) SetBytes(buf []byte) {
+	var words Uint256
+	words[0] = binary.LittleEndian.Uint64(buf[0:8])
+	words[1] = binary.LittleEndian.Uint64(buf[8:16])
+	words[2] = binary.LittleEndian.Uint64(buf[16:24])
+	words[3] = binary.LittleEndian.Uint64(buf[24:32])
+	if !words.is_fully_reduced() {
+		panic(ErrorPrefix + "SetBytes called on bsFieldElement_MontgomeryUnique with byte slice that is not a valid internal representation")
+	}
+	z.words = words
+}
+
+// BytesLength returns the size of the slice used by [ToBytes] and [SetBytes]
+func (z *bsFieldElement_MontgomeryUnique) BytesLength() int { return 32 }
@@ -0,0 +1,77 @@
+package fieldElements
+
+import (
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// This file is part of the fieldElements package. See the documentation of field_element.go for general remarks.
+
+// This file contains tests specific to bsFieldElement_MontgomeryUnique. Generic tests are run via the differential test and testAllFieldElementProperties.
+
+// TestMontgomeryUniqueRepresentation checks that different ways of computing the same field element give identical internal representations,
+// so that == and map keys work.
+func TestMontgomeryUniqueRepresentation(t *testing.T) {
+	prepareTestFieldElements(t)
+	const num = 200
+	xs := GetPrecomputedFieldElements[bsFieldElement_MontgomeryUnique](10001, num)
+	ys := GetPrecomputedFieldElementsNonZero[bsFieldElement_MontgomeryUnique](10002, num)
+	var minusOne, zero bsFieldElement_MontgomeryUnique
+	minusOne.SetInt64(-1)
+
+	seen := make(map[bsFieldElement_MontgomeryUnique]int)
+	for i := range xs {
+		x, y := xs[i], ys[i]
+		testutils.FatalUnless(t, x.words.is_fully_reduced(), "precomputed element not fully reduced")
+		var sum, diff, prod, quot, negNeg bsFieldElement_MontgomeryUnique
+		sum.Add(&x, &y)
+		diff.Sub(&sum, &y)
+		prod.Mul(&x, &y)
+		quot.Divide(&prod, &y)
+		negNeg.Neg(&x)
+		negNeg.NegEq()
+		for _, result := range []bsFieldElement_MontgomeryUnique{diff, quot, negNeg} {
+			testutils.FatalUnless(t, result == x, "internal representation is not unique")
+			var buf1, buf2 [32]byte
+			result.ToBytes(buf1[:])
+			x.ToBytes(buf2[:])
+			testutils.FatalUnless(t, buf1 == buf2, "internal bytes are not unique")
+		}
+		var xMinusX bsFieldElement_MontgomeryUnique
+		xMinusX.Sub(&x, &x)
+		testutils.FatalUnless(t, xMinusX == zero, "x - x has non-zero representation")
+		var minusXPlusX bsFieldElement_MontgomeryUnique
+		minusXPlusX.Mul(&x, &minusOne)
+		minusXPlusX.AddEq(&x)
+		testutils.FatalUnless(t, minusXPlusX == zero, "-x + x has non-zero representation")
+
+		if previous, ok := seen[x]; ok {
+			testutils.FatalUnless(t, xs[previous].IsEqual(&x), "map lookup found different element")
+		}
+		seen[x] = i
+		testutils.FatalUnless(t, seen[diff] == i && seen[quot] == i, "map lookup with equal element failed")
+	}
+
+	// -1 + 1 == 0 is the case where the non-unique implementation may produce the alternative representation BaseFieldSize of 0.
+	var one, result bsFieldElement_MontgomeryUnique
+	one.SetOne()
+	result.Add(&minusOne, &one)
+	testutils.FatalUnless(t, result == zero && result.IsZero(), "-1 + 1 != 0")
+	result.Sub(&one, &one)
+	testutils.FatalUnless(t, result == zero, "1 - 1 != 0")
+}
+
+func TestMontgomeryUniqueSetBytes(t *testing.T) {
+	var x bsFieldElement_MontgomeryUnique
+	x.SetInt64(-5)
+	buf := make([]byte, x.BytesLength())
+	x.ToBytes(buf)
+	var y bsFieldElement_MontgomeryUnique
+	y.SetBytes(buf)
+	testutils.FatalUnless(t, x == y, "ToBytes / SetBytes roundtrip failed")
+
+	// BaseFieldSize is a valid representation for bsFieldElement_MontgomeryNonUnique, but not for bsFieldElement_MontgomeryUnique
+	bsFieldElement_64_zero_alt.ToBytes(buf)
+	testutils.FatalUnless(t, testutils.CheckPanic(y.SetBytes, buf), "SetBytes did not panic for non-reduced input")
+}
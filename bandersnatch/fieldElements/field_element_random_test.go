@@ -0,0 +1,66 @@
+package fieldElements
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// TestSetRandom checks that SetRandom gives the same results for all implementations, handles errors and gives uniform-looking outputs.
+func TestSetRandom(t *testing.T) {
+	// Compare implementations on the same randomness
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		buf := make([]byte, HashToFieldBytesPerElement)
+		drng.Read(buf)
+		var x FieldElement
+		var y bsFieldElement_BigInt
+		errX := x.SetRandom(bytes.NewReader(buf))
+		errY := y.SetRandom(bytes.NewReader(buf))
+		testutils.FatalUnless(t, errX == nil && errY == nil, "Unexpected error")
+		testutils.FatalUnless(t, x.ToBigInt().Cmp(y.ToBigInt()) == 0, "SetRandom differs between implementations")
+	}
+
+	// default randomness source
+	var x, y FieldElement
+	testutils.FatalUnless(t, x.SetRandom(nil) == nil, "SetRandom(nil) failed")
+	testutils.FatalUnless(t, y.SetRandom(nil) == nil, "SetRandom(nil) failed")
+	testutils.FatalUnless(t, !x.IsEqual(&y), "SetRandom(nil) gave identical outputs")
+
+	// errors
+	designatedErr := errors.New("designated error")
+	y = x
+	err := x.SetRandom(iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "SetRandom did not report error")
+	testutils.FatalUnless(t, x.IsEqual(&y), "SetRandom modified receiver on error")
+	err = x.SetRandom(bytes.NewReader(make([]byte, HashToFieldBytesPerElement-1)))
+	testutils.FatalUnless(t, errors.Is(err, io.ErrUnexpectedEOF), "SetRandom did not report error on short read")
+}
+
+// TestSetRandomUniformity performs chi-squared tests on small projections of the output of SetRandom.
+func TestSetRandomUniformity(t *testing.T) {
+	const numBuckets = 16
+	const numSamples = 16000
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	var lowBits, highBits [numBuckets]int
+	var x FieldElement
+	var xInt Uint256
+	for i := 0; i < numSamples; i++ {
+		err := x.SetRandom(drng)
+		testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+		x.ToUint256(&xInt)
+		lowBits[xInt[0]%numBuckets]++
+		// bucket floor(x * numBuckets / BaseFieldSize)
+		bucket := new(big.Int).Mul(x.ToBigInt(), big.NewInt(numBuckets))
+		bucket.Div(bucket, baseFieldSize_Int)
+		highBits[bucket.Int64()]++
+	}
+	testutils.FatalUnless(t, testutils.ChiSquaredIsUniform(lowBits[:]), "Low bits of SetRandom fail chi-squared test: %v", lowBits)
+	testutils.FatalUnless(t, testutils.ChiSquaredIsUniform(highBits[:]), "High bits of SetRandom fail chi-squared test: %v", highBits)
+}
@@ -59,6 +59,7 @@ var (
 func TestFESerialization(t *testing.T) {
 	t.Run("MontgomeryRepresentation", testFESerialization_All[bsFieldElement_MontgomeryNonUnique])
 	t.Run("BigInt-Implementation", testFESerialization_All[bsFieldElement_BigInt])
+	t.Run("MontgomeryUnique-Implementation", testFESerialization_All[bsFieldElement_MontgomeryUnique])
 }
 
 // testFESerialization_All runs tests for the serialization methods for a given type satisfying the FieldElementInterface.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"math/rand"
 	"testing"
 
 	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
@@ -19,9 +20,13 @@ var _ FieldElementInterface[*bsFieldElement_MontgomeryNonUnique] = &bsFieldEleme
 var _ FieldElementInterface_common = &bsFieldElement_BigInt{}
 var _ FieldElementInterface[*bsFieldElement_BigInt] = &bsFieldElement_BigInt{}
 
+var _ FieldElementInterface_common = &bsFieldElement_MontgomeryUnique{}
+var _ FieldElementInterface[*bsFieldElement_MontgomeryUnique] = &bsFieldElement_MontgomeryUnique{}
+
 func TestFieldElementProperties(t *testing.T) {
 	t.Run("Montgomery implementation", testAllFieldElementProperties[bsFieldElement_MontgomeryNonUnique])
 	t.Run("trivial big.Int implementation", testAllFieldElementProperties[bsFieldElement_BigInt])
+	t.Run("Montgomery implementation with unique representation", testAllFieldElementProperties[bsFieldElement_MontgomeryUnique])
 }
 
 func testAllFieldElementProperties[FE any, FEPtr interface {
@@ -46,6 +51,7 @@ func testAllFieldElementProperties[FE any, FEPtr interface {
 	t.Run("Square root", testFEProperty_SquareRoot[FE, FEPtr](10001, 100))
 	t.Run("Jacobi symbol", testFEProperty_Jacobi[FE, FEPtr](10001, 500, 500))
 	t.Run("Exponentiation", testFEProperty_Exponentiation[FE, FEPtr](10001, 10002, 100))
+	t.Run("Constant-time operations", testFEProperty_ConstantTime[FE, FEPtr](10001, 10002, 10003, 100))
 }
 
 // For copy&pasting:
@@ -1325,3 +1331,109 @@ func testFEProperty_Exponentiation[FE any, FEPtr interface {
 
 	}
 }
+
+// testFEProperty_ConstantTime checks that the constant-time operations agree with their non-constant-time counterparts.
+// Note that constant-timeness itself is not tested here.
+func testFEProperty_ConstantTime[FE any, FEPtr interface {
+	*FE
+	FieldElementInterface[FEPtr]
+}](seedX int64, seedY int64, seedInt int64, num int) func(t *testing.T) {
+	return func(t *testing.T) {
+		prepareTestFieldElements(t)
+		var xs []FE = GetPrecomputedFieldElements[FE, FEPtr](seedX, num)
+		var ys []FE = GetPrecomputedFieldElements[FE, FEPtr](seedY, num)
+		var exponents []Uint256 = CachedUint256.GetElements(SeedAndRange{seed: seedInt, allowedRange: twoTo256_Int}, num)
+
+		var targetVal, expectedVal, otherVal FE
+		target, expected, other := FEPtr(&targetVal), FEPtr(&expectedVal), FEPtr(&otherVal)
+		for i := range xs {
+			x, y := FEPtr(&xs[i]), FEPtr(&ys[i])
+
+			// CondAssign, CondSwap and CondNeg
+			targetVal = xs[i]
+			target.CondAssign(0, y)
+			testutils.FatalUnless(t, target.IsEqual(x), "CondAssign(0, .) changed the receiver")
+			target.CondAssign(1, y)
+			testutils.FatalUnless(t, target.IsEqual(y), "CondAssign(1, y) did not set receiver to y")
+
+			targetVal, otherVal = xs[i], ys[i]
+			target.CondSwap(0, other)
+			testutils.FatalUnless(t, target.IsEqual(x) && other.IsEqual(y), "CondSwap(0, .) changed its arguments")
+			target.CondSwap(1, other)
+			testutils.FatalUnless(t, target.IsEqual(y) && other.IsEqual(x), "CondSwap(1, .) did not swap")
+
+			target.CondNeg(0, x)
+			testutils.FatalUnless(t, target.IsEqual(x), "CondNeg(0, x) != x")
+			target.CondNeg(1, x)
+			expected.Neg(x)
+			testutils.FatalUnless(t, target.IsEqual(expected), "CondNeg(1, x) != -x")
+			targetVal = xs[i]
+			target.CondNeg(1, target)
+			testutils.FatalUnless(t, target.IsEqual(expected), "CondNeg does not work for aliasing arguments")
+
+			// InvConstantTime
+			if x.IsZero() {
+				expected.SetZero()
+			} else {
+				expected.Inv(x)
+			}
+			target.InvConstantTime(x)
+			testutils.FatalUnless(t, target.IsEqual(expected), "InvConstantTime does not match Inv")
+			targetVal = xs[i]
+			target.InvConstantTime(target)
+			testutils.FatalUnless(t, target.IsEqual(expected), "InvConstantTime does not work for aliasing arguments")
+
+			// SquareRootConstantTime
+			target.SetInt64(101) // dummy value
+			ok := target.SquareRootConstantTime(x)
+			testutils.FatalUnless(t, ok == (x.Jacobi() >= 0), "Success of constant-time square root does not match Jacobi symbol")
+			if ok {
+				target.SquareEq()
+				testutils.FatalUnless(t, target.IsEqual(x), "SquareRootConstantTime did not return square root")
+			} else {
+				v, err := target.ToUint64()
+				testutils.FatalUnless(t, err == nil && v == 101, "SquareRootConstantTime modified argument on failure")
+			}
+
+			// ExpConstantTime
+			expected.Exp(x, &exponents[i])
+			target.ExpConstantTime(x, &exponents[i])
+			testutils.FatalUnless(t, target.IsEqual(expected), "ExpConstantTime does not match Exp")
+			target.ExpConstantTime(x, &zero_uint256)
+			testutils.FatalUnless(t, target.IsOne(), "x^0 != 1 for ExpConstantTime")
+			targetVal = xs[i]
+			target.ExpConstantTime(target, &exponents[i])
+			testutils.FatalUnless(t, target.IsEqual(expected), "ExpConstantTime does not work for aliasing arguments")
+		}
+
+		// special cases for 0
+		var zeroVal FE
+		zero := FEPtr(&zeroVal)
+		zero.SetZero()
+		target.SetOne()
+		target.InvConstantTime(zero)
+		testutils.FatalUnless(t, target.IsZero(), "InvConstantTime(0) != 0")
+		target.SetOne()
+		ok := target.SquareRootConstantTime(zero)
+		testutils.FatalUnless(t, ok && target.IsZero(), "SquareRootConstantTime(0) did not return 0")
+		target.ExpConstantTime(zero, &zero_uint256)
+		testutils.FatalUnless(t, target.IsOne(), "0^0 != 1 for ExpConstantTime")
+	}
+}
+
+// TestCondAssign checks that CondAssign behaves as intended.
+// Note that constant-timeness is not tested here.
+func TestCondAssign(t *testing.T) {
+	prepareTestFieldElements(t)
+	var drng *rand.Rand = rand.New(rand.NewSource(10001))
+	for i := 0; i < 100; i++ {
+		var x, y, z FieldElement
+		x.SetRandomUnsafe(drng)
+		y.SetRandomUnsafe(drng)
+		z = x
+		z.CondAssign(0, &y)
+		testutils.FatalUnless(t, z.words == x.words, "CondAssign(0, .) changed the receiver")
+		z.CondAssign(1, &y)
+		testutils.FatalUnless(t, z.words == y.words, "CondAssign(1, y) did not set receiver to y")
+	}
+}
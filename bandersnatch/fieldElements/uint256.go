@@ -377,6 +377,25 @@ func (z *Uint256) IsOne() bool {
 	return (z[0]-1)|z[1]|z[2]|z[3] == 0
 }
 
+// isEqual_constantTime returns 1 if z == x and 0 otherwise.
+//
+// Unlike z == x, the running time does not depend on the values of z and x.
+func (z *Uint256) isEqual_constantTime(x *Uint256) uint64 {
+	diff := (z[0] ^ x[0]) | (z[1] ^ x[1]) | (z[2] ^ x[2]) | (z[3] ^ x[3])
+	return 1 ^ ((diff | -diff) >> 63)
+}
+
+// condAssign_constantTime sets z = x if cond == 1 and leaves z unchanged if cond == 0. Other values of cond are not allowed.
+//
+// The running time and memory access pattern do not depend on cond or on the values of z and x.
+func (z *Uint256) condAssign_constantTime(cond uint64, x *Uint256) {
+	mask := -cond
+	z[0] ^= mask & (z[0] ^ x[0])
+	z[1] ^= mask & (z[1] ^ x[1])
+	z[2] ^= mask & (z[2] ^ x[2])
+	z[3] ^= mask & (z[3] ^ x[3])
+}
+
 // ShiftRightEq_64 right-shifts the internal uint64 array by 64 bit (equivalent to truncated-towards-minus-infinity division by 2^64) and returns the shifted-out uint64
 func (z *Uint256) ShiftRightEq_64() (ShiftOut uint64) {
 	ShiftOut = z[0]
@@ -70,19 +70,15 @@ func (z *Uint256) addAndReduce_b_c(x, y *Uint256) {
 
 	// On overflow, subtract an appropriate multiple of BaseFieldSize.
 	// The preconditions guarantee that subtracting 2*BaseFieldSize always remedies the overflow.
-	if carry != 0 {
-		z[0], carry = bits.Sub64(z[0], twiceBaseFieldSize_64_0, 0)
-		z[1], carry = bits.Sub64(z[1], twiceBaseFieldSize_64_1, carry)
-		z[2], carry = bits.Sub64(z[2], twiceBaseFieldSize_64_2, carry)
-		z[3], _ = bits.Sub64(z[3], twiceBaseFieldSize_64_3, carry)
-	}
+	// We do this via bit-masking rather than with an if, so the running time does not depend on x and y.
+	var mask uint64 = -carry
+	z[0], carry = bits.Sub64(z[0], twiceBaseFieldSize_64_0&mask, 0)
+	z[1], carry = bits.Sub64(z[1], twiceBaseFieldSize_64_1&mask, carry)
+	z[2], carry = bits.Sub64(z[2], twiceBaseFieldSize_64_2&mask, carry)
+	z[3], _ = bits.Sub64(z[3], twiceBaseFieldSize_64_3&mask, carry)
+
 	// NOTE: We could do an else if here! This works for both cases of preconditions.
-	if z[3] > baseFieldSize_3 {
-		z[0], carry = bits.Sub64(z[0], baseFieldSize_0, 0)
-		z[1], carry = bits.Sub64(z[1], baseFieldSize_1, carry)
-		z[2], carry = bits.Sub64(z[2], baseFieldSize_2, carry)
-		z[3], _ = bits.Sub64(z[3], baseFieldSize_3, carry) // _ is guaranteed to be 0
-	}
+	z.Reduce_ca()
 }
 
 // AddEqAndReduce_a computes z+=x mod BaseFieldSize, where the result may not be fully reduced.
@@ -132,30 +128,25 @@ func (z *Uint256) SubAndReduce_c(x, y *Uint256) {
 
 	// If we do not underflow, the result is correct: It is at least as reduces as x was.
 	// Otherwise, we need to add an appropriate multiple of BaseFieldSize
-	if borrow != 0 {
-		// NOTE: mentally rename borrow -> carry
-
-		// If z[3] > 0xFFFFFFFF_FFFFFFFF-baseFieldSize_3, then adding 1*BaseFieldSize is guaranteed to overflow
-		// Consequently, adding 1*BaseFieldSize is already enough (and the resulting z is actually fully reduced)
-		if z[3] > 0xFFFFFFFF_FFFFFFFF-baseFieldSize_3 {
-			z[0], borrow = bits.Add64(z[0], baseFieldSize_0, 0)
-			z[1], borrow = bits.Add64(z[1], baseFieldSize_1, borrow)
-			z[2], borrow = bits.Add64(z[2], baseFieldSize_2, borrow)
-			z[3], _ = bits.Add64(z[3], baseFieldSize_3, borrow) // _ is guaranteed to be 1
-		} else {
-			// Due to constraints to y, adding 2*BaseFieldSize is guaranteed to create an overflow, so we end up with some z' == x-y mod BaseFieldSize.
-			// Note that the result is in the correct range.
-			// This is because the only case where we choose +=2*BaseFieldSize even though += 1* BaseFieldSize would suffice is
-			// when z[3] == 0xFFFFFFFF_FFFFFFFF-baseFieldSize_3 in the condition above (and we would need to look at the other words to decide)
-			// In this case, after adding +=2*BaseFieldSize, the resulting z' has
-			// z'[3] == baseFieldSize_3 or z'[3]== baseFieldSize_3+1. Each case guarantees that z is in [0, UINT256MAX-BaseFieldSize)
-			z[0], borrow = bits.Add64(z[0], twiceBaseFieldSize_64_0, 0)
-			z[1], borrow = bits.Add64(z[1], twiceBaseFieldSize_64_1, borrow)
-			z[2], borrow = bits.Add64(z[2], twiceBaseFieldSize_64_2, borrow)
-			z[3], _ = bits.Add64(z[3], twiceBaseFieldSize_64_3, borrow) // _ is guaranteed to be 1
-		}
-	}
-
+	//
+	// If z[3] > 0xFFFFFFFF_FFFFFFFF-baseFieldSize_3, then adding 1*BaseFieldSize is guaranteed to overflow
+	// Consequently, adding 1*BaseFieldSize is already enough (and the resulting z is actually fully reduced)
+	// Otherwise, due to constraints to y, adding 2*BaseFieldSize is guaranteed to create an overflow, so we end up with some z' == x-y mod BaseFieldSize.
+	// Note that the result is in the correct range.
+	// This is because the only case where we choose +=2*BaseFieldSize even though += 1* BaseFieldSize would suffice is
+	// when z[3] == 0xFFFFFFFF_FFFFFFFF-baseFieldSize_3 in the condition above (and we would need to look at the other words to decide)
+	// In this case, after adding +=2*BaseFieldSize, the resulting z' has
+	// z'[3] == baseFieldSize_3 or z'[3]== baseFieldSize_3+1. Each case guarantees that z is in [0, UINT256MAX-BaseFieldSize)
+	//
+	// We select what to add via bit-masking rather than with an if, so the running time does not depend on x and y.
+	_, large := bits.Sub64(0xFFFFFFFF_FFFFFFFF-baseFieldSize_3, z[3], 0) // large == 1 iff z[3] > 0xFFFFFFFF_FFFFFFFF-baseFieldSize_3
+	var maskOnce uint64 = -(borrow & large)
+	var maskTwice uint64 = -(borrow & (1 ^ large))
+	var carry uint64
+	z[0], carry = bits.Add64(z[0], (baseFieldSize_0&maskOnce)|(twiceBaseFieldSize_64_0&maskTwice), 0)
+	z[1], carry = bits.Add64(z[1], (baseFieldSize_1&maskOnce)|(twiceBaseFieldSize_64_1&maskTwice), carry)
+	z[2], carry = bits.Add64(z[2], (baseFieldSize_2&maskOnce)|(twiceBaseFieldSize_64_2&maskTwice), carry)
+	z[3], _ = bits.Add64(z[3], (baseFieldSize_3&maskOnce)|(twiceBaseFieldSize_64_3&maskTwice), carry) // _ is guaranteed to be equal to borrow
 }
 
 // SubAndReduce_b sets z, such that z==x-y mod BaseFieldSize holds.
@@ -489,12 +480,14 @@ func (z *Uint256) Reduce_ca() {
 	var borrow uint64
 	// Note: if z.words[3] == m_64_3, we may or may not be able to reduce, depending on the other words.
 	// At any rate, we do not really need to, so we don't check.
-	if z[3] > baseFieldSize_3 {
-		z[0], borrow = bits.Sub64(z[0], baseFieldSize_0, 0)
-		z[1], borrow = bits.Sub64(z[1], baseFieldSize_1, borrow)
-		z[2], borrow = bits.Sub64(z[2], baseFieldSize_2, borrow)
-		z[3], _ = bits.Sub64(z[3], baseFieldSize_3, borrow) // _ is guaranteed to be 0
-	}
+	//
+	// We subtract BaseFieldSize iff z[3] > baseFieldSize_3. This is done via bit-masking rather than with an if, so the running time does not depend on z.
+	_, mask := bits.Sub64(baseFieldSize_3, z[3], 0) // mask == 1 iff z[3] > baseFieldSize_3
+	mask = -mask
+	z[0], borrow = bits.Sub64(z[0], baseFieldSize_0&mask, 0)
+	z[1], borrow = bits.Sub64(z[1], baseFieldSize_1&mask, borrow)
+	z[2], borrow = bits.Sub64(z[2], baseFieldSize_2&mask, borrow)
+	z[3], _ = bits.Sub64(z[3], baseFieldSize_3&mask, borrow) // _ is guaranteed to be 0
 }
 
 // Reduce_fb replaces z by some number z' with z' == z mod BaseFieldSize. Assumes z is already weakly reduced.
@@ -515,6 +508,21 @@ func (z *Uint256) reduce_fb_exact() {
 	}
 }
 
+// reduce_fb_constantTime replaces z by some number z' with z' == z mod BaseFieldSize. Assumes z is already weakly reduced.
+//
+// As for Reduce_fb, z' is guaranteed to be in [0, BaseFieldSize), provided z is in [0, 2*BaseFieldSize).
+// Unlike Reduce_fb, the running time does not depend on z: we always subtract BaseFieldSize and keep the result iff there was no borrow.
+func (z *Uint256) reduce_fb_constantTime() {
+	var reduced Uint256
+	var borrow uint64
+	reduced[0], borrow = bits.Sub64(z[0], baseFieldSize_0, 0)
+	reduced[1], borrow = bits.Sub64(z[1], baseFieldSize_1, borrow)
+	reduced[2], borrow = bits.Sub64(z[2], baseFieldSize_2, borrow)
+	reduced[3], borrow = bits.Sub64(z[3], baseFieldSize_3, borrow)
+	// borrow == 1 iff z < BaseFieldSize, in which case z is already fully reduced.
+	z.condAssign_constantTime(1^borrow, &reduced)
+}
+
 func (z *Uint256) reduce_fb_optimistic() {
 	if z[3] < baseFieldSize_3 {
 		return
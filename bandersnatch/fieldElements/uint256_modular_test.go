@@ -314,6 +314,7 @@ func TestUint256_Reduce(t *testing.T) {
 	testReductionFunction(t, (*Uint256).reduce_fa_loop, pc_uint256_a, (*Uint256).IsReduced_f, "reduce_fa_loop")
 	testReductionFunction(t, (*Uint256).reduce_fb_exact, pc_uint256_b, (*Uint256).IsReduced_f, "reduce_fb_exact")
 	testReductionFunction(t, (*Uint256).reduce_fb_optimistic, pc_uint256_b, (*Uint256).IsReduced_f, "reduce_fb_optimistic")
+	testReductionFunction(t, (*Uint256).reduce_fb_constantTime, pc_uint256_b, (*Uint256).IsReduced_f, "reduce_fb_constantTime")
 }
 
 func TestUint256_IsFullyReduced(t *testing.T) {
@@ -35,33 +35,33 @@ func montgomery_iteration(t *[5]uint64, x *Uint256, y uint64) {
 
 	// Change t to an equivalent representation modulo BaseFieldSize, s.t. t[0] == 0
 
-	// If t[0] == 0, we don't need to do anything (and the algorithm below would actually be wrong)
-	if t[0] != 0 {
-		q := t[0] * negativeInverseModulus_uint64 // computation will overflow, so this is performed modulo 2**64. This is exactly as desired.
-		// q is chosen, s.t. t + q*BaseFieldSize == 0 mod 2**64.
-		// We now add q*BaseFieldSize to t.
-
-		high, _ = bits.Mul64(q, baseFieldSize_0)
-		// t[0], carry = bits.Add64(t[0], _, 0) for _ from the line above gives t[0] == 0, carry==1 by construction; we can omit this.
-		// t[0] = 0 is omitted, because we will later write to t[0] anyway.
-		t[1], carry1 = bits.Add64(t[1], high, 1) // After this, carry1 needs to go in t[2]
-
-		high, low = bits.Mul64(q, baseFieldSize_1)
-		t[1], carry2 = bits.Add64(t[1], low, 0)       // After this, carry2 needs to go in t[2]
-		t[2], carry2 = bits.Add64(t[2], high, carry2) // After this, carry2 needs to go in t[3]
-
-		high, low = bits.Mul64(q, baseFieldSize_2)
-		t[2], carry1 = bits.Add64(t[2], low, carry1)  // After this, carry1 needs to go in t[3]
-		t[3], carry1 = bits.Add64(t[3], high, carry1) // After this, carry1 needs to go in t[4]
-
-		high, low = bits.Mul64(q, baseFieldSize_3)
-		t[3], carry2 = bits.Add64(t[3], low, carry2)    // After this, carry2 needs to go in t[4]
-		t[4], _ = bits.Add64(t[4], high+carry1, carry2) // _ == 0.
-		// The last carry is_ = 0 here:
-		// In fact, we know for the input q < 2**64  and t>>64 + BaseFieldSize < 2**256, so we get:
-		// t < 2**320 - 2**64 * BaseFieldSize
-		// => (t + q*BaseFieldSize) < 2**320 + BaseFieldSize * (-2**64 + q) <= 2**320 - BaseFieldSize.
-	}
+	// If t[0] == 0, we don't need to do anything. Note that in this case, q == 0 below and the lowest carry c0 is 0, so the computation below does nothing.
+	// We do not branch on t[0] == 0 in order to keep the running time independent of the values.
+	c0 := (t[0] | -t[0]) >> 63                // c0 == 1 iff t[0] != 0
+	q := t[0] * negativeInverseModulus_uint64 // computation will overflow, so this is performed modulo 2**64. This is exactly as desired.
+	// q is chosen, s.t. t + q*BaseFieldSize == 0 mod 2**64.
+	// We now add q*BaseFieldSize to t.
+
+	high, _ = bits.Mul64(q, baseFieldSize_0)
+	// t[0], carry = bits.Add64(t[0], _, 0) for _ from the line above gives t[0] == 0, carry==c0 by construction; we can omit this.
+	// t[0] = 0 is omitted, because we will later write to t[0] anyway.
+	t[1], carry1 = bits.Add64(t[1], high, c0) // After this, carry1 needs to go in t[2]
+
+	high, low = bits.Mul64(q, baseFieldSize_1)
+	t[1], carry2 = bits.Add64(t[1], low, 0)       // After this, carry2 needs to go in t[2]
+	t[2], carry2 = bits.Add64(t[2], high, carry2) // After this, carry2 needs to go in t[3]
+
+	high, low = bits.Mul64(q, baseFieldSize_2)
+	t[2], carry1 = bits.Add64(t[2], low, carry1)  // After this, carry1 needs to go in t[3]
+	t[3], carry1 = bits.Add64(t[3], high, carry1) // After this, carry1 needs to go in t[4]
+
+	high, low = bits.Mul64(q, baseFieldSize_3)
+	t[3], carry2 = bits.Add64(t[3], low, carry2)    // After this, carry2 needs to go in t[4]
+	t[4], _ = bits.Add64(t[4], high+carry1, carry2) // _ == 0.
+	// The last carry is_ = 0 here:
+	// In fact, we know for the input q < 2**64  and t>>64 + BaseFieldSize < 2**256, so we get:
+	// t < 2**320 - 2**64 * BaseFieldSize
+	// => (t + q*BaseFieldSize) < 2**320 + BaseFieldSize * (-2**64 + q) <= 2**320 - BaseFieldSize.
 	// Mentally apply t[0] = 0. (We omit this, as t[0] will be overwritten in the next operation, but it helps to understand)
 	// After this, t now stores an equivalent representation (i.e. differing by a multiple of BaseFieldSize) of the values that was given for t as input.
 
@@ -118,30 +118,25 @@ func (z *Uint256) mulMontgomery_Unrolled_c(x, y *Uint256) {
 	montgomery_iteration(&temp, x, y[2]) // temp == ((x*y[0] / r) + x*y[1])/r + x*y[2]
 	montgomery_iteration(&temp, x, y[3]) // temp == (((x*y[0] / r) + x*y[1])/r + x*y[2])/r + x*y[3]
 	// We need to divide by r mod BaseFieldSize. This is just another montgomery_iteration, but with y == 0 and we can write directly to z (so the second part is done by just writing to the correct z[i]).
-	if temp[0] == 0 {
-		z[0] = temp[1]
-		z[1] = temp[2]
-		z[2] = temp[3]
-		z[3] = temp[4]
-	} else {
-		var carry1, carry2, high, low uint64
-		temp[0] *= negativeInverseModulus_uint64
-
-		high, _ = bits.Mul64(temp[0], baseFieldSize_0)
-		z[0], carry1 = bits.Add64(temp[1], high, 1)
-
-		high, low = bits.Mul64(temp[0], baseFieldSize_1)
-		z[0], carry2 = bits.Add64(z[0], low, 0)
-		z[1], carry2 = bits.Add64(temp[2], high, carry2)
-
-		high, low = bits.Mul64(temp[0], baseFieldSize_2)
-		z[1], carry1 = bits.Add64(z[1], low, carry1)
-		z[2], carry1 = bits.Add64(temp[3], high, carry1)
-
-		high, low = bits.Mul64(temp[0], baseFieldSize_3)
-		z[2], carry2 = bits.Add64(z[2], low, carry2)
-		z[3], _ = bits.Add64(temp[4], high+carry1, carry2) // _ == 0 for the same
-	}
+	// If temp[0] == 0, this just shifts; as in montgomery_iteration, we do not branch on that.
+	var carry1, carry2, high, low uint64
+	c0 := (temp[0] | -temp[0]) >> 63 // c0 == 1 iff temp[0] != 0
+	temp[0] *= negativeInverseModulus_uint64
+
+	high, _ = bits.Mul64(temp[0], baseFieldSize_0)
+	z[0], carry1 = bits.Add64(temp[1], high, c0)
+
+	high, low = bits.Mul64(temp[0], baseFieldSize_1)
+	z[0], carry2 = bits.Add64(z[0], low, 0)
+	z[1], carry2 = bits.Add64(temp[2], high, carry2)
+
+	high, low = bits.Mul64(temp[0], baseFieldSize_2)
+	z[1], carry1 = bits.Add64(z[1], low, carry1)
+	z[2], carry1 = bits.Add64(temp[3], high, carry1)
+
+	high, low = bits.Mul64(temp[0], baseFieldSize_3)
+	z[2], carry2 = bits.Add64(z[2], low, carry2)
+	z[3], _ = bits.Add64(temp[4], high+carry1, carry2) // _ == 0 for the same
 	z.Reduce_ca()
 }
 
@@ -219,6 +214,45 @@ func (z *Uint256) ModularExponentiationMontgomery_fa(base *Uint256, exponent *Ui
 	z.modularExponentiationSquareAndMultiplyMontgomery_fa(base, exponent)
 }
 
+// ModularExponentiationMontgomeryConstantTime_fa sets z := base^exponent modulo BaseFieldSize, where z and base are both in Montgomery form.
+//
+// By convention, 0^0 is 1 here. Unlike ModularExponentiationMontgomery_fa, the running time and memory access pattern do not depend on the values of base and exponent.
+// This is intended for secret bases and/or exponents.
+func (z *Uint256) ModularExponentiationMontgomeryConstantTime_fa(base *Uint256, exponent *Uint256) {
+	// We use a fixed window of size 4, processing all 256 bits of exponent (irrespective of its actual bitlength).
+	// The table entry for each window is selected by scanning the whole table, so the memory access pattern does not depend on exponent.
+	// This takes 256 squarings and 64+14 multiplications.
+
+	// table[i] == base^i in Montgomery form, c-reduced
+	var table [16]Uint256
+	table[0] = twoTo256ModBaseField_uint256 // montgomery representation of 1
+	table[1] = *base
+	// We need to reduce here, because mulMontgomery_Unrolled_c requires its inputs c-reduced.
+	table[1].Reduce_ca()
+	for i := 2; i < 16; i++ {
+		table[i].mulMontgomery_Unrolled_c(&table[i-1], &table[1])
+	}
+
+	var acc, entry Uint256
+	acc = table[0]
+	for i := 63; i >= 0; i-- {
+		// acc == base^(exponent >> (4*(i+1)))
+		acc.SquareMontgomery_c(&acc)
+		acc.SquareMontgomery_c(&acc)
+		acc.SquareMontgomery_c(&acc)
+		acc.SquareMontgomery_c(&acc)
+		window := (exponent[i/16] >> (4 * (i % 16))) & 0xF
+		for j := range table {
+			diff := window ^ uint64(j)
+			entry.condAssign_constantTime(1^((diff|-diff)>>63), &table[j]) // assigns iff window == j
+		}
+		acc.mulMontgomery_Unrolled_c(&acc, &entry)
+		// acc == base^(exponent >> (4*i))
+	}
+	acc.reduce_fb_constantTime()
+	*z = acc
+}
+
 // modularExponentiationSquareAndMultiplyMontgomery_fa implements ModularExponentiationMontgomery_fa using naive square&multiply
 func (z *Uint256) modularExponentiationSquareAndMultiplyMontgomery_fa(base *Uint256, exponent *Uint256) {
 	// simple sliding window exponentiation
@@ -162,6 +162,37 @@ func TestUint256_MontgomeryModularExponentiation(t *testing.T) {
 
 }
 
+func TestUint256_MontgomeryModularExponentiationConstantTime(t *testing.T) {
+	prepareTestFieldElements(t)
+	const num = 1000
+
+	bases := CachedUint256.GetElements(SeedAndRange{seed: 10003, allowedRange: twoTo256_Int}, num)
+	exponents := CachedUint256.GetElements(SeedAndRange{seed: 10004, allowedRange: twoTo256_Int}, num)
+
+	var target, expected Uint256
+	target.ModularExponentiationMontgomeryConstantTime_fa(&zero_uint256, &zero_uint256)
+	testutils.FatalUnless(t, target == twoTo256ModBaseField_uint256, "0^0 != 1")
+
+	for i, basis := range bases {
+		exponent := exponents[i]
+		basisCopy, exponentCopy := basis, exponent
+		expected.modularExponentiationSlidingWindowMontgomery_fa(&basis, &exponent)
+		target.ModularExponentiationMontgomeryConstantTime_fa(&basis, &exponent)
+		testutils.FatalUnless(t, target.IsReduced_f(), "ModularExponentiationMontgomeryConstantTime_fa does not fully reduce")
+		testutils.FatalUnless(t, target == expected, "ModularExponentiationMontgomeryConstantTime_fa does not match non-constant-time version")
+		testutils.FatalUnless(t, basis == basisCopy && exponent == exponentCopy, "Argument was modified during ModularExponentiationMontgomeryConstantTime_fa")
+
+		target = basis
+		target.ModularExponentiationMontgomeryConstantTime_fa(&target, &exponent)
+		testutils.FatalUnless(t, target == expected, "ModularExponentiationMontgomeryConstantTime_fa does not work for aliasing args")
+
+		expected.modularExponentiationSlidingWindowMontgomery_fa(&basis, &basis)
+		target = basis
+		target.ModularExponentiationMontgomeryConstantTime_fa(&target, &target)
+		testutils.FatalUnless(t, target == expected, "ModularExponentiationMontgomeryConstantTime_fa does not work for target, basis, exponent all aliasing")
+	}
+}
+
 /****************
 OLD TESTS
 somewhat outdated, incompatible in style and redundant, but the tests themselves are valid, so there is no harm in keeping them for now.
@@ -0,0 +1,156 @@
+package frost
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/threshold"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+)
+
+// This file contains the first round of FROST signing: each signer creates a pair of secret nonces (d_i, e_i) and publishes the commitment (D_i, E_i) = (d_i*G, e_i*G).
+// The coordinator collects the commitments of the signers into a list, sorted by identifier, which is sent to all signers in the second round together with the message.
+
+// CommitmentSize is the size in bytes of an encoded SigningCommitment: SerializeScalar(identifier) || SerializeElement(D) || SerializeElement(E).
+const CommitmentSize = ScalarSize + 2*ElementSize
+
+// nonceRandomSize is the number of random bytes used by nonceGenerate.
+const nonceRandomSize = 32
+
+// SigningCommitment is the public commitment (D_i, E_i) of the signer with identifier i. Use the nonces' Commitment method, NewSigningCommitment or SigningCommitmentFromBytes to obtain one.
+type SigningCommitment struct {
+	identifier uint32
+	hiding     curvePoints.Point_xtw_subgroup // D_i
+	binding    curvePoints.Point_xtw_subgroup // E_i
+}
+
+// SigningNonces holds the secret nonces (d_i, e_i) of a signer for a single signing operation. Use Commit to create them.
+//
+// The nonces must only be used once: Sign erases them and refuses to use them again. Never copy or serialize SigningNonces.
+type SigningNonces struct {
+	hiding     exponents.Exponent // d_i
+	binding    exponents.Exponent // e_i
+	commitment SigningCommitment
+	used       bool
+}
+
+// nonceGenerate computes H3(random_bytes || SerializeScalar(secret)), reading random_bytes from rnd.
+//
+// Mixing in the secret share protects against a bad source of randomness.
+func nonceGenerate(secret *exponents.Exponent, rnd io.Reader) (ret exponents.Exponent, err error) {
+	input := make([]byte, nonceRandomSize, nonceRandomSize+ScalarSize)
+	if err = common.ReadRandomBytes(rnd, input); err != nil {
+		return
+	}
+	input = append(input, serializeScalar(secret)...)
+	ret = keypair.DeriveNonce(input, ContextString+"nonce")
+	return
+}
+
+// Commit performs the first round of signing for the holder of keyShare: it generates fresh nonces, reading randomness from rnd. If rnd is nil, we use crypto/rand.Reader.
+// The commitment, obtained via the Commitment method of the result, needs to be sent to the coordinator; the nonces are kept secret until Sign.
+//
+// We return an error if reading from rnd fails.
+func Commit(keyShare *threshold.KeyShare, rnd io.Reader) (*SigningNonces, error) {
+	secretShare := keyShare.SecretShare()
+	var ret SigningNonces
+	var err error
+	if ret.hiding, err = nonceGenerate(&secretShare.Value, rnd); err != nil {
+		return nil, err
+	}
+	if ret.binding, err = nonceGenerate(&secretShare.Value, rnd); err != nil {
+		return nil, err
+	}
+	ret.commitment.identifier = keyShare.Index()
+	ret.commitment.hiding.ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, &ret.hiding)
+	ret.commitment.binding.ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, &ret.binding)
+	return &ret, nil
+}
+
+// Commitment returns the public commitment (D_i, E_i) to the nonces.
+func (nonces *SigningNonces) Commitment() SigningCommitment {
+	return nonces.commitment
+}
+
+// erase overwrites the nonces and marks them as used.
+func (nonces *SigningNonces) erase() {
+	nonces.hiding.SetZero()
+	nonces.binding.SetZero()
+	nonces.used = true
+}
+
+// NewSigningCommitment creates the commitment (D, E) of the signer with the given identifier.
+//
+// We return an error wrapping ErrInvalidCommitment if identifier is 0 or if one of the points is a NaP or the neutral element.
+func NewSigningCommitment(identifier uint32, hiding, binding *curvePoints.Point_xtw_subgroup) (SigningCommitment, error) {
+	if identifier == 0 {
+		return SigningCommitment{}, fmt.Errorf("%w: identifier is 0", ErrInvalidCommitment)
+	}
+	if hiding.IsNaP() || binding.IsNaP() {
+		return SigningCommitment{}, fmt.Errorf("%w: commitment of signer %v contains a NaP", ErrInvalidCommitment, identifier)
+	}
+	if hiding.IsNeutralElement() || binding.IsNeutralElement() {
+		return SigningCommitment{}, fmt.Errorf("%w: commitment of signer %v contains the neutral element", ErrInvalidCommitment, identifier)
+	}
+	return SigningCommitment{identifier: identifier, hiding: *hiding, binding: *binding}, nil
+}
+
+// Identifier returns the identifier of the signer.
+func (c *SigningCommitment) Identifier() uint32 {
+	return c.identifier
+}
+
+// Hiding returns the hiding nonce commitment D_i.
+func (c *SigningCommitment) Hiding() curvePoints.Point_xtw_subgroup {
+	return c.hiding
+}
+
+// Binding returns the binding nonce commitment E_i.
+func (c *SigningCommitment) Binding() curvePoints.Point_xtw_subgroup {
+	return c.binding
+}
+
+// IsEqual checks whether two commitments are equal.
+func (c *SigningCommitment) IsEqual(other *SigningCommitment) bool {
+	return c.identifier == other.identifier && c.hiding.IsEqual(&other.hiding) && c.binding.IsEqual(&other.binding)
+}
+
+// Bytes returns the encoding SerializeScalar(identifier) || SerializeElement(D) || SerializeElement(E) of the commitment.
+// This is also the format used for the entries of the encoded commitment list that enters the binding factors.
+func (c *SigningCommitment) Bytes() []byte {
+	if c.identifier == 0 {
+		panic(ErrorPrefix + "called Bytes on an uninitialized signing commitment")
+	}
+	ret := make([]byte, 0, CommitmentSize)
+	id := identifierScalar(c.identifier)
+	ret = append(ret, serializeScalar(&id)...)
+	hiding, binding := keypair.EncodePoint(&c.hiding), keypair.EncodePoint(&c.binding)
+	ret = append(ret, hiding[:]...)
+	ret = append(ret, binding[:]...)
+	return ret
+}
+
+// SigningCommitmentFromBytes decodes a commitment in the format written by Bytes.
+//
+// We return an error wrapping ErrInvalidCommitment if buf has the wrong length, the identifier is not in [1, 2^32) or
+// one of the points is not a valid encoding of a non-neutral point in the prime-order subgroup.
+func SigningCommitmentFromBytes(buf []byte) (SigningCommitment, error) {
+	if len(buf) != CommitmentSize {
+		return SigningCommitment{}, fmt.Errorf("%w: commitment has length %v, expected %v", ErrInvalidCommitment, len(buf), CommitmentSize)
+	}
+	var ret SigningCommitment
+	var err error
+	if ret.identifier, err = decodeIdentifier(buf[0:ScalarSize]); err != nil {
+		return SigningCommitment{}, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	if ret.hiding, err = decodeElement(buf[ScalarSize : ScalarSize+ElementSize]); err != nil {
+		return SigningCommitment{}, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	if ret.binding, err = decodeElement(buf[ScalarSize+ElementSize:]); err != nil {
+		return SigningCommitment{}, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	return ret, nil
+}
@@ -0,0 +1,144 @@
+package frost
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/schnorr"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/threshold"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+)
+
+// This file contains the FROST ciphersuite for Bandersnatch, following the structure of RFC 9591, and the public key material of a threshold key.
+//
+// The ciphersuite uses the prime-order subgroup with generator G = curvePoints.SubgroupGenerator_xtw_subgroup and the following primitives:
+//
+//   - SerializeElement is the Banderwagon encoding pointserializer.BanderwagonShort (32 bytes); deserialization rejects the neutral element.
+//   - SerializeScalar encodes exponents as 32-byte numbers in [0, p253) with the same endianness (little endian).
+//   - H1 (binding factors) and H3 (nonces) are exponents.HashToExponent with DSTs ContextString || "rho" and ContextString || "nonce".
+//   - H2 (the challenge) is schnorr.Challenge, i.e. a tagged hash of enc(R) || enc(PK) || msg. This deviates from RFC 9591, which uses
+//     ContextString || "chal", and is chosen so that FROST signatures are ordinary Schnorr signatures that verify with schnorr.Verify.
+//   - H4 and H5 (hashing the message and the commitment list) are SHA-512(ContextString || "msg" || m) and SHA-512(ContextString || "com" || m).
+//
+// Participant identifiers are the (non-zero) share indices from the threshold package; the identifier i is mapped to the scalar i.
+// Key shares can be obtained from the distributed key generation in the threshold package or from a trusted dealer (threshold.Split and threshold.NewKeyShare).
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / frost: "
+
+// ContextString is the context string of the ciphersuite, which is used as prefix for all hash functions (except the challenge, see above).
+const ContextString = "FROST-BANDERSNATCH-SHA512-v1"
+
+const (
+	ElementSize = keypair.PointSize  // size in bytes of serialized group elements
+	ScalarSize  = keypair.ScalarSize // size in bytes of serialized scalars
+)
+
+var (
+	ErrInvalidPublicKeyPackage = errors.New(ErrorPrefix + "invalid public key package")
+	ErrInvalidCommitment       = errors.New(ErrorPrefix + "invalid signing commitment")
+	ErrInvalidCommitmentList   = errors.New(ErrorPrefix + "invalid list of signing commitments")
+	ErrInvalidSignatureShare   = errors.New(ErrorPrefix + "invalid signature share")
+	ErrNonceReused             = errors.New(ErrorPrefix + "signing nonces were already used")
+)
+
+// serializeScalar returns SerializeScalar(s).
+func serializeScalar(s *exponents.Exponent) []byte {
+	ret := keypair.EncodeScalar(s)
+	return ret[:]
+}
+
+// decodeElement returns DeserializeElement(buf). This includes a subgroup check and we return an error for the neutral element.
+func decodeElement(buf []byte) (ret curvePoints.Point_xtw_subgroup, err error) {
+	if ret, err = keypair.DecodePoint(buf); err != nil {
+		return
+	}
+	if ret.IsNeutralElement() {
+		err = keypair.ErrNeutralElement
+	}
+	return
+}
+
+// decodeIdentifier decodes a participant identifier that was encoded with serializeScalar. We return an error unless buf encodes a number in [1, 2^32).
+func decodeIdentifier(buf []byte) (uint32, error) {
+	if len(buf) != ScalarSize {
+		return 0, fmt.Errorf(ErrorPrefix+"encoded identifier has length %v, expected %v", len(buf), ScalarSize)
+	}
+	scalar, err := keypair.DecodeScalar(buf)
+	if err != nil {
+		return 0, err
+	}
+	value := scalar.ToBigInt_Subgroup()
+	if !value.IsUint64() || value.Uint64() == 0 || value.Uint64() > math.MaxUint32 {
+		return 0, fmt.Errorf(ErrorPrefix+"encoded identifier %v is out of range", value)
+	}
+	return uint32(value.Uint64()), nil
+}
+
+// identifierScalar returns the scalar corresponding to a participant identifier.
+func identifierScalar(identifier uint32) (ret exponents.Exponent) {
+	ret.SetUInt(uint64(identifier))
+	return
+}
+
+// hashToScalar computes H1 or H3, depending on the tag.
+func hashToScalar(tag string, input []byte) exponents.Exponent {
+	ret, err := exponents.HashToExponent(input, []byte(ContextString+tag))
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error in hash to scalar: %w", err))
+	}
+	return ret
+}
+
+// hashToBytes computes H4 or H5, depending on the tag.
+func hashToBytes(tag string, input []byte) []byte {
+	h := sha512.New()
+	h.Write([]byte(ContextString + tag))
+	h.Write(input)
+	return h.Sum(nil)
+}
+
+// PublicKeyPackage is the public information about a threshold key: the group public key and the verifying shares PK_i = x_i*G of all participants.
+// It is needed to verify signature shares. Use NewPublicKeyPackage to create one.
+type PublicKeyPackage struct {
+	groupPublicKey *schnorr.PublicKey
+	commitment     threshold.FeldmanCommitment // Feldman commitment to the sharing of the group secret key, which determines the verifying shares
+}
+
+// NewPublicKeyPackage creates a public key package from the Feldman commitment to the sharing of the group secret key (e.g. threshold.KeyShare.Commitment()).
+//
+// We return an error wrapping ErrInvalidPublicKeyPackage if commitment is the zero value or the group public key is the neutral element.
+func NewPublicKeyPackage(commitment *threshold.FeldmanCommitment) (*PublicKeyPackage, error) {
+	// NewFeldmanCommitment validates the points and makes a copy, so later modifications of *commitment do not affect us.
+	ownCommitment, err := threshold.NewFeldmanCommitment(commitment.Points())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKeyPackage, err)
+	}
+	groupKeyPoint := ownCommitment.PublicKey()
+	groupPublicKey, err := schnorr.PublicKeyFromPoint(&groupKeyPoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKeyPackage, err)
+	}
+	return &PublicKeyPackage{groupPublicKey: groupPublicKey, commitment: ownCommitment}, nil
+}
+
+// Threshold returns the number of participants required to sign.
+func (pkp *PublicKeyPackage) Threshold() int {
+	return pkp.commitment.Threshold()
+}
+
+// GroupPublicKey returns the group public key. Signatures created with FROST verify under this key with schnorr.Verify.
+func (pkp *PublicKeyPackage) GroupPublicKey() *schnorr.PublicKey {
+	return pkp.groupPublicKey
+}
+
+// VerifyingShare returns the verifying share PK_i = x_i*G of the participant with the given identifier.
+//
+// identifier must be non-zero.
+func (pkp *PublicKeyPackage) VerifyingShare(identifier uint32) curvePoints.Point_xtw_subgroup {
+	return pkp.commitment.PublicShare(identifier)
+}
@@ -0,0 +1,298 @@
+package frost
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/schnorr"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/threshold"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// dealKeyShares creates key shares for a t-out-of-n sharing of a random secret, using a trusted dealer. It also returns the secret.
+func dealKeyShares(t *testing.T, thresh int, n int, rnd *rand.Rand) ([]*threshold.KeyShare, exponents.Exponent) {
+	var secret exponents.Exponent
+	if err := secret.SetRandom(rnd); err != nil {
+		t.Fatal(err)
+	}
+	f, err := threshold.NewRandomPolynomial(&secret, thresh, rnd)
+	testutils.FatalUnless(t, err == nil, "NewRandomPolynomial failed: %v", err)
+	commitment := f.Commit()
+	keyShares := make([]*threshold.KeyShare, n)
+	for i := range keyShares {
+		share := f.Share(uint32(i + 1))
+		keyShares[i], err = threshold.NewKeyShare(&share, &commitment)
+		testutils.FatalUnless(t, err == nil, "NewKeyShare failed: %v", err)
+	}
+	return keyShares, secret
+}
+
+// runRound1 performs the first round of signing for the given signers and returns their nonces and the sorted commitment list.
+func runRound1(t *testing.T, signers []*threshold.KeyShare, rnd *rand.Rand) ([]*SigningNonces, []SigningCommitment) {
+	nonces := make([]*SigningNonces, len(signers))
+	commitments := make([]SigningCommitment, len(signers))
+	for i := range signers {
+		var err error
+		nonces[i], err = Commit(signers[i], rnd)
+		testutils.FatalUnless(t, err == nil, "Commit failed: %v", err)
+		commitments[i] = nonces[i].Commitment()
+	}
+	sort.Slice(commitments, func(i, j int) bool { return commitments[i].Identifier() < commitments[j].Identifier() })
+	return nonces, commitments
+}
+
+// runRound2 performs the second round of signing for the given signers.
+func runRound2(t *testing.T, signers []*threshold.KeyShare, nonces []*SigningNonces, msg []byte, commitments []SigningCommitment) []SignatureShare {
+	shares := make([]SignatureShare, len(signers))
+	for i := range signers {
+		share, err := Sign(signers[i], nonces[i], msg, commitments)
+		testutils.FatalUnless(t, err == nil, "Sign failed: %v", err)
+		shares[i] = *share
+	}
+	return shares
+}
+
+func TestFROSTSignVerify(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, params := range [][2]int{{1, 1}, {1, 3}, {2, 3}, {3, 5}, {5, 5}} {
+		thresh, n := params[0], params[1]
+		keyShares, secret := dealKeyShares(t, thresh, n, rnd)
+		commitment := keyShares[0].Commitment()
+		pkp, err := NewPublicKeyPackage(&commitment)
+		testutils.FatalUnless(t, err == nil, "NewPublicKeyPackage failed: %v", err)
+		testutils.FatalUnless(t, pkp.Threshold() == thresh, "wrong threshold")
+		var expectedGroupKey curvePoints.Point_xtw_subgroup
+		expectedGroupKey.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &secret)
+		groupKeyPoint := pkp.GroupPublicKey().Point()
+		testutils.FatalUnless(t, groupKeyPoint.IsEqual(&expectedGroupKey), "wrong group public key")
+
+		for numSigners := thresh; numSigners <= n; numSigners++ {
+			perm := rnd.Perm(n)
+			signers := make([]*threshold.KeyShare, numSigners)
+			for i := range signers {
+				signers[i] = keyShares[perm[i]]
+			}
+			msg := []byte("FROST test message")
+			nonces, commitments := runRound1(t, signers, rnd)
+			shares := runRound2(t, signers, nonces, msg, commitments)
+			for i := range shares {
+				err = pkp.VerifySignatureShare(msg, commitments, &shares[i])
+				testutils.FatalUnless(t, err == nil, "valid share rejected: %v", err)
+			}
+			// shares are given in signer order, which differs from the order of commitments
+			sig, err := pkp.Aggregate(msg, commitments, shares)
+			testutils.FatalUnless(t, err == nil, "Aggregate failed for (%v, %v) with %v signers: %v", thresh, n, numSigners, err)
+			testutils.FatalUnless(t, schnorr.Verify(pkp.GroupPublicKey(), msg, sig), "aggregated signature does not verify")
+			testutils.FatalUnless(t, !schnorr.Verify(pkp.GroupPublicKey(), []byte("other message"), sig), "aggregated signature verifies for other message")
+			decodedSig, err := schnorr.SignatureFromBytes(sig.Bytes())
+			testutils.FatalUnless(t, err == nil && schnorr.Verify(pkp.GroupPublicKey(), msg, decodedSig), "roundtrip of signature failed: %v", err)
+		}
+	}
+}
+
+func TestFROSTWithDKG(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const thresh, n = 2, 4
+	participants := make([]*threshold.Participant, n)
+	for i := range participants {
+		var err error
+		participants[i], err = threshold.NewParticipant(uint32(i+1), thresh, n, rnd)
+		testutils.FatalUnless(t, err == nil, "NewParticipant failed: %v", err)
+	}
+	for _, dealer := range participants {
+		commitment := dealer.Commitment()
+		for _, recipient := range participants {
+			if recipient == dealer {
+				continue
+			}
+			share := dealer.DealShare(recipient.Index())
+			err := recipient.ReceiveCommitment(dealer.Index(), &commitment)
+			testutils.FatalUnless(t, err == nil, "ReceiveCommitment failed: %v", err)
+			err = recipient.ReceiveShare(dealer.Index(), &share)
+			testutils.FatalUnless(t, err == nil, "ReceiveShare failed: %v", err)
+		}
+	}
+	keyShares := make([]*threshold.KeyShare, n)
+	for i := range participants {
+		var err error
+		keyShares[i], err = participants[i].Finalize()
+		testutils.FatalUnless(t, err == nil, "Finalize failed: %v", err)
+	}
+	commitment := keyShares[2].Commitment()
+	pkp, err := NewPublicKeyPackage(&commitment)
+	testutils.FatalUnless(t, err == nil, "NewPublicKeyPackage failed: %v", err)
+
+	msg := []byte("signed by participants 2 and 4")
+	signers := []*threshold.KeyShare{keyShares[3], keyShares[1]}
+	nonces, commitments := runRound1(t, signers, rnd)
+	shares := runRound2(t, signers, nonces, msg, commitments)
+	sig, err := pkp.Aggregate(msg, commitments, shares)
+	testutils.FatalUnless(t, err == nil && schnorr.Verify(pkp.GroupPublicKey(), msg, sig), "signing with DKG key failed: %v", err)
+}
+
+func TestFROSTInvalidShares(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	keyShares, _ := dealKeyShares(t, 3, 5, rnd)
+	commitment := keyShares[0].Commitment()
+	pkp, _ := NewPublicKeyPackage(&commitment)
+	signers := []*threshold.KeyShare{keyShares[0], keyShares[2], keyShares[3], keyShares[4]}
+	msg := []byte("message")
+	nonces, commitments := runRound1(t, signers, rnd)
+	shares := runRound2(t, signers, nonces, msg, commitments)
+
+	// corrupt the shares of signers 3 and 5
+	var one exponents.Exponent
+	one.SetOne()
+	for _, i := range []int{3, 1} {
+		value := shares[i].Value()
+		value.Add(&value, &one)
+		shares[i] = NewSignatureShare(shares[i].Identifier(), &value)
+	}
+	_, err := pkp.Aggregate(msg, commitments, shares)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureShare), "invalid shares not detected")
+	testutils.FatalUnless(t, err.Error() == ErrInvalidSignatureShare.Error()+": invalid shares from signers [3 5]", "wrong attribution: %v", err)
+	for i, expectValid := range []bool{true, false, true, false} {
+		err = pkp.VerifySignatureShare(msg, commitments, &shares[i])
+		testutils.FatalUnless(t, (err == nil) == expectValid, "VerifySignatureShare wrong for signer %v: %v", shares[i].Identifier(), err)
+	}
+	// valid shares for a different message are invalid
+	err = pkp.VerifySignatureShare([]byte("other message"), commitments, &shares[0])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureShare), "share for different message accepted")
+
+	// wrong number of shares, duplicates and unknown signers
+	_, err = pkp.Aggregate(msg, commitments, shares[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureShare), "missing share not detected")
+	duplicate := append([]SignatureShare(nil), shares...)
+	duplicate[1] = duplicate[0]
+	_, err = pkp.Aggregate(msg, commitments, duplicate)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureShare), "duplicate share not detected")
+	unknown := NewSignatureShare(2, &one)
+	err = pkp.VerifySignatureShare(msg, commitments, &unknown)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureShare), "share of non-signer accepted")
+	duplicate[1] = unknown
+	_, err = pkp.Aggregate(msg, commitments, duplicate)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureShare), "share of non-signer accepted")
+}
+
+func TestFROSTCommitmentList(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	keyShares, _ := dealKeyShares(t, 2, 3, rnd)
+	signers := keyShares[0:2]
+	msg := []byte("message")
+	nonces, commitments := runRound1(t, signers, rnd)
+
+	_, err := Sign(signers[0], nonces[0], msg, commitments[0:1])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitmentList), "too few commitments accepted")
+	_, err = Sign(signers[0], nonces[0], msg, []SigningCommitment{commitments[1], commitments[0]})
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitmentList), "unsorted commitments accepted")
+	_, err = Sign(signers[0], nonces[0], msg, []SigningCommitment{commitments[1], commitments[1]})
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitmentList), "duplicate commitments accepted")
+	_, err = Sign(signers[0], nonces[0], msg, []SigningCommitment{{}, commitments[1]})
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "uninitialized commitment accepted")
+
+	// commitment list without the signer's own commitment
+	otherNonces, err := Commit(keyShares[2], rnd)
+	testutils.FatalUnless(t, err == nil, "Commit failed: %v", err)
+	_, err = Sign(signers[0], nonces[0], msg, []SigningCommitment{commitments[1], otherNonces.Commitment()})
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitmentList), "commitment list without own commitment accepted")
+	// commitment list with a replaced commitment of the signer
+	replaced, _ := Commit(signers[0], rnd)
+	_, err = Sign(signers[0], nonces[0], msg, []SigningCommitment{replaced.Commitment(), commitments[1]})
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitmentList), "commitment list with wrong own commitment accepted")
+
+	// failed attempts do not consume the nonces, but successful ones do
+	_, err = Sign(signers[0], nonces[0], msg, commitments)
+	testutils.FatalUnless(t, err == nil, "Sign failed: %v", err)
+	_, err = Sign(signers[0], nonces[0], msg, commitments)
+	testutils.FatalUnless(t, errors.Is(err, ErrNonceReused), "nonce reuse not detected")
+	testutils.FatalUnless(t, testutils.CheckPanic(func() { Sign(signers[0], nonces[1], msg, commitments) }), "Sign did not panic for nonces of other signer")
+
+	designatedErr := errors.New("designated error")
+	_, err = Commit(signers[0], iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "Commit did not report randomness failure")
+}
+
+func TestFROSTEncoding(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	keyShares, _ := dealKeyShares(t, 2, 3, rnd)
+	nonces, commitments := runRound1(t, keyShares, rnd)
+
+	encoded := commitments[1].Bytes()
+	testutils.FatalUnless(t, len(encoded) == CommitmentSize, "wrong commitment encoding length")
+	decoded, err := SigningCommitmentFromBytes(encoded)
+	testutils.FatalUnless(t, err == nil && decoded.IsEqual(&commitments[1]), "roundtrip of commitment failed: %v", err)
+	hiding, binding := decoded.Hiding(), decoded.Binding()
+	fromPoints, err := NewSigningCommitment(decoded.Identifier(), &hiding, &binding)
+	testutils.FatalUnless(t, err == nil && fromPoints.IsEqual(&commitments[1]), "NewSigningCommitment failed: %v", err)
+
+	_, err = SigningCommitmentFromBytes(encoded[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "commitment with wrong length accepted")
+	bad := append([]byte(nil), encoded...)
+	for i := 0; i < ScalarSize; i++ {
+		bad[i] = 0
+	}
+	_, err = SigningCommitmentFromBytes(bad)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "commitment with identifier 0 accepted")
+	bad = append([]byte(nil), encoded...)
+	large := identifierScalar(0)
+	large.SetUInt(1 << 32)
+	copy(bad[0:ScalarSize], serializeScalar(&large))
+	_, err = SigningCommitmentFromBytes(bad)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "commitment with large identifier accepted")
+	bad = append([]byte(nil), encoded...)
+	neutral := keypair.EncodePoint(&curvePoints.NeutralElement_xtw_subgroup)
+	copy(bad[ScalarSize+ElementSize:], neutral[:])
+	_, err = SigningCommitmentFromBytes(bad)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "commitment with neutral element accepted")
+	_, err = NewSigningCommitment(2, &hiding, &curvePoints.NeutralElement_xtw_subgroup)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "commitment with neutral element accepted")
+	_, err = NewSigningCommitment(2, &hiding, &curvePoints.Point_xtw_subgroup{})
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "commitment with NaP accepted")
+	_, err = NewSigningCommitment(0, &hiding, &binding)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "commitment with identifier 0 accepted")
+
+	msg := []byte("message")
+	shares := runRound2(t, keyShares, nonces, msg, commitments)
+	encodedShare := shares[2].Bytes()
+	testutils.FatalUnless(t, len(encodedShare) == SignatureShareSize, "wrong signature share encoding length")
+	decodedShare, err := SignatureShareFromBytes(encodedShare)
+	testutils.FatalUnless(t, err == nil && decodedShare.Identifier() == 3, "roundtrip of signature share failed: %v", err)
+	value, decodedValue := shares[2].Value(), decodedShare.Value()
+	testutils.FatalUnless(t, value.IsEqual(&decodedValue), "roundtrip of signature share failed")
+	_, err = SignatureShareFromBytes(encodedShare[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureShare), "share with wrong length accepted")
+	badShare := append([]byte(nil), encodedShare...)
+	for i := ScalarSize; i < SignatureShareSize; i++ {
+		badShare[i] = 0xff
+	}
+	_, err = SignatureShareFromBytes(badShare)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureShare), "share with non-reduced value accepted")
+	var empty SignatureShare
+	testutils.FatalUnless(t, testutils.CheckPanic(empty.Bytes), "Bytes on zero value did not panic")
+}
+
+func TestPublicKeyPackage(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	keyShares, _ := dealKeyShares(t, 2, 3, rnd)
+	commitment := keyShares[0].Commitment()
+	pkp, _ := NewPublicKeyPackage(&commitment)
+	for _, ks := range keyShares {
+		expected := ks.PublicShare(ks.Index())
+		got := pkp.VerifyingShare(ks.Index())
+		testutils.FatalUnless(t, got.IsEqual(&expected), "wrong verifying share")
+	}
+	var empty threshold.FeldmanCommitment
+	_, err := NewPublicKeyPackage(&empty)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKeyPackage), "empty commitment accepted")
+	points := commitment.Points()
+	points[0] = curvePoints.NeutralElement_xtw_subgroup
+	neutralKey, _ := threshold.NewFeldmanCommitment(points)
+	_, err = NewPublicKeyPackage(&neutralKey)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKeyPackage), "neutral group public key accepted")
+}
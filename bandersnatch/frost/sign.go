@@ -0,0 +1,272 @@
+package frost
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/schnorr"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/threshold"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+)
+
+// This file contains the second round of FROST signing and the aggregation of signature shares.
+//
+// Given the message msg and the list of commitments (i, D_i, E_i) of the signers, sorted by identifier, everyone computes
+//
+//	rho_i = H1(SerializeElement(PK) || H4(msg) || H5(encoded commitment list) || SerializeScalar(i))  (binding factors)
+//	R     = sum_i D_i + rho_i*E_i                                                                    (group commitment)
+//	c     = H2(R, PK, msg)                                                                           (challenge, see schnorr.Challenge)
+//
+// Signer i responds with z_i = d_i + e_i*rho_i + lambda_i*x_i*c, where lambda_i is the Lagrange coefficient of i for the set of signers.
+// The coordinator checks z_i*G == D_i + rho_i*E_i + (c*lambda_i)*PK_i and outputs the Schnorr signature (R, z) with z = sum_i z_i.
+
+// SignatureShareSize is the size in bytes of an encoded SignatureShare: SerializeScalar(identifier) || SerializeScalar(z_i).
+const SignatureShareSize = 2 * ScalarSize
+
+// SignatureShare is the contribution z_i of the signer with identifier i to a signature.
+type SignatureShare struct {
+	identifier uint32
+	value      exponents.Exponent // fully reduced
+}
+
+// signingContext holds the values derived from the message and the commitment list that are common to all signers.
+type signingContext struct {
+	commitments     []SigningCommitment // sorted by identifier
+	bindingFactors  []exponents.Exponent
+	lambdas         []exponents.Exponent
+	groupCommitment curvePoints.Point_xtw_subgroup // R
+	challenge       exponents.Exponent             // c
+}
+
+// newSigningContext validates the commitment list and computes the binding factors, Lagrange coefficients, group commitment and challenge.
+//
+// We return an error wrapping ErrInvalidCommitmentList if there are fewer commitments than the threshold or the commitments are not sorted by identifier or contain duplicates,
+// and an error wrapping ErrInvalidCommitment if some commitment is uninitialized.
+func (pkp *PublicKeyPackage) newSigningContext(msg []byte, commitments []SigningCommitment) (*signingContext, error) {
+	if len(commitments) < pkp.Threshold() {
+		return nil, fmt.Errorf("%w: got %v commitments, but the threshold is %v", ErrInvalidCommitmentList, len(commitments), pkp.Threshold())
+	}
+	identifiers := make([]uint32, len(commitments))
+	encodedList := make([]byte, 0, len(commitments)*CommitmentSize)
+	for i := range commitments {
+		identifiers[i] = commitments[i].identifier
+		if identifiers[i] == 0 {
+			return nil, fmt.Errorf("%w: commitment number %v is uninitialized", ErrInvalidCommitment, i)
+		}
+		if i > 0 && identifiers[i] <= identifiers[i-1] {
+			return nil, fmt.Errorf("%w: commitments are not sorted by identifier or contain duplicates", ErrInvalidCommitmentList)
+		}
+		encodedList = append(encodedList, commitments[i].Bytes()...)
+	}
+
+	ret := &signingContext{commitments: append([]SigningCommitment(nil), commitments...)}
+	var err error
+	ret.lambdas, err = threshold.LagrangeCoefficientsAtZero(identifiers)
+	if err != nil {
+		// cannot happen, as we checked the identifiers above.
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCommitmentList, err)
+	}
+
+	// binding factors
+	prefix := make([]byte, 0, ElementSize+2*64+ScalarSize)
+	prefix = append(prefix, pkp.groupPublicKey.Bytes()...)
+	prefix = append(prefix, hashToBytes("msg", msg)...)
+	prefix = append(prefix, hashToBytes("com", encodedList)...)
+	ret.bindingFactors = make([]exponents.Exponent, len(commitments))
+	for i := range commitments {
+		id := identifierScalar(identifiers[i])
+		ret.bindingFactors[i] = hashToScalar("rho", append(prefix, serializeScalar(&id)...))
+	}
+
+	// R = sum_i D_i + rho_i * E_i, computed with a single MSM
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, 0, 2*len(commitments))
+	scalars := make([]exponents.Exponent, 0, 2*len(commitments))
+	var one exponents.Exponent
+	one.SetOne()
+	for i := range commitments {
+		points = append(points, commitments[i].hiding, commitments[i].binding)
+		scalars = append(scalars, one, ret.bindingFactors[i])
+	}
+	result := curvePoints.MultiExponentiate(points, scalars)
+	ret.groupCommitment.SetFrom(&result)
+	ret.challenge = schnorr.Challenge(&ret.groupCommitment, pkp.groupPublicKey, msg)
+	return ret, nil
+}
+
+// position returns the position of the signer with the given identifier in the commitment list or -1 if there is none.
+func (ctx *signingContext) position(identifier uint32) int {
+	pos := sort.Search(len(ctx.commitments), func(i int) bool { return ctx.commitments[i].identifier >= identifier })
+	if pos == len(ctx.commitments) || ctx.commitments[pos].identifier != identifier {
+		return -1
+	}
+	return pos
+}
+
+// Sign performs the second round of signing for the holder of keyShare: it computes the signature share on msg, given the list of commitments of all signers (sorted by identifier).
+// The commitment list must contain the commitment of nonces. nonces are erased and cannot be used again.
+//
+// We return ErrNonceReused if nonces were already used, an error wrapping ErrInvalidCommitmentList or ErrInvalidCommitment if the commitment list is invalid
+// or does not contain the commitment of nonces, and an error wrapping ErrInvalidPublicKeyPackage if the group public key is the neutral element.
+// We panic if nonces were created for a different key share.
+func Sign(keyShare *threshold.KeyShare, nonces *SigningNonces, msg []byte, commitments []SigningCommitment) (*SignatureShare, error) {
+	if nonces.used {
+		return nil, ErrNonceReused
+	}
+	identifier := keyShare.Index()
+	if nonces.commitment.identifier != identifier {
+		panic(fmt.Errorf(ErrorPrefix+"nonces of signer %v used with key share of signer %v", nonces.commitment.identifier, identifier))
+	}
+	groupCommitment := keyShare.Commitment()
+	pkp, err := NewPublicKeyPackage(&groupCommitment)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := pkp.newSigningContext(msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+	pos := ctx.position(identifier)
+	if pos < 0 || !ctx.commitments[pos].IsEqual(&nonces.commitment) {
+		return nil, fmt.Errorf("%w: commitment list does not contain the commitment of signer %v", ErrInvalidCommitmentList, identifier)
+	}
+
+	// z_i = d_i + e_i * rho_i + lambda_i * x_i * c
+	secretShare := keyShare.SecretShare()
+	var z, temp exponents.Exponent
+	z.Mul(&ctx.lambdas[pos], &secretShare.Value)
+	z.Mul(&z, &ctx.challenge)
+	temp.Mul(&nonces.binding, &ctx.bindingFactors[pos])
+	z.Add(&z, &temp)
+	z.Add(&z, &nonces.hiding)
+	nonces.erase()
+	return &SignatureShare{identifier: identifier, value: z.ModuloP253()}, nil
+}
+
+// verifyShare checks z_i*G == D_i + rho_i*E_i + (c*lambda_i)*PK_i for the signer at position pos with a single MSM.
+func (pkp *PublicKeyPackage) verifyShare(ctx *signingContext, pos int, share *SignatureShare) bool {
+	commitment := &ctx.commitments[pos]
+	var negZ, cLambda exponents.Exponent
+	negZ.Neg(&share.value)
+	cLambda.Mul(&ctx.challenge, &ctx.lambdas[pos])
+	points := curvePoints.CurvePointSlice_xtw_subgroup{curvePoints.SubgroupGenerator_xtw_subgroup, commitment.hiding, commitment.binding, pkp.VerifyingShare(commitment.identifier)}
+	var one exponents.Exponent
+	one.SetOne()
+	result := curvePoints.MultiExponentiate(points, []exponents.Exponent{negZ, one, ctx.bindingFactors[pos], cLambda})
+	return result.IsNeutralElement()
+}
+
+// VerifySignatureShare checks whether share is a valid signature share on msg for the given commitment list. This allows to identify misbehaving signers.
+//
+// We return an error wrapping ErrInvalidSignatureShare if the share is invalid or its signer is not in the commitment list and
+// the errors of Sign if the commitment list is invalid.
+func (pkp *PublicKeyPackage) VerifySignatureShare(msg []byte, commitments []SigningCommitment, share *SignatureShare) error {
+	ctx, err := pkp.newSigningContext(msg, commitments)
+	if err != nil {
+		return err
+	}
+	pos := ctx.position(share.identifier)
+	if pos < 0 {
+		return fmt.Errorf("%w: signer %v is not in the commitment list", ErrInvalidSignatureShare, share.identifier)
+	}
+	if !pkp.verifyShare(ctx, pos, share) {
+		return fmt.Errorf("%w: share of signer %v is invalid", ErrInvalidSignatureShare, share.identifier)
+	}
+	return nil
+}
+
+// Aggregate combines the signature shares of all signers in the commitment list into a Schnorr signature on msg, which verifies under the group public key with schnorr.Verify.
+// The order of shares does not matter.
+//
+// We first verify the aggregated signature; only if this fails, we verify the individual shares.
+// We return an error wrapping ErrInvalidSignatureShare if shares does not contain exactly one share per signer or if the signature is invalid;
+// in the latter case, the error message lists the identifiers of the signers that sent invalid shares.
+// For an invalid commitment list, we return the same errors as Sign.
+func (pkp *PublicKeyPackage) Aggregate(msg []byte, commitments []SigningCommitment, shares []SignatureShare) (*schnorr.Signature, error) {
+	ctx, err := pkp.newSigningContext(msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) != len(ctx.commitments) {
+		return nil, fmt.Errorf("%w: got %v signature shares for %v signers", ErrInvalidSignatureShare, len(shares), len(ctx.commitments))
+	}
+	positions := make([]int, len(shares))
+	seen := make([]bool, len(ctx.commitments))
+	var z exponents.Exponent
+	for i := range shares {
+		pos := ctx.position(shares[i].identifier)
+		if pos < 0 {
+			return nil, fmt.Errorf("%w: signer %v is not in the commitment list", ErrInvalidSignatureShare, shares[i].identifier)
+		}
+		if seen[pos] {
+			return nil, fmt.Errorf("%w: duplicate share of signer %v", ErrInvalidSignatureShare, shares[i].identifier)
+		}
+		seen[pos] = true
+		positions[i] = pos
+		z.Add(&z, &shares[i].value)
+	}
+	signature := schnorr.SignatureFromComponents(&ctx.groupCommitment, &z)
+	if schnorr.Verify(pkp.groupPublicKey, msg, signature) {
+		return signature, nil
+	}
+	var culprits []uint32
+	for i := range shares {
+		if !pkp.verifyShare(ctx, positions[i], &shares[i]) {
+			culprits = append(culprits, shares[i].identifier)
+		}
+	}
+	sort.Slice(culprits, func(i, j int) bool { return culprits[i] < culprits[j] })
+	return nil, fmt.Errorf("%w: invalid shares from signers %v", ErrInvalidSignatureShare, culprits)
+}
+
+// NewSignatureShare creates the signature share z_i of the signer with the given identifier.
+//
+// We panic if identifier is 0.
+func NewSignatureShare(identifier uint32, value *exponents.Exponent) SignatureShare {
+	if identifier == 0 {
+		panic(ErrorPrefix + "signature share with identifier 0")
+	}
+	return SignatureShare{identifier: identifier, value: value.ModuloP253()}
+}
+
+// Identifier returns the identifier of the signer.
+func (share *SignatureShare) Identifier() uint32 {
+	return share.identifier
+}
+
+// Value returns z_i.
+func (share *SignatureShare) Value() exponents.Exponent {
+	return share.value
+}
+
+// Bytes returns the encoding SerializeScalar(identifier) || SerializeScalar(z_i) of the signature share.
+func (share *SignatureShare) Bytes() []byte {
+	if share.identifier == 0 {
+		panic(ErrorPrefix + "called Bytes on an uninitialized signature share")
+	}
+	ret := make([]byte, 0, SignatureShareSize)
+	id := identifierScalar(share.identifier)
+	ret = append(ret, serializeScalar(&id)...)
+	ret = append(ret, serializeScalar(&share.value)...)
+	return ret
+}
+
+// SignatureShareFromBytes decodes a signature share in the format written by Bytes.
+//
+// We return an error wrapping ErrInvalidSignatureShare if buf has the wrong length, the identifier is not in [1, 2^32) or z_i encodes a number >= p253.
+// Note that succesfully decoding a share says nothing about its validity.
+func SignatureShareFromBytes(buf []byte) (SignatureShare, error) {
+	if len(buf) != SignatureShareSize {
+		return SignatureShare{}, fmt.Errorf("%w: signature share has length %v, expected %v", ErrInvalidSignatureShare, len(buf), SignatureShareSize)
+	}
+	var ret SignatureShare
+	var err error
+	if ret.identifier, err = decodeIdentifier(buf[0:ScalarSize]); err != nil {
+		return SignatureShare{}, fmt.Errorf("%w: %v", ErrInvalidSignatureShare, err)
+	}
+	if ret.value, err = keypair.DecodeScalar(buf[ScalarSize:]); err != nil {
+		return SignatureShare{}, fmt.Errorf("%w: %v", ErrInvalidSignatureShare, err)
+	}
+	return ret, nil
+}
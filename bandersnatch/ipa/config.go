@@ -0,0 +1,263 @@
+package ipa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/pedersen"
+)
+
+// This file contains the Config type, which holds the public parameters for inner-product arguments, and polynomial arithmetic in the Lagrange basis.
+//
+// Polynomials of degree < n are given by their evaluations f_0, ..., f_{n-1} at the domain 0, 1, ..., n-1 (i.e. in the Lagrange basis). By default, n = DomainSize = 256.
+// The commitment to such a polynomial is the (non-hiding) Pedersen vector commitment C = sum_i f_i * G_i.
+// The generators G_i and the additional generator Q used by the inner-product argument are taken from a pedersen.Generators (Q is its blinding generator),
+// so C is also the Pedersen commitment to (f_0, ..., f_{n-1}) with blinding factor 0.
+//
+// DefaultConfig uses the same parameters as Ethereum's Verkle tries, so commitments and proofs are interoperable with those (e.g. with go-ipa):
+// The generators G_i are derived by DeriveGenerators from the seed "eth_verkle_oct_2021": For a counter i = 0, 1, 2, ..., we compute
+//
+//	x = SHA-256(seed || i) modulo BaseFieldSize
+//
+// where i is encoded as an 8-byte big-endian number, and try to decode the 32-byte big-endian encoding of x as a point in the point encoding of Verkle tries (see readPoint).
+// Counters for which this fails (because there is no such point in the prime-order subgroup) are skipped. Q is the generator curvePoints.SubgroupGenerator_xtw_subgroup.
+//
+// For evaluating outside the domain, we use the barycentric formula
+//
+//	f(z) = A(z) * sum_i f_i * w_i / (z - i)
+//
+// with A(X) = prod_i (X - i) and barycentric weights w_i = 1/A'(i), where A'(i) = prod_{j != i} (i - j) = (-1)^(n-1-i) * i! * (n-1-i)!.
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / ipa: "
+
+// DomainSize is the size of the evaluation domain used by DefaultConfig. This is the same size as in Verkle tries.
+const DomainSize = 256
+
+// DefaultGeneratorSeed is the seed for DeriveGenerators used by DefaultConfig. This is the seed used by Verkle tries.
+const DefaultGeneratorSeed = "eth_verkle_oct_2021"
+
+var ErrInvalidConfig = errors.New(ErrorPrefix + "invalid configuration")
+
+// Config holds the public parameters for inner-product arguments and multiproofs for polynomials over a domain of size n,
+// where n is a power of two. Use DefaultConfig or NewConfig to obtain one.
+//
+// A Config is immutable after creation and can be used concurrently.
+type Config struct {
+	domainSize      int
+	numRounds       int                                      // log_2(domainSize)
+	generators      curvePoints.CurvePointSlice_xtw_subgroup // G_0, ..., G_{n-1}
+	q               curvePoints.Point_xtw_subgroup           // Q
+	weights         []exponents.Exponent                     // barycentric weights w_i = 1/A'(i)
+	weightsInverted []exponents.Exponent                     // A'(i)
+	inverses        []exponents.Exponent                     // inverses[k] = 1/k for 1 <= k < n (inverses[0] is unused)
+}
+
+var (
+	defaultConfig     *Config
+	defaultConfigOnce sync.Once
+)
+
+// DefaultConfig returns the configuration for DomainSize, with generators DeriveGenerators(DefaultGeneratorSeed, DomainSize). This is the configuration of Verkle tries.
+//
+// The configuration is created on first use.
+func DefaultConfig() *Config {
+	defaultConfigOnce.Do(func() {
+		var err error
+		defaultConfig, err = NewConfig(DeriveGenerators(DefaultGeneratorSeed, DomainSize))
+		if err != nil {
+			panic(fmt.Errorf(ErrorPrefix+"could not create default configuration: %w", err))
+		}
+	})
+	return defaultConfig
+}
+
+// DeriveGenerators derives n generators G_0, ..., G_{n-1} from seed in the same way as Verkle tries (see the documentation at the top of this file).
+// The blinding generator of the result (which is used as Q) is curvePoints.SubgroupGenerator_xtw_subgroup.
+//
+// n must be positive, else we panic.
+func DeriveGenerators(seed string, n int) *pedersen.Generators {
+	if n <= 0 {
+		panic(fmt.Errorf(ErrorPrefix+"DeriveGenerators called with n = %v", n))
+	}
+	points := make([]curvePoints.Point_xtw_subgroup, 0, n)
+	var counterBytes [8]byte
+	var xBytes [32]byte
+	x := new(big.Int)
+	for counter := uint64(0); len(points) < n; counter++ {
+		hasher := sha256.New()
+		hasher.Write([]byte(seed))
+		binary.BigEndian.PutUint64(counterBytes[:], counter)
+		hasher.Write(counterBytes[:])
+		x.SetBytes(hasher.Sum(nil))
+		x.Mod(x, common.BaseFieldSize_Int)
+		x.FillBytes(xBytes[:])
+
+		var point curvePoints.Point_xtw_subgroup
+		if err := readPoint(bytes.NewReader(xBytes[:]), &point); err != nil {
+			continue // x is not the encoding of a point in the prime-order subgroup
+		}
+		points = append(points, point)
+	}
+	ret, err := pedersen.NewGenerators(points, &curvePoints.SubgroupGenerator_xtw_subgroup)
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when deriving generators: %w", err))
+	}
+	return ret
+}
+
+// NewConfig creates a configuration using the message generators of gens as G_0, ..., G_{n-1} and the blinding generator of gens as Q.
+//
+// n = gens.Len() must be a power of two with n >= 2, else we return an error wrapping ErrInvalidConfig.
+func NewConfig(gens *pedersen.Generators) (*Config, error) {
+	n := gens.Len()
+	if n < 2 || n&(n-1) != 0 {
+		return nil, fmt.Errorf("%w: domain size must be a power of two >= 2, got %v", ErrInvalidConfig, n)
+	}
+	ret := &Config{domainSize: n, generators: gens.MessageGenerators(), q: gens.BlindingGenerator()}
+	for 1<<ret.numRounds < n {
+		ret.numRounds++
+	}
+
+	// factorials[i] == i!
+	factorials := make([]exponents.Exponent, n)
+	factorials[0].SetOne()
+	for i := 1; i < n; i++ {
+		var iExp exponents.Exponent
+		iExp.SetUInt(uint64(i))
+		factorials[i].Mul(&factorials[i-1], &iExp)
+	}
+	ret.weightsInverted = make([]exponents.Exponent, n)
+	for i := 0; i < n; i++ {
+		ret.weightsInverted[i].Mul(&factorials[i], &factorials[n-1-i])
+		if (n-1-i)%2 == 1 {
+			ret.weightsInverted[i].Neg(&ret.weightsInverted[i])
+		}
+	}
+	ret.weights = append([]exponents.Exponent(nil), ret.weightsInverted...)
+	ret.inverses = make([]exponents.Exponent, n)
+	for k := 1; k < n; k++ {
+		ret.inverses[k].SetUInt(uint64(k))
+	}
+	if err := exponents.MultiInvertEqSlice(ret.weights); err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when computing barycentric weights: %w", err))
+	}
+	if err := exponents.MultiInvertEqSlice(ret.inverses[1:]); err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when computing inverses: %w", err))
+	}
+	return ret, nil
+}
+
+// DomainSize returns the size n of the evaluation domain, i.e. the length of polynomials in the Lagrange basis.
+func (cfg *Config) DomainSize() int {
+	return cfg.domainSize
+}
+
+// Generators returns (a copy of) the generators G_0, ..., G_{n-1} used for commitments.
+func (cfg *Config) Generators() curvePoints.CurvePointSlice_xtw_subgroup {
+	return append(curvePoints.CurvePointSlice_xtw_subgroup(nil), cfg.generators...)
+}
+
+// checkPolynomial panics if poly does not have length cfg.DomainSize().
+func (cfg *Config) checkPolynomial(poly []exponents.Exponent) {
+	if len(poly) != cfg.domainSize {
+		panic(fmt.Errorf(ErrorPrefix+"polynomial has %v evaluations, expected %v", len(poly), cfg.domainSize))
+	}
+}
+
+// Commit computes the commitment sum_i poly[i] * G_i to the polynomial given by its evaluations poly[0], ..., poly[n-1].
+//
+// len(poly) must equal cfg.DomainSize(), else we panic.
+//
+// NOTE: This uses curvePoints.MultiExponentiate, which is not constant-time.
+func (cfg *Config) Commit(poly []exponents.Exponent) (ret curvePoints.Point_xtw_subgroup) {
+	cfg.checkPolynomial(poly)
+	result := curvePoints.MultiExponentiate(cfg.generators, poly)
+	ret.SetFrom(&result)
+	return
+}
+
+// domainIndex returns the index i if z == i modulo p253 for some i in the domain. Otherwise, ok is false.
+func (cfg *Config) domainIndex(z *exponents.Exponent) (index int, ok bool) {
+	zBig := z.ToBigInt_Subgroup()
+	if zBig.IsUint64() && zBig.Uint64() < uint64(cfg.domainSize) {
+		return int(zBig.Uint64()), true
+	}
+	return 0, false
+}
+
+// LagrangeCoefficients returns the values L_0(z), ..., L_{n-1}(z) of the Lagrange basis polynomials at z, so f(z) = sum_i f_i * L_i(z).
+func (cfg *Config) LagrangeCoefficients(z *exponents.Exponent) []exponents.Exponent {
+	ret := make([]exponents.Exponent, cfg.domainSize)
+	if index, ok := cfg.domainIndex(z); ok {
+		ret[index].SetOne()
+		return ret
+	}
+	// ret[i] = z - i, inverted below; A(z) = prod_i (z - i)
+	var A, iExp exponents.Exponent
+	A.SetOne()
+	for i := range ret {
+		iExp.SetUInt(uint64(i))
+		ret[i].Sub(z, &iExp)
+		A.Mul(&A, &ret[i])
+	}
+	if err := exponents.MultiInvertEqSlice(ret); err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error in barycentric evaluation: %w", err))
+	}
+	for i := range ret {
+		ret[i].Mul(&ret[i], &cfg.weights[i])
+		ret[i].Mul(&ret[i], &A)
+	}
+	return ret
+}
+
+// Evaluate evaluates the polynomial given by its evaluations poly[0], ..., poly[n-1] at z.
+//
+// len(poly) must equal cfg.DomainSize(), else we panic.
+func (cfg *Config) Evaluate(poly []exponents.Exponent, z *exponents.Exponent) exponents.Exponent {
+	cfg.checkPolynomial(poly)
+	return innerProduct(poly, cfg.LagrangeCoefficients(z))
+}
+
+// divideOnDomain computes the quotient q(X) = (f(X) - f(m)) / (X - m) in the Lagrange basis, where m is in the domain.
+//
+// For j != m, we have q_j = (f_j - f_m) / (j - m). The remaining value q_m = f'(m) is obtained from the barycentric formula for the derivative, which gives
+// q_m = - sum_{j != m} q_j * A'(m) / A'(j).
+func (cfg *Config) divideOnDomain(poly []exponents.Exponent, m int) []exponents.Exponent {
+	ret := make([]exponents.Exponent, cfg.domainSize)
+	var tmp exponents.Exponent
+	for j := range ret {
+		if j == m {
+			continue
+		}
+		ret[j].Sub(&poly[j], &poly[m])
+		if j > m {
+			ret[j].Mul(&ret[j], &cfg.inverses[j-m])
+		} else {
+			ret[j].Mul(&ret[j], &cfg.inverses[m-j])
+			ret[j].Neg(&ret[j])
+		}
+		tmp.Mul(&ret[j], &cfg.weights[j])
+		ret[m].Sub(&ret[m], &tmp)
+	}
+	ret[m].Mul(&ret[m], &cfg.weightsInverted[m])
+	return ret
+}
+
+// innerProduct computes sum_i a[i] * b[i]. The slices must have the same length.
+func innerProduct(a, b []exponents.Exponent) (ret exponents.Exponent) {
+	var tmp exponents.Exponent
+	for i := range a {
+		tmp.Mul(&a[i], &b[i])
+		ret.Add(&ret, &tmp)
+	}
+	return
+}
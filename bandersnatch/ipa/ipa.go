@@ -0,0 +1,268 @@
+package ipa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/pointserializer"
+)
+
+// This file contains the (non-zero-knowledge) Bulletproofs-style inner-product argument for opening a polynomial commitment.
+// With DefaultConfig and Transcript, proofs are the same as the inner-product arguments of Verkle tries (as implemented in go-ipa).
+//
+// Given a commitment C = <a, G> to a polynomial with evaluations a (in the Lagrange basis) and a point z, the prover shows that y = f(z) = <a, b>,
+// where b = (L_0(z), ..., L_{n-1}(z)) are the Lagrange coefficients at z (which the verifier can compute itself).
+// After appending C, z and y to the transcript, we obtain a challenge w and set Q' = w*Q. The claim then is that C + y*Q' == <a, G> + <a, b>*Q'.
+// In each of the log_2(n) rounds, we split a, b and G into left and right halves and the prover sends
+//
+//	L = <a_R, G_L> + <a_R, b_L>*Q'	and	R = <a_L, G_R> + <a_L, b_R>*Q'.
+//
+// For the challenge x, both sides fold a' = a_L + x*a_R, b' = b_L + x^-1*b_R, G' = G_L + x^-1*G_R and C' = C + x*L + x^-1*R. At the end, the prover sends the single remaining exponent a.
+//
+// The verifier does not fold the generators round by round. Rather, the final generator and final b are sum_i s_i * G_i resp. sum_i s_i * b_i,
+// where s_i is the product of x^-1 over all rounds in which i was in the right half. The verifier then checks the final equation with a single multi-exponentiation.
+//
+// A proof consists of log_2(n) points L, log_2(n) points R and one exponent; for n = 256, this is 544 bytes with the encoding of Verkle tries
+// (points as in writePoint, the exponent as a 32-byte little-endian number).
+//
+// NOTE: The prover uses non-constant-time algorithms; this is fine since the polynomial is not secret (the argument is not zero-knowledge anyway).
+
+var ErrInvalidProofEncoding = errors.New(ErrorPrefix + "invalid proof encoding")
+
+// verklePointSerializer is pointserializer.BanderwagonShort, but big-endian and without header bits.
+// Note that Verkle tries use the opposite sign convention for Y, so we need to negate points; see writePoint and readPoint.
+var verklePointSerializer = pointserializer.BanderwagonShort.WithEndianness(binary.BigEndian).WithParameter("BitHeader", common.MakeBitHeader(common.PrefixBits(0), 0))
+
+// writePoint writes the encoding of p used by Verkle tries to buf. p must be in the prime-order subgroup, else we panic.
+//
+// Verkle tries encode P as the big-endian X-coordinate of the representative of P with lexicographically largest Y (i.e. Y > (BaseFieldSize-1)/2).
+// This is X * Sign(Y) with the opposite sign convention for Y than pointserializer.BanderwagonShort, so we serialize -P instead.
+func writePoint(buf *bytes.Buffer, p *curvePoints.Point_xtw_subgroup) {
+	var negP curvePoints.Point_xtw_subgroup
+	negP.Neg(p)
+	if _, err := verklePointSerializer.SerializeCurvePoint(buf, &negP); err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"could not serialize point: %w", err))
+	}
+}
+
+// readPoint reads a point in the format written by writePoint from input. We reject non-canonical encodings and points outside the prime-order subgroup.
+//
+// On error, p is unchanged.
+func readPoint(input io.Reader, p *curvePoints.Point_xtw_subgroup) error {
+	var negP curvePoints.Point_xtw_subgroup
+	if _, err := verklePointSerializer.DeserializeCurvePoint(input, common.UntrustedInput, &negP); err != nil {
+		return err
+	}
+	p.Neg(&negP)
+	return nil
+}
+
+// proofExponentEndianness is the endianness used for exponents in proofs. Note that this differs from the endianness of points.
+var proofExponentEndianness common.FieldElementEndianness = common.LittleEndian
+
+// pointSize is the size of a serialized point in proofs.
+const pointSize = 32
+
+// Proof is an inner-product argument. Use CreateProof to create one or ProofFromBytes to decode one.
+type Proof struct {
+	L []curvePoints.Point_xtw_subgroup
+	R []curvePoints.Point_xtw_subgroup
+	A exponents.Exponent
+}
+
+// ProofSize returns the size in bytes of encoded proofs for domain size n = cfg.DomainSize().
+func (cfg *Config) ProofSize() int {
+	return 2*cfg.numRounds*pointSize + exponents.ExponentBytesLength
+}
+
+// appendStatement appends the statement (C, z, y) to the transcript and returns the challenge w.
+func appendStatement(t *Transcript, commitment *curvePoints.Point_xtw_subgroup, z, y *exponents.Exponent) exponents.Exponent {
+	t.DomainSep("ipa")
+	t.AppendPoint("C", commitment)
+	t.AppendExponent("input point", z)
+	t.AppendExponent("output point", y)
+	return t.ChallengeExponent("w")
+}
+
+// CreateProof creates an inner-product argument showing that commitment commits to a polynomial poly with poly(z) == y. It returns the proof and y.
+//
+// len(poly) must equal cfg.DomainSize(), else we panic. The caller is responsible for ensuring that commitment == cfg.Commit(poly); otherwise, the proof will not verify.
+// The transcript t is modified. The verifier must call VerifyProof with a transcript in the same state.
+func CreateProof(t *Transcript, cfg *Config, commitment *curvePoints.Point_xtw_subgroup, poly []exponents.Exponent, z *exponents.Exponent) (proof *Proof, y exponents.Exponent) {
+	cfg.checkPolynomial(poly)
+	b := cfg.LagrangeCoefficients(z)
+	y = innerProduct(poly, b)
+	a := append([]exponents.Exponent(nil), poly...)
+	G := cfg.Generators()
+
+	w := appendStatement(t, commitment, z, &y)
+	var qPrime curvePoints.Point_xtw_subgroup
+	qPrime.Exponentiate(&cfg.q, &w)
+
+	proof = &Proof{L: make([]curvePoints.Point_xtw_subgroup, cfg.numRounds), R: make([]curvePoints.Point_xtw_subgroup, cfg.numRounds)}
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, 0, cfg.domainSize/2+1)
+	scalars := make([]exponents.Exponent, 0, cfg.domainSize/2+1)
+	var tmp curvePoints.Point_xtw_subgroup
+	var scaled exponents.Exponent
+	for round := 0; round < cfg.numRounds; round++ {
+		half := len(a) / 2
+		aL, aR := a[:half], a[half:]
+		bL, bR := b[:half], b[half:]
+		GL, GR := G[:half], G[half:]
+
+		zL := innerProduct(aR, bL)
+		points = append(append(points[:0], GL...), qPrime)
+		scalars = append(append(scalars[:0], aR...), zL)
+		result := curvePoints.MultiExponentiate(points, scalars)
+		proof.L[round].SetFrom(&result)
+
+		zR := innerProduct(aL, bR)
+		points = append(append(points[:0], GR...), qPrime)
+		scalars = append(append(scalars[:0], aL...), zR)
+		result = curvePoints.MultiExponentiate(points, scalars)
+		proof.R[round].SetFrom(&result)
+
+		t.AppendPoint("L", &proof.L[round])
+		t.AppendPoint("R", &proof.R[round])
+		x := t.ChallengeExponent("x")
+		var xInv exponents.Exponent
+		xInv.Inv(&x)
+
+		for i := 0; i < half; i++ {
+			scaled.Mul(&x, &aR[i])
+			aL[i].Add(&aL[i], &scaled)
+			scaled.Mul(&xInv, &bR[i])
+			bL[i].Add(&bL[i], &scaled)
+			tmp.Exponentiate(&GR[i], &xInv)
+			GL[i].AddEq(&tmp)
+		}
+		a, b, G = aL, bL, GL
+	}
+	proof.A = a[0]
+	return
+}
+
+// VerifyProof checks an inner-product argument created by CreateProof, i.e. whether the polynomial committed to in commitment evaluates to y at z.
+//
+// The transcript t is modified. It must be in the same state as the prover's transcript when CreateProof was called.
+func VerifyProof(t *Transcript, cfg *Config, commitment *curvePoints.Point_xtw_subgroup, z, y *exponents.Exponent, proof *Proof) bool {
+	if len(proof.L) != cfg.numRounds || len(proof.R) != cfg.numRounds || commitment.IsNaP() {
+		return false
+	}
+	for i := range proof.L {
+		if proof.L[i].IsNaP() || proof.R[i].IsNaP() {
+			return false
+		}
+	}
+
+	w := appendStatement(t, commitment, z, y)
+	x := make([]exponents.Exponent, cfg.numRounds)
+	for round := range x {
+		t.AppendPoint("L", &proof.L[round])
+		t.AppendPoint("R", &proof.R[round])
+		x[round] = t.ChallengeExponent("x")
+	}
+	xInv := append([]exponents.Exponent(nil), x...)
+	if err := exponents.MultiInvertEqSlice(xInv); err != nil {
+		// a challenge of 0 only happens with negligible probability
+		return false
+	}
+
+	// s[i] is the product of xInv[round] over all rounds where i was in the right half. Round 0 corresponds to the most significant bit of i.
+	s := make([]exponents.Exponent, 1, cfg.domainSize)
+	s[0].SetOne()
+	for round := 0; round < cfg.numRounds; round++ {
+		s = s[:2*len(s)]
+		for i := len(s)/2 - 1; i >= 0; i-- {
+			s[2*i+1].Mul(&s[i], &xInv[round])
+			s[2*i] = s[i]
+		}
+	}
+	b := cfg.LagrangeCoefficients(z)
+	bFinal := innerProduct(s, b)
+
+	// We check C + y*w*Q + sum_round (x*L + x^-1*R) - a*sum_i s_i*G_i - a*bFinal*w*Q == 0 with a single multi-exponentiation.
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, 0, cfg.domainSize+2*cfg.numRounds+2)
+	scalars := make([]exponents.Exponent, 0, cfg.domainSize+2*cfg.numRounds+2)
+	var one, qScalar, tmp exponents.Exponent
+	one.SetOne()
+	points = append(points, *commitment)
+	scalars = append(scalars, one)
+	tmp.Mul(&proof.A, &bFinal)
+	qScalar.Sub(y, &tmp)
+	qScalar.Mul(&qScalar, &w)
+	points = append(points, cfg.q)
+	scalars = append(scalars, qScalar)
+	points = append(points, proof.L...)
+	scalars = append(scalars, x...)
+	points = append(points, proof.R...)
+	scalars = append(scalars, xInv...)
+	points = append(points, cfg.generators...)
+	for i := range s {
+		tmp.Mul(&proof.A, &s[i])
+		tmp.Neg(&tmp)
+		scalars = append(scalars, tmp)
+	}
+	result := curvePoints.MultiExponentiate(points, scalars)
+	return result.IsNeutralElement()
+}
+
+// Bytes returns the encoding L_0 || ... || L_{k-1} || R_0 || ... || R_{k-1} || A of the proof. Points are encoded as in Verkle tries (see writePoint), A is little-endian.
+//
+// All points must be in the prime-order subgroup, else we panic (this is always the case for proofs created by CreateProof or ProofFromBytes).
+func (proof *Proof) Bytes() []byte {
+	var buf bytes.Buffer
+	proof.writeTo(&buf)
+	return buf.Bytes()
+}
+
+// writeTo writes the encoding of the proof to buf.
+func (proof *Proof) writeTo(buf *bytes.Buffer) {
+	for i := range proof.L {
+		writePoint(buf, &proof.L[i])
+	}
+	for i := range proof.R {
+		writePoint(buf, &proof.R[i])
+	}
+	a := proof.A.ToCanonicalBytes(proofExponentEndianness)
+	buf.Write(a[:])
+}
+
+// ProofFromBytes decodes a proof for the domain size of cfg in the format written by Proof.Bytes.
+//
+// We return an error wrapping ErrInvalidProofEncoding if buf has the wrong length or contains an invalid point or exponent.
+func ProofFromBytes(buf []byte, cfg *Config) (*Proof, error) {
+	if len(buf) != cfg.ProofSize() {
+		return nil, fmt.Errorf("%w: proof has length %v, expected %v", ErrInvalidProofEncoding, len(buf), cfg.ProofSize())
+	}
+	ret := &Proof{L: make([]curvePoints.Point_xtw_subgroup, cfg.numRounds), R: make([]curvePoints.Point_xtw_subgroup, cfg.numRounds)}
+	if err := ret.readFrom(bytes.NewReader(buf)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// readFrom reads the proof from input. The lengths of proof.L and proof.R determine the number of points read.
+func (proof *Proof) readFrom(input *bytes.Reader) error {
+	for _, points := range [][]curvePoints.Point_xtw_subgroup{proof.L, proof.R} {
+		for i := range points {
+			if err := readPoint(input, &points[i]); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidProofEncoding, err)
+			}
+		}
+	}
+	var a [exponents.ExponentBytesLength]byte
+	if _, errRead := input.Read(a[:]); errRead != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProofEncoding, errRead)
+	}
+	if errExponent := proof.A.SetCanonicalBytes(a[:], proofExponentEndianness); errExponent != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProofEncoding, errExponent)
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package ipa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/pedersen"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// smallConfig returns a configuration with domain size n for faster tests.
+func smallConfig(n int) *Config {
+	cfg, err := NewConfig(pedersen.DeriveGenerators("ipa-test", n))
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func randomPolynomial(rnd *rand.Rand, n int) []exponents.Exponent {
+	ret := make([]exponents.Exponent, n)
+	for i := range ret {
+		if err := ret[i].SetRandom(rnd); err != nil {
+			panic(err)
+		}
+	}
+	return ret
+}
+
+func randomExponent(rnd *rand.Rand) exponents.Exponent {
+	return randomPolynomial(rnd, 1)[0]
+}
+
+func TestNewConfig(t *testing.T) {
+	for _, n := range []int{1, 3, 6, 100} {
+		_, err := NewConfig(pedersen.DeriveGenerators("ipa-test", n))
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidConfig), "domain size %v accepted", n)
+	}
+	cfg := DefaultConfig()
+	testutils.FatalUnless(t, cfg.DomainSize() == DomainSize, "wrong domain size")
+	testutils.FatalUnless(t, cfg.numRounds == 8, "wrong number of rounds")
+	testutils.FatalUnless(t, DefaultConfig() == cfg, "DefaultConfig not cached")
+	testutils.FatalUnless(t, cfg.ProofSize() == 544 && cfg.MultiProofSize() == 576, "unexpected proof sizes")
+
+	testutils.FatalUnless(t, cfg.q.IsEqual(&curvePoints.SubgroupGenerator_xtw_subgroup), "Q is not the Banderwagon generator")
+}
+
+// pointHex returns the hex encoding of the serialization of p as used in proofs.
+func pointHex(p *curvePoints.Point_xtw_subgroup) string {
+	var buf bytes.Buffer
+	writePoint(&buf, p)
+	return hex.EncodeToString(buf.Bytes())
+}
+
+// exponentHex returns the hex encoding of the serialization of x as used in proofs.
+func exponentHex(x *exponents.Exponent) string {
+	buf := x.ToCanonicalBytes(proofExponentEndianness)
+	return hex.EncodeToString(buf[:])
+}
+
+// testPolynomial returns the polynomial of length DomainSize whose evaluations repeat the given values.
+func testPolynomial(values ...uint64) []exponents.Exponent {
+	ret := make([]exponents.Exponent, DomainSize)
+	for i := range ret {
+		ret[i].SetUInt(values[i%len(values)])
+	}
+	return ret
+}
+
+// TestGeneratorVectors compares the generators of DefaultConfig with the test vectors from go-ipa (TestCRSGeneration).
+func TestGeneratorVectors(t *testing.T) {
+	generators := DefaultConfig().Generators()
+	testutils.FatalUnless(t, pointHex(&generators[0]) == "01587ad1336675eb912550ec2a28eb8923b824b490dd2ba82e48f14590a298a0", "first generator differs from Verkle generators")
+	testutils.FatalUnless(t, pointHex(&generators[255]) == "3de2be346b539395b0c0de56a5ccca54a317f1b5c80107b0802af9a62276a4d8", "last generator differs from Verkle generators")
+	hasher := sha256.New()
+	for i := range generators {
+		testutils.FatalUnless(t, !generators[i].IsEqual(&curvePoints.SubgroupGenerator_xtw_subgroup), "generator %v is Q", i)
+		buf, _ := hex.DecodeString(pointHex(&generators[i]))
+		hasher.Write(buf)
+	}
+	testutils.FatalUnless(t, hex.EncodeToString(hasher.Sum(nil)) == "1fcaea10bf24f750200e06fa473c76ff0468007291fa548e2d99f09ba9256fdb", "generators differ from Verkle generators")
+
+	// the generators for a smaller n are a prefix
+	smaller := DeriveGenerators(DefaultGeneratorSeed, 4).MessageGenerators()
+	for i := range smaller {
+		testutils.FatalUnless(t, smaller[i].IsEqual(&generators[i]), "DeriveGenerators is not prefix-consistent")
+	}
+	testutils.FatalUnless(t, testutils.CheckPanic(DeriveGenerators, DefaultGeneratorSeed, 0), "DeriveGenerators did not panic on n = 0")
+}
+
+// TestIPAVector compares CreateProof with the test vector from go-ipa (TestIPAConsistencySimpleProof).
+func TestIPAVector(t *testing.T) {
+	cfg := DefaultConfig()
+	poly := testPolynomial(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32)
+	commitment := cfg.Commit(poly)
+	testutils.FatalUnless(t, pointHex(&commitment) == "1b9dff8f5ebbac250d291dfe90e36283a227c64b113c37f1bfb9e7a743cdb128", "commitment differs from test vector")
+
+	var z exponents.Exponent
+	z.SetUInt(2101)
+	proverTranscript := NewTranscript("test")
+	proof, y := CreateProof(proverTranscript, cfg, &commitment, poly, &z)
+	testutils.FatalUnless(t, exponentHex(&y) == "4a353e70b03c89f161de002e8713beec0d740a5e20722fd5bd68b30540a33208", "evaluation differs from test vector")
+	proverChallenge := proverTranscript.ChallengeExponent("state")
+	testutils.FatalUnless(t, exponentHex(&proverChallenge) == "0a81881cbfd7d7197a54ebd67ed6a68b5867f3c783706675b34ece43e85e7306", "transcript state differs from test vector")
+
+	const expected = "273395a8febdaed38e94c3d874e99c911a47dd84616d54c55021d5c4131b507e46a4ec2c7e82b77ec2f533994c91ca7edaef212c666a1169b29c323eabb0cf690e0146638d0e2d543f81da4bd597bf3013e1663f340a8f87b845495598d0a3951590b6417f868edaeb3424ff174901d1185a53a3ee127fb7be0af42dda44bf992885bde279ef821a298087717ef3f2b78b2ede7f5d2ea1b60a4195de86a530eb247fd7e456012ae9a070c61635e55d1b7a340dfab8dae991d6273d099d9552815434cc1ba7bcdae341cf7928c6f25102370bdf4b26aad3af654d9dff4b3735661db3177342de5aad774a59d3e1b12754aee641d5f9cd1ecd2751471b308d2d8410add1c9fcc5a2b7371259f0538270832a98d18151f653efbc60895fab8be9650510449081626b5cd24671d1a3253487d44f589c2ff0da3557e307e520cf4e0054bbf8bdffaa24b7e4cce5092ccae5a08281ee24758374f4e65f126cacce64051905b5e2038060ad399c69ca6cb1d596d7c9cb5e161c7dcddc1a7ad62660dd4a5f69b31229b80e6b3df520714e4ea2b5896ebd48d14c7455e91c1ecf4acc5ffb36937c49413b7d1005dd6efbd526f5af5d61131ca3fcdae1218ce81c75e62b39100ec7f474b48a2bee6cef453fa1bc3db95c7c6575bc2d5927cbf7413181ac905766a4038a7b422a8ef2bf7b5059b5c546c19a33c1049482b9a9093f864913ca82290decf6e9a65bf3f66bc3ba4a8ed17b56d890a83bcbe74435a42499dec115"
+	testutils.FatalUnless(t, hex.EncodeToString(proof.Bytes()) == expected, "proof differs from test vector")
+
+	encoded, _ := hex.DecodeString(expected)
+	decoded, err := ProofFromBytes(encoded, cfg)
+	testutils.FatalUnless(t, err == nil, "could not decode test vector: %v", err)
+	verifierTranscript := NewTranscript("test")
+	testutils.FatalUnless(t, VerifyProof(verifierTranscript, cfg, &commitment, &z, &y, decoded), "test vector rejected")
+	verifierChallenge := verifierTranscript.ChallengeExponent("state")
+	testutils.FatalUnless(t, verifierChallenge.IsEqual(&proverChallenge), "prover and verifier transcripts differ")
+}
+
+func TestLagrange(t *testing.T) {
+	const n = 8
+	rnd := rand.New(rand.NewSource(1))
+	cfg := smallConfig(n)
+	poly := randomPolynomial(rnd, n)
+
+	// inside the domain
+	for i := 0; i < n; i++ {
+		var z exponents.Exponent
+		z.SetUInt(uint64(i))
+		y := cfg.Evaluate(poly, &z)
+		testutils.FatalUnless(t, y.IsEqual(&poly[i]), "evaluation at domain element %v failed", i)
+	}
+
+	// outside the domain, compare with the naive Lagrange formula L_i(z) = prod_{j != i} (z - j) / (i - j)
+	z := randomExponent(rnd)
+	coefficients := cfg.LagrangeCoefficients(&z)
+	var num, denom, iExp, jExp exponents.Exponent
+	for i := 0; i < n; i++ {
+		var expected exponents.Exponent
+		expected.SetOne()
+		iExp.SetUInt(uint64(i))
+		for j := 0; j < n; j++ {
+			if j == i {
+				continue
+			}
+			jExp.SetUInt(uint64(j))
+			num.Sub(&z, &jExp)
+			denom.Sub(&iExp, &jExp)
+			num.Divide(&num, &denom)
+			expected.Mul(&expected, &num)
+		}
+		testutils.FatalUnless(t, expected.IsEqual(&coefficients[i]), "Lagrange coefficient %v is wrong", i)
+	}
+
+	// the sum of all Lagrange coefficients is 1
+	ones := make([]exponents.Exponent, n)
+	for i := range ones {
+		ones[i].SetOne()
+	}
+	sum := cfg.Evaluate(ones, &z)
+	testutils.FatalUnless(t, sum.IsOne_Subgroup(), "constant polynomial evaluated incorrectly")
+
+	testutils.FatalUnless(t, testutils.CheckPanic(cfg.Evaluate, poly[1:], &z), "Evaluate did not panic on wrong length")
+	testutils.FatalUnless(t, testutils.CheckPanic(cfg.Commit, poly[1:]), "Commit did not panic on wrong length")
+}
+
+func TestDivideOnDomain(t *testing.T) {
+	const n = 16
+	rnd := rand.New(rand.NewSource(1))
+	cfg := smallConfig(n)
+	poly := randomPolynomial(rnd, n)
+	z := randomExponent(rnd)
+	fz := cfg.Evaluate(poly, &z)
+	for m := 0; m < n; m++ {
+		quotient := cfg.divideOnDomain(poly, m)
+		// check q(z) * (z - m) == f(z) - f(m)
+		qz := cfg.Evaluate(quotient, &z)
+		var lhs, rhs, mExp exponents.Exponent
+		mExp.SetUInt(uint64(m))
+		lhs.Sub(&z, &mExp)
+		lhs.Mul(&lhs, &qz)
+		rhs.Sub(&fz, &poly[m])
+		testutils.FatalUnless(t, lhs.IsEqual(&rhs), "divideOnDomain wrong for m = %v", m)
+	}
+}
+
+func TestIPA(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, cfg := range []*Config{smallConfig(2), smallConfig(16), DefaultConfig()} {
+		n := cfg.DomainSize()
+		poly := randomPolynomial(rnd, n)
+		commitment := cfg.Commit(poly)
+		for _, inDomain := range []bool{false, true} {
+			z := randomExponent(rnd)
+			if inDomain {
+				z.SetUInt(uint64(rnd.Intn(n)))
+			}
+			proof, y := CreateProof(NewTranscript("test"), cfg, &commitment, poly, &z)
+			expected := cfg.Evaluate(poly, &z)
+			testutils.FatalUnless(t, y.IsEqual(&expected), "CreateProof returned wrong evaluation")
+			testutils.FatalUnless(t, VerifyProof(NewTranscript("test"), cfg, &commitment, &z, &y, proof), "valid proof rejected for n = %v", n)
+
+			// wrong statements or transcripts
+			var wrongY exponents.Exponent
+			wrongY.SetOne()
+			wrongY.Add(&wrongY, &y)
+			testutils.FatalUnless(t, !VerifyProof(NewTranscript("test"), cfg, &commitment, &z, &wrongY, proof), "wrong evaluation accepted")
+			wrongZ := randomExponent(rnd)
+			testutils.FatalUnless(t, !VerifyProof(NewTranscript("test"), cfg, &commitment, &wrongZ, &y, proof), "wrong point accepted")
+			var wrongCommitment = cfg.generators[0]
+			wrongCommitment.AddEq(&commitment)
+			testutils.FatalUnless(t, !VerifyProof(NewTranscript("test"), cfg, &wrongCommitment, &z, &y, proof), "wrong commitment accepted")
+			testutils.FatalUnless(t, !VerifyProof(NewTranscript("other"), cfg, &commitment, &z, &y, proof), "wrong transcript accepted")
+
+			// serialization
+			encoded := proof.Bytes()
+			testutils.FatalUnless(t, len(encoded) == cfg.ProofSize(), "wrong proof size")
+			decoded, err := ProofFromBytes(encoded, cfg)
+			testutils.FatalUnless(t, err == nil, "decoding failed: %v", err)
+			testutils.FatalUnless(t, VerifyProof(NewTranscript("test"), cfg, &commitment, &z, &y, decoded), "decoded proof rejected")
+
+			// tampering
+			tampered := *decoded
+			tampered.A.Add(&tampered.A, &wrongY)
+			testutils.FatalUnless(t, !VerifyProof(NewTranscript("test"), cfg, &commitment, &z, &y, &tampered), "tampered proof accepted")
+			tampered = *decoded
+			tampered.L = append(tampered.L[:0:0], decoded.L...)
+			tampered.R = append(tampered.R[:0:0], decoded.R...)
+			tampered.L[0], tampered.R[0] = decoded.R[0], decoded.L[0]
+			testutils.FatalUnless(t, !VerifyProof(NewTranscript("test"), cfg, &commitment, &z, &y, &tampered), "tampered proof accepted")
+			tampered = *decoded
+			tampered.L = tampered.L[1:]
+			testutils.FatalUnless(t, !VerifyProof(NewTranscript("test"), cfg, &commitment, &z, &y, &tampered), "proof with wrong length accepted")
+		}
+	}
+}
+
+func TestProofFromBytesErrors(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	cfg := smallConfig(4)
+	poly := randomPolynomial(rnd, 4)
+	commitment := cfg.Commit(poly)
+	z := randomExponent(rnd)
+	proof, _ := CreateProof(NewTranscript("test"), cfg, &commitment, poly, &z)
+	encoded := proof.Bytes()
+
+	_, err := ProofFromBytes(encoded[1:], cfg)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidProofEncoding), "wrong length accepted")
+	_, err = ProofFromBytes(encoded, smallConfig(8))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidProofEncoding), "wrong domain size accepted")
+
+	bad := append([]byte(nil), encoded...)
+	for i := len(bad) - exponents.ExponentBytesLength; i < len(bad); i++ {
+		bad[i] = 0xff
+	}
+	_, err = ProofFromBytes(bad, cfg)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidProofEncoding), "non-reduced exponent accepted")
+
+	// Random first point; about half of all 32-byte strings are invalid.
+	for i := 0; ; i++ {
+		testutils.FatalUnless(t, i < 100, "random points were always accepted")
+		bad = append(bad[:0], encoded...)
+		rnd.Read(bad[0:pointSize])
+		bad[0] &= 0x0f
+		if _, err = ProofFromBytes(bad, cfg); err != nil {
+			testutils.FatalUnless(t, errors.Is(err, ErrInvalidProofEncoding), "wrong error %v", err)
+			break
+		}
+	}
+}
+
+func BenchmarkCreateProof(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	cfg := DefaultConfig()
+	poly := randomPolynomial(rnd, DomainSize)
+	commitment := cfg.Commit(poly)
+	z := randomExponent(rnd)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CreateProof(NewTranscript("bench"), cfg, &commitment, poly, &z)
+	}
+}
+
+func BenchmarkVerifyProof(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	cfg := DefaultConfig()
+	poly := randomPolynomial(rnd, DomainSize)
+	commitment := cfg.Commit(poly)
+	z := randomExponent(rnd)
+	proof, y := CreateProof(NewTranscript("bench"), cfg, &commitment, poly, &z)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		VerifyProof(NewTranscript("bench"), cfg, &commitment, &z, &y, proof)
+	}
+}
@@ -0,0 +1,214 @@
+package ipa
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains multiproofs, which aggregate openings of many commitments (at points in the domain) into a single inner-product argument.
+// The protocol is the multiproof scheme of Verkle tries; with DefaultConfig and Transcript, proofs are interoperable with those (as implemented in go-ipa).
+//
+// The prover wants to show f_k(z_k) == y_k for commitments C_k, k = 0, ..., m-1, where the z_k are in the domain. The protocol is:
+//
+//   - Append all (C_k, z_k, y_k) to the transcript and obtain the challenge r.
+//   - The prover commits to g(X) = sum_k r^k * (f_k(X) - y_k) / (X - z_k) as D. This is a polynomial iff all claims are correct.
+//   - Append D to the transcript and obtain the challenge t (outside the domain with overwhelming probability).
+//   - Both sides compute E = sum_k r^k / (t - z_k) * C_k, which is the commitment to h(X) = sum_k r^k * f_k(X) / (t - z_k).
+//   - The prover shows via an inner-product argument that E - D commits to a polynomial that evaluates to sum_k r^k * y_k / (t - z_k) at t.
+//     Note that this is h(t) - g(t), and the verifier can compute the value itself.
+//
+// A multiproof consists of D and the inner-product argument; for n = 256, this is 576 bytes.
+
+// ProverQuery is a single opening claim for CreateMultiProof: the polynomial Polynomial (in the Lagrange basis) committed to in Commitment, to be opened at the domain element Index.
+type ProverQuery struct {
+	Commitment curvePoints.Point_xtw_subgroup
+	Polynomial []exponents.Exponent
+	Index      int
+}
+
+// VerifierQuery is a single opening claim for VerifyMultiProof: The polynomial committed to in Commitment evaluates to Value at the domain element Index.
+type VerifierQuery struct {
+	Commitment curvePoints.Point_xtw_subgroup
+	Index      int
+	Value      exponents.Exponent
+}
+
+// MultiProof is a proof for several opening claims. Use CreateMultiProof to create one or MultiProofFromBytes to decode one.
+type MultiProof struct {
+	D   curvePoints.Point_xtw_subgroup
+	IPA Proof
+}
+
+// MultiProofSize returns the size in bytes of encoded multiproofs for domain size n = cfg.DomainSize().
+func (cfg *Config) MultiProofSize() int {
+	return pointSize + cfg.ProofSize()
+}
+
+// appendMultiProofStatement appends the opening claims to the transcript and returns the challenge r.
+func appendMultiProofStatement(t *Transcript, queries []VerifierQuery) exponents.Exponent {
+	t.DomainSep("multiproof")
+	var z exponents.Exponent
+	for k := range queries {
+		z.SetUInt(uint64(queries[k].Index))
+		t.AppendPoint("C", &queries[k].Commitment)
+		t.AppendExponent("z", &z)
+		t.AppendExponent("y", &queries[k].Value)
+	}
+	return t.ChallengeExponent("r")
+}
+
+// multiProofCoefficients computes r^k / (t - z_k) for all queries. If some t - z_k is zero (which happens with negligible probability), ok is false.
+func multiProofCoefficients(queries []VerifierQuery, r, t *exponents.Exponent) (coefficients []exponents.Exponent, ok bool) {
+	coefficients = make([]exponents.Exponent, len(queries))
+	var z exponents.Exponent
+	for k := range queries {
+		z.SetUInt(uint64(queries[k].Index))
+		coefficients[k].Sub(t, &z)
+	}
+	if err := exponents.MultiInvertEqSlice(coefficients); err != nil {
+		return nil, false
+	}
+	var rPower exponents.Exponent
+	rPower.SetOne()
+	for k := range coefficients {
+		coefficients[k].Mul(&coefficients[k], &rPower)
+		rPower.Mul(&rPower, r)
+	}
+	return coefficients, true
+}
+
+// checkIndex panics if index is not in the domain.
+func (cfg *Config) checkIndex(index int) {
+	if index < 0 || index >= cfg.domainSize {
+		panic(fmt.Errorf(ErrorPrefix+"evaluation point %v is not in the domain of size %v", index, cfg.domainSize))
+	}
+}
+
+// CreateMultiProof creates a multiproof for the given queries. The claimed values are the evaluations of the polynomials at the indices and are not part of the proof.
+//
+// There must be at least one query, every polynomial must have length cfg.DomainSize() and every index must be in the domain, else we panic.
+// The caller is responsible for ensuring that each commitment is cfg.Commit of its polynomial; otherwise, the proof will not verify.
+// The transcript t is modified. The verifier must call VerifyMultiProof with a transcript in the same state.
+func CreateMultiProof(t *Transcript, cfg *Config, queries []ProverQuery) *MultiProof {
+	if len(queries) == 0 {
+		panic(ErrorPrefix + "CreateMultiProof called without queries")
+	}
+	verifierQueries := make([]VerifierQuery, len(queries))
+	for k := range queries {
+		cfg.checkPolynomial(queries[k].Polynomial)
+		cfg.checkIndex(queries[k].Index)
+		verifierQueries[k] = VerifierQuery{Commitment: queries[k].Commitment, Index: queries[k].Index, Value: queries[k].Polynomial[queries[k].Index]}
+	}
+	r := appendMultiProofStatement(t, verifierQueries)
+
+	// g = sum_k r^k * (f_k - y_k) / (X - z_k)
+	g := make([]exponents.Exponent, cfg.domainSize)
+	var rPower, tmp exponents.Exponent
+	rPower.SetOne()
+	for k := range queries {
+		quotient := cfg.divideOnDomain(queries[k].Polynomial, queries[k].Index)
+		for i := range g {
+			tmp.Mul(&quotient[i], &rPower)
+			g[i].Add(&g[i], &tmp)
+		}
+		rPower.Mul(&rPower, &r)
+	}
+	ret := &MultiProof{D: cfg.Commit(g)}
+	t.AppendPoint("D", &ret.D)
+	tChallenge := t.ChallengeExponent("t")
+
+	coefficients, ok := multiProofCoefficients(verifierQueries, &r, &tChallenge)
+	if !ok {
+		panic(ErrorPrefix + "evaluation challenge is in the domain")
+	}
+	// h - g, where h = sum_k r^k / (t - z_k) * f_k
+	hMinusG := make([]exponents.Exponent, cfg.domainSize)
+	for k := range queries {
+		for i := range hMinusG {
+			tmp.Mul(&queries[k].Polynomial[i], &coefficients[k])
+			hMinusG[i].Add(&hMinusG[i], &tmp)
+		}
+	}
+	for i := range hMinusG {
+		hMinusG[i].Sub(&hMinusG[i], &g[i])
+	}
+	E, _ := multiProofCommitment(verifierQueries, coefficients)
+	t.AppendPoint("E", &E)
+	var EMinusD curvePoints.Point_xtw_subgroup
+	EMinusD.Sub(&E, &ret.D)
+	proof, _ := CreateProof(t, cfg, &EMinusD, hMinusG, &tChallenge)
+	ret.IPA = *proof
+	return ret
+}
+
+// multiProofCommitment computes E = sum_k coefficients[k] * C_k and the value sum_k coefficients[k] * y_k.
+func multiProofCommitment(queries []VerifierQuery, coefficients []exponents.Exponent) (E curvePoints.Point_xtw_subgroup, value exponents.Exponent) {
+	commitments := make(curvePoints.CurvePointSlice_xtw_subgroup, len(queries))
+	var tmp exponents.Exponent
+	for k := range queries {
+		commitments[k] = queries[k].Commitment
+		tmp.Mul(&coefficients[k], &queries[k].Value)
+		value.Add(&value, &tmp)
+	}
+	result := curvePoints.MultiExponentiate(commitments, coefficients)
+	E.SetFrom(&result)
+	return
+}
+
+// VerifyMultiProof checks a multiproof created by CreateMultiProof for the given queries.
+// We return false if there are no queries or some query has an index outside the domain.
+//
+// The transcript t is modified. It must be in the same state as the prover's transcript when CreateMultiProof was called.
+func VerifyMultiProof(t *Transcript, cfg *Config, queries []VerifierQuery, proof *MultiProof) bool {
+	if len(queries) == 0 || proof.D.IsNaP() {
+		return false
+	}
+	for k := range queries {
+		if queries[k].Index < 0 || queries[k].Index >= cfg.domainSize || queries[k].Commitment.IsNaP() {
+			return false
+		}
+	}
+	r := appendMultiProofStatement(t, queries)
+	t.AppendPoint("D", &proof.D)
+	tChallenge := t.ChallengeExponent("t")
+	coefficients, ok := multiProofCoefficients(queries, &r, &tChallenge)
+	if !ok {
+		return false
+	}
+	E, value := multiProofCommitment(queries, coefficients)
+	t.AppendPoint("E", &E)
+	var EMinusD curvePoints.Point_xtw_subgroup
+	EMinusD.Sub(&E, &proof.D)
+	return VerifyProof(t, cfg, &EMinusD, &tChallenge, &value, &proof.IPA)
+}
+
+// Bytes returns the encoding D || IPA of the multiproof, where IPA is encoded as by Proof.Bytes.
+//
+// All points must be in the prime-order subgroup, else we panic (this is always the case for proofs created by CreateMultiProof or MultiProofFromBytes).
+func (proof *MultiProof) Bytes() []byte {
+	var buf bytes.Buffer
+	writePoint(&buf, &proof.D)
+	proof.IPA.writeTo(&buf)
+	return buf.Bytes()
+}
+
+// MultiProofFromBytes decodes a multiproof for the domain size of cfg in the format written by MultiProof.Bytes.
+//
+// We return an error wrapping ErrInvalidProofEncoding if buf has the wrong length or contains an invalid point or exponent.
+func MultiProofFromBytes(buf []byte, cfg *Config) (*MultiProof, error) {
+	if len(buf) != cfg.MultiProofSize() {
+		return nil, fmt.Errorf("%w: multiproof has length %v, expected %v", ErrInvalidProofEncoding, len(buf), cfg.MultiProofSize())
+	}
+	ret := &MultiProof{IPA: Proof{L: make([]curvePoints.Point_xtw_subgroup, cfg.numRounds), R: make([]curvePoints.Point_xtw_subgroup, cfg.numRounds)}}
+	input := bytes.NewReader(buf)
+	if err := readPoint(input, &ret.D); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProofEncoding, err)
+	}
+	if err := ret.IPA.readFrom(input); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,146 @@
+package ipa
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// makeQueries creates numQueries queries for numPolys random polynomials (so some polynomials are opened several times).
+func makeQueries(rnd *rand.Rand, cfg *Config, numPolys, numQueries int) ([]ProverQuery, []VerifierQuery) {
+	polys := make([][]exponents.Exponent, numPolys)
+	commitments := make([]curvePoints.Point_xtw_subgroup, numPolys)
+	for i := range polys {
+		polys[i] = randomPolynomial(rnd, cfg.DomainSize())
+		commitments[i] = cfg.Commit(polys[i])
+	}
+	proverQueries := make([]ProverQuery, numQueries)
+	verifierQueries := make([]VerifierQuery, numQueries)
+	for k := range proverQueries {
+		i := k % numPolys
+		index := rnd.Intn(cfg.DomainSize())
+		proverQueries[k] = ProverQuery{Commitment: commitments[i], Polynomial: polys[i], Index: index}
+		verifierQueries[k] = VerifierQuery{Commitment: commitments[i], Index: index, Value: polys[i][index]}
+	}
+	return proverQueries, verifierQueries
+}
+
+func TestMultiProof(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, cfg := range []*Config{smallConfig(4), DefaultConfig()} {
+		for _, numQueries := range []int{1, 2, 7} {
+			proverQueries, verifierQueries := makeQueries(rnd, cfg, 3, numQueries)
+			proof := CreateMultiProof(NewTranscript("test"), cfg, proverQueries)
+			testutils.FatalUnless(t, VerifyMultiProof(NewTranscript("test"), cfg, verifierQueries, proof), "valid multiproof rejected")
+			testutils.FatalUnless(t, !VerifyMultiProof(NewTranscript("other"), cfg, verifierQueries, proof), "wrong transcript accepted")
+
+			encoded := proof.Bytes()
+			testutils.FatalUnless(t, len(encoded) == cfg.MultiProofSize(), "wrong multiproof size")
+			decoded, err := MultiProofFromBytes(encoded, cfg)
+			testutils.FatalUnless(t, err == nil, "decoding failed: %v", err)
+			testutils.FatalUnless(t, VerifyMultiProof(NewTranscript("test"), cfg, verifierQueries, decoded), "decoded multiproof rejected")
+
+			// wrong value
+			wrong := append([]VerifierQuery(nil), verifierQueries...)
+			var one exponents.Exponent
+			one.SetOne()
+			wrong[numQueries-1].Value.Add(&wrong[numQueries-1].Value, &one)
+			testutils.FatalUnless(t, !VerifyMultiProof(NewTranscript("test"), cfg, wrong, proof), "wrong value accepted")
+
+			// wrong index
+			wrong = append(wrong[:0], verifierQueries...)
+			wrong[0].Index = (wrong[0].Index + 1) % cfg.DomainSize()
+			testutils.FatalUnless(t, !VerifyMultiProof(NewTranscript("test"), cfg, wrong, proof), "wrong index accepted")
+			wrong[0].Index = cfg.DomainSize()
+			testutils.FatalUnless(t, !VerifyMultiProof(NewTranscript("test"), cfg, wrong, proof), "index outside domain accepted")
+
+			// missing query
+			if numQueries > 1 {
+				testutils.FatalUnless(t, !VerifyMultiProof(NewTranscript("test"), cfg, verifierQueries[1:], proof), "proof accepted for fewer queries")
+			}
+
+			// tampered D
+			tampered := *decoded
+			tampered.D.AddEq(&cfg.generators[0])
+			testutils.FatalUnless(t, !VerifyMultiProof(NewTranscript("test"), cfg, verifierQueries, &tampered), "tampered multiproof accepted")
+		}
+	}
+}
+
+// TestMultiProofVector compares CreateMultiProof with the test vector from go-ipa (TestMultiProofConsistency).
+func TestMultiProofVector(t *testing.T) {
+	cfg := DefaultConfig()
+	polyA := testPolynomial(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32)
+	polyB := testPolynomial(32, 31, 30, 29, 28, 27, 26, 25, 24, 23, 22, 21, 20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1)
+	commitmentA, commitmentB := cfg.Commit(polyA), cfg.Commit(polyB)
+	proverQueries := []ProverQuery{
+		{Commitment: commitmentA, Polynomial: polyA, Index: 0},
+		{Commitment: commitmentB, Polynomial: polyB, Index: 0},
+	}
+	verifierQueries := []VerifierQuery{
+		{Commitment: commitmentA, Index: 0, Value: polyA[0]},
+		{Commitment: commitmentB, Index: 0, Value: polyB[0]},
+	}
+
+	proverTranscript := NewTranscript("test")
+	proof := CreateMultiProof(proverTranscript, cfg, proverQueries)
+	proverChallenge := proverTranscript.ChallengeExponent("state")
+	testutils.FatalUnless(t, exponentHex(&proverChallenge) == "eee8a80357ff74b766eba39db90797d022e8d6dee426ded71234241be504d519", "transcript state differs from test vector")
+
+	const expected = "4f53588244efaf07a370ee3f9c467f933eed360d4fbf7a19dfc8bc49b67df4711bf1d0a720717cd6a8c75f1a668cb7cbdd63b48c676b89a7aee4298e71bd7f4013d7657146aa9736817da47051ed6a45fc7b5a61d00eb23e5df82a7f285cc10e67d444e91618465ca68d8ae4f2c916d1942201b7e2aae491ef0f809867d00e83468fb7f9af9b42ede76c1e90d89dd789ff22eb09e8b1d062d8a58b6f88b3cbe80136fc68331178cd45a1df9496ded092d976911b5244b85bc3de41e844ec194256b39aeee4ea55538a36139211e9910ad6b7a74e75d45b869d0a67aa4bf600930a5f760dfb8e4df9938d1f47b743d71c78ba8585e3b80aba26d24b1f50b36fa1458e79d54c05f58049245392bc3e2b5c5f9a1b99d43ed112ca82b201fb143d401741713188e47f1d6682b0bf496a5d4182836121efff0fd3b030fc6bfb5e21d6314a200963fe75cb856d444a813426b2084dfdc49dca2e649cb9da8bcb47859a4c629e97898e3547c591e39764110a224150d579c33fb74fa5eb96427036899c04154feab5344873d36a53a5baefd78c132be419f3f3a8dd8f60f72eb78dd5f43c53226f5ceb68947da3e19a750d760fb31fa8d4c7f53bfef11c4b89158aa56b1f4395430e16a3128f88e234ce1df7ef865f2d2c4975e8c82225f578310c31fd41d265fd530cbfa2b8895b228a510b806c31dff3b1fa5c08bffad443d567ed0e628febdd22775776e0cc9cebcaea9c6df9279a5d91dd0ee5e7a0434e989a160005321c97026cb559f71db23360105460d959bcdf74bee22c4ad8805a1d497507"
+	testutils.FatalUnless(t, hex.EncodeToString(proof.Bytes()) == expected, "multiproof differs from test vector")
+
+	encoded, _ := hex.DecodeString(expected)
+	decoded, err := MultiProofFromBytes(encoded, cfg)
+	testutils.FatalUnless(t, err == nil, "could not decode test vector: %v", err)
+	verifierTranscript := NewTranscript("test")
+	testutils.FatalUnless(t, VerifyMultiProof(verifierTranscript, cfg, verifierQueries, decoded), "test vector rejected")
+	verifierChallenge := verifierTranscript.ChallengeExponent("state")
+	testutils.FatalUnless(t, verifierChallenge.IsEqual(&proverChallenge), "prover and verifier transcripts differ")
+}
+
+func TestMultiProofErrors(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	cfg := smallConfig(4)
+	proverQueries, _ := makeQueries(rnd, cfg, 2, 2)
+	testutils.FatalUnless(t, testutils.CheckPanic(CreateMultiProof, NewTranscript("test"), cfg, []ProverQuery(nil)), "no panic without queries")
+	bad := append([]ProverQuery(nil), proverQueries...)
+	bad[0].Index = -1
+	testutils.FatalUnless(t, testutils.CheckPanic(CreateMultiProof, NewTranscript("test"), cfg, bad), "no panic for index outside domain")
+	bad[0] = proverQueries[0]
+	bad[0].Polynomial = bad[0].Polynomial[1:]
+	testutils.FatalUnless(t, testutils.CheckPanic(CreateMultiProof, NewTranscript("test"), cfg, bad), "no panic for wrong polynomial length")
+
+	proof := CreateMultiProof(NewTranscript("test"), cfg, proverQueries)
+	testutils.FatalUnless(t, !VerifyMultiProof(NewTranscript("test"), cfg, nil, proof), "proof accepted without queries")
+
+	encoded := proof.Bytes()
+	_, err := MultiProofFromBytes(encoded[:len(encoded)-1], cfg)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidProofEncoding), "wrong length accepted")
+}
+
+func BenchmarkCreateMultiProof(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	cfg := DefaultConfig()
+	proverQueries, _ := makeQueries(rnd, cfg, 16, 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CreateMultiProof(NewTranscript("bench"), cfg, proverQueries)
+	}
+}
+
+func BenchmarkVerifyMultiProof(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	cfg := DefaultConfig()
+	proverQueries, verifierQueries := makeQueries(rnd, cfg, 16, 16)
+	proof := CreateMultiProof(NewTranscript("bench"), cfg, proverQueries)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		VerifyMultiProof(NewTranscript("bench"), cfg, verifierQueries, proof)
+	}
+}
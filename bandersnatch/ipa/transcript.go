@@ -0,0 +1,81 @@
+package ipa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"math/big"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains Transcript, the Fiat-Shamir transcript used by Verkle tries (as implemented in go-ipa and rust-verkle).
+//
+// The transcript is a running SHA-256 hash, initialized with a protocol label. Messages are appended as label || data without any length prefixes;
+// domain separators are appended as plain labels. When a challenge with a given label is requested, we append the label, set
+//
+//	challenge = SHA-256(everything appended so far) interpreted as a little-endian number modulo p253,
+//
+// restart the hash and append the challenge (with the same label) as the first message of the new hash.
+// Points are appended in the encoding of Verkle tries (see writePoint), exponents as 32-byte little-endian numbers modulo p253.
+//
+// NOTE: Contrary to sigma.Transcript, this is not injective: Since there are no length prefixes, different sequences of labels and messages can give the same transcript.
+// This is fine for the fixed message formats of this package, but Transcript should not be used for other protocols.
+// Also, since SHA-256 outputs only 256 bits and 2^256 is about 8.83 * p253, the challenges have a statistical distance of about 2^-6 from uniform modulo p253.
+// This increases the probability of any given challenge by a factor of at most 9/8.83, which does not matter for the soundness of the protocols here,
+// but is another reason not to use Transcript elsewhere.
+
+// transcriptExponentEndianness is the endianness used for appending exponents and for interpreting hash outputs as challenges.
+var transcriptExponentEndianness common.FieldElementEndianness = common.LittleEndian
+
+// Transcript is a Fiat-Shamir transcript in the format of Verkle tries. Use NewTranscript to create one. The zero value is not a valid transcript.
+type Transcript struct {
+	state hash.Hash
+}
+
+// NewTranscript creates a new transcript for the protocol identified by protocolLabel. Verkle tries use the label "vt".
+func NewTranscript(protocolLabel string) *Transcript {
+	ret := &Transcript{state: sha256.New()}
+	ret.state.Write([]byte(protocolLabel))
+	return ret
+}
+
+// DomainSep appends the domain separator label to the transcript.
+func (t *Transcript) DomainSep(label string) {
+	t.state.Write([]byte(label))
+}
+
+// AppendMessage appends label || data to the transcript.
+func (t *Transcript) AppendMessage(label string, data []byte) {
+	t.state.Write([]byte(label))
+	t.state.Write(data)
+}
+
+// AppendPoint appends the curve point p to the transcript. p must be in the prime-order subgroup, else we panic.
+func (t *Transcript) AppendPoint(label string, p *curvePoints.Point_xtw_subgroup) {
+	var buf bytes.Buffer
+	writePoint(&buf, p)
+	t.AppendMessage(label, buf.Bytes())
+}
+
+// AppendExponent appends the exponent x (modulo p253) to the transcript.
+func (t *Transcript) AppendExponent(label string, x *exponents.Exponent) {
+	buf := x.ToCanonicalBytes(transcriptExponentEndianness)
+	t.AppendMessage(label, buf[:])
+}
+
+// ChallengeExponent derives a challenge exponent modulo p253 from the transcript. See the documentation at the top of this file.
+func (t *Transcript) ChallengeExponent(label string) (ret exponents.Exponent) {
+	t.DomainSep(label)
+	digest := t.state.Sum(nil)
+	// digest is little-endian, but big.Int.SetBytes expects big-endian.
+	for i, j := 0, len(digest)-1; i < j; i, j = i+1, j-1 {
+		digest[i], digest[j] = digest[j], digest[i]
+	}
+	ret.SetBigInt(new(big.Int).SetBytes(digest))
+	t.state.Reset()
+	t.AppendExponent(label, &ret)
+	return
+}
@@ -0,0 +1,43 @@
+package ipa
+
+import (
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// TestTranscriptVectors compares Transcript with the test vectors from go-ipa (common/transcript_test.go).
+func TestTranscriptVectors(t *testing.T) {
+	tr := NewTranscript("simple_protocol")
+	challenge1 := tr.ChallengeExponent("simple_challenge")
+	challenge2 := tr.ChallengeExponent("simple_challenge")
+	testutils.FatalUnless(t, !challenge1.IsEqual(&challenge2), "repeated challenges are equal")
+	testutils.FatalUnless(t, exponentHex(&challenge1) == "c2aa02607cbdf5595f00ee0dd94a2bbff0bed6a2bf8452ada9011eadb538d003", "challenge differs from test vector 1")
+
+	var five exponents.Exponent
+	five.SetUInt(5)
+	tr = NewTranscript("simple_protocol")
+	tr.AppendExponent("five", &five)
+	tr.AppendExponent("five again", &five)
+	challenge := tr.ChallengeExponent("simple_challenge")
+	testutils.FatalUnless(t, exponentHex(&challenge) == "498732b694a8ae1622d4a9347535be589e4aee6999ffc0181d13fe9e4d037b0b", "challenge differs from test vector 2")
+
+	var one, minusOne exponents.Exponent
+	one.SetOne()
+	minusOne.SetInt(-1)
+	tr = NewTranscript("simple_protocol")
+	tr.AppendExponent("-1", &minusOne)
+	tr.DomainSep("separate me")
+	tr.AppendExponent("-1 again", &minusOne)
+	tr.DomainSep("separate me again")
+	tr.AppendExponent("now 1", &one)
+	challenge = tr.ChallengeExponent("simple_challenge")
+	testutils.FatalUnless(t, exponentHex(&challenge) == "14f59938e9e9b1389e74311a464f45d3d88d8ac96adf1c1129ac466de088d618", "challenge differs from test vector 3")
+
+	tr = NewTranscript("simple_protocol")
+	tr.AppendPoint("generator", &curvePoints.SubgroupGenerator_xtw_subgroup)
+	challenge = tr.ChallengeExponent("simple_challenge")
+	testutils.FatalUnless(t, exponentHex(&challenge) == "8c2dafe7c0aabfa9ed542bb2cbf0568399ae794fc44fdfd7dff6cc0e6144921c", "challenge differs from test vector 4")
+}
@@ -0,0 +1,151 @@
+package pedersen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains Pedersen vector commitments.
+//
+// A commitment to a vector (m_0, ..., m_{k-1}) of exponents with k <= n and blinding factor r is C = sum_i m_i*G_i + r*H.
+// Commitments are perfectly hiding (if r is uniform) and computationally binding under the discrete logarithm assumption.
+// They are additively homomorphic: commit(m, r) + commit(m', r') == commit(m + m', r + r') and c * commit(m, r) == commit(c*m, c*r).
+//
+// Commitments are serialized as a single point via pointserializer.BanderwagonShort.
+//
+// NOTE: Computing commitments uses curvePoints.MultiExponentiate, which is not constant-time. The running time may leak information about the committed values.
+
+// CommitmentSize is the size in bytes of serialized commitments.
+const CommitmentSize = 32
+
+var ErrInvalidCommitmentEncoding = errors.New(ErrorPrefix + "invalid commitment encoding")
+
+// Commitment is a Pedersen vector commitment. The zero value is not a valid commitment; use Generators.Commit, CommitmentFromBytes or Deserialize to obtain one.
+type Commitment struct {
+	point curvePoints.Point_xtw_subgroup
+}
+
+// Commit computes the commitment sum_i values[i]*G_i + blinding*H.
+//
+// len(values) must be at most gens.Len(), else we panic. Shorter vectors are treated as if padded with zeros.
+func (gens *Generators) Commit(values []exponents.Exponent, blinding *exponents.Exponent) (ret Commitment) {
+	if len(values) > len(gens.messageGenerators) {
+		panic(fmt.Errorf(ErrorPrefix+"trying to commit to a vector of length %v with generators for length %v", len(values), len(gens.messageGenerators)))
+	}
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, 0, len(values)+1)
+	points = append(points, gens.messageGenerators[0:len(values)]...)
+	points = append(points, gens.blindingGenerator)
+	scalars := make([]exponents.Exponent, 0, len(values)+1)
+	scalars = append(scalars, values...)
+	scalars = append(scalars, *blinding)
+	result := curvePoints.MultiExponentiate(points, scalars)
+	ret.point.SetFrom(&result)
+	return
+}
+
+// CommitRandom computes a commitment to values with a uniformly random blinding factor, which is read from rnd (crypto/rand.Reader if rnd is nil).
+// It returns the commitment and the blinding factor, which is needed to open the commitment.
+//
+// We return an error if reading from rnd fails. len(values) must be at most gens.Len(), else we panic.
+func (gens *Generators) CommitRandom(values []exponents.Exponent, rnd io.Reader) (commitment Commitment, blinding exponents.Exponent, err error) {
+	if err = blinding.SetRandom(rnd); err != nil {
+		return
+	}
+	commitment = gens.Commit(values, &blinding)
+	return
+}
+
+// VerifyOpening checks whether (values, blinding) is an opening of the commitment c.
+//
+// len(values) must be at most gens.Len(), else we panic.
+func (gens *Generators) VerifyOpening(c *Commitment, values []exponents.Exponent, blinding *exponents.Exponent) bool {
+	expected := gens.Commit(values, blinding)
+	return expected.IsEqual(c)
+}
+
+// Add sets z = x + y. This is a commitment to the sum of the committed vectors with the sum of the blinding factors.
+func (z *Commitment) Add(x, y *Commitment) {
+	z.point.Add(&x.point, &y.point)
+}
+
+// Sub sets z = x - y. This is a commitment to the difference of the committed vectors with the difference of the blinding factors.
+func (z *Commitment) Sub(x, y *Commitment) {
+	z.point.Sub(&x.point, &y.point)
+}
+
+// Scale sets z = factor * x. This is a commitment to the committed vector multiplied by factor with the blinding factor multiplied by factor.
+func (z *Commitment) Scale(x *Commitment, factor *exponents.Exponent) {
+	z.point.Exponentiate(&x.point, factor)
+}
+
+// IsEqual checks whether z and x are the same commitment.
+func (z *Commitment) IsEqual(x *Commitment) bool {
+	return z.point.IsEqual(&x.point)
+}
+
+// Point returns the commitment as a curve point.
+func (z *Commitment) Point() curvePoints.Point_xtw_subgroup {
+	return z.point
+}
+
+// CommitmentFromPoint creates a commitment from a curve point. p must not be a NaP, else we panic.
+func CommitmentFromPoint(p *curvePoints.Point_xtw_subgroup) (ret Commitment) {
+	if p.IsNaP() {
+		panic(ErrorPrefix + "CommitmentFromPoint called with NaP")
+	}
+	ret.point = *p
+	return
+}
+
+// Serialize writes the commitment to output. It returns the number of bytes written and an error (nil if ok), which can only come from output.
+func (z *Commitment) Serialize(output io.Writer) (bytesWritten int, err error) {
+	bytesWritten, errSerialize := pointSerializer.SerializeCurvePoint(output, &z.point)
+	if errSerialize != nil {
+		err = errSerialize
+	}
+	return
+}
+
+// Deserialize reads a commitment in the format written by Serialize from input.
+//
+// It returns the number of bytes read and an error (nil if ok). On error, z is unchanged.
+func (z *Commitment) Deserialize(input io.Reader) (bytesRead int, err error) {
+	var point curvePoints.Point_xtw_subgroup
+	bytesRead, errDeserialize := pointSerializer.DeserializeCurvePoint(input, common.UntrustedInput, &point)
+	if errDeserialize != nil {
+		err = errDeserialize
+		return
+	}
+	z.point = point
+	return
+}
+
+// Bytes returns the serialization of the commitment.
+func (z *Commitment) Bytes() []byte {
+	var buf bytes.Buffer
+	bytesWritten, err := z.Serialize(&buf)
+	if err != nil || bytesWritten != CommitmentSize {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when serializing commitment: %w", err))
+	}
+	return buf.Bytes()
+}
+
+// CommitmentFromBytes decodes a commitment in the format written by Bytes.
+//
+// We return an error wrapping ErrInvalidCommitmentEncoding if buf has the wrong length or does not encode a point in the prime-order subgroup.
+func CommitmentFromBytes(buf []byte) (ret Commitment, err error) {
+	if len(buf) != CommitmentSize {
+		err = fmt.Errorf("%w: commitment has length %v, expected %v", ErrInvalidCommitmentEncoding, len(buf), CommitmentSize)
+		return
+	}
+	if _, errDeserialize := ret.Deserialize(bytes.NewReader(buf)); errDeserialize != nil {
+		err = fmt.Errorf("%w: %v", ErrInvalidCommitmentEncoding, errDeserialize)
+	}
+	return
+}
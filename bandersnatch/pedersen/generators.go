@@ -0,0 +1,154 @@
+package pedersen
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/pointserializer"
+)
+
+// This file contains the generators used for Pedersen vector commitments.
+//
+// A set of generators for vectors of length n consists of n message generators G_0, ..., G_{n-1} and a blinding generator H, all in the prime-order subgroup.
+// For binding commitments, nobody must know a non-trivial linear relation between these generators.
+// We ensure this by deriving each generator via hash_to_curve (RFC 9380, suite bandersnatch_XMD:SHA-512_ELL2_RO_) with domain separation tag GeneratorDST from
+//
+//	len(seed) || seed || tag || i
+//
+// where len(seed) and i are encoded as 8-byte big-endian numbers and tag is the byte 'G' for message generators G_i resp. 'H' for the blinding generator (with i = 0).
+// Note that the message generators for a smaller n are a prefix of those for a larger n with the same seed.
+//
+// Generators can be exported and imported via the Banderwagon encoding pointserializer.BanderwagonShort as the list G_0, ..., G_{n-1}, H.
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / pedersen: "
+
+// GeneratorDST is the domain separation tag for deriving generators.
+const GeneratorDST = "BANDERSNATCH-PEDERSEN-V01-GENERATORS"
+
+// tags distinguishing message and blinding generators in the derivation.
+const (
+	messageGeneratorTag  byte = 'G'
+	blindingGeneratorTag byte = 'H'
+)
+
+var ErrInvalidGenerators = errors.New(ErrorPrefix + "invalid generators")
+
+// pointSerializer is the serializer used for generators and commitments.
+var pointSerializer = pointserializer.BanderwagonShort
+
+// Generators is a set of generators for Pedersen vector commitments. Use DeriveGenerators or DeserializeGenerators to obtain one.
+//
+// Generators are immutable after creation and can be used concurrently.
+type Generators struct {
+	messageGenerators curvePoints.CurvePointSlice_xtw_subgroup // G_0, ..., G_{n-1}
+	blindingGenerator curvePoints.Point_xtw_subgroup           // H
+}
+
+// deriveGenerator computes the generator with the given tag and index from seed.
+func deriveGenerator(seed string, tag byte, index uint64) curvePoints.Point_xtw_subgroup {
+	msg := make([]byte, 0, 8+len(seed)+1+8)
+	msg = binary.BigEndian.AppendUint64(msg, uint64(len(seed)))
+	msg = append(msg, seed...)
+	msg = append(msg, tag)
+	msg = binary.BigEndian.AppendUint64(msg, index)
+	return curvePoints.HashToCurve(msg, []byte(GeneratorDST))
+}
+
+// DeriveGenerators derives generators for committing to vectors of length n from seed. See the documentation at the top of this file.
+//
+// n must be positive, else we panic.
+func DeriveGenerators(seed string, n int) *Generators {
+	if n <= 0 {
+		panic(fmt.Errorf(ErrorPrefix+"DeriveGenerators called with n = %v", n))
+	}
+	ret := &Generators{messageGenerators: make(curvePoints.CurvePointSlice_xtw_subgroup, n)}
+	for i := 0; i < n; i++ {
+		ret.messageGenerators[i] = deriveGenerator(seed, messageGeneratorTag, uint64(i))
+	}
+	ret.blindingGenerator = deriveGenerator(seed, blindingGeneratorTag, 0)
+	return ret
+}
+
+// NewGenerators creates generators from the given message generators and blinding generator, which are copied.
+//
+// We return an error wrapping ErrInvalidGenerators if there are no message generators or some generator is a NaP or the neutral element.
+//
+// NOTE: The binding property of commitments requires that nobody knows a linear relation between the generators. This cannot be checked;
+// it is the caller's responsibility to obtain the generators from a trusted source (such as DeriveGenerators).
+func NewGenerators(messageGenerators []curvePoints.Point_xtw_subgroup, blindingGenerator *curvePoints.Point_xtw_subgroup) (*Generators, error) {
+	if len(messageGenerators) == 0 {
+		return nil, fmt.Errorf("%w: no message generators", ErrInvalidGenerators)
+	}
+	ret := &Generators{messageGenerators: make(curvePoints.CurvePointSlice_xtw_subgroup, len(messageGenerators)), blindingGenerator: *blindingGenerator}
+	copy(ret.messageGenerators, messageGenerators)
+	for i := 0; i <= len(messageGenerators); i++ {
+		var p *curvePoints.Point_xtw_subgroup
+		if i == len(messageGenerators) {
+			p = &ret.blindingGenerator
+		} else {
+			p = &ret.messageGenerators[i]
+		}
+		if p.IsNaP() || p.IsNeutralElement() {
+			return nil, fmt.Errorf("%w: generator %v is a NaP or the neutral element", ErrInvalidGenerators, i)
+		}
+	}
+	return ret, nil
+}
+
+// Len returns the number n of message generators, i.e. the maximal length of vectors we can commit to.
+func (gens *Generators) Len() int {
+	return len(gens.messageGenerators)
+}
+
+// MessageGenerators returns (a copy of) the message generators G_0, ..., G_{n-1}.
+func (gens *Generators) MessageGenerators() curvePoints.CurvePointSlice_xtw_subgroup {
+	return append(curvePoints.CurvePointSlice_xtw_subgroup(nil), gens.messageGenerators...)
+}
+
+// BlindingGenerator returns the blinding generator H.
+func (gens *Generators) BlindingGenerator() curvePoints.Point_xtw_subgroup {
+	return gens.blindingGenerator
+}
+
+// Points returns (a copy of) all generators as the list G_0, ..., G_{n-1}, H. This is the order used by Serialize.
+func (gens *Generators) Points() curvePoints.CurvePointSlice_xtw_subgroup {
+	ret := make(curvePoints.CurvePointSlice_xtw_subgroup, 0, len(gens.messageGenerators)+1)
+	ret = append(ret, gens.messageGenerators...)
+	ret = append(ret, gens.blindingGenerator)
+	return ret
+}
+
+// Serialize writes the generators G_0, ..., G_{n-1}, H to output via BanderwagonShort.SerializeCurvePoints. Note that n itself is not written.
+//
+// It returns the number of bytes written and an error (nil if ok), which can only come from output.
+func (gens *Generators) Serialize(output io.Writer) (bytesWritten int, err error) {
+	bytesWritten, errSerialize := pointSerializer.SerializeCurvePoints(output, gens.Points())
+	if errSerialize != nil {
+		err = errSerialize
+	}
+	return
+}
+
+// DeserializeGenerators reads generators for vectors of length n in the format written by Generators.Serialize, i.e. n+1 points, from input.
+//
+// It returns the generators, the number of bytes read and an error (nil if ok).
+// We return an error wrapping ErrInvalidGenerators if the points read are not valid generators (see NewGenerators) and the error from the deserializer otherwise.
+// n must be positive, else we panic.
+func DeserializeGenerators(input io.Reader, n int) (gens *Generators, bytesRead int, err error) {
+	if n <= 0 {
+		panic(fmt.Errorf(ErrorPrefix+"DeserializeGenerators called with n = %v", n))
+	}
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, n+1)
+	bytesRead, errDeserialize := pointSerializer.DeserializeCurvePoints(input, common.UntrustedInput, points)
+	if errDeserialize != nil {
+		err = errDeserialize
+		return
+	}
+	gens, err = NewGenerators(points[0:n], &points[n])
+	return
+}
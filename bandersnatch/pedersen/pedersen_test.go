@@ -0,0 +1,241 @@
+package pedersen
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func randomExponents(rnd *rand.Rand, n int) []exponents.Exponent {
+	ret := make([]exponents.Exponent, n)
+	for i := range ret {
+		if err := ret[i].SetRandom(rnd); err != nil {
+			panic(err)
+		}
+	}
+	return ret
+}
+
+func TestDeriveGenerators(t *testing.T) {
+	gens := DeriveGenerators("test", 8)
+	testutils.FatalUnless(t, gens.Len() == 8, "wrong length")
+	points := gens.Points()
+	testutils.FatalUnless(t, len(points) == 9, "wrong number of points")
+	for i := range points {
+		testutils.FatalUnless(t, !points[i].IsNeutralElement(), "generator %v is neutral", i)
+		for j := 0; j < i; j++ {
+			testutils.FatalUnless(t, !points[i].IsEqual(&points[j]), "generators %v and %v coincide", i, j)
+		}
+	}
+	H := gens.BlindingGenerator()
+	testutils.FatalUnless(t, H.IsEqual(&points[8]), "blinding generator is not the last point")
+
+	// deterministic and prefix-compatible
+	smaller := DeriveGenerators("test", 3)
+	smallerH := smaller.BlindingGenerator()
+	testutils.FatalUnless(t, smallerH.IsEqual(&H), "blinding generator depends on n")
+	for i, G := range smaller.MessageGenerators() {
+		testutils.FatalUnless(t, G.IsEqual(&points[i]), "message generators are not prefix-compatible")
+	}
+
+	other := DeriveGenerators("test2", 3)
+	otherPoints := other.Points()
+	for i := range otherPoints {
+		testutils.FatalUnless(t, !otherPoints[i].IsEqual(&points[i]), "generators do not depend on seed")
+	}
+
+	// returned slices are copies
+	gens.MessageGenerators()[0] = curvePoints.NeutralElement_xtw_subgroup
+	gens.Points()[0] = curvePoints.NeutralElement_xtw_subgroup
+	testutils.FatalUnless(t, !gens.messageGenerators[0].IsNeutralElement(), "generators were modified via returned slice")
+
+	testutils.FatalUnless(t, testutils.CheckPanic(DeriveGenerators, "test", 0), "DeriveGenerators did not panic for n = 0")
+}
+
+func TestGeneratorsSerialization(t *testing.T) {
+	gens := DeriveGenerators("serialization", 5)
+	var buf bytes.Buffer
+	bytesWritten, err := gens.Serialize(&buf)
+	testutils.FatalUnless(t, err == nil, "serialization failed: %v", err)
+	testutils.FatalUnless(t, bytesWritten == 6*CommitmentSize && buf.Len() == bytesWritten, "unexpected length %v", bytesWritten)
+	encoded := append([]byte(nil), buf.Bytes()...)
+
+	gens2, bytesRead, err := DeserializeGenerators(&buf, 5)
+	testutils.FatalUnless(t, err == nil, "deserialization failed: %v", err)
+	testutils.FatalUnless(t, bytesRead == bytesWritten, "unexpected number of bytes read %v", bytesRead)
+	points, points2 := gens.Points(), gens2.Points()
+	for i := range points {
+		testutils.FatalUnless(t, points[i].IsEqual(&points2[i]), "roundtrip failed at %v", i)
+	}
+
+	_, _, err = DeserializeGenerators(bytes.NewReader(encoded[:len(encoded)-1]), 5)
+	testutils.FatalUnless(t, err != nil, "no error on truncated input")
+	_, _, err = DeserializeGenerators(iotest.ErrReader(errors.New("read error")), 5)
+	testutils.FatalUnless(t, err != nil, "no error on failing reader")
+
+	// neutral element as generator
+	neutral := append(gens.MessageGenerators(), curvePoints.NeutralElement_xtw_subgroup)
+	buf.Reset()
+	_, err = pointSerializer.SerializeCurvePoints(&buf, neutral)
+	testutils.FatalUnless(t, err == nil, "serialization failed: %v", err)
+	_, _, err = DeserializeGenerators(&buf, 5)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidGenerators), "neutral element accepted as generator: %v", err)
+
+	testutils.FatalUnless(t, testutils.CheckPanic(DeserializeGenerators, &buf, 0), "DeserializeGenerators did not panic for n = 0")
+}
+
+func TestNewGenerators(t *testing.T) {
+	gens := DeriveGenerators("new", 2)
+	H := gens.BlindingGenerator()
+	_, err := NewGenerators(nil, &H)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidGenerators), "empty generators accepted")
+	_, err = NewGenerators(gens.MessageGenerators(), &curvePoints.NeutralElement_xtw_subgroup)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidGenerators), "neutral blinding generator accepted")
+	var nap curvePoints.Point_xtw_subgroup
+	_, err = NewGenerators([]curvePoints.Point_xtw_subgroup{nap}, &H)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidGenerators), "NaP generator accepted")
+
+	messageGenerators := gens.MessageGenerators()
+	gens2, err := NewGenerators(messageGenerators, &H)
+	testutils.FatalUnless(t, err == nil, "valid generators rejected: %v", err)
+	messageGenerators[0] = H
+	testutils.FatalUnless(t, gens2.messageGenerators[0].IsEqual(&gens.messageGenerators[0]), "NewGenerators did not copy")
+}
+
+func TestCommit(t *testing.T) {
+	const n = 6
+	rnd := rand.New(rand.NewSource(1))
+	gens := DeriveGenerators("commit", n)
+	values := randomExponents(rnd, n)
+	blinding := randomExponents(rnd, 1)[0]
+
+	c := gens.Commit(values, &blinding)
+
+	// compare with naive computation
+	var expected, tmp curvePoints.Point_xtw_subgroup
+	expected.Exponentiate(&gens.blindingGenerator, &blinding)
+	for i := range values {
+		tmp.Exponentiate(&gens.messageGenerators[i], &values[i])
+		expected.AddEq(&tmp)
+	}
+	point := c.Point()
+	testutils.FatalUnless(t, point.IsEqual(&expected), "commitment differs from naive computation")
+
+	testutils.FatalUnless(t, gens.VerifyOpening(&c, values, &blinding), "valid opening rejected")
+	values[2].Add(&values[2], &blinding)
+	testutils.FatalUnless(t, !gens.VerifyOpening(&c, values, &blinding), "wrong opening accepted")
+
+	// shorter vectors are padded with zeros
+	short := randomExponents(rnd, 2)
+	padded := append(append([]exponents.Exponent(nil), short...), make([]exponents.Exponent, n-2)...)
+	c1 := gens.Commit(short, &blinding)
+	c2 := gens.Commit(padded, &blinding)
+	testutils.FatalUnless(t, c1.IsEqual(&c2), "short vector not treated as padded with zeros")
+
+	testutils.FatalUnless(t, testutils.CheckPanic(gens.Commit, randomExponents(rnd, n+1), &blinding), "Commit did not panic on too long vector")
+
+	c3, blinding3, err := gens.CommitRandom(short, rnd)
+	testutils.FatalUnless(t, err == nil, "CommitRandom failed: %v", err)
+	testutils.FatalUnless(t, gens.VerifyOpening(&c3, short, &blinding3), "CommitRandom returned wrong blinding")
+	_, _, err = gens.CommitRandom(short, iotest.ErrReader(errors.New("read error")))
+	testutils.FatalUnless(t, err != nil, "CommitRandom did not report randomness error")
+}
+
+func TestHomomorphism(t *testing.T) {
+	const n = 4
+	rnd := rand.New(rand.NewSource(1))
+	gens := DeriveGenerators("homomorphism", n)
+	x, y := randomExponents(rnd, n), randomExponents(rnd, n)
+	r := randomExponents(rnd, 3)
+	cx := gens.Commit(x, &r[0])
+	cy := gens.Commit(y, &r[1])
+	factor := &r[2]
+
+	sum, diff, scaled := make([]exponents.Exponent, n), make([]exponents.Exponent, n), make([]exponents.Exponent, n)
+	for i := 0; i < n; i++ {
+		sum[i].Add(&x[i], &y[i])
+		diff[i].Sub(&x[i], &y[i])
+		scaled[i].Mul(&x[i], factor)
+	}
+	var rSum, rDiff, rScaled exponents.Exponent
+	rSum.Add(&r[0], &r[1])
+	rDiff.Sub(&r[0], &r[1])
+	rScaled.Mul(&r[0], factor)
+
+	var c Commitment
+	c.Add(&cx, &cy)
+	testutils.FatalUnless(t, gens.VerifyOpening(&c, sum, &rSum), "Add is not homomorphic")
+	c.Sub(&cx, &cy)
+	testutils.FatalUnless(t, gens.VerifyOpening(&c, diff, &rDiff), "Sub is not homomorphic")
+	c.Scale(&cx, factor)
+	testutils.FatalUnless(t, gens.VerifyOpening(&c, scaled, &rScaled), "Scale is not homomorphic")
+
+	// aliasing
+	c = cx
+	c.Add(&c, &cy)
+	testutils.FatalUnless(t, gens.VerifyOpening(&c, sum, &rSum), "Add with aliasing failed")
+	c = cx
+	c.Scale(&c, factor)
+	testutils.FatalUnless(t, gens.VerifyOpening(&c, scaled, &rScaled), "Scale with aliasing failed")
+}
+
+func TestCommitmentSerialization(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gens := DeriveGenerators("serialization", 3)
+	c, _, err := gens.CommitRandom(randomExponents(rnd, 3), rnd)
+	testutils.FatalUnless(t, err == nil, "CommitRandom failed: %v", err)
+
+	encoded := c.Bytes()
+	testutils.FatalUnless(t, len(encoded) == CommitmentSize, "wrong encoding length %v", len(encoded))
+	decoded, err := CommitmentFromBytes(encoded)
+	testutils.FatalUnless(t, err == nil, "decoding failed: %v", err)
+	testutils.FatalUnless(t, decoded.IsEqual(&c), "roundtrip failed")
+
+	var buf bytes.Buffer
+	bytesWritten, err := c.Serialize(&buf)
+	testutils.FatalUnless(t, err == nil && bytesWritten == CommitmentSize, "Serialize failed: %v", err)
+	testutils.FatalUnless(t, bytes.Equal(buf.Bytes(), encoded), "Serialize and Bytes differ")
+	var deserialized Commitment
+	bytesRead, err := deserialized.Deserialize(&buf)
+	testutils.FatalUnless(t, err == nil && bytesRead == CommitmentSize, "Deserialize failed: %v", err)
+	testutils.FatalUnless(t, deserialized.IsEqual(&c), "Deserialize roundtrip failed")
+
+	_, err = CommitmentFromBytes(encoded[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitmentEncoding), "wrong length accepted")
+	for i := 0; i < 20; i++ {
+		var bad [CommitmentSize]byte
+		rnd.Read(bad[:])
+		bad[0] &= 0x0f
+		if _, err = CommitmentFromBytes(bad[:]); err != nil {
+			testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitmentEncoding), "wrong error type %v", err)
+			return
+		}
+	}
+	t.Fatalf("random bytes were always accepted as commitment")
+}
+
+func TestCommitmentFromPoint(t *testing.T) {
+	c := CommitmentFromPoint(&curvePoints.SubgroupGenerator_xtw_subgroup)
+	point := c.Point()
+	testutils.FatalUnless(t, point.IsEqual(&curvePoints.SubgroupGenerator_xtw_subgroup), "CommitmentFromPoint roundtrip failed")
+	var nap curvePoints.Point_xtw_subgroup
+	testutils.FatalUnless(t, testutils.CheckPanic(CommitmentFromPoint, &nap), "CommitmentFromPoint did not panic on NaP")
+}
+
+func BenchmarkCommit(b *testing.B) {
+	const n = 256
+	rnd := rand.New(rand.NewSource(1))
+	gens := DeriveGenerators("benchmark", n)
+	values := randomExponents(rnd, n)
+	blinding := randomExponents(rnd, 1)[0]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gens.Commit(values, &blinding)
+	}
+}
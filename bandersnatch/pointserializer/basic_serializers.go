@@ -831,10 +831,14 @@ var basicBanderwagonLong = &pointSerializerYXTimesSignY{valuesSerializerHeaderFe
 
 var basicXYSerializer = &pointSerializerXY{valuesSerializerHeaderFeHeaderFe: valuesSerializerHeaderFeHeaderFe{fieldElementEndianness: common.DefaultEndian}}
 
+// basicEdwardsCompressed is the "Ed25519-style" encoding Sign(X)||Y. Unlike the Banderwagon serializers, it is not restricted to the subgroup.
+var basicEdwardsCompressed = &pointSerializerYAndSignX{valuesSerializerFeCompressedBit: valuesSerializerFeCompressedBit{fieldElementEndianness: common.DefaultEndian}, subgroupRestriction: subgroupRestriction{}}
+
 func init() {
 	bitHeaderBanderwagonX.Validate()
 	bitHeaderBanderwagonY.Validate()
 	basicBanderwagonShort.Validate()
 	basicBanderwagonLong.Validate()
 	basicXYSerializer.Validate()
+	basicEdwardsCompressed.Validate()
 }
@@ -143,6 +143,7 @@ type CurvePointSerializer interface {
 	DeserializeSlice(inputStream io.Reader, trustLevel common.IsInputTrusted, sliceMaker DeserializeSliceMaker) (output any, bytesRead int, err BatchDeserializationError)
 
 	SerializeCurvePoints(outputStream io.Writer, inputPoints curvePoints.CurvePointSlice) (bytesWritten int, err BatchSerializationError) // SerializePoints(os, points) is equivalent (if no error occurs) to calling Serialize(os, point[i]) for all i.
+	SerializeSlice(outputStream io.Writer, inputPoints curvePoints.CurvePointSlice) (bytesWritten int, err BatchSerializationError)       // SerializeSlice(os, points) writes points as a single object, including its length. It can be read back with DeserializeSlice.
 }
 
 // Note: WithParameter, WithEndianness and Clone "forget" their types.
@@ -156,9 +157,12 @@ type CurvePointSerializerModifyable interface {
 	AsDeserializer() CurvePointDeserializerModifyable
 }
 
+// BanderwagonShort and BanderwagonLong serialize points in the prime-order subgroup (modulo the affine 2-torsion point A).
+// EdwardsCompressed serializes arbitrary affine rational points as Sign(X)||Y; this is meant for protocols that need to distinguish P from P+A (e.g. cofactorless EdDSA).
 var (
-	BanderwagonShort CurvePointSerializerModifyable = newMultiSerializer(basicBanderwagonShort, trivialSimpleHeaderSerializer)
-	BanderwagonLong  CurvePointSerializerModifyable = newMultiSerializer(basicBanderwagonLong, trivialSimpleHeaderSerializer)
+	BanderwagonShort  CurvePointSerializerModifyable = newMultiSerializer(basicBanderwagonShort, trivialSimpleHeaderSerializer)
+	BanderwagonLong   CurvePointSerializerModifyable = newMultiSerializer(basicBanderwagonLong, trivialSimpleHeaderSerializer)
+	EdwardsCompressed CurvePointSerializerModifyable = newMultiSerializer(basicEdwardsCompressed, trivialSimpleHeaderSerializer)
 )
 
 // Note: We cannot directly use variables of interface type inside the struct, but rather use generics for two reasons:
@@ -65,6 +65,33 @@ type (
 func TestEnsureExportedSerializersValidate(t *testing.T) {
 	BanderwagonLong.Validate()
 	BanderwagonShort.Validate()
+	EdwardsCompressed.Validate()
+}
+
+// EdwardsCompressed must roundtrip arbitrary rational points and distinguish P from P+A.
+
+func TestEdwardsCompressedRoundtrip(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	var p, pPlusA, decoded curvePoints.Point_xtw_full
+	for i := 0; i < 50; i++ {
+		var subgroupPoint curvePoints.Point_xtw_subgroup
+		subgroupPoint, _ = curvePoints.RandomSubgroupPoint(drng)
+		p.SetFrom(&subgroupPoint)
+		pPlusA.Add(&p, &curvePoints.AffineOrderTwoPoint_xtw)
+		var buf, bufPlusA bytes.Buffer
+		bytesWritten, err := EdwardsCompressed.SerializeCurvePoint(&buf, &p)
+		testutils.FatalUnless(t, err == nil && bytesWritten == 32, "Serialization failed: %v", err)
+		_, err = EdwardsCompressed.SerializeCurvePoint(&bufPlusA, &pPlusA)
+		testutils.FatalUnless(t, err == nil, "Serialization failed: %v", err)
+		testutils.FatalUnless(t, !bytes.Equal(buf.Bytes(), bufPlusA.Bytes()), "P and P+A have the same encoding")
+
+		_, errDeserialize := EdwardsCompressed.DeserializeCurvePoint(&bufPlusA, common.UntrustedInput, &decoded)
+		testutils.FatalUnless(t, errDeserialize == nil, "Deserialization failed: %v", errDeserialize)
+		testutils.FatalUnless(t, decoded.IsEqual(&pPlusA), "Roundtrip failed for P+A")
+		_, errDeserialize = EdwardsCompressed.DeserializeCurvePoint(&buf, common.UntrustedInput, &decoded)
+		testutils.FatalUnless(t, errDeserialize == nil, "Deserialization failed: %v", errDeserialize)
+		testutils.FatalUnless(t, decoded.IsEqual(&p), "Roundtrip failed for P")
+	}
 }
 
 // ensure Clone preserves the dynamic type.
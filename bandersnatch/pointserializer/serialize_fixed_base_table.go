@@ -0,0 +1,46 @@
+package pointserializer
+
+import (
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/bandersnatchErrors"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/errorsWithData"
+)
+
+// This file contains functions to (de)serialize precomputed tables of type [curvePoints.FixedBaseTable].
+// A table is serialized as a slice (in the sense of SerializeSlice) of its precomputed points; the window size of the table is determined by the length of the slice.
+// Note that the format therefore depends on the serializer used.
+
+// SerializeFixedBaseTable writes the given precomputed table to outputStream, using the given serializer.
+//
+// The output can be read back using DeserializeFixedBaseTable with a matching deserializer.
+// Errors are as for SerializeSlice.
+func SerializeFixedBaseTable(serializer CurvePointSerializer, outputStream io.Writer, table *curvePoints.FixedBaseTable) (bytesWritten int, err BatchSerializationError) {
+	points := table.Points()
+	return serializer.SerializeSlice(outputStream, curvePoints.AsCurvePointSlice(points))
+}
+
+// DeserializeFixedBaseTable reads a precomputed table that was written by SerializeFixedBaseTable from inputStream, using the given deserializer.
+//
+// For untrusted input, we verify that the points read actually form a valid table. Note that this is about as expensive as creating the table from scratch.
+// On error, table is nil. If the points were read correctly but do not form a valid table, the returned error wraps [curvePoints.ErrInvalidFixedBaseTable].
+func DeserializeFixedBaseTable(deserializer CurvePointDeserializer, inputStream io.Reader, trustLevel common.IsInputTrusted) (table *curvePoints.FixedBaseTable, bytesRead int, err BatchDeserializationError) {
+	var output any
+	output, bytesRead, err = deserializer.DeserializeSlice(inputStream, trustLevel, CreateNewSlice[curvePoints.Point_axtw_subgroup])
+	if err != nil {
+		return
+	}
+	points := output.([]curvePoints.Point_axtw_subgroup)
+	table, errTable := curvePoints.NewFixedBaseTableFromPoints(points, trustLevel)
+	if errTable != nil {
+		err = errorsWithData.NewErrorWithData_struct(errTable, ErrorPrefix+"the deserialized points do not form a valid precomputed table: %w", &BatchDeserializationErrorData{
+			ReadErrorData:      bandersnatchErrors.ReadErrorData{PartialRead: false},
+			PointsDeserialized: len(points),
+		})
+		table = nil
+		return
+	}
+	return
+}
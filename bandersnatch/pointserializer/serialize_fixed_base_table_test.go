@@ -0,0 +1,62 @@
+package pointserializer
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// TestFixedBaseTableRoundtrip checks that serializing and deserializing a FixedBaseTable gives an equivalent table.
+func TestFixedBaseTableRoundtrip(t *testing.T) {
+	var buf bytes.Buffer
+	drng := rand.New(rand.NewSource(1))
+	base := curvePoints.MakeRandomPointUnsafe_xtw_subgroup(drng)
+	table := curvePoints.NewFixedBaseTable(&base, 3)
+	for _, serializer := range allTestMultiSerializers {
+		buf.Reset()
+		bytesWritten, err := SerializeFixedBaseTable(serializer, &buf, table)
+		testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+		testutils.FatalUnless(t, bytesWritten == buf.Len(), "Wrong number of bytes written reported")
+		for _, trustLevel := range []IsInputTrusted{common.TrustedInput, common.UntrustedInput} {
+			readBuf := bytes.NewReader(buf.Bytes())
+			readBack, bytesRead, err := DeserializeFixedBaseTable(serializer.AsDeserializer(), readBuf, trustLevel)
+			testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+			testutils.FatalUnless(t, bytesRead == bytesWritten, "Did not read back as much as was written")
+			testutils.FatalUnless(t, readBack.WindowSize() == table.WindowSize(), "Read back table has different window size")
+			var exponent curvePoints.Exponent
+			exponent.SetBigInt(new(big.Int).Rand(drng, common.CurveExponent_Int))
+			result1 := table.Exponentiate(&exponent)
+			result2 := readBack.Exponentiate(&exponent)
+			testutils.FatalUnless(t, result1.IsEqual(&result2), "Read back table gives different results")
+		}
+	}
+}
+
+// TestFixedBaseTableInvalid checks that deserializing an invalid FixedBaseTable fails for untrusted input.
+func TestFixedBaseTableInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	drng := rand.New(rand.NewSource(1))
+	base := curvePoints.MakeRandomPointUnsafe_xtw_subgroup(drng)
+	points := curvePoints.NewFixedBaseTable(&base, 3).Points()
+	points[5].DoubleEq()
+	invalidTable, err := curvePoints.NewFixedBaseTableFromPoints(points, common.TrustedInput)
+	testutils.FatalUnless(t, err == nil, "Unexpected error %v", err)
+
+	_, errWrite := SerializeFixedBaseTable(BanderwagonShort, &buf, invalidTable)
+	testutils.FatalUnless(t, errWrite == nil, "Unexpected error %v", errWrite)
+	readBack, _, errRead := DeserializeFixedBaseTable(BanderwagonShort, bytes.NewReader(buf.Bytes()), common.UntrustedInput)
+	testutils.FatalUnless(t, errors.Is(errRead, curvePoints.ErrInvalidFixedBaseTable), "Deserializing invalid table did not give expected error. Got %v", errRead)
+	testutils.FatalUnless(t, readBack == nil, "Deserializing invalid table returned non-nil table")
+	_, _, errRead = DeserializeFixedBaseTable(BanderwagonShort, bytes.NewReader(buf.Bytes()), common.TrustedInput)
+	testutils.FatalUnless(t, errRead == nil, "Deserializing invalid table as trusted input gave error %v", errRead)
+
+	// truncated input
+	_, _, errRead = DeserializeFixedBaseTable(BanderwagonShort, bytes.NewReader(buf.Bytes()[0:buf.Len()-1]), common.TrustedInput)
+	testutils.FatalUnless(t, errRead != nil, "Deserializing truncated input did not give an error")
+}
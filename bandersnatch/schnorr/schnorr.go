@@ -0,0 +1,268 @@
+package schnorr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+)
+
+// This file contains Schnorr signatures over the prime-order subgroup of the Bandersnatch curve.
+//
+// Notation: G is the generator curvePoints.SubgroupGenerator_xtw_subgroup, a private key is an exponent x != 0, the public key is P = x*G.
+// A signature on a message m is a pair (R, s) with
+//
+//	R = k*G for a nonce k, e = H_challenge(enc(R) || enc(P) || m), s = k + e*x (modulo p253)
+//
+// and it is valid iff s*G == R + e*P. Here, enc denotes the Banderwagon encoding pointserializer.BanderwagonShort and
+// H_challenge is the tagged hash exponents.HashToExponent with domain separation tag ChallengeDST.
+//
+// The nonce k is derived deterministically from the private key, the public key and the message (similar in spirit to RFC 6979, but using hash_to_field from RFC 9380 to avoid modular bias).
+// Optionally, signing can be hedged by mixing in fresh randomness, which protects against fault attacks; the resulting signatures verify the same way.
+//
+// Encodings: Public keys and R are encoded via pointserializer.BanderwagonShort (32 bytes), exponents are encoded as 32-byte numbers in 0 <= . < p253 with the same endianness.
+// A signature is enc(R) || enc(s), i.e. 64 bytes. Decoding is strict, i.e. every valid signature has a unique encoding.
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / schnorr: "
+
+const (
+	PublicKeySize  = keypair.PointSize  // size in bytes of encoded public keys
+	PrivateKeySize = keypair.ScalarSize // size in bytes of encoded private keys
+	SignatureSize  = 2 * PublicKeySize  // size in bytes of encoded signatures
+)
+
+// Domain separation tags for the tagged hashes used for challenges and nonces.
+const (
+	ChallengeDST = "BANDERSNATCH-SCHNORR-V01-CHALLENGE"
+	NonceDST     = "BANDERSNATCH-SCHNORR-V01-NONCE"
+)
+
+// hedgeSize is the number of random bytes mixed into the nonce derivation for hedged signatures.
+const hedgeSize = 32
+
+var (
+	ErrInvalidPrivateKey        = errors.New(ErrorPrefix + "invalid private key")
+	ErrInvalidPublicKey         = errors.New(ErrorPrefix + "invalid public key")
+	ErrInvalidSignatureEncoding = errors.New(ErrorPrefix + "invalid signature encoding")
+)
+
+// PrivateKey is a Schnorr private key. The zero value is not a valid private key; use GenerateKey or PrivateKeyFromBytes to create one.
+type PrivateKey struct {
+	key keypair.PrivateKey // private exponent x and public key x*G
+}
+
+// PublicKey is a Schnorr public key. The zero value is not a valid public key; use PublicKeyFromBytes, PublicKeyFromPoint or PrivateKey.PublicKey to obtain one.
+type PublicKey struct {
+	key keypair.PublicKey // P and its encoding, which is part of the challenge hash
+}
+
+// Signature is a Schnorr signature (R, s). Use SignatureFromBytes to decode one.
+type Signature struct {
+	r        curvePoints.Point_xtw_subgroup
+	encodedR [PublicKeySize]byte // cached encoding of r
+	s        exponents.Exponent  // fully reduced
+}
+
+// GenerateKey generates a new private key, reading randomness from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// We return an error if reading from rnd fails.
+func GenerateKey(rnd io.Reader) (*PrivateKey, error) {
+	key, err := keypair.GenerateKey(rnd)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{key: key}, nil
+}
+
+// PrivateKeyFromBytes decodes a private key in the format written by PrivateKey.Bytes.
+//
+// We return an error wrapping ErrInvalidPrivateKey if buf has the wrong length, encodes a number >= p253 or encodes 0.
+func PrivateKeyFromBytes(buf []byte) (*PrivateKey, error) {
+	key, err := keypair.PrivateKeyFromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+	return &PrivateKey{key: key}, nil
+}
+
+// Bytes returns the encoding of the private key. Note that the result is secret.
+func (sk *PrivateKey) Bytes() []byte {
+	return sk.key.Bytes()
+}
+
+// PublicKey returns the public key corresponding to sk.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{key: *sk.key.PublicKey()}
+}
+
+// Equal checks whether sk and other are the same private key.
+func (sk *PrivateKey) Equal(other *PrivateKey) bool {
+	return sk.key.Equal(&other.key)
+}
+
+// PublicKeyFromPoint creates a public key from the given curve point.
+//
+// We return an error wrapping ErrInvalidPublicKey if p is a NaP or the neutral element.
+func PublicKeyFromPoint(p *curvePoints.Point_xtw_subgroup) (*PublicKey, error) {
+	key, err := keypair.PublicKeyFromPoint(p)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return &PublicKey{key: key}, nil
+}
+
+// PublicKeyFromBytes decodes a public key in the format written by PublicKey.Bytes.
+//
+// We return an error wrapping ErrInvalidPublicKey if buf is not a valid encoding of a point in the prime-order subgroup or encodes the neutral element.
+func PublicKeyFromBytes(buf []byte) (*PublicKey, error) {
+	key, err := keypair.PublicKeyFromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return &PublicKey{key: key}, nil
+}
+
+// Bytes returns the encoding of the public key.
+func (pk *PublicKey) Bytes() []byte {
+	return pk.key.Bytes()
+}
+
+// Point returns the public key as a curve point.
+func (pk *PublicKey) Point() curvePoints.Point_xtw_subgroup {
+	return *pk.key.Point()
+}
+
+// Equal checks whether pk and other are the same public key.
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	return pk.key.Equal(&other.key)
+}
+
+// SignatureFromBytes decodes a signature in the format written by Signature.Bytes.
+//
+// We return an error wrapping ErrInvalidSignatureEncoding if buf has the wrong length, R is not a valid encoding of a point in the prime-order subgroup or s encodes a number >= p253.
+// Note that succesfully decoding a signature says nothing about its validity.
+func SignatureFromBytes(buf []byte) (*Signature, error) {
+	if len(buf) != SignatureSize {
+		return nil, fmt.Errorf("%w: signature has length %v, expected %v", ErrInvalidSignatureEncoding, len(buf), SignatureSize)
+	}
+	var ret Signature
+	var err error
+	ret.r, err = keypair.DecodePoint(buf[0:PublicKeySize])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignatureEncoding, err)
+	}
+	copy(ret.encodedR[:], buf[0:PublicKeySize])
+	if ret.s, err = keypair.DecodeScalar(buf[PublicKeySize:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignatureEncoding, err)
+	}
+	return &ret, nil
+}
+
+// SignatureFromComponents creates a signature from its components R and s.
+//
+// This is meant for protocols that create Schnorr signatures in other ways (e.g. threshold signatures); see the documentation at the top of this file for the meaning of R and s.
+func SignatureFromComponents(r *curvePoints.Point_xtw_subgroup, s *exponents.Exponent) *Signature {
+	if r.IsNaP() {
+		panic(ErrorPrefix + "SignatureFromComponents called with NaP")
+	}
+	ret := &Signature{r: *r, s: s.ModuloP253()}
+	ret.encodedR = keypair.EncodePoint(&ret.r)
+	return ret
+}
+
+// Bytes returns the encoding of the signature.
+func (sig *Signature) Bytes() []byte {
+	ret := make([]byte, SignatureSize)
+	copy(ret[0:PublicKeySize], sig.encodedR[:])
+	sBytes := keypair.EncodeScalar(&sig.s)
+	copy(ret[PublicKeySize:], sBytes[:])
+	return ret
+}
+
+// R returns the commitment part R of the signature.
+func (sig *Signature) R() curvePoints.Point_xtw_subgroup {
+	return sig.r
+}
+
+// S returns the response part s of the signature.
+func (sig *Signature) S() exponents.Exponent {
+	return sig.s
+}
+
+// Challenge computes the challenge e = H_challenge(enc(R) || enc(P) || msg) used in signing and verification.
+//
+// This is exported for protocols that create or verify Schnorr signatures in other ways (e.g. threshold signatures or batch verification).
+func Challenge(r *curvePoints.Point_xtw_subgroup, pk *PublicKey, msg []byte) exponents.Exponent {
+	encodedR := keypair.EncodePoint(r)
+	return challenge(&encodedR, pk, msg)
+}
+
+// challenge is Challenge with the encoding of R already given.
+func challenge(encodedR *[PublicKeySize]byte, pk *PublicKey, msg []byte) exponents.Exponent {
+	input := make([]byte, 0, 2*PublicKeySize+len(msg))
+	input = append(input, encodedR[:]...)
+	input = append(input, pk.key.Bytes()...)
+	input = append(input, msg...)
+	e, err := exponents.HashToExponent(input, []byte(ChallengeDST))
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when computing challenge: %w", err))
+	}
+	return e
+}
+
+// Sign creates a signature on msg. The nonce is derived deterministically from sk and msg, so signing the same message twice gives the same signature.
+func (sk *PrivateKey) Sign(msg []byte) *Signature {
+	var noHedge [hedgeSize]byte
+	return sk.sign(&noHedge, msg)
+}
+
+// SignHedged creates a signature on msg, mixing fresh randomness read from rnd into the nonce derivation. If rnd is nil, we use crypto/rand.Reader.
+//
+// The security of the signature does not rely on the quality of rnd; mixing in randomness protects against side-channel and fault attacks on deterministic signing.
+// We return an error if reading from rnd fails.
+func (sk *PrivateKey) SignHedged(rnd io.Reader, msg []byte) (*Signature, error) {
+	var hedge [hedgeSize]byte
+	if err := common.ReadRandomBytes(rnd, hedge[:]); err != nil {
+		return nil, err
+	}
+	return sk.sign(&hedge, msg), nil
+}
+
+// sign creates a signature on msg, using nonce derived from sk, msg and hedge.
+func (sk *PrivateKey) sign(hedge *[hedgeSize]byte, msg []byte) *Signature {
+	k := sk.deriveNonce(hedge, msg)
+	var ret Signature
+	ret.r.ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, &k)
+	ret.encodedR = keypair.EncodePoint(&ret.r)
+	e := challenge(&ret.encodedR, sk.PublicKey(), msg)
+	// s = k + e*x
+	ret.s.Mul(&e, sk.key.Scalar())
+	ret.s.Add(&ret.s, &k)
+	ret.s = ret.s.ModuloP253()
+	return &ret
+}
+
+// deriveNonce computes the nonce k = H_nonce(enc(x) || hedge || enc(P) || msg).
+func (sk *PrivateKey) deriveNonce(hedge *[hedgeSize]byte, msg []byte) exponents.Exponent {
+	input := make([]byte, 0, PrivateKeySize+hedgeSize+PublicKeySize+len(msg))
+	input = append(input, sk.Bytes()...)
+	input = append(input, hedge[:]...)
+	input = append(input, sk.key.PublicKey().Bytes()...)
+	input = append(input, msg...)
+	return keypair.DeriveNonce(input, NonceDST)
+}
+
+// Verify checks whether sig is a valid signature on msg under the public key pk.
+func Verify(pk *PublicKey, msg []byte, sig *Signature) bool {
+	e := challenge(&sig.encodedR, pk, msg)
+	// Check s*G - e*P == R
+	e.Neg(&e)
+	points := curvePoints.CurvePointSlice_xtw_subgroup{curvePoints.SubgroupGenerator_xtw_subgroup, *pk.key.Point()}
+	result := curvePoints.MultiExponentiate(points, []exponents.Exponent{sig.s, e})
+	return result.IsEqual(&sig.r)
+}
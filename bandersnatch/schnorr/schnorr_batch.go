@@ -0,0 +1,40 @@
+package schnorr
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/batchverify"
+)
+
+// This file contains batch verification of Schnorr signatures.
+
+// BatchVerify verifies the signatures sigs[i] on msgs[i] under the public keys pks[i] and returns the (sorted) indices of the invalid signatures.
+// All signatures are valid iff invalid == nil and err == nil; if some signature is invalid, invalid is non-empty. This includes the case of an empty batch.
+//
+// We check a random linear combination of the verification equations s_i*G - e_i*P_i - R_i == neutral element with a single multi-exponentiation,
+// which is considerably faster than verifying individually. If that check fails, we bisect to find the invalid signatures.
+// The random weights are read from rnd; if rnd is nil, we use crypto/rand.Reader. The probability that an invalid signature is not reported is at most 2^-128.
+//
+// We return an error if reading from rnd fails. pks, msgs and sigs must have the same length (we panic otherwise).
+//
+// NOTE: This function is not constant-time. This is fine, as all inputs are public.
+func BatchVerify(rnd io.Reader, pks []*PublicKey, msgs [][]byte, sigs []*Signature) (invalid []int, err error) {
+	n := len(sigs)
+	if len(pks) != n || len(msgs) != n {
+		panic(fmt.Errorf(ErrorPrefix+"BatchVerify called with mismatched lengths: %v public keys, %v messages, %v signatures", len(pks), len(msgs), n))
+	}
+	equations := make([]batchverify.Equation, n)
+	var minusOne exponents.Exponent
+	minusOne.SetInt(-1)
+	for i := 0; i < n; i++ {
+		e := challenge(&sigs[i].encodedR, pks[i], msgs[i])
+		e.Neg(&e)
+		equations[i].GeneratorScalar = sigs[i].s
+		equations[i].Points = []curvePoints.Point_xtw_subgroup{*pks[i].key.Point(), sigs[i].r}
+		equations[i].Scalars = []exponents.Exponent{e, minusOne}
+	}
+	return batchverify.FindInvalid(rnd, equations)
+}
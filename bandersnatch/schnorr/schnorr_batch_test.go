@@ -0,0 +1,62 @@
+package schnorr
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// makeTestBatch creates batchSize valid signatures under distinct keys.
+func makeTestBatch(drng *rand.Rand, batchSize int) (pks []*PublicKey, msgs [][]byte, sigs []*Signature) {
+	pks = make([]*PublicKey, batchSize)
+	msgs = make([][]byte, batchSize)
+	sigs = make([]*Signature, batchSize)
+	for i := 0; i < batchSize; i++ {
+		sk, _ := GenerateKey(drng)
+		pks[i] = sk.PublicKey()
+		msgs[i] = []byte{byte(i), byte(i >> 8)}
+		sigs[i] = sk.Sign(msgs[i])
+	}
+	return
+}
+
+func TestBatchVerify(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	const batchSize = 13
+	pks, msgs, sigs := makeTestBatch(drng, batchSize)
+	invalid, err := BatchVerify(drng, pks, msgs, sigs)
+	testutils.FatalUnless(t, err == nil && invalid == nil, "Batch of valid signatures did not verify")
+	invalid, err = BatchVerify(drng, nil, nil, nil)
+	testutils.FatalUnless(t, err == nil && invalid == nil, "Empty batch did not verify")
+
+	// Invalidate various subsets of signatures by using the signature of a different message.
+	for _, badSet := range [][]int{{0}, {12}, {5}, {0, 1}, {3, 4, 5, 6}, {0, 6, 12}, {1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}} {
+		modifiedSigs := append([]*Signature{}, sigs...)
+		for _, bad := range badSet {
+			modifiedSigs[bad] = sigs[(bad+1)%batchSize]
+		}
+		invalid, err = BatchVerify(drng, pks, msgs, modifiedSigs)
+		testutils.FatalUnless(t, err == nil, "BatchVerify failed: %v", err)
+		testutils.FatalUnless(t, len(invalid) == len(badSet), "BatchVerify reported %v invalid signatures, expected %v", invalid, badSet)
+		for i := range badSet {
+			testutils.FatalUnless(t, invalid[i] == badSet[i], "BatchVerify reported %v invalid signatures, expected %v", invalid, badSet)
+		}
+	}
+
+	testutils.FatalUnless(t, testutils.CheckPanic(BatchVerify, drng, pks, msgs[1:], sigs), "BatchVerify did not panic on length mismatch")
+	designatedErr := errors.New("designated error")
+	_, err = BatchVerify(iotest.ErrReader(designatedErr), pks, msgs, sigs)
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "BatchVerify did not report randomness error")
+}
+
+func BenchmarkBatchVerify64(b *testing.B) {
+	drng := rand.New(rand.NewSource(1))
+	pks, msgs, sigs := makeTestBatch(drng, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(drng, pks, msgs, sigs)
+	}
+}
@@ -0,0 +1,191 @@
+package schnorr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// regression test vector: private key 42 (little endian), message "abc"
+const (
+	katPublicKey = "853866878683ced2f5320869e9e0ce60a3d5373e3fd36108444109c036892cc0"
+	katSignature = "2dd48f4251a1d3a1f651a2548f1109c5a55c706441216c0382a622c9277b01f2ee700bb5f6026dac0e1e3b3190df87bd1066bab62a91f4c1dbf04c31ad9dd310"
+)
+
+func TestSignVerify(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		sk, err := GenerateKey(drng)
+		testutils.FatalUnless(t, err == nil, "GenerateKey failed: %v", err)
+		pk := sk.PublicKey()
+		msg := []byte("message " + string(rune('a'+i)))
+
+		sig := sk.Sign(msg)
+		testutils.FatalUnless(t, Verify(pk, msg, sig), "Valid signature did not verify")
+		sig2 := sk.Sign(msg)
+		testutils.FatalUnless(t, bytes.Equal(sig.Bytes(), sig2.Bytes()), "Signing is not deterministic")
+
+		hedged, err := sk.SignHedged(drng, msg)
+		testutils.FatalUnless(t, err == nil, "SignHedged failed: %v", err)
+		testutils.FatalUnless(t, Verify(pk, msg, hedged), "Valid hedged signature did not verify")
+		testutils.FatalUnless(t, !bytes.Equal(sig.Bytes(), hedged.Bytes()), "Hedged signature equals deterministic one")
+
+		// wrong message, wrong key, modified signature
+		testutils.FatalUnless(t, !Verify(pk, []byte("other message"), sig), "Signature verified for wrong message")
+		otherSk, _ := GenerateKey(drng)
+		testutils.FatalUnless(t, !Verify(otherSk.PublicKey(), msg, sig), "Signature verified for wrong key")
+		var one exponents.Exponent
+		one.SetOne()
+		s := sig.S()
+		s.Add(&s, &one)
+		r := sig.R()
+		testutils.FatalUnless(t, !Verify(pk, msg, SignatureFromComponents(&r, &s)), "Modified signature verified")
+	}
+}
+
+func TestKnownAnswer(t *testing.T) {
+	skBytes := make([]byte, PrivateKeySize)
+	skBytes[0] = 42
+	sk, err := PrivateKeyFromBytes(skBytes)
+	testutils.FatalUnless(t, err == nil, "PrivateKeyFromBytes failed: %v", err)
+
+	var expectedPk curvePoints.Point_xtw_subgroup
+	var x exponents.Exponent
+	x.SetUInt(42)
+	expectedPk.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &x)
+	pkPoint := sk.PublicKey().Point()
+	testutils.FatalUnless(t, pkPoint.IsEqual(&expectedPk), "Public key is not x*G")
+
+	testutils.FatalUnless(t, hex.EncodeToString(sk.PublicKey().Bytes()) == katPublicKey, "Public key encoding does not match test vector")
+	sig := sk.Sign([]byte("abc"))
+	testutils.FatalUnless(t, hex.EncodeToString(sig.Bytes()) == katSignature, "Signature does not match test vector")
+}
+
+func TestSignatureFromComponents(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk, _ := GenerateKey(drng)
+	pk := sk.PublicKey()
+	msg := []byte("abc")
+
+	// create a signature "by hand"
+	var k exponents.Exponent
+	k.SetRandom(drng)
+	var r curvePoints.Point_xtw_subgroup
+	r.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &k)
+	e := Challenge(&r, pk, msg)
+	var s exponents.Exponent
+	s.Mul(&e, sk.key.Scalar())
+	s.Add(&s, &k)
+	sig := SignatureFromComponents(&r, &s)
+	testutils.FatalUnless(t, Verify(pk, msg, sig), "Manually created signature did not verify")
+	rOut, sOut := sig.R(), sig.S()
+	testutils.FatalUnless(t, rOut.IsEqual(&r) && sOut.IsEqual(&s), "Getters do not return components")
+}
+
+func TestEncodingRoundtrip(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk, _ := GenerateKey(drng)
+	sk2, err := PrivateKeyFromBytes(sk.Bytes())
+	testutils.FatalUnless(t, err == nil && sk.Equal(sk2), "Private key roundtrip failed")
+	testutils.FatalUnless(t, sk2.PublicKey().Equal(sk.PublicKey()), "Private key roundtrip changed public key")
+
+	pk, err := PublicKeyFromBytes(sk.PublicKey().Bytes())
+	testutils.FatalUnless(t, err == nil && pk.Equal(sk.PublicKey()), "Public key roundtrip failed")
+	testutils.FatalUnless(t, len(pk.Bytes()) == PublicKeySize, "Wrong public key size")
+
+	msg := []byte("abc")
+	sig, err := SignatureFromBytes(sk.Sign(msg).Bytes())
+	testutils.FatalUnless(t, err == nil, "Signature roundtrip failed: %v", err)
+	testutils.FatalUnless(t, len(sig.Bytes()) == SignatureSize, "Wrong signature size")
+	testutils.FatalUnless(t, Verify(pk, msg, sig), "Decoded signature does not verify")
+}
+
+func TestInvalidEncodings(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk, _ := GenerateKey(drng)
+	sigBytes := sk.Sign([]byte("abc")).Bytes()
+
+	// wrong lengths
+	_, err := PrivateKeyFromBytes(make([]byte, PrivateKeySize-1))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "PrivateKeyFromBytes accepted wrong length")
+	_, err = PublicKeyFromBytes(make([]byte, PublicKeySize+1))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "PublicKeyFromBytes accepted wrong length")
+	_, err = SignatureFromBytes(sigBytes[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureEncoding), "SignatureFromBytes accepted wrong length")
+
+	// zero resp. too large private keys
+	_, err = PrivateKeyFromBytes(make([]byte, PrivateKeySize))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "PrivateKeyFromBytes accepted zero")
+	var p253 [exponents.ExponentBytesLength]byte
+	keypair.ScalarEndianness.PutUint256(p253[:], [4]uint64{0x74fd06b5_2876e7e1, 0xff8f8700_74190471, 0x0cce7602_02687600, 0x1cfb69d4_ca675f52})
+	_, err = PrivateKeyFromBytes(p253[:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "PrivateKeyFromBytes accepted p253")
+
+	// neutral element as public key
+	neutral := keypair.EncodePoint(&curvePoints.NeutralElement_xtw_subgroup)
+	_, err = PublicKeyFromBytes(neutral[:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "PublicKeyFromBytes accepted neutral element")
+	_, err = PublicKeyFromPoint(&curvePoints.NeutralElement_xtw_subgroup)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "PublicKeyFromPoint accepted neutral element")
+
+	// find some encoding that does not decode to a point in the prime-order subgroup
+	var invalidPoint []byte
+	for i := 0; ; i++ {
+		candidate := make([]byte, PublicKeySize)
+		candidate[0] = byte(i)
+		if _, errDecode := keypair.DecodePoint(candidate); errDecode != nil {
+			invalidPoint = candidate
+			break
+		}
+	}
+	_, err = PublicKeyFromBytes(invalidPoint)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "PublicKeyFromBytes accepted invalid point")
+	modifiedSig := append(append([]byte{}, invalidPoint...), sigBytes[PublicKeySize:]...)
+	_, err = SignatureFromBytes(modifiedSig)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureEncoding), "SignatureFromBytes accepted invalid R")
+
+	// non-canonical s
+	modifiedSig = append(append([]byte{}, sigBytes[:PublicKeySize]...), p253[:]...)
+	_, err = SignatureFromBytes(modifiedSig)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidSignatureEncoding), "SignatureFromBytes accepted non-canonical s")
+}
+
+func TestRandomnessErrors(t *testing.T) {
+	designatedErr := errors.New("designated error")
+	_, err := GenerateKey(iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "GenerateKey did not report error")
+	sk, err := GenerateKey(nil)
+	testutils.FatalUnless(t, err == nil, "GenerateKey(nil) failed: %v", err)
+	_, err = sk.SignHedged(iotest.ErrReader(designatedErr), []byte("abc"))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "SignHedged did not report error")
+	sig, err := sk.SignHedged(nil, []byte("abc"))
+	testutils.FatalUnless(t, err == nil && Verify(sk.PublicKey(), []byte("abc"), sig), "SignHedged(nil) failed")
+}
+
+func BenchmarkSign(b *testing.B) {
+	sk, _ := GenerateKey(rand.New(rand.NewSource(1)))
+	msg := []byte("abc")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sk.Sign(msg)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	sk, _ := GenerateKey(rand.New(rand.NewSource(1)))
+	msg := []byte("abc")
+	sig := sk.Sign(msg)
+	pk := sk.PublicKey()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Verify(pk, msg, sig)
+	}
+}
@@ -0,0 +1,76 @@
+package sigma
+
+import (
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains ready-made proofs for common relations, built on LinearRelation.
+//
+// Every proof also appends a label identifying the kind of proof to the transcript.
+
+// proofKindLabel is the transcript label under which the kind of proof is appended.
+const proofKindLabel = "sigma-proof"
+
+// KnowledgeRelation returns the relation Y == x*G (knowledge of a discrete logarithm, i.e. a Schnorr proof of knowledge).
+func KnowledgeRelation(G, Y *curvePoints.Point_xtw_subgroup) *LinearRelation {
+	return &LinearRelation{
+		NumWitnesses: 1,
+		Equations:    []Equation{{Image: *Y, Terms: []Term{{Witness: 0, Base: *G}}}},
+	}
+}
+
+// DLEQRelation returns the relation Y == x*G, Z == x*H (equality of discrete logarithms, i.e. a Chaum-Pedersen proof).
+func DLEQRelation(G, Y, H, Z *curvePoints.Point_xtw_subgroup) *LinearRelation {
+	return &LinearRelation{
+		NumWitnesses: 1,
+		Equations: []Equation{
+			{Image: *Y, Terms: []Term{{Witness: 0, Base: *G}}},
+			{Image: *Z, Terms: []Term{{Witness: 0, Base: *H}}},
+		},
+	}
+}
+
+// OpeningRelation returns the relation C == m*G + r*H (knowledge of an opening (m, r) of a Pedersen commitment C, i.e. an Okamoto proof).
+// The witnesses are m (index 0) and r (index 1).
+func OpeningRelation(G, H, C *curvePoints.Point_xtw_subgroup) *LinearRelation {
+	return &LinearRelation{
+		NumWitnesses: 2,
+		Equations:    []Equation{{Image: *C, Terms: []Term{{Witness: 0, Base: *G}, {Witness: 1, Base: *H}}}},
+	}
+}
+
+// ProveKnowledge proves knowledge of x with Y == x*G. See LinearRelation.Prove for the meaning of the arguments and possible errors.
+func ProveKnowledge(t *Transcript, G, Y *curvePoints.Point_xtw_subgroup, x *exponents.Exponent, rnd io.Reader) (*Proof, error) {
+	return KnowledgeRelation(G, Y).prove(t, "knowledge", []exponents.Exponent{*x}, rnd)
+}
+
+// VerifyKnowledge verifies a proof created by ProveKnowledge.
+func VerifyKnowledge(t *Transcript, G, Y *curvePoints.Point_xtw_subgroup, proof *Proof) bool {
+	t.AppendMessage(proofKindLabel, []byte("knowledge"))
+	return KnowledgeRelation(G, Y).Verify(t, proof)
+}
+
+// ProveDLEQ proves knowledge of x with Y == x*G and Z == x*H. See LinearRelation.Prove for the meaning of the arguments and possible errors.
+func ProveDLEQ(t *Transcript, G, Y, H, Z *curvePoints.Point_xtw_subgroup, x *exponents.Exponent, rnd io.Reader) (*Proof, error) {
+	return DLEQRelation(G, Y, H, Z).prove(t, "dleq", []exponents.Exponent{*x}, rnd)
+}
+
+// VerifyDLEQ verifies a proof created by ProveDLEQ.
+func VerifyDLEQ(t *Transcript, G, Y, H, Z *curvePoints.Point_xtw_subgroup, proof *Proof) bool {
+	t.AppendMessage(proofKindLabel, []byte("dleq"))
+	return DLEQRelation(G, Y, H, Z).Verify(t, proof)
+}
+
+// ProveOpening proves knowledge of (m, r) with C == m*G + r*H. See LinearRelation.Prove for the meaning of the arguments and possible errors.
+func ProveOpening(t *Transcript, G, H, C *curvePoints.Point_xtw_subgroup, m, r *exponents.Exponent, rnd io.Reader) (*Proof, error) {
+	return OpeningRelation(G, H, C).prove(t, "opening", []exponents.Exponent{*m, *r}, rnd)
+}
+
+// VerifyOpening verifies a proof created by ProveOpening.
+func VerifyOpening(t *Transcript, G, H, C *curvePoints.Point_xtw_subgroup, proof *Proof) bool {
+	t.AppendMessage(proofKindLabel, []byte("opening"))
+	return OpeningRelation(G, H, C).Verify(t, proof)
+}
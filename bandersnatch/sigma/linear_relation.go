@@ -0,0 +1,319 @@
+package sigma
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains non-interactive zero-knowledge proofs of knowledge for linear relations between points in the prime-order subgroup.
+//
+// A LinearRelation consists of n secret witnesses x_0, ..., x_{n-1} (exponents) and a list of equations of the form
+//
+//	Image_j == sum_i x_{w(j,i)} * Base_{j,i}
+//
+// where w(j,i) is the index of the witness used in the i-th term of equation j. Witnesses may appear in several equations; this is how
+// statements are AND-composed (e.g. a DLEQ proof is the relation Y == x*G, Z == x*H with a single witness x).
+//
+// The proof is the standard sigma protocol (Schnorr / Chaum-Pedersen / Okamoto) made non-interactive via the Fiat-Shamir transform with a Transcript:
+// The prover picks nonces r_i, sends commitments A_j = sum_i r_{w(j,i)} * Base_{j,i}, gets the challenge c and responds with z_i = r_i + c*x_i.
+// The verifier checks sum_i z_{w(j,i)} * Base_{j,i} == A_j + c * Image_j.
+// We use the compact form of the proof (c, z_0, ..., z_{n-1}), where the verifier recomputes the A_j and checks the challenge.
+//
+// The statement (all bases and images, and the structure of the relation) is appended to the transcript before the commitments,
+// so proofs are bound to the statement. Protocols should append any further context (e.g. a session identifier) to the transcript before proving resp. verifying.
+//
+// Nonces are derived from the transcript state, the witnesses and fresh randomness (as in Merlin's transcript RNG). This way, nonces are safe even if the randomness is bad.
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / sigma: "
+
+// NonceDST is the domain separation tag used for deriving the prover's nonces.
+const NonceDST = "BANDERSNATCH-SIGMA-V01-NONCE"
+
+// nonceRandomnessSize is the number of random bytes mixed into the nonce derivation.
+const nonceRandomnessSize = 32
+
+var (
+	ErrInvalidRelation      = errors.New(ErrorPrefix + "invalid linear relation")
+	ErrWitnessNotSatisfying = errors.New(ErrorPrefix + "witnesses do not satisfy the relation")
+	ErrInvalidProofEncoding = errors.New(ErrorPrefix + "invalid proof encoding")
+)
+
+// Term is a single term x_Witness * Base of an Equation.
+type Term struct {
+	Witness int // index of the witness
+	Base    curvePoints.Point_xtw_subgroup
+}
+
+// Equation is the equation Image == sum_i x_{Terms[i].Witness} * Terms[i].Base.
+type Equation struct {
+	Image curvePoints.Point_xtw_subgroup
+	Terms []Term
+}
+
+// LinearRelation is a list of equations in NumWitnesses secret witnesses. See the documentation at the top of this file.
+//
+// Points are of type Point_xtw_subgroup; use SetFromSubgroupPoint to convert points of other types.
+type LinearRelation struct {
+	NumWitnesses int
+	Equations    []Equation
+}
+
+// Proof is a non-interactive proof for a LinearRelation. Use LinearRelation.Prove to create one or ProofFromBytes to decode one.
+type Proof struct {
+	challenge exponents.Exponent
+	responses []exponents.Exponent
+}
+
+// Validate checks that the relation is well-formed: It must have at least one witness and one equation, every equation must have at least one term,
+// all witness indices must be in range, every witness must be used and no point may be a NaP. We return an error wrapping ErrInvalidRelation otherwise.
+func (rel *LinearRelation) Validate() error {
+	if rel.NumWitnesses <= 0 {
+		return fmt.Errorf("%w: relation has no witnesses", ErrInvalidRelation)
+	}
+	if len(rel.Equations) == 0 {
+		return fmt.Errorf("%w: relation has no equations", ErrInvalidRelation)
+	}
+	used := make([]bool, rel.NumWitnesses)
+	for j := range rel.Equations {
+		if rel.Equations[j].Image.IsNaP() {
+			return fmt.Errorf("%w: image of equation %v is a NaP", ErrInvalidRelation, j)
+		}
+		if len(rel.Equations[j].Terms) == 0 {
+			return fmt.Errorf("%w: equation %v has no terms", ErrInvalidRelation, j)
+		}
+		for i := range rel.Equations[j].Terms {
+			term := &rel.Equations[j].Terms[i]
+			if term.Witness < 0 || term.Witness >= rel.NumWitnesses {
+				return fmt.Errorf("%w: term %v of equation %v uses witness %v, but there are only %v witnesses", ErrInvalidRelation, i, j, term.Witness, rel.NumWitnesses)
+			}
+			if term.Base.IsNaP() {
+				return fmt.Errorf("%w: base of term %v of equation %v is a NaP", ErrInvalidRelation, i, j)
+			}
+			used[term.Witness] = true
+		}
+	}
+	for k := range used {
+		if !used[k] {
+			return fmt.Errorf("%w: witness %v is not used", ErrInvalidRelation, k)
+		}
+	}
+	return nil
+}
+
+// And returns the AND-composition of the given relations, where the witnesses of the relations are independent.
+// The witnesses of the result are the witnesses of relations[0], followed by those of relations[1] etc.
+//
+// If some witnesses should be shared between the relations, construct the combined relation directly instead.
+func And(relations ...*LinearRelation) *LinearRelation {
+	ret := &LinearRelation{}
+	for _, rel := range relations {
+		for j := range rel.Equations {
+			equation := Equation{Image: rel.Equations[j].Image, Terms: make([]Term, len(rel.Equations[j].Terms))}
+			for i, term := range rel.Equations[j].Terms {
+				equation.Terms[i] = Term{Witness: term.Witness + ret.NumWitnesses, Base: term.Base}
+			}
+			ret.Equations = append(ret.Equations, equation)
+		}
+		ret.NumWitnesses += rel.NumWitnesses
+	}
+	return ret
+}
+
+// IsSatisfiedBy checks whether the given witnesses satisfy all equations of the relation. The relation must be valid and len(witnesses) must equal NumWitnesses (we panic otherwise).
+//
+// The running time does not depend on the witnesses (but the result does, of course).
+func (rel *LinearRelation) IsSatisfiedBy(witnesses []exponents.Exponent) bool {
+	rel.mustBeValid()
+	if len(witnesses) != rel.NumWitnesses {
+		panic(fmt.Errorf(ErrorPrefix+"got %v witnesses for a relation with %v witnesses", len(witnesses), rel.NumWitnesses))
+	}
+	satisfied := true
+	for j := range rel.Equations {
+		lhs := rel.evaluate(j, witnesses)
+		// no early exit, so the running time does not reveal which equation is not satisfied.
+		satisfied = lhs.IsEqual(&rel.Equations[j].Image) && satisfied
+	}
+	return satisfied
+}
+
+// evaluate computes sum_i scalars[w(j,i)] * Base_{j,i} for equation j.
+//
+// Since this is used by the prover with secret scalars, we use constant-time exponentiation for each term rather than a (faster) multi-exponentiation.
+func (rel *LinearRelation) evaluate(j int, scalars []exponents.Exponent) (ret curvePoints.Point_xtw_subgroup) {
+	terms := rel.Equations[j].Terms
+	ret.ExponentiateConstantTime(&terms[0].Base, &scalars[terms[0].Witness])
+	for i := 1; i < len(terms); i++ {
+		var term curvePoints.Point_xtw_subgroup
+		term.ExponentiateConstantTime(&terms[i].Base, &scalars[terms[i].Witness])
+		ret.AddEq(&term)
+	}
+	return
+}
+
+// mustBeValid panics if the relation is not valid.
+func (rel *LinearRelation) mustBeValid() {
+	if err := rel.Validate(); err != nil {
+		panic(err)
+	}
+}
+
+// appendToTranscript appends the statement to the transcript.
+func (rel *LinearRelation) appendToTranscript(t *Transcript) {
+	t.AppendMessage("sigma-protocol", []byte("linear-relation"))
+	t.AppendUint64("num-witnesses", uint64(rel.NumWitnesses))
+	t.AppendUint64("num-equations", uint64(len(rel.Equations)))
+	for j := range rel.Equations {
+		t.AppendUint64("num-terms", uint64(len(rel.Equations[j].Terms)))
+		for i := range rel.Equations[j].Terms {
+			t.AppendUint64("witness-index", uint64(rel.Equations[j].Terms[i].Witness))
+			t.AppendPoint("base", &rel.Equations[j].Terms[i].Base)
+		}
+		t.AppendPoint("image", &rel.Equations[j].Image)
+	}
+}
+
+// Prove creates a proof of knowledge of witnesses satisfying the relation. The proof is bound to the current state of the transcript t, which is updated.
+//
+// Fresh randomness for the nonces is read from rnd; if rnd is nil, we use crypto/rand.Reader.
+// We return an error wrapping ErrInvalidRelation if the relation is not valid, an error wrapping ErrWitnessNotSatisfying if the witnesses do not satisfy the relation
+// and an error if reading from rnd fails. If we return an error, t is unchanged.
+//
+// The running time does not depend on the witnesses.
+func (rel *LinearRelation) Prove(t *Transcript, witnesses []exponents.Exponent, rnd io.Reader) (*Proof, error) {
+	return rel.prove(t, "", witnesses, rnd)
+}
+
+// prove is Prove, where a non-empty proofLabel is appended to the transcript before the statement. This is used by the ready-made proofs.
+func (rel *LinearRelation) prove(t *Transcript, proofLabel string, witnesses []exponents.Exponent, rnd io.Reader) (*Proof, error) {
+	if err := rel.Validate(); err != nil {
+		return nil, err
+	}
+	if len(witnesses) != rel.NumWitnesses || !rel.IsSatisfiedBy(witnesses) {
+		return nil, ErrWitnessNotSatisfying
+	}
+	var randomness [nonceRandomnessSize]byte
+	if err := common.ReadRandomBytes(rnd, randomness[:]); err != nil {
+		return nil, err
+	}
+
+	// From here on, nothing can fail, so we may modify t.
+	t.checkInitialized()
+	if proofLabel != "" {
+		t.AppendMessage(proofKindLabel, []byte(proofLabel))
+	}
+	rel.appendToTranscript(t)
+	nonces := t.deriveNonces(witnesses, &randomness)
+	for j := range rel.Equations {
+		commitment := rel.evaluate(j, nonces)
+		t.AppendPoint("commitment", &commitment)
+	}
+	ret := &Proof{challenge: t.ChallengeExponent("challenge"), responses: make([]exponents.Exponent, rel.NumWitnesses)}
+	for k := range ret.responses {
+		// z_k = r_k + c*x_k
+		ret.responses[k].Mul(&ret.challenge, &witnesses[k])
+		ret.responses[k].Add(&ret.responses[k], &nonces[k])
+		ret.responses[k] = ret.responses[k].ModuloP253()
+	}
+	return ret, nil
+}
+
+// Verify verifies the proof for the relation, using the transcript t (which is updated).
+// The transcript must be in the same state as the prover's when calling Prove.
+//
+// The relation must be valid (we panic otherwise).
+func (rel *LinearRelation) Verify(t *Transcript, proof *Proof) bool {
+	rel.mustBeValid()
+	if len(proof.responses) != rel.NumWitnesses {
+		return false
+	}
+	rel.appendToTranscript(t)
+	// A_j = sum_i z_{w(j,i)} * Base_{j,i} - c * Image_j
+	var minusC exponents.Exponent
+	minusC.Neg(&proof.challenge)
+	for j := range rel.Equations {
+		terms := rel.Equations[j].Terms
+		points := make(curvePoints.CurvePointSlice_xtw_subgroup, len(terms)+1)
+		scalars := make([]exponents.Exponent, len(terms)+1)
+		for i := range terms {
+			points[i] = terms[i].Base
+			scalars[i] = proof.responses[terms[i].Witness]
+		}
+		points[len(terms)] = rel.Equations[j].Image
+		scalars[len(terms)] = minusC
+		commitment := curvePoints.MultiExponentiate(points, scalars)
+		t.AppendPoint("commitment", &commitment)
+	}
+	expectedChallenge := t.ChallengeExponent("challenge")
+	return expectedChallenge.IsEqual(&proof.challenge)
+}
+
+// deriveNonces derives len(witnesses) nonces from the transcript state, the witnesses and the given randomness.
+func (t *Transcript) deriveNonces(witnesses []exponents.Exponent, randomness *[nonceRandomnessSize]byte) []exponents.Exponent {
+	input := make([]byte, 0, transcriptStateSize+nonceRandomnessSize+exponents.ExponentBytesLength*len(witnesses)+8)
+	input = append(input, t.state[:]...)
+	input = append(input, randomness[:]...)
+	for k := range witnesses {
+		buf := witnesses[k].ToCanonicalBytes(transcriptExponentEndianness)
+		input = append(input, buf[:]...)
+	}
+	ret := make([]exponents.Exponent, len(witnesses))
+	indexPos := len(input)
+	input = append(input, make([]byte, 8)...)
+	for k := range ret {
+		transcriptExponentEndianness.PutUint64(input[indexPos:], uint64(k))
+		var err error
+		ret[k], err = exponents.HashToExponent(input, []byte(NonceDST))
+		if err != nil {
+			panic(fmt.Errorf(ErrorPrefix+"unexpected error when deriving nonce: %w", err))
+		}
+	}
+	return ret
+}
+
+// Challenge returns the challenge c of the proof.
+func (proof *Proof) Challenge() exponents.Exponent {
+	return proof.challenge
+}
+
+// Responses returns the responses z_0, ..., z_{n-1} of the proof.
+func (proof *Proof) Responses() []exponents.Exponent {
+	return append([]exponents.Exponent(nil), proof.responses...)
+}
+
+// Bytes returns the encoding c || z_0 || ... || z_{n-1} of the proof, where each exponent takes exponents.ExponentBytesLength bytes.
+func (proof *Proof) Bytes() []byte {
+	ret := make([]byte, 0, exponents.ExponentBytesLength*(1+len(proof.responses)))
+	buf := proof.challenge.ToCanonicalBytes(transcriptExponentEndianness)
+	ret = append(ret, buf[:]...)
+	for k := range proof.responses {
+		buf = proof.responses[k].ToCanonicalBytes(transcriptExponentEndianness)
+		ret = append(ret, buf[:]...)
+	}
+	return ret
+}
+
+// ProofFromBytes decodes a proof for a relation with numWitnesses witnesses in the format written by Proof.Bytes.
+//
+// We return an error wrapping ErrInvalidProofEncoding if buf has the wrong length or contains an exponent >= p253.
+func ProofFromBytes(buf []byte, numWitnesses int) (*Proof, error) {
+	if numWitnesses <= 0 || len(buf) != exponents.ExponentBytesLength*(1+numWitnesses) {
+		return nil, fmt.Errorf("%w: proof has length %v, expected %v", ErrInvalidProofEncoding, len(buf), exponents.ExponentBytesLength*(1+numWitnesses))
+	}
+	ret := &Proof{responses: make([]exponents.Exponent, numWitnesses)}
+	if err := ret.challenge.SetCanonicalBytes(buf[0:exponents.ExponentBytesLength], transcriptExponentEndianness); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProofEncoding, err)
+	}
+	for k := range ret.responses {
+		start := exponents.ExponentBytesLength * (k + 1)
+		if err := ret.responses[k].SetCanonicalBytes(buf[start:start+exponents.ExponentBytesLength], transcriptExponentEndianness); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidProofEncoding, err)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,180 @@
+package sigma
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// randomPoint returns a random point in the prime-order subgroup with unknown discrete logarithm (for the purpose of tests).
+func randomPoint(drng *rand.Rand) curvePoints.Point_xtw_subgroup {
+	p, err := curvePoints.RandomSubgroupPoint(drng)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// randomRelation creates a random relation with numWitnesses witnesses and numEquations equations, together with satisfying witnesses.
+func randomRelation(drng *rand.Rand, numWitnesses int, numEquations int) (*LinearRelation, []exponents.Exponent) {
+	witnesses := make([]exponents.Exponent, numWitnesses)
+	for k := range witnesses {
+		witnesses[k].SetRandom(drng)
+	}
+	rel := &LinearRelation{NumWitnesses: numWitnesses, Equations: make([]Equation, numEquations)}
+	for j := range rel.Equations {
+		numTerms := 1 + drng.Intn(3)
+		for i := 0; i < numTerms; i++ {
+			rel.Equations[j].Terms = append(rel.Equations[j].Terms, Term{Witness: drng.Intn(numWitnesses), Base: randomPoint(drng)})
+		}
+	}
+	// make sure every witness is used
+	for k := 0; k < numWitnesses; k++ {
+		j := drng.Intn(numEquations)
+		rel.Equations[j].Terms = append(rel.Equations[j].Terms, Term{Witness: k, Base: randomPoint(drng)})
+	}
+	for j := range rel.Equations {
+		image := rel.evaluate(j, witnesses)
+		rel.Equations[j].Image.SetFrom(&image)
+	}
+	return rel, witnesses
+}
+
+func TestLinearRelationProveVerify(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	for numWitnesses := 1; numWitnesses <= 3; numWitnesses++ {
+		for numEquations := 1; numEquations <= 3; numEquations++ {
+			rel, witnesses := randomRelation(drng, numWitnesses, numEquations)
+			testutils.FatalUnless(t, rel.Validate() == nil, "Random relation is invalid")
+			testutils.FatalUnless(t, rel.IsSatisfiedBy(witnesses), "Witnesses do not satisfy random relation")
+
+			proof, err := rel.Prove(NewTranscript("test"), witnesses, drng)
+			testutils.FatalUnless(t, err == nil, "Prove failed: %v", err)
+			testutils.FatalUnless(t, rel.Verify(NewTranscript("test"), proof), "Valid proof did not verify")
+
+			decoded, err := ProofFromBytes(proof.Bytes(), numWitnesses)
+			testutils.FatalUnless(t, err == nil, "Proof roundtrip failed: %v", err)
+			testutils.FatalUnless(t, rel.Verify(NewTranscript("test"), decoded), "Decoded proof did not verify")
+
+			// wrong transcript
+			testutils.FatalUnless(t, !rel.Verify(NewTranscript("other"), proof), "Proof verified with wrong transcript")
+			// modified statement
+			modified := *rel
+			modified.Equations = append([]Equation{}, rel.Equations...)
+			modified.Equations[0].Image.AddEq(&curvePoints.SubgroupGenerator_xtw_subgroup)
+			testutils.FatalUnless(t, !modified.Verify(NewTranscript("test"), proof), "Proof verified for modified statement")
+			// modified response
+			responses := proof.Responses()
+			var one exponents.Exponent
+			one.SetOne()
+			responses[0].Add(&responses[0], &one)
+			modifiedProof := &Proof{challenge: proof.Challenge(), responses: responses}
+			testutils.FatalUnless(t, !rel.Verify(NewTranscript("test"), modifiedProof), "Modified proof verified")
+
+			// non-satisfying witnesses
+			witnesses[0].Add(&witnesses[0], &one)
+			_, err = rel.Prove(NewTranscript("test"), witnesses, drng)
+			testutils.FatalUnless(t, errors.Is(err, ErrWitnessNotSatisfying), "Prove accepted wrong witnesses")
+		}
+	}
+}
+
+func TestAnd(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	rel1, witnesses1 := randomRelation(drng, 2, 2)
+	rel2, witnesses2 := randomRelation(drng, 1, 2)
+	combined := And(rel1, rel2)
+	testutils.FatalUnless(t, combined.NumWitnesses == 3 && len(combined.Equations) == 4, "And has wrong shape")
+	witnesses := append(append([]exponents.Exponent{}, witnesses1...), witnesses2...)
+	testutils.FatalUnless(t, combined.IsSatisfiedBy(witnesses), "And is not satisfied by concatenated witnesses")
+	proof, err := combined.Prove(NewTranscript("test"), witnesses, drng)
+	testutils.FatalUnless(t, err == nil && combined.Verify(NewTranscript("test"), proof), "And proof failed")
+	// the proof must not be valid for either part alone
+	testutils.FatalUnless(t, !rel1.Verify(NewTranscript("test"), proof), "And proof verified for first relation")
+}
+
+func TestInvalidRelations(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	G := randomPoint(drng)
+	var nap curvePoints.Point_xtw_subgroup
+	invalid := []*LinearRelation{
+		{NumWitnesses: 0, Equations: []Equation{{Image: G, Terms: []Term{{Witness: 0, Base: G}}}}},
+		{NumWitnesses: 1},
+		{NumWitnesses: 1, Equations: []Equation{{Image: G}}},
+		{NumWitnesses: 1, Equations: []Equation{{Image: G, Terms: []Term{{Witness: 1, Base: G}}}}},
+		{NumWitnesses: 2, Equations: []Equation{{Image: G, Terms: []Term{{Witness: 1, Base: G}}}}},
+		{NumWitnesses: 1, Equations: []Equation{{Image: nap, Terms: []Term{{Witness: 0, Base: G}}}}},
+		{NumWitnesses: 1, Equations: []Equation{{Image: G, Terms: []Term{{Witness: 0, Base: nap}}}}},
+	}
+	var one exponents.Exponent
+	one.SetOne()
+	for i, rel := range invalid {
+		testutils.FatalUnless(t, errors.Is(rel.Validate(), ErrInvalidRelation), "Invalid relation %v validated", i)
+		_, err := rel.Prove(NewTranscript("test"), []exponents.Exponent{one, one}, drng)
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidRelation), "Prove accepted invalid relation %v", i)
+		testutils.FatalUnless(t, testutils.CheckPanic(rel.Verify, NewTranscript("test"), &Proof{}), "Verify did not panic on invalid relation %v", i)
+	}
+}
+
+func TestProofEncoding(t *testing.T) {
+	_, err := ProofFromBytes(make([]byte, 64), 2)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidProofEncoding), "ProofFromBytes accepted wrong length")
+	_, err = ProofFromBytes(nil, 0)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidProofEncoding), "ProofFromBytes accepted zero witnesses")
+	buf := make([]byte, 64)
+	for i := 32; i < 64; i++ {
+		buf[i] = 0xFF
+	}
+	_, err = ProofFromBytes(buf, 1)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidProofEncoding), "ProofFromBytes accepted non-canonical exponent")
+}
+
+func TestReadyMadeProofs(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	G, H := randomPoint(drng), randomPoint(drng)
+	var x, m, r exponents.Exponent
+	x.SetRandom(drng)
+	m.SetRandom(drng)
+	r.SetRandom(drng)
+	var Y, Z, C, temp curvePoints.Point_xtw_subgroup
+	Y.Exponentiate(&G, &x)
+	Z.Exponentiate(&H, &x)
+	C.Exponentiate(&G, &m)
+	temp.Exponentiate(&H, &r)
+	C.AddEq(&temp)
+
+	proof, err := ProveKnowledge(NewTranscript("test"), &G, &Y, &x, drng)
+	testutils.FatalUnless(t, err == nil && VerifyKnowledge(NewTranscript("test"), &G, &Y, proof), "Knowledge proof failed")
+	testutils.FatalUnless(t, !VerifyKnowledge(NewTranscript("test"), &H, &Y, proof), "Knowledge proof verified for wrong base")
+
+	proof, err = ProveDLEQ(NewTranscript("test"), &G, &Y, &H, &Z, &x, drng)
+	testutils.FatalUnless(t, err == nil && VerifyDLEQ(NewTranscript("test"), &G, &Y, &H, &Z, proof), "DLEQ proof failed")
+	testutils.FatalUnless(t, !VerifyDLEQ(NewTranscript("test"), &G, &Y, &H, &C, proof), "DLEQ proof verified for wrong statement")
+	// A DLEQ proof must not be accepted as a (differently labelled) proof for the same relation.
+	testutils.FatalUnless(t, !DLEQRelation(&G, &Y, &H, &Z).Verify(NewTranscript("test"), proof), "DLEQ proof verified without label")
+	_, err = ProveDLEQ(NewTranscript("test"), &G, &Y, &H, &C, &x, drng)
+	testutils.FatalUnless(t, errors.Is(err, ErrWitnessNotSatisfying), "ProveDLEQ accepted false statement")
+
+	proof, err = ProveOpening(NewTranscript("test"), &G, &H, &C, &m, &r, drng)
+	testutils.FatalUnless(t, err == nil && VerifyOpening(NewTranscript("test"), &G, &H, &C, proof), "Opening proof failed")
+	testutils.FatalUnless(t, !VerifyOpening(NewTranscript("test"), &G, &H, &Y, proof), "Opening proof verified for wrong commitment")
+
+	designatedErr := errors.New("designated error")
+	_, err = ProveKnowledge(NewTranscript("test"), &G, &Y, &x, iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "Prove did not report randomness error")
+
+	// failing proofs must not modify the transcript
+	transcript := NewTranscript("test")
+	_, err = ProveDLEQ(transcript, &G, &Y, &H, &C, &x, drng)
+	testutils.FatalUnless(t, err != nil, "ProveDLEQ accepted false statement")
+	_, err = ProveKnowledge(transcript, &G, &Y, &x, iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, err != nil, "ProveKnowledge did not report randomness error")
+	got, expected := transcript.ChallengeBytes("check", 32), NewTranscript("test").ChallengeBytes("check", 32)
+	testutils.FatalUnless(t, bytes.Equal(got, expected), "Failed proof modified the transcript")
+}
@@ -0,0 +1,155 @@
+package sigma
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/pointserializer"
+)
+
+// This file contains Transcript, which is used to make interactive protocols non-interactive via the Fiat-Shamir transform.
+//
+// The design is similar to Merlin (https://merlin.cool), but based on SHA-512 rather than STROBE:
+// The transcript keeps a 64-byte state, which is initialized from a protocol label. Every operation absorbs its inputs into the state via
+//
+//	state' = SHA-512(TranscriptDST || op || state || len(label) || label || len(data) || data)
+//
+// where op is a single byte identifying the operation and lengths are encoded as 8-byte big-endian numbers.
+// Challenges are derived from the current state (via exponents.HashToExponent resp. expand_message_xmd) and absorbed into the state afterwards,
+// so every challenge depends on everything appended before and later challenges also depend on earlier ones.
+//
+// Prover and verifier must perform the same sequence of operations with the same labels. Labels are meant to be fixed strings, data is the (variable) protocol message.
+//
+// Points are appended in the Banderwagon encoding pointserializer.BanderwagonShort; this requires that points are in the prime-order subgroup.
+// Exponents are appended as 32-byte numbers modulo p253 with the same endianness.
+
+// TranscriptDST is the domain separation tag for all hashes made by a Transcript.
+const TranscriptDST = "BANDERSNATCH-TRANSCRIPT-V01"
+
+// operation codes for the state update.
+const (
+	transcriptOpInit      byte = 0x00
+	transcriptOpMessage   byte = 0x01
+	transcriptOpChallenge byte = 0x02
+)
+
+// transcriptStateSize is the size in bytes of the transcript's state.
+const transcriptStateSize = sha512.Size
+
+// transcriptPointSerializer is the serializer used for appending curve points.
+var transcriptPointSerializer = pointserializer.BanderwagonShort
+
+// transcriptExponentEndianness is the endianness used for appending exponents.
+var transcriptExponentEndianness common.FieldElementEndianness = transcriptPointSerializer.GetFieldElementEndianness()
+
+// Transcript is a Fiat-Shamir transcript. Use NewTranscript to create one. The zero value is not a valid transcript.
+//
+// Transcripts are values; copying a Transcript (or using Clone) gives an independent transcript that continues from the current state.
+type Transcript struct {
+	state       [transcriptStateSize]byte
+	initialized bool
+}
+
+// NewTranscript creates a new transcript for the protocol identified by protocolLabel.
+func NewTranscript(protocolLabel string) *Transcript {
+	var ret Transcript
+	ret.absorb(transcriptOpInit, protocolLabel, nil)
+	ret.initialized = true
+	return &ret
+}
+
+// Clone returns an independent copy of t.
+func (t *Transcript) Clone() *Transcript {
+	ret := *t
+	return &ret
+}
+
+// absorb updates the state with the given operation code, label and data.
+func (t *Transcript) absorb(op byte, label string, data []byte) {
+	var lengthBuf [8]byte
+	hasher := sha512.New()
+	hasher.Write([]byte(TranscriptDST))
+	hasher.Write([]byte{op})
+	hasher.Write(t.state[:])
+	binary.BigEndian.PutUint64(lengthBuf[:], uint64(len(label)))
+	hasher.Write(lengthBuf[:])
+	hasher.Write([]byte(label))
+	binary.BigEndian.PutUint64(lengthBuf[:], uint64(len(data)))
+	hasher.Write(lengthBuf[:])
+	hasher.Write(data)
+	copy(t.state[:], hasher.Sum(nil))
+}
+
+// checkInitialized panics if t was not created by NewTranscript.
+func (t *Transcript) checkInitialized() {
+	if !t.initialized {
+		panic(ErrorPrefix + "Transcript was not created by NewTranscript")
+	}
+}
+
+// AppendMessage appends data to the transcript.
+func (t *Transcript) AppendMessage(label string, data []byte) {
+	t.checkInitialized()
+	t.absorb(transcriptOpMessage, label, data)
+}
+
+// AppendUint64 appends the number x to the transcript.
+func (t *Transcript) AppendUint64(label string, x uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], x)
+	t.AppendMessage(label, buf[:])
+}
+
+// AppendPoint appends the curve point p to the transcript. p must be in the prime-order subgroup, else we panic.
+func (t *Transcript) AppendPoint(label string, p curvePoints.CurvePointPtrInterfaceRead) {
+	var buf bytes.Buffer
+	_, err := transcriptPointSerializer.SerializeCurvePoint(&buf, p)
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"could not append curve point to transcript: %w", err))
+	}
+	t.AppendMessage(label, buf.Bytes())
+}
+
+// AppendExponent appends the exponent x (modulo p253) to the transcript.
+func (t *Transcript) AppendExponent(label string, x *exponents.Exponent) {
+	buf := x.ToCanonicalBytes(transcriptExponentEndianness)
+	t.AppendMessage(label, buf[:])
+}
+
+// ChallengeBytes derives numBytes challenge bytes from the transcript. numBytes must be at most 255*64, else we panic.
+func (t *Transcript) ChallengeBytes(label string, numBytes int) []byte {
+	t.checkInitialized()
+	ret, err := common.ExpandMessageXMD(crypto.SHA512, t.challengeInput(label), []byte(TranscriptDST), numBytes)
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"could not derive challenge: %w", err))
+	}
+	t.absorb(transcriptOpChallenge, label, ret)
+	return ret
+}
+
+// ChallengeExponent derives a (close to uniform) challenge exponent modulo p253 from the transcript.
+func (t *Transcript) ChallengeExponent(label string) exponents.Exponent {
+	t.checkInitialized()
+	ret, err := exponents.HashToExponent(t.challengeInput(label), []byte(TranscriptDST))
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"could not derive challenge: %w", err))
+	}
+	buf := ret.ToCanonicalBytes(transcriptExponentEndianness)
+	t.absorb(transcriptOpChallenge, label, buf[:])
+	return ret
+}
+
+// challengeInput returns the input to the hash for deriving challenges, namely state || len(label) || label.
+func (t *Transcript) challengeInput(label string) []byte {
+	ret := make([]byte, 0, transcriptStateSize+8+len(label))
+	ret = append(ret, t.state[:]...)
+	ret = binary.BigEndian.AppendUint64(ret, uint64(len(label)))
+	ret = append(ret, label...)
+	return ret
+}
@@ -0,0 +1,75 @@
+package sigma
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func TestTranscriptDeterminism(t *testing.T) {
+	makeTranscript := func() *Transcript {
+		tr := NewTranscript("test protocol")
+		tr.AppendMessage("msg", []byte("hello"))
+		tr.AppendPoint("point", &curvePoints.SubgroupGenerator_xtw_subgroup)
+		var x exponents.Exponent
+		x.SetUInt(5)
+		tr.AppendExponent("exponent", &x)
+		tr.AppendUint64("number", 17)
+		return tr
+	}
+	t1, t2 := makeTranscript(), makeTranscript()
+	c1, c2 := t1.ChallengeExponent("c"), t2.ChallengeExponent("c")
+	testutils.FatalUnless(t, c1.IsEqual(&c2), "Transcript is not deterministic")
+	b1, b2 := t1.ChallengeBytes("b", 100), t2.ChallengeBytes("b", 100)
+	testutils.FatalUnless(t, len(b1) == 100 && bytes.Equal(b1, b2), "Transcript is not deterministic")
+
+	// subsequent challenges differ
+	c3 := t1.ChallengeExponent("c")
+	testutils.FatalUnless(t, !c1.IsEqual(&c3), "Repeated challenges are equal")
+
+	// clones are independent
+	clone := t1.Clone()
+	t1.AppendMessage("msg", []byte("x"))
+	clone.AppendMessage("msg", []byte("y"))
+	c4, c5 := t1.ChallengeExponent("c"), clone.ChallengeExponent("c")
+	testutils.FatalUnless(t, !c4.IsEqual(&c5), "Clone is not independent")
+}
+
+func TestTranscriptDomainSeparation(t *testing.T) {
+	challenge := func(f func(tr *Transcript)) exponents.Exponent {
+		tr := NewTranscript("test protocol")
+		f(tr)
+		return tr.ChallengeExponent("c")
+	}
+	variants := []func(tr *Transcript){
+		func(tr *Transcript) {},
+		func(tr *Transcript) { tr.AppendMessage("ab", []byte("c")) },
+		func(tr *Transcript) { tr.AppendMessage("a", []byte("bc")) },
+		func(tr *Transcript) { tr.AppendMessage("a", []byte("b")); tr.AppendMessage("", []byte("c")) },
+		func(tr *Transcript) { tr.AppendMessage("a", nil) },
+		func(tr *Transcript) { tr.ChallengeBytes("a", 0) },
+	}
+	challenges := make([]exponents.Exponent, len(variants))
+	for i := range variants {
+		challenges[i] = challenge(variants[i])
+		for j := 0; j < i; j++ {
+			testutils.FatalUnless(t, !challenges[i].IsEqual(&challenges[j]), "Transcript variants %v and %v give the same challenge", i, j)
+		}
+	}
+	other := NewTranscript("other protocol")
+	c := other.ChallengeExponent("c")
+	testutils.FatalUnless(t, !c.IsEqual(&challenges[0]), "Protocol label does not affect challenges")
+}
+
+func TestTranscriptInvalidUse(t *testing.T) {
+	var zero Transcript
+	testutils.FatalUnless(t, testutils.CheckPanic(zero.AppendMessage, "a", []byte("b")), "Zero transcript did not panic")
+	testutils.FatalUnless(t, testutils.CheckPanic(zero.ChallengeExponent, "a"), "Zero transcript did not panic")
+	tr := NewTranscript("test")
+	var fullPoint curvePoints.Point_xtw_full
+	fullPoint.SetFrom(&curvePoints.AffineOrderTwoPoint_xtw)
+	testutils.FatalUnless(t, testutils.CheckPanic(tr.AppendPoint, "p", &fullPoint), "Appending point outside subgroup did not panic")
+}
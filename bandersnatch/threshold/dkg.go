@@ -0,0 +1,301 @@
+package threshold
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains distributed key generation (DKG) for threshold schemes, following Pedersen's DKG (a.k.a. Joint-Feldman) with complaints.
+//
+// There are n participants with indices 1, ..., n and a threshold t. The protocol runs as follows:
+//
+//  1. Dealing: Each participant j chooses a random polynomial f_j of degree t-1, broadcasts its Feldman commitment C_j (see Participant.Commitment)
+//     and privately sends the share f_j(i) to every other participant i (see Participant.DealShare).
+//  2. Verification: Each participant i processes all commitments (ReceiveCommitment) and shares (ReceiveShare).
+//     For every dealer whose share is missing or inconsistent with the commitment, it broadcasts a complaint (see Participant.Complaints).
+//  3. Complaint resolution: For every complaint against j, dealer j broadcasts the disputed share (RespondToComplaint). Everyone checks it against C_j (ResolveComplaint).
+//     If the dealer does not respond or the revealed share is invalid, the dealer is disqualified. Otherwise, the accuser uses the revealed share.
+//  4. Finalizing: Let Q be the set of qualified (i.e. not disqualified) dealers. Participant i's key share is x_i = sum_{j in Q} f_j(i),
+//     the group public key is X = sum_{j in Q} f_j(0)*B and the combined commitment sum_{j in Q} C_j allows everyone to compute x_i*B for any i.
+//
+// Since steps 2 and 3 only depend on broadcast information, all honest participants agree on Q.
+// The group secret key x = sum_{j in Q} f_j(0) is never computed by anyone; any t participants can jointly use it.
+//
+// NOTE: As is well known, this protocol allows a rushing adversary to bias the distribution of the public key. This is fine for threshold Schnorr signatures.
+//
+// All methods of Participant are meant to be called from a single goroutine. Transport (authenticated broadcast and private channels) is up to the caller.
+
+var (
+	ErrInvalidShare        = errors.New(ErrorPrefix + "invalid share")
+	ErrUnknownParticipant  = errors.New(ErrorPrefix + "unknown participant")
+	ErrDuplicateMessage    = errors.New(ErrorPrefix + "duplicate message")
+	ErrUnresolvedComplaint = errors.New(ErrorPrefix + "unresolved complaint")
+	ErrNoQualifiedDealers  = errors.New(ErrorPrefix + "no qualified dealers")
+)
+
+// Complaint is broadcast by participant Accuser if the share it received from dealer Accused is missing or invalid.
+type Complaint struct {
+	Accuser uint32
+	Accused uint32
+}
+
+// Participant holds the state of a single participant in the DKG protocol. Use NewParticipant to create one.
+type Participant struct {
+	index        uint32
+	threshold    int
+	n            int
+	polynomial   *Polynomial
+	commitments  map[uint32]*FeldmanCommitment // commitments received from dealers, including our own
+	shares       map[uint32]*Share             // valid shares received from dealers, including our own
+	disqualified map[uint32]struct{}           // disqualified dealers
+}
+
+// NewParticipant creates the participant with the given index for a DKG with n participants and the given threshold.
+// It chooses its random polynomial, reading randomness from rnd (crypto/rand.Reader if rnd is nil).
+//
+// We require 1 <= threshold <= n and 1 <= index <= n, else we panic. We return an error if reading from rnd fails.
+func NewParticipant(index uint32, threshold int, n int, rnd io.Reader) (*Participant, error) {
+	if threshold < 1 || threshold > n || uint64(n) > uint64(^uint32(0)) {
+		panic(fmt.Errorf(ErrorPrefix+"NewParticipant called with threshold %v and n %v", threshold, n))
+	}
+	if index == 0 || uint64(index) > uint64(n) {
+		panic(fmt.Errorf(ErrorPrefix+"NewParticipant called with index %v for n = %v", index, n))
+	}
+	var secret exponents.Exponent
+	if err := secret.SetRandom(rnd); err != nil {
+		return nil, err
+	}
+	polynomial, err := NewRandomPolynomial(&secret, threshold, rnd)
+	if err != nil {
+		return nil, err
+	}
+	ownCommitment := polynomial.Commit()
+	ownShare := polynomial.Share(index)
+	ret := &Participant{
+		index:        index,
+		threshold:    threshold,
+		n:            n,
+		polynomial:   polynomial,
+		commitments:  map[uint32]*FeldmanCommitment{index: &ownCommitment},
+		shares:       map[uint32]*Share{index: &ownShare},
+		disqualified: make(map[uint32]struct{}),
+	}
+	return ret, nil
+}
+
+// Index returns the index of the participant.
+func (p *Participant) Index() uint32 {
+	return p.index
+}
+
+// Commitment returns the Feldman commitment to the participant's polynomial, which is to be broadcast to all other participants.
+func (p *Participant) Commitment() FeldmanCommitment {
+	return *p.commitments[p.index]
+}
+
+// DealShare returns the share for the given recipient, which is to be sent privately to that recipient.
+//
+// recipient must be a valid index, else we panic.
+func (p *Participant) DealShare(recipient uint32) Share {
+	if recipient == 0 || uint64(recipient) > uint64(p.n) {
+		panic(fmt.Errorf(ErrorPrefix+"DealShare called with invalid recipient %v", recipient))
+	}
+	return p.polynomial.Share(recipient)
+}
+
+// checkDealer returns an error if dealer is not a valid index of another participant.
+func (p *Participant) checkDealer(dealer uint32) error {
+	if dealer == 0 || uint64(dealer) > uint64(p.n) || dealer == p.index {
+		return fmt.Errorf("%w: invalid dealer %v", ErrUnknownParticipant, dealer)
+	}
+	return nil
+}
+
+// ReceiveCommitment processes the commitment broadcast by dealer.
+//
+// If the commitment does not have the correct threshold, the dealer is disqualified and we return an error wrapping ErrInvalidCommitment.
+// We return an error wrapping ErrUnknownParticipant or ErrDuplicateMessage if dealer is invalid (or ourselves) or we already received its commitment.
+func (p *Participant) ReceiveCommitment(dealer uint32, commitment *FeldmanCommitment) error {
+	if err := p.checkDealer(dealer); err != nil {
+		return err
+	}
+	if _, ok := p.commitments[dealer]; ok {
+		return fmt.Errorf("%w: commitment of dealer %v received twice", ErrDuplicateMessage, dealer)
+	}
+	if commitment.Threshold() != p.threshold {
+		p.disqualified[dealer] = struct{}{}
+		return fmt.Errorf("%w: commitment of dealer %v has threshold %v, expected %v", ErrInvalidCommitment, dealer, commitment.Threshold(), p.threshold)
+	}
+	copied := FeldmanCommitment{points: commitment.Points()}
+	p.commitments[dealer] = &copied
+	return nil
+}
+
+// ReceiveShare processes the share privately sent by dealer. The dealer's commitment must have been received before.
+//
+// If the share is invalid, we return an error wrapping ErrInvalidShare and Complaints will include a complaint against dealer.
+// We return an error wrapping ErrUnknownParticipant or ErrDuplicateMessage if dealer is invalid (or ourselves), we did not receive its commitment or we already received a share.
+func (p *Participant) ReceiveShare(dealer uint32, share *Share) error {
+	if err := p.checkDealer(dealer); err != nil {
+		return err
+	}
+	if _, ok := p.shares[dealer]; ok {
+		return fmt.Errorf("%w: share of dealer %v received twice", ErrDuplicateMessage, dealer)
+	}
+	commitment, ok := p.commitments[dealer]
+	if !ok {
+		return fmt.Errorf("%w: received share of dealer %v without commitment", ErrUnknownParticipant, dealer)
+	}
+	if share.Index != p.index || !commitment.VerifyShare(share) {
+		return fmt.Errorf("%w: share of dealer %v is inconsistent with its commitment", ErrInvalidShare, dealer)
+	}
+	copied := *share
+	p.shares[dealer] = &copied
+	return nil
+}
+
+// Complaints returns the complaints to be broadcast: one for every non-disqualified dealer whose share was invalid or has not been received.
+// The result is sorted by the accused dealer.
+func (p *Participant) Complaints() (ret []Complaint) {
+	for dealer := uint32(1); uint64(dealer) <= uint64(p.n); dealer++ {
+		if _, ok := p.disqualified[dealer]; ok {
+			continue
+		}
+		if _, ok := p.shares[dealer]; !ok {
+			ret = append(ret, Complaint{Accuser: p.index, Accused: dealer})
+		}
+	}
+	return
+}
+
+// RespondToComplaint returns the disputed share, which is to be broadcast in response to a complaint against the participant.
+//
+// complaint.Accused must be the participant itself and complaint.Accuser a valid index, else we panic.
+func (p *Participant) RespondToComplaint(complaint Complaint) Share {
+	if complaint.Accused != p.index {
+		panic(fmt.Errorf(ErrorPrefix+"participant %v asked to respond to a complaint against %v", p.index, complaint.Accused))
+	}
+	return p.DealShare(complaint.Accuser)
+}
+
+// ResolveComplaint processes a broadcast complaint and the (broadcast) response of the accused dealer, which is nil if the dealer did not respond.
+//
+// If the response is missing or inconsistent with the dealer's commitment, the dealer is disqualified and we return true.
+// Otherwise, if we are the accuser, we use the revealed share from now on.
+// Complaints against ourselves must be processed as well (we might have to disqualify ourselves if we misbehaved).
+func (p *Participant) ResolveComplaint(complaint Complaint, response *Share) (disqualified bool) {
+	commitment, ok := p.commitments[complaint.Accused]
+	if !ok || response == nil || response.Index != complaint.Accuser || !commitment.VerifyShare(response) {
+		p.disqualified[complaint.Accused] = struct{}{}
+		return true
+	}
+	if complaint.Accuser == p.index {
+		copied := *response
+		p.shares[complaint.Accused] = &copied
+	}
+	return false
+}
+
+// Disqualified returns the sorted list of disqualified dealers.
+func (p *Participant) Disqualified() []uint32 {
+	ret := make([]uint32, 0, len(p.disqualified))
+	for dealer := range p.disqualified {
+		ret = append(ret, dealer)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
+
+// Finalize ends the protocol and returns the participant's key share.
+//
+// The qualified dealers are all dealers whose commitment was received and who were not disqualified.
+// We return an error wrapping ErrUnresolvedComplaint if we do not hold a valid share from some qualified dealer (i.e. complaints still need to be resolved)
+// and an error wrapping ErrNoQualifiedDealers if no dealer is qualified.
+func (p *Participant) Finalize() (*KeyShare, error) {
+	ret := &KeyShare{index: p.index, threshold: p.threshold}
+	for dealer := uint32(1); uint64(dealer) <= uint64(p.n); dealer++ {
+		if _, ok := p.disqualified[dealer]; ok {
+			continue
+		}
+		commitment, ok := p.commitments[dealer]
+		if !ok {
+			continue
+		}
+		share, ok := p.shares[dealer]
+		if !ok {
+			return nil, fmt.Errorf("%w: no valid share from qualified dealer %v", ErrUnresolvedComplaint, dealer)
+		}
+		ret.qualified = append(ret.qualified, dealer)
+		ret.secret.Add(&ret.secret, &share.Value)
+		if len(ret.qualified) == 1 {
+			ret.commitment = FeldmanCommitment{points: commitment.Points()}
+		} else {
+			ret.commitment.Add(&ret.commitment, commitment)
+		}
+	}
+	if len(ret.qualified) == 0 {
+		return nil, ErrNoQualifiedDealers
+	}
+	return ret, nil
+}
+
+// KeyShare is the result of the DKG for a single participant. It holds the participant's share x_i of the group secret key x
+// and public information about the sharing.
+type KeyShare struct {
+	index      uint32
+	threshold  int
+	secret     exponents.Exponent // x_i
+	commitment FeldmanCommitment  // combined commitment of all qualified dealers
+	qualified  []uint32           // sorted
+}
+
+// NewKeyShare creates a key share from a share x_i of a secret and the Feldman commitment of its sharing (e.g. obtained from a trusted dealer via Split).
+// The list of qualified dealers is empty.
+//
+// We return an error wrapping ErrInvalidShare if the share is inconsistent with the commitment.
+func NewKeyShare(share *Share, commitment *FeldmanCommitment) (*KeyShare, error) {
+	if !commitment.VerifyShare(share) {
+		return nil, fmt.Errorf("%w: share %v is inconsistent with the commitment", ErrInvalidShare, share.Index)
+	}
+	return &KeyShare{index: share.Index, threshold: commitment.Threshold(), secret: share.Value, commitment: FeldmanCommitment{points: commitment.Points()}}, nil
+}
+
+// Index returns the index i of the participant holding the key share.
+func (ks *KeyShare) Index() uint32 {
+	return ks.index
+}
+
+// Threshold returns the number of participants needed to use the group secret key.
+func (ks *KeyShare) Threshold() int {
+	return ks.threshold
+}
+
+// SecretShare returns the share x_i of the group secret key. Note that the result is secret.
+func (ks *KeyShare) SecretShare() Share {
+	return Share{Index: ks.index, Value: ks.secret}
+}
+
+// GroupPublicKey returns the group public key X = x*B.
+func (ks *KeyShare) GroupPublicKey() curvePoints.Point_xtw_subgroup {
+	return ks.commitment.PublicKey()
+}
+
+// PublicShare returns x_j*B for the participant with the given index. This is used to verify contributions of other participants.
+func (ks *KeyShare) PublicShare(index uint32) curvePoints.Point_xtw_subgroup {
+	return ks.commitment.PublicShare(index)
+}
+
+// Commitment returns the combined Feldman commitment to the sharing of the group secret key.
+func (ks *KeyShare) Commitment() FeldmanCommitment {
+	return FeldmanCommitment{points: ks.commitment.Points()}
+}
+
+// Qualified returns the sorted list of qualified dealers whose contributions make up the group key.
+func (ks *KeyShare) Qualified() []uint32 {
+	return append([]uint32(nil), ks.qualified...)
+}
@@ -0,0 +1,293 @@
+package threshold
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// dkgSimulation runs the DKG protocol among in-process participants. Misbehaviour of dealers can be injected via the function fields.
+type dkgSimulation struct {
+	threshold    int
+	participants []*Participant // participant with index i is at position i-1
+
+	// corruptShare is called for every share sent privately; it may modify the share. If it returns false, the share is not sent. nil means honest behaviour.
+	corruptShare func(dealer, recipient uint32, share *Share) (send bool)
+	// respond returns the response broadcast for a complaint, given the honest response. nil means honest behaviour.
+	respond func(complaint Complaint, honest Share) *Share
+}
+
+func newDKGSimulation(rnd *rand.Rand, threshold int, n int) *dkgSimulation {
+	sim := &dkgSimulation{threshold: threshold, participants: make([]*Participant, n)}
+	for i := range sim.participants {
+		p, err := NewParticipant(uint32(i+1), threshold, n, rnd)
+		if err != nil {
+			panic(err)
+		}
+		sim.participants[i] = p
+	}
+	return sim
+}
+
+// run runs all rounds of the protocol and returns the key shares. It checks that all participants agree on the disqualified dealers and the group key.
+func (sim *dkgSimulation) run(t *testing.T) []*KeyShare {
+	// dealing and verification
+	for _, dealer := range sim.participants {
+		commitment := dealer.Commitment()
+		for _, recipient := range sim.participants {
+			if recipient == dealer {
+				continue
+			}
+			err := recipient.ReceiveCommitment(dealer.Index(), &commitment)
+			testutils.FatalUnless(t, err == nil, "ReceiveCommitment failed: %v", err)
+			share := dealer.DealShare(recipient.Index())
+			if sim.corruptShare != nil && !sim.corruptShare(dealer.Index(), recipient.Index(), &share) {
+				continue
+			}
+			err = recipient.ReceiveShare(dealer.Index(), &share)
+			testutils.FatalUnless(t, err == nil || errors.Is(err, ErrInvalidShare), "ReceiveShare failed: %v", err)
+		}
+	}
+
+	// complaints
+	var complaints []Complaint
+	for _, p := range sim.participants {
+		complaints = append(complaints, p.Complaints()...)
+	}
+	for _, complaint := range complaints {
+		honest := sim.participants[complaint.Accused-1].RespondToComplaint(complaint)
+		response := &honest
+		if sim.respond != nil {
+			response = sim.respond(complaint, honest)
+		}
+		for _, p := range sim.participants {
+			p.ResolveComplaint(complaint, response)
+		}
+	}
+
+	// finalize
+	keyShares := make([]*KeyShare, len(sim.participants))
+	for i, p := range sim.participants {
+		keyShare, err := p.Finalize()
+		testutils.FatalUnless(t, err == nil, "Finalize failed: %v", err)
+		testutils.FatalUnless(t, len(p.Complaints()) == 0, "complaints remain after resolution")
+		keyShares[i] = keyShare
+	}
+	disqualified := sim.participants[0].Disqualified()
+	groupKey := keyShares[0].GroupPublicKey()
+	for i := range keyShares {
+		testutils.FatalUnless(t, equalIndices(sim.participants[i].Disqualified(), disqualified), "participants disagree on disqualified dealers")
+		otherGroupKey := keyShares[i].GroupPublicKey()
+		testutils.FatalUnless(t, otherGroupKey.IsEqual(&groupKey), "participants disagree on group public key")
+		commitment, otherCommitment := keyShares[0].Commitment(), keyShares[i].Commitment()
+		testutils.FatalUnless(t, commitment.IsEqual(&otherCommitment), "participants disagree on combined commitment")
+		testutils.FatalUnless(t, len(keyShares[i].Qualified())+len(disqualified) == len(sim.participants), "qualified and disqualified dealers do not add up")
+	}
+	return keyShares
+}
+
+func equalIndices(x, y []uint32) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkKeyShares checks that the key shares form a valid sharing of the discrete logarithm of the group public key.
+func checkKeyShares(t *testing.T, rnd *rand.Rand, keyShares []*KeyShare, threshold int) {
+	groupKey := keyShares[0].GroupPublicKey()
+	testutils.FatalUnless(t, !groupKey.IsNeutralElement(), "group public key is neutral")
+	for _, ks := range keyShares {
+		share := ks.SecretShare()
+		var expected curvePoints.Point_xtw_subgroup
+		expected.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &share.Value)
+		for _, other := range keyShares {
+			publicShare := other.PublicShare(ks.Index())
+			testutils.FatalUnless(t, publicShare.IsEqual(&expected), "PublicShare inconsistent with secret share")
+		}
+		testutils.FatalUnless(t, ks.Threshold() == threshold, "wrong threshold")
+	}
+
+	for trial := 0; trial < 3; trial++ {
+		perm := rnd.Perm(len(keyShares))
+		shares := make([]Share, threshold)
+		pointShares := make([]PointShare, threshold)
+		for i := range shares {
+			shares[i] = keyShares[perm[i]].SecretShare()
+			pointShares[i] = PointShare{Index: shares[i].Index, Value: keyShares[0].PublicShare(shares[i].Index)}
+		}
+		secret, err := Reconstruct(shares)
+		testutils.FatalUnless(t, err == nil, "Reconstruct failed: %v", err)
+		var publicKey curvePoints.Point_xtw_subgroup
+		publicKey.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &secret)
+		testutils.FatalUnless(t, publicKey.IsEqual(&groupKey), "key shares do not reconstruct the group secret key")
+		interpolated, err := InterpolateInExponent(pointShares)
+		testutils.FatalUnless(t, err == nil && interpolated.IsEqual(&groupKey), "interpolating public shares does not give the group public key")
+		if threshold > 1 {
+			secret, _ = Reconstruct(shares[1:])
+			publicKey.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &secret)
+			testutils.FatalUnless(t, !publicKey.IsEqual(&groupKey), "too few key shares reconstruct the group secret key")
+		}
+	}
+}
+
+func TestDKGHonest(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, params := range [][2]int{{1, 1}, {1, 2}, {2, 3}, {3, 5}, {4, 4}} {
+		threshold, n := params[0], params[1]
+		sim := newDKGSimulation(rnd, threshold, n)
+		keyShares := sim.run(t)
+		checkKeyShares(t, rnd, keyShares, threshold)
+		testutils.FatalUnless(t, len(keyShares[0].Qualified()) == n, "honest dealers were disqualified")
+
+		// the group secret key is the sum of the dealers' secrets
+		var expected curvePoints.Point_xtw_subgroup
+		expected.SetFrom(&curvePoints.NeutralElement_xtw_subgroup)
+		for _, p := range sim.participants {
+			commitment := p.Commitment()
+			dealerKey := commitment.PublicKey()
+			expected.AddEq(&dealerKey)
+		}
+		groupKey := keyShares[0].GroupPublicKey()
+		testutils.FatalUnless(t, groupKey.IsEqual(&expected), "group public key is not the sum of the dealers' keys")
+	}
+}
+
+func TestDKGComplaints(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const threshold, n = 3, 5
+	delta := randomExponent(rnd)
+
+	// dealer 2 sends a wrong share to 4 and no share to 5, but answers complaints honestly: nobody is disqualified.
+	sim := newDKGSimulation(rnd, threshold, n)
+	sim.corruptShare = func(dealer, recipient uint32, share *Share) bool {
+		if dealer == 2 && recipient == 4 {
+			share.Value.Add(&share.Value, &delta)
+		}
+		return !(dealer == 2 && recipient == 5)
+	}
+	keyShares := sim.run(t)
+	checkKeyShares(t, rnd, keyShares, threshold)
+	testutils.FatalUnless(t, len(sim.participants[0].Disqualified()) == 0, "dealer answering complaints honestly was disqualified")
+
+	// dealer 2 sends a wrong share to 4 and answers the complaint with a wrong share, dealer 3 withholds a share and does not answer: both are disqualified.
+	sim = newDKGSimulation(rnd, threshold, n)
+	sim.corruptShare = func(dealer, recipient uint32, share *Share) bool {
+		if dealer == 2 && recipient == 4 {
+			share.Value.Add(&share.Value, &delta)
+		}
+		return !(dealer == 3 && recipient == 1)
+	}
+	sim.respond = func(complaint Complaint, honest Share) *Share {
+		switch complaint.Accused {
+		case 2:
+			honest.Value.Add(&honest.Value, &delta)
+			return &honest
+		case 3:
+			return nil
+		default:
+			return &honest
+		}
+	}
+	keyShares = sim.run(t)
+	checkKeyShares(t, rnd, keyShares, threshold)
+	testutils.FatalUnless(t, equalIndices(sim.participants[0].Disqualified(), []uint32{2, 3}), "misbehaving dealers were not disqualified")
+	testutils.FatalUnless(t, equalIndices(keyShares[0].Qualified(), []uint32{1, 4, 5}), "wrong qualified set")
+
+	// a response for the wrong accuser does not resolve the complaint
+	sim = newDKGSimulation(rnd, threshold, n)
+	sim.corruptShare = func(dealer, recipient uint32, share *Share) bool {
+		return !(dealer == 1 && recipient == 2)
+	}
+	sim.respond = func(complaint Complaint, honest Share) *Share {
+		wrong := sim.participants[complaint.Accused-1].DealShare(complaint.Accuser + 1)
+		return &wrong
+	}
+	keyShares = sim.run(t)
+	checkKeyShares(t, rnd, keyShares, threshold)
+	testutils.FatalUnless(t, equalIndices(sim.participants[0].Disqualified(), []uint32{1}), "dealer revealing the wrong share was not disqualified")
+}
+
+func TestDKGMessageErrors(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const threshold, n = 2, 3
+	sim := newDKGSimulation(rnd, threshold, n)
+	p1, p2 := sim.participants[0], sim.participants[1]
+	c2 := p2.Commitment()
+
+	testutils.FatalUnless(t, errors.Is(p1.ReceiveCommitment(1, &c2), ErrUnknownParticipant), "commitment from self accepted")
+	testutils.FatalUnless(t, errors.Is(p1.ReceiveCommitment(0, &c2), ErrUnknownParticipant), "commitment from index 0 accepted")
+	testutils.FatalUnless(t, errors.Is(p1.ReceiveCommitment(4, &c2), ErrUnknownParticipant), "commitment from unknown dealer accepted")
+	share := p2.DealShare(1)
+	testutils.FatalUnless(t, errors.Is(p1.ReceiveShare(2, &share), ErrUnknownParticipant), "share without commitment accepted")
+	testutils.FatalUnless(t, p1.ReceiveCommitment(2, &c2) == nil, "valid commitment rejected")
+	testutils.FatalUnless(t, errors.Is(p1.ReceiveCommitment(2, &c2), ErrDuplicateMessage), "duplicate commitment accepted")
+	wrongRecipient := p2.DealShare(3)
+	testutils.FatalUnless(t, errors.Is(p1.ReceiveShare(2, &wrongRecipient), ErrInvalidShare), "share for other recipient accepted")
+	testutils.FatalUnless(t, p1.ReceiveShare(2, &share) == nil, "valid share rejected")
+	testutils.FatalUnless(t, errors.Is(p1.ReceiveShare(2, &share), ErrDuplicateMessage), "duplicate share accepted")
+
+	// commitment with wrong threshold disqualifies the dealer
+	f, _ := NewRandomPolynomial(&share.Value, threshold+1, rnd)
+	wrongThreshold := f.Commit()
+	err := p1.ReceiveCommitment(3, &wrongThreshold)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "commitment with wrong threshold accepted")
+	testutils.FatalUnless(t, equalIndices(p1.Disqualified(), []uint32{3}), "dealer with wrong threshold not disqualified")
+	testutils.FatalUnless(t, len(p1.Complaints()) == 0, "complaint against disqualified dealer")
+
+	keyShare, err := p1.Finalize()
+	testutils.FatalUnless(t, err == nil && equalIndices(keyShare.Qualified(), []uint32{1, 2}), "Finalize failed: %v", err)
+
+	// unresolved complaints: p1 received no share from 2 and nothing at all from 3.
+	sim = newDKGSimulation(rnd, threshold, n)
+	p1, p2 = sim.participants[0], sim.participants[1]
+	c2 = p2.Commitment()
+	_ = p1.ReceiveCommitment(2, &c2)
+	complaints := p1.Complaints()
+	testutils.FatalUnless(t, len(complaints) == 2 && complaints[0] == Complaint{Accuser: 1, Accused: 2} && complaints[1] == Complaint{Accuser: 1, Accused: 3}, "wrong complaints %v", complaints)
+	_, err = p1.Finalize()
+	testutils.FatalUnless(t, errors.Is(err, ErrUnresolvedComplaint), "Finalize succeeded with unresolved complaint")
+	testutils.FatalUnless(t, testutils.CheckPanic(p1.RespondToComplaint, complaints[0]), "participant responded to complaint against someone else")
+	response := p2.RespondToComplaint(complaints[0])
+	testutils.FatalUnless(t, !p1.ResolveComplaint(complaints[0], &response), "valid response disqualified dealer")
+	testutils.FatalUnless(t, p1.ResolveComplaint(complaints[1], nil), "dealer without commitment and response was not disqualified")
+	keyShare, err = p1.Finalize()
+	testutils.FatalUnless(t, err == nil, "Finalize failed after complaint resolution: %v", err)
+	testutils.FatalUnless(t, equalIndices(keyShare.Qualified(), []uint32{1, 2}), "wrong qualified set")
+
+	testutils.FatalUnless(t, testutils.CheckPanic(NewParticipant, uint32(0), 2, 3, rnd), "NewParticipant accepted index 0")
+	testutils.FatalUnless(t, testutils.CheckPanic(NewParticipant, uint32(4), 2, 3, rnd), "NewParticipant accepted index > n")
+	testutils.FatalUnless(t, testutils.CheckPanic(NewParticipant, uint32(1), 4, 3, rnd), "NewParticipant accepted threshold > n")
+	testutils.FatalUnless(t, testutils.CheckPanic(p1.DealShare, uint32(4)), "DealShare accepted invalid recipient")
+	designatedErr := errors.New("designated error")
+	_, err = NewParticipant(1, 2, 3, iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "NewParticipant did not report randomness failure")
+}
+
+func TestNewKeyShare(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	secret := randomExponent(rnd)
+	f, _ := NewRandomPolynomial(&secret, 2, rnd)
+	commitment := f.Commit()
+	keyShares := make([]*KeyShare, 4)
+	for i := range keyShares {
+		share := f.Share(uint32(i + 1))
+		ks, err := NewKeyShare(&share, &commitment)
+		testutils.FatalUnless(t, err == nil, "NewKeyShare failed: %v", err)
+		keyShares[i] = ks
+	}
+	checkKeyShares(t, rnd, keyShares, 2)
+	wrong := f.Share(1)
+	wrong.Index = 2
+	_, err := NewKeyShare(&wrong, &commitment)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidShare), "NewKeyShare accepted invalid share")
+}
@@ -0,0 +1,168 @@
+package threshold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/pointserializer"
+)
+
+// This file contains Feldman commitments to sharing polynomials, which make Shamir secret sharing verifiable.
+//
+// For a polynomial f(x) = sum_k a_k x^k of degree t-1, the commitment is the vector (A_0, ..., A_{t-1}) with A_k = a_k*B.
+// A share s = f(i) is valid iff s*B == sum_k i^k * A_k, which we check with a single multi-exponentiation.
+// The commitment reveals A_0 = f(0)*B (the public key if f(0) is a secret key), but nothing else about f(0).
+//
+// Commitments are additive: The sum of the commitments to f and g is the commitment to f+g. This is used in distributed key generation.
+// Commitments are serialized as the concatenation of the points A_0, ..., A_{t-1} via pointserializer.BanderwagonShort.SerializeCurvePoints.
+
+// PointSize is the size in bytes of a serialized point in a FeldmanCommitment.
+const PointSize = 32
+
+var ErrInvalidCommitment = errors.New(ErrorPrefix + "invalid Feldman commitment")
+
+// pointSerializer is the serializer used for Feldman commitments.
+var pointSerializer = pointserializer.BanderwagonShort
+
+// FeldmanCommitment is a commitment (A_0, ..., A_{t-1}) to a polynomial of degree t-1.
+// The zero value is not a valid commitment; use Polynomial.Commit, NewFeldmanCommitment or FeldmanCommitmentFromBytes to obtain one.
+type FeldmanCommitment struct {
+	points curvePoints.CurvePointSlice_xtw_subgroup
+}
+
+// Commit returns the Feldman commitment to f.
+func (f *Polynomial) Commit() FeldmanCommitment {
+	ret := FeldmanCommitment{points: make(curvePoints.CurvePointSlice_xtw_subgroup, len(f.coefficients))}
+	for k := range f.coefficients {
+		ret.points[k].ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, &f.coefficients[k])
+	}
+	return ret
+}
+
+// NewFeldmanCommitment creates a commitment from the points (A_0, ..., A_{t-1}). The points are copied.
+//
+// We return an error wrapping ErrInvalidCommitment if points is empty or contains a NaP.
+func NewFeldmanCommitment(points []curvePoints.Point_xtw_subgroup) (FeldmanCommitment, error) {
+	if len(points) == 0 {
+		return FeldmanCommitment{}, fmt.Errorf("%w: commitment is empty", ErrInvalidCommitment)
+	}
+	for i := range points {
+		if points[i].IsNaP() {
+			return FeldmanCommitment{}, fmt.Errorf("%w: point %v is a NaP", ErrInvalidCommitment, i)
+		}
+	}
+	return FeldmanCommitment{points: append(curvePoints.CurvePointSlice_xtw_subgroup(nil), points...)}, nil
+}
+
+// Threshold returns t for a commitment to a polynomial of degree t-1. This is 0 for the zero value.
+func (c *FeldmanCommitment) Threshold() int {
+	return len(c.points)
+}
+
+// Points returns (a copy of) the points (A_0, ..., A_{t-1}).
+func (c *FeldmanCommitment) Points() curvePoints.CurvePointSlice_xtw_subgroup {
+	return append(curvePoints.CurvePointSlice_xtw_subgroup(nil), c.points...)
+}
+
+// PublicKey returns A_0 = f(0)*B.
+func (c *FeldmanCommitment) PublicKey() curvePoints.Point_xtw_subgroup {
+	if len(c.points) == 0 {
+		panic(ErrorPrefix + "called PublicKey on an uninitialized Feldman commitment")
+	}
+	return c.points[0]
+}
+
+// powers returns (1, x, x^2, ..., x^{t-1}) for x = index.
+func (c *FeldmanCommitment) powers(index uint32) []exponents.Exponent {
+	ret := make([]exponents.Exponent, len(c.points))
+	var x exponents.Exponent
+	x.SetUInt(uint64(index))
+	ret[0].SetOne()
+	for k := 1; k < len(ret); k++ {
+		ret[k].Mul(&ret[k-1], &x)
+	}
+	return ret
+}
+
+// PublicShare returns f(index)*B = sum_k index^k * A_k, i.e. the share of participant index "in the exponent".
+//
+// c must not be the zero value, else we panic.
+func (c *FeldmanCommitment) PublicShare(index uint32) (ret curvePoints.Point_xtw_subgroup) {
+	if len(c.points) == 0 {
+		panic(ErrorPrefix + "called PublicShare on an uninitialized Feldman commitment")
+	}
+	result := curvePoints.MultiExponentiate(c.points, c.powers(index))
+	ret.SetFrom(&result)
+	return
+}
+
+// VerifyShare checks whether share is consistent with the commitment, i.e. whether share.Value*B == sum_k share.Index^k * A_k.
+//
+// We return false for shares with index 0 and for the zero value of c.
+func (c *FeldmanCommitment) VerifyShare(share *Share) bool {
+	if share.Index == 0 || len(c.points) == 0 {
+		return false
+	}
+	// check sum_k index^k * A_k - value*B == neutral element with a single MSM.
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, 0, len(c.points)+1)
+	points = append(points, c.points...)
+	points = append(points, curvePoints.SubgroupGenerator_xtw_subgroup)
+	scalars := c.powers(share.Index)
+	var negValue exponents.Exponent
+	negValue.Neg(&share.Value)
+	scalars = append(scalars, negValue)
+	result := curvePoints.MultiExponentiate(points, scalars)
+	return result.IsNeutralElement()
+}
+
+// Add sets c = x + y, which is a commitment to the sum of the committed polynomials. x and y may have different thresholds.
+func (c *FeldmanCommitment) Add(x, y *FeldmanCommitment) {
+	if len(x.points) < len(y.points) {
+		x, y = y, x
+	}
+	sum := append(curvePoints.CurvePointSlice_xtw_subgroup(nil), x.points...)
+	for k := range y.points {
+		sum[k].AddEq(&y.points[k])
+	}
+	c.points = sum
+}
+
+// IsEqual checks whether c and x are the same commitment.
+func (c *FeldmanCommitment) IsEqual(x *FeldmanCommitment) bool {
+	if len(c.points) != len(x.points) {
+		return false
+	}
+	for k := range c.points {
+		if !c.points[k].IsEqual(&x.points[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bytes returns the serialization of the commitment, which has length c.Threshold() * PointSize.
+func (c *FeldmanCommitment) Bytes() []byte {
+	var buf bytes.Buffer
+	if _, err := pointSerializer.SerializeCurvePoints(&buf, c.points); err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when serializing Feldman commitment: %w", err))
+	}
+	return buf.Bytes()
+}
+
+// FeldmanCommitmentFromBytes decodes a commitment in the format written by Bytes.
+//
+// We return an error wrapping ErrInvalidCommitment if buf is empty, its length is not a multiple of PointSize or it contains invalid points.
+func FeldmanCommitmentFromBytes(buf []byte) (FeldmanCommitment, error) {
+	if len(buf) == 0 || len(buf)%PointSize != 0 {
+		return FeldmanCommitment{}, fmt.Errorf("%w: commitment has length %v, expected a positive multiple of %v", ErrInvalidCommitment, len(buf), PointSize)
+	}
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, len(buf)/PointSize)
+	if _, err := pointSerializer.DeserializeCurvePoints(bytes.NewReader(buf), common.UntrustedInput, points); err != nil {
+		return FeldmanCommitment{}, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	return FeldmanCommitment{points: points}, nil
+}
@@ -0,0 +1,104 @@
+package threshold
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func TestFeldmanCommitment(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	secret := randomExponent(rnd)
+	f, _ := NewRandomPolynomial(&secret, 3, rnd)
+	c := f.Commit()
+	testutils.FatalUnless(t, c.Threshold() == 3, "wrong threshold")
+	var expected curvePoints.Point_xtw_subgroup
+	expected.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &secret)
+	publicKey := c.PublicKey()
+	testutils.FatalUnless(t, publicKey.IsEqual(&expected), "PublicKey is not secret*B")
+
+	var one exponents.Exponent
+	one.SetOne()
+	for index := uint32(1); index <= 10; index++ {
+		share := f.Share(index)
+		testutils.FatalUnless(t, c.VerifyShare(&share), "valid share rejected")
+		expected.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &share.Value)
+		publicShare := c.PublicShare(index)
+		testutils.FatalUnless(t, publicShare.IsEqual(&expected), "PublicShare wrong")
+
+		wrongValue := share
+		wrongValue.Value.Add(&wrongValue.Value, &one)
+		testutils.FatalUnless(t, !c.VerifyShare(&wrongValue), "wrong share value accepted")
+		wrongIndex := share
+		wrongIndex.Index++
+		testutils.FatalUnless(t, !c.VerifyShare(&wrongIndex), "wrong share index accepted")
+	}
+	zeroIndex := Share{Index: 0, Value: secret}
+	testutils.FatalUnless(t, !c.VerifyShare(&zeroIndex), "share with index 0 accepted")
+	var empty FeldmanCommitment
+	share := f.Share(1)
+	testutils.FatalUnless(t, !empty.VerifyShare(&share), "zero value commitment accepted a share")
+	testutils.FatalUnless(t, testutils.CheckPanic(empty.PublicKey), "PublicKey on zero value did not panic")
+	testutils.FatalUnless(t, testutils.CheckPanic(empty.PublicShare, uint32(1)), "PublicShare on zero value did not panic")
+}
+
+func TestFeldmanCommitmentAdd(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s1, s2 := randomExponent(rnd), randomExponent(rnd)
+	f, _ := NewRandomPolynomial(&s1, 3, rnd)
+	g, _ := NewRandomPolynomial(&s2, 2, rnd)
+	cf, cg := f.Commit(), g.Commit()
+	var sum FeldmanCommitment
+	sum.Add(&cf, &cg)
+	testutils.FatalUnless(t, sum.Threshold() == 3, "sum has wrong threshold")
+	for index := uint32(1); index <= 5; index++ {
+		sf, sg := f.Share(index), g.Share(index)
+		combined := Share{Index: index}
+		combined.Value.Add(&sf.Value, &sg.Value)
+		testutils.FatalUnless(t, sum.VerifyShare(&combined), "sum of shares not accepted by sum of commitments")
+	}
+	// aliasing and commutativity
+	var other FeldmanCommitment
+	other.Add(&cg, &cf)
+	testutils.FatalUnless(t, other.IsEqual(&sum), "Add is not commutative")
+	cfCopy := f.Commit()
+	cf.Add(&cf, &cg)
+	testutils.FatalUnless(t, cf.IsEqual(&sum), "Add with aliasing failed")
+	testutils.FatalUnless(t, !cf.IsEqual(&cfCopy), "commitments to different polynomials are equal")
+}
+
+func TestFeldmanCommitmentEncoding(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	secret := randomExponent(rnd)
+	f, _ := NewRandomPolynomial(&secret, 4, rnd)
+	c := f.Commit()
+	encoded := c.Bytes()
+	testutils.FatalUnless(t, len(encoded) == 4*PointSize, "wrong encoding length")
+	decoded, err := FeldmanCommitmentFromBytes(encoded)
+	testutils.FatalUnless(t, err == nil && decoded.IsEqual(&c), "roundtrip failed: %v", err)
+
+	fromPoints, err := NewFeldmanCommitment(c.Points())
+	testutils.FatalUnless(t, err == nil && fromPoints.IsEqual(&c), "NewFeldmanCommitment failed: %v", err)
+	points := c.Points()
+	points[0] = curvePoints.Point_xtw_subgroup{}
+	testutils.FatalUnless(t, c.IsEqual(&fromPoints), "Points did not return a copy")
+	_, err = NewFeldmanCommitment(points)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "NaP accepted")
+	_, err = NewFeldmanCommitment(nil)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "empty commitment accepted")
+
+	_, err = FeldmanCommitmentFromBytes(nil)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "empty encoding accepted")
+	_, err = FeldmanCommitmentFromBytes(encoded[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "encoding with wrong length accepted")
+	bad := append([]byte(nil), encoded...)
+	for i := PointSize; i < 2*PointSize; i++ {
+		bad[i] = 0xff
+	}
+	_, err = FeldmanCommitmentFromBytes(bad)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidCommitment), "invalid point accepted")
+}
@@ -0,0 +1,202 @@
+package threshold
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains Shamir secret sharing of exponents and Lagrange interpolation, both of exponents and "in the exponent", i.e. of curve points.
+//
+// A (t, n)-sharing of a secret s uses a uniformly random polynomial f of degree t-1 with f(0) = s. Participant i (for 1 <= i <= n) obtains the share f(i).
+// Any t shares determine f and hence s = f(0) via Lagrange interpolation:
+//
+//	f(0) = sum_{i in S} lambda_i * f(i), where lambda_i = prod_{j in S, j != i} j / (j - i)
+//
+// for a set S of t indices. Since interpolation is linear, the same coefficients recover f(0)*B from the points f(i)*B for i in S.
+// Fewer than t shares give no information about s.
+//
+// Shares are indexed by non-zero uint32's. All arithmetic is modulo p253.
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / threshold: "
+
+var (
+	ErrInvalidIndex   = errors.New(ErrorPrefix + "invalid share index")
+	ErrDuplicateIndex = errors.New(ErrorPrefix + "duplicate share index")
+	ErrNoShares       = errors.New(ErrorPrefix + "no shares given")
+)
+
+// Share is a share f(Index) of a secret shared via a polynomial f.
+type Share struct {
+	Index uint32
+	Value exponents.Exponent
+}
+
+// PointShare is a share f(Index)*B "in the exponent". For Lagrange interpolation, B can be any fixed point.
+type PointShare struct {
+	Index uint32
+	Value curvePoints.Point_xtw_subgroup
+}
+
+// Polynomial is a polynomial over the exponents modulo p253, used to share its constant coefficient.
+type Polynomial struct {
+	coefficients []exponents.Exponent // coefficients[k] is the coefficient of x^k
+}
+
+// NewRandomPolynomial returns a uniformly random polynomial f of degree threshold - 1 with f(0) = secret, reading randomness from rnd (crypto/rand.Reader if rnd is nil).
+//
+// threshold must be at least 1, else we panic. We return an error if reading from rnd fails.
+func NewRandomPolynomial(secret *exponents.Exponent, threshold int, rnd io.Reader) (*Polynomial, error) {
+	if threshold < 1 {
+		panic(fmt.Errorf(ErrorPrefix+"NewRandomPolynomial called with threshold %v", threshold))
+	}
+	ret := &Polynomial{coefficients: make([]exponents.Exponent, threshold)}
+	ret.coefficients[0] = secret.ModuloP253()
+	for k := 1; k < threshold; k++ {
+		if err := ret.coefficients[k].SetRandom(rnd); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// Threshold returns the number of shares needed to recover the secret, i.e. the degree of f plus 1.
+func (f *Polynomial) Threshold() int {
+	return len(f.coefficients)
+}
+
+// Secret returns the shared secret f(0).
+func (f *Polynomial) Secret() exponents.Exponent {
+	return f.coefficients[0]
+}
+
+// Evaluate returns f(x).
+func (f *Polynomial) Evaluate(x *exponents.Exponent) (ret exponents.Exponent) {
+	// Horner's method
+	for k := len(f.coefficients) - 1; k >= 0; k-- {
+		ret.Mul(&ret, x)
+		ret.Add(&ret, &f.coefficients[k])
+	}
+	return
+}
+
+// Share returns the share f(index) for the participant with the given index. index must be non-zero, else we panic.
+func (f *Polynomial) Share(index uint32) Share {
+	if index == 0 {
+		panic(ErrorPrefix + "trying to create a share for index 0, which would reveal the secret")
+	}
+	var x exponents.Exponent
+	x.SetUInt(uint64(index))
+	return Share{Index: index, Value: f.Evaluate(&x)}
+}
+
+// Split creates a (threshold, n)-sharing of secret, i.e. n shares with indices 1, ..., n, any threshold of which recover the secret.
+// It reads randomness from rnd (crypto/rand.Reader if rnd is nil).
+//
+// We require 1 <= threshold <= n, else we panic. We return an error if reading from rnd fails.
+func Split(secret *exponents.Exponent, threshold int, n int, rnd io.Reader) ([]Share, error) {
+	if threshold > n || uint64(n) > uint64(^uint32(0)) {
+		panic(fmt.Errorf(ErrorPrefix+"Split called with threshold %v and n %v", threshold, n))
+	}
+	f, err := NewRandomPolynomial(secret, threshold, rnd)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([]Share, n)
+	for i := range shares {
+		shares[i] = f.Share(uint32(i + 1))
+	}
+	return shares, nil
+}
+
+// LagrangeCoefficientsAtZero returns the Lagrange coefficients lambda_i for interpolating at 0 from the given indices,
+// i.e. f(0) = sum_i lambda_i * f(indices[i]) for every polynomial f of degree less than len(indices).
+//
+// We return an error wrapping ErrInvalidIndex if some index is 0, ErrDuplicateIndex if some index appears twice and ErrNoShares if indices is empty.
+func LagrangeCoefficientsAtZero(indices []uint32) ([]exponents.Exponent, error) {
+	if len(indices) == 0 {
+		return nil, ErrNoShares
+	}
+	seen := make(map[uint32]struct{}, len(indices))
+	for _, index := range indices {
+		if index == 0 {
+			return nil, fmt.Errorf("%w: index 0 is not allowed", ErrInvalidIndex)
+		}
+		if _, ok := seen[index]; ok {
+			return nil, fmt.Errorf("%w: index %v appears more than once", ErrDuplicateIndex, index)
+		}
+		seen[index] = struct{}{}
+	}
+
+	// lambda_i = prod_{j != i} x_j / prod_{j != i} (x_j - x_i). We batch-invert all denominators.
+	xs := make([]exponents.Exponent, len(indices))
+	for i, index := range indices {
+		xs[i].SetUInt(uint64(index))
+	}
+	numerators := make([]exponents.Exponent, len(indices))
+	denominators := make([]exponents.Exponent, len(indices))
+	var diff exponents.Exponent
+	for i := range xs {
+		numerators[i].SetOne()
+		denominators[i].SetOne()
+		for j := range xs {
+			if j == i {
+				continue
+			}
+			numerators[i].Mul(&numerators[i], &xs[j])
+			diff.Sub(&xs[j], &xs[i])
+			denominators[i].Mul(&denominators[i], &diff)
+		}
+	}
+	if err := exponents.MultiInvertEqSlice(denominators); err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error in Lagrange interpolation: %w", err)) // cannot happen for distinct indices < p253
+	}
+	for i := range numerators {
+		numerators[i].Mul(&numerators[i], &denominators[i])
+	}
+	return numerators, nil
+}
+
+// Reconstruct recovers f(0) from the given shares, which must come from a polynomial f of degree less than len(shares).
+//
+// Note that if too few shares are given, the result is just a wrong value; use a FeldmanCommitment to detect this.
+// We return an error wrapping ErrInvalidIndex, ErrDuplicateIndex or ErrNoShares for invalid sets of indices (see LagrangeCoefficientsAtZero).
+func Reconstruct(shares []Share) (ret exponents.Exponent, err error) {
+	indices := make([]uint32, len(shares))
+	for i := range shares {
+		indices[i] = shares[i].Index
+	}
+	lambdas, err := LagrangeCoefficientsAtZero(indices)
+	if err != nil {
+		return
+	}
+	var term exponents.Exponent
+	for i := range shares {
+		term.Mul(&lambdas[i], &shares[i].Value)
+		ret.Add(&ret, &term)
+	}
+	return
+}
+
+// InterpolateInExponent recovers f(0)*B from the given points f(i)*B, where f is a polynomial of degree less than len(shares).
+//
+// This uses a single multi-exponentiation. We return the same errors as Reconstruct.
+func InterpolateInExponent(shares []PointShare) (ret curvePoints.Point_xtw_subgroup, err error) {
+	indices := make([]uint32, len(shares))
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, len(shares))
+	for i := range shares {
+		indices[i] = shares[i].Index
+		points[i] = shares[i].Value
+	}
+	lambdas, err := LagrangeCoefficientsAtZero(indices)
+	if err != nil {
+		return
+	}
+	result := curvePoints.MultiExponentiate(points, lambdas)
+	ret.SetFrom(&result)
+	return
+}
@@ -0,0 +1,125 @@
+package threshold
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func randomExponent(rnd *rand.Rand) (ret exponents.Exponent) {
+	if err := ret.SetRandom(rnd); err != nil {
+		panic(err)
+	}
+	return
+}
+
+func TestSplitReconstruct(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, params := range [][2]int{{1, 1}, {1, 3}, {2, 3}, {3, 5}, {5, 5}, {4, 10}} {
+		threshold, n := params[0], params[1]
+		secret := randomExponent(rnd)
+		shares, err := Split(&secret, threshold, n, rnd)
+		testutils.FatalUnless(t, err == nil && len(shares) == n, "Split failed: %v", err)
+		for i := range shares {
+			testutils.FatalUnless(t, shares[i].Index == uint32(i+1), "wrong index")
+		}
+
+		// any threshold shares reconstruct the secret
+		for trial := 0; trial < 5; trial++ {
+			perm := rnd.Perm(n)
+			subset := make([]Share, threshold)
+			for i := range subset {
+				subset[i] = shares[perm[i]]
+			}
+			reconstructed, err := Reconstruct(subset)
+			testutils.FatalUnless(t, err == nil && reconstructed.IsEqual(&secret), "reconstruction failed for (%v, %v): %v", threshold, n, err)
+			// more shares work as well
+			reconstructed, _ = Reconstruct(shares)
+			testutils.FatalUnless(t, reconstructed.IsEqual(&secret), "reconstruction from all shares failed")
+			// fewer shares do not
+			if threshold > 1 {
+				reconstructed, _ = Reconstruct(subset[1:])
+				testutils.FatalUnless(t, !reconstructed.IsEqual(&secret), "reconstruction from too few shares succeeded")
+			}
+		}
+	}
+
+	designatedErr := errors.New("designated error")
+	secret := randomExponent(rnd)
+	_, err := Split(&secret, 2, 3, iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "Split did not report randomness failure")
+	testutils.FatalUnless(t, testutils.CheckPanic(Split, &secret, 4, 3, rnd), "Split did not panic for threshold > n")
+	testutils.FatalUnless(t, testutils.CheckPanic(Split, &secret, 0, 3, rnd), "Split did not panic for threshold 0")
+}
+
+func TestPolynomial(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	secret := randomExponent(rnd)
+	f, err := NewRandomPolynomial(&secret, 4, rnd)
+	testutils.FatalUnless(t, err == nil, "NewRandomPolynomial failed: %v", err)
+	testutils.FatalUnless(t, f.Threshold() == 4, "wrong threshold")
+	s := f.Secret()
+	testutils.FatalUnless(t, s.IsEqual(&secret), "wrong secret")
+	var zero exponents.Exponent
+	f0 := f.Evaluate(&zero)
+	testutils.FatalUnless(t, f0.IsEqual(&secret), "f(0) != secret")
+
+	// compare Horner's method with the naive evaluation
+	x := randomExponent(rnd)
+	var expected, power, term exponents.Exponent
+	power.SetOne()
+	for k := range f.coefficients {
+		term.Mul(&power, &f.coefficients[k])
+		expected.Add(&expected, &term)
+		power.Mul(&power, &x)
+	}
+	got := f.Evaluate(&x)
+	testutils.FatalUnless(t, got.IsEqual(&expected), "Evaluate is wrong")
+	testutils.FatalUnless(t, testutils.CheckPanic(f.Share, uint32(0)), "Share did not panic for index 0")
+}
+
+func TestLagrangeCoefficientsErrors(t *testing.T) {
+	_, err := LagrangeCoefficientsAtZero(nil)
+	testutils.FatalUnless(t, errors.Is(err, ErrNoShares), "empty index set accepted")
+	_, err = LagrangeCoefficientsAtZero([]uint32{1, 0, 2})
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidIndex), "index 0 accepted")
+	_, err = LagrangeCoefficientsAtZero([]uint32{1, 5, 2, 5})
+	testutils.FatalUnless(t, errors.Is(err, ErrDuplicateIndex), "duplicate index accepted")
+	_, err = Reconstruct([]Share{{Index: 3}, {Index: 3}})
+	testutils.FatalUnless(t, errors.Is(err, ErrDuplicateIndex), "duplicate index accepted")
+	_, err = InterpolateInExponent(nil)
+	testutils.FatalUnless(t, errors.Is(err, ErrNoShares), "empty index set accepted")
+
+	// a single share at index i corresponds to a constant polynomial
+	lambdas, err := LagrangeCoefficientsAtZero([]uint32{7})
+	testutils.FatalUnless(t, err == nil && lambdas[0].IsOne_Subgroup(), "Lagrange coefficient for single index is not 1")
+	// the Lagrange coefficients always sum to 1
+	lambdas, _ = LagrangeCoefficientsAtZero([]uint32{3, 1, 4, 15, 9})
+	var sum exponents.Exponent
+	for i := range lambdas {
+		sum.Add(&sum, &lambdas[i])
+	}
+	testutils.FatalUnless(t, sum.IsOne_Subgroup(), "Lagrange coefficients do not sum to 1")
+}
+
+func TestInterpolateInExponent(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	secret := randomExponent(rnd)
+	shares, _ := Split(&secret, 3, 6, rnd)
+	pointShares := make([]PointShare, 0, 3)
+	for _, i := range []int{5, 0, 3} {
+		var p PointShare
+		p.Index = shares[i].Index
+		p.Value.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &shares[i].Value)
+		pointShares = append(pointShares, p)
+	}
+	var expected curvePoints.Point_xtw_subgroup
+	expected.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &secret)
+	got, err := InterpolateInExponent(pointShares)
+	testutils.FatalUnless(t, err == nil && got.IsEqual(&expected), "InterpolateInExponent failed: %v", err)
+}
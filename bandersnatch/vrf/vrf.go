@@ -0,0 +1,290 @@
+package vrf
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+)
+
+// This file contains a verifiable random function (VRF) on Bandersnatch, following the structure of ECVRF from RFC 9381.
+//
+// Notation: B is the generator curvePoints.SubgroupGenerator_xtw_subgroup of the prime-order subgroup, the private key is an exponent x != 0, the public key is Y = x*B.
+// To evaluate the VRF on an input alpha, we compute
+//
+//	H = encode_to_curve(alpha), Gamma = x*H, beta = SHA-512(suite_string || 0x03 || enc(Gamma) || 0x00)
+//
+// where beta is the VRF output and encode_to_curve uses the suite bandersnatch_XMD:SHA-512_ELL2_NU_ from RFC 9380 with DST EncodeToCurveDST.
+// The proof pi shows that log_B(Y) == log_H(Gamma) (a Chaum-Pedersen DLEQ proof): For a nonce k, we set U = k*B, V = k*H,
+//
+//	c = first ChallengeSize bytes of SHA-512(suite_string || 0x02 || enc(Y) || enc(H) || enc(Gamma) || enc(U) || enc(V) || 0x00), s = k + c*x (modulo p253)
+//
+// and pi = enc(Gamma) || c || s. A verifier recomputes U = s*B - c*Y, V = s*H - c*Gamma and checks the challenge.
+//
+// Here, enc is the Banderwagon encoding pointserializer.BanderwagonShort; c and s are encoded as numbers with the same endianness (i.e. little endian), as in the Edwards suites of RFC 9381.
+// The nonce k is derived deterministically from x and enc(H) (via exponents.HashToExponent with DST NonceDST) rather than as in RFC 8032.
+//
+// Differences to RFC 9381:
+//   - We do not salt encode_to_curve with the public key. This way, the input point H only depends on alpha (and Gamma = x*H only on alpha and x),
+//     which is required for compatibility with ring VRFs, where the public key is hidden.
+//   - Since the Banderwagon encoding can only represent points in the prime-order subgroup, decoding enforces subgroup membership and
+//     we do not need to multiply by the cofactor when computing beta.
+//
+// Consequently, outputs and proofs are NOT compatible with any RFC 9381 suite.
+//
+// NOTE: The VRF output beta is only pseudorandom for parties not knowing x. Anyone knowing the public key Y can verify a claimed output with a proof.
+
+// ErrorPrefix is prepended to all error messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / vrf: "
+
+const (
+	PublicKeySize  = keypair.PointSize                              // size in bytes of encoded public keys
+	PrivateKeySize = keypair.ScalarSize                             // size in bytes of encoded private keys
+	ChallengeSize  = 16                                             // size in bytes of the challenge c in proofs
+	ProofSize      = PublicKeySize + ChallengeSize + PrivateKeySize // size in bytes of proofs enc(Gamma) || c || s
+	OutputSize     = sha512.Size                                    // size in bytes of the VRF output beta
+)
+
+// SuiteString identifies the VRF suite. It is included in all hashes.
+const SuiteString = "Bandersnatch_SHA-512_ELL2"
+
+// Domain separation tags for encode_to_curve and the nonce derivation.
+const (
+	EncodeToCurveDST = "ECVRF_bandersnatch_XMD:SHA-512_ELL2_NU_" + SuiteString
+	NonceDST         = "ECVRF_" + SuiteString + "_NONCE"
+)
+
+// domain separators inside the hashes, as in RFC 9381.
+const (
+	challengeGenerationDomainSeparatorFront byte = 0x02
+	proofToHashDomainSeparatorFront         byte = 0x03
+	domainSeparatorBack                     byte = 0x00
+)
+
+var (
+	ErrInvalidPrivateKey = errors.New(ErrorPrefix + "invalid private key")
+	ErrInvalidPublicKey  = errors.New(ErrorPrefix + "invalid public key")
+	ErrInvalidProof      = errors.New(ErrorPrefix + "invalid VRF proof")
+)
+
+// PrivateKey is a VRF private key. The zero value is not a valid private key; use GenerateKey or PrivateKeyFromBytes to create one.
+type PrivateKey struct {
+	key keypair.PrivateKey // private exponent x and public key x*B
+}
+
+// PublicKey is a VRF public key. The zero value is not a valid public key; use PublicKeyFromBytes or PrivateKey.PublicKey to obtain one.
+type PublicKey struct {
+	key keypair.PublicKey // Y and its encoding, which is part of the challenge hash
+}
+
+// GenerateKey generates a new private key, reading randomness from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// We return an error if reading from rnd fails.
+func GenerateKey(rnd io.Reader) (*PrivateKey, error) {
+	key, err := keypair.GenerateKey(rnd)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{key: key}, nil
+}
+
+// PrivateKeyFromBytes decodes a private key in the format written by PrivateKey.Bytes.
+//
+// We return an error wrapping ErrInvalidPrivateKey if buf has the wrong length, encodes a number >= p253 or encodes 0.
+func PrivateKeyFromBytes(buf []byte) (*PrivateKey, error) {
+	key, err := keypair.PrivateKeyFromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+	return &PrivateKey{key: key}, nil
+}
+
+// Bytes returns the encoding of the private key. Note that the result is secret.
+func (sk *PrivateKey) Bytes() []byte {
+	return sk.key.Bytes()
+}
+
+// PublicKey returns the public key corresponding to sk.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{key: *sk.key.PublicKey()}
+}
+
+// Equal checks whether sk and other are the same private key.
+func (sk *PrivateKey) Equal(other *PrivateKey) bool {
+	return sk.key.Equal(&other.key)
+}
+
+// PublicKeyFromBytes decodes a public key in the format written by PublicKey.Bytes.
+//
+// We return an error wrapping ErrInvalidPublicKey if buf is not a valid encoding of a point in the prime-order subgroup or encodes the neutral element.
+func PublicKeyFromBytes(buf []byte) (*PublicKey, error) {
+	key, err := keypair.PublicKeyFromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return &PublicKey{key: key}, nil
+}
+
+// Bytes returns the encoding of the public key.
+func (pk *PublicKey) Bytes() []byte {
+	return pk.key.Bytes()
+}
+
+// Point returns the public key as a curve point.
+func (pk *PublicKey) Point() curvePoints.Point_xtw_subgroup {
+	return *pk.key.Point()
+}
+
+// Equal checks whether pk and other are the same public key.
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	return pk.key.Equal(&other.key)
+}
+
+// InputPoint computes the point H = encode_to_curve(alpha) that the VRF input alpha is mapped to.
+//
+// The VRF output for a private key x is determined by Gamma = x*H. This is exported for protocols (such as ring VRFs) that prove statements about Gamma in other ways.
+func InputPoint(alpha []byte) curvePoints.Point_xtw_subgroup {
+	return curvePoints.EncodeToCurve(alpha, []byte(EncodeToCurveDST))
+}
+
+// Prove evaluates the VRF with private key sk on input alpha. It returns the point Gamma = x*H and the proof.
+//
+// The VRF output beta can be obtained from Gamma via OutputFromGamma or from the proof via ProofToHash; Verify returns it as well.
+// Proving is deterministic.
+func Prove(sk *PrivateKey, alpha []byte) (gamma curvePoints.Point_xtw_subgroup, proof []byte) {
+	H := InputPoint(alpha)
+	encodedH := keypair.EncodePoint(&H)
+	gamma.ExponentiateConstantTime(&H, sk.key.Scalar())
+	encodedGamma := keypair.EncodePoint(&gamma)
+
+	k := sk.deriveNonce(&encodedH)
+	var U, V curvePoints.Point_xtw_subgroup
+	U.ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, &k)
+	V.ExponentiateConstantTime(&H, &k)
+	encodedU := keypair.EncodePoint(&U)
+	encodedV := keypair.EncodePoint(&V)
+
+	encodedY := sk.key.PublicKey().Encoding()
+	c := challenge(&encodedY, &encodedH, &encodedGamma, &encodedU, &encodedV)
+	cExponent := challengeToExponent(&c)
+	// s = k + c*x
+	var s exponents.Exponent
+	s.Mul(&cExponent, sk.key.Scalar())
+	s.Add(&s, &k)
+	sBytes := keypair.EncodeScalar(&s)
+
+	proof = make([]byte, 0, ProofSize)
+	proof = append(proof, encodedGamma[:]...)
+	proof = append(proof, c[:]...)
+	proof = append(proof, sBytes[:]...)
+	return
+}
+
+// Verify verifies the VRF proof for input alpha under the public key pk. On success, it returns the VRF output beta.
+//
+// We return an error wrapping ErrInvalidProof if the proof is malformed or invalid.
+func Verify(pk *PublicKey, alpha []byte, proof []byte) (beta []byte, err error) {
+	gamma, c, s, err := decodeProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	H := InputPoint(alpha)
+	encodedH := keypair.EncodePoint(&H)
+
+	// U = s*B - c*Y, V = s*H - c*Gamma
+	minusC := challengeToExponent(&c)
+	minusC.Neg(&minusC)
+	U := curvePoints.MultiExponentiate(curvePoints.CurvePointSlice_xtw_subgroup{curvePoints.SubgroupGenerator_xtw_subgroup, *pk.key.Point()}, []exponents.Exponent{s, minusC})
+	V := curvePoints.MultiExponentiate(curvePoints.CurvePointSlice_xtw_subgroup{H, gamma}, []exponents.Exponent{s, minusC})
+	encodedU := keypair.EncodePoint(&U)
+	encodedV := keypair.EncodePoint(&V)
+
+	encodedY := pk.key.Encoding()
+	expectedC := challenge(&encodedY, &encodedH, (*[PublicKeySize]byte)(proof[0:PublicKeySize]), &encodedU, &encodedV)
+	if expectedC != c {
+		return nil, fmt.Errorf("%w: proof does not verify", ErrInvalidProof)
+	}
+	return OutputFromGamma(&gamma), nil
+}
+
+// ProofToHash returns the VRF output beta contained in the given proof (proof_to_hash in RFC 9381).
+//
+// This does NOT verify the proof. We return an error wrapping ErrInvalidProof if the proof is malformed.
+func ProofToHash(proof []byte) (beta []byte, err error) {
+	gamma, _, _, err := decodeProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	return OutputFromGamma(&gamma), nil
+}
+
+// OutputFromGamma computes the VRF output beta from the point Gamma.
+func OutputFromGamma(gamma *curvePoints.Point_xtw_subgroup) []byte {
+	encodedGamma := keypair.EncodePoint(gamma)
+	hasher := sha512.New()
+	hasher.Write([]byte(SuiteString))
+	hasher.Write([]byte{proofToHashDomainSeparatorFront})
+	hasher.Write(encodedGamma[:])
+	hasher.Write([]byte{domainSeparatorBack})
+	return hasher.Sum(nil)
+}
+
+// decodeProof decodes a proof enc(Gamma) || c || s. We return an error wrapping ErrInvalidProof if the proof has the wrong length,
+// Gamma is not a valid encoding of a point in the prime-order subgroup or s encodes a number >= p253.
+func decodeProof(proof []byte) (gamma curvePoints.Point_xtw_subgroup, c [ChallengeSize]byte, s exponents.Exponent, err error) {
+	if len(proof) != ProofSize {
+		err = fmt.Errorf("%w: proof has length %v, expected %v", ErrInvalidProof, len(proof), ProofSize)
+		return
+	}
+	gamma, err = keypair.DecodePoint(proof[0:PublicKeySize])
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrInvalidProof, err)
+		return
+	}
+	copy(c[:], proof[PublicKeySize:PublicKeySize+ChallengeSize])
+	if s, err = keypair.DecodeScalar(proof[PublicKeySize+ChallengeSize:]); err != nil {
+		err = fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	return
+}
+
+// challenge computes the challenge c (challenge_generation in RFC 9381) from the encodings of Y, H, Gamma, U and V.
+func challenge(encodedY, encodedH, encodedGamma, encodedU, encodedV *[PublicKeySize]byte) (c [ChallengeSize]byte) {
+	hasher := sha512.New()
+	hasher.Write([]byte(SuiteString))
+	hasher.Write([]byte{challengeGenerationDomainSeparatorFront})
+	hasher.Write(encodedY[:])
+	hasher.Write(encodedH[:])
+	hasher.Write(encodedGamma[:])
+	hasher.Write(encodedU[:])
+	hasher.Write(encodedV[:])
+	hasher.Write([]byte{domainSeparatorBack})
+	copy(c[:], hasher.Sum(nil))
+	return
+}
+
+// challengeToExponent interprets the challenge c as a number with endianness keypair.ScalarEndianness. Since c has only 128 bits, this is smaller than p253.
+func challengeToExponent(c *[ChallengeSize]byte) (ret exponents.Exponent) {
+	var buf [exponents.ExponentBytesLength]byte
+	if keypair.ScalarEndianness.StartsWithMSB() {
+		copy(buf[exponents.ExponentBytesLength-ChallengeSize:], c[:])
+	} else {
+		copy(buf[:], c[:])
+	}
+	if err := ret.SetCanonicalBytes(buf[:], keypair.ScalarEndianness); err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when converting challenge: %w", err))
+	}
+	return
+}
+
+// deriveNonce computes the nonce k = H_nonce(enc(x) || enc(H)).
+func (sk *PrivateKey) deriveNonce(encodedH *[PublicKeySize]byte) exponents.Exponent {
+	input := make([]byte, 0, PrivateKeySize+PublicKeySize)
+	input = append(input, sk.Bytes()...)
+	input = append(input, encodedH[:]...)
+	return keypair.DeriveNonce(input, NonceDST)
+}
@@ -0,0 +1,160 @@
+package vrf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/keypair"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// regression test vector: private key 42 (little endian), input "sample"
+const (
+	katPublicKey = "853866878683ced2f5320869e9e0ce60a3d5373e3fd36108444109c036892cc0"
+	katProof     = "3c6422f9cb03fa858724302cd44a5821a104ae680bb9919d61018b65c0a2f0c6e8b75f834bf2fc64667f4420c1a50f484065bb98cd72b4fd97f2945e7e3dfe563ba7c32686e47877e3c6329080f96415"
+	katOutput    = "c81020381cae548a6caeb5fc856386ec20f560a9a97014e80c2bac624564c86f7fdc9766033627b36d6dc3f55115fca7f83a8fa297d5fbb69fd1a3c1bac8c13b"
+)
+
+func TestProveVerify(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		sk, err := GenerateKey(drng)
+		testutils.FatalUnless(t, err == nil, "GenerateKey failed: %v", err)
+		pk := sk.PublicKey()
+		alpha := make([]byte, drng.Intn(50))
+		drng.Read(alpha)
+
+		gamma, proof := Prove(sk, alpha)
+		testutils.FatalUnless(t, len(proof) == ProofSize, "Proof has wrong size")
+		beta, err := Verify(pk, alpha, proof)
+		testutils.FatalUnless(t, err == nil, "Valid proof did not verify: %v", err)
+		testutils.FatalUnless(t, len(beta) == OutputSize, "Output has wrong size")
+		testutils.FatalUnless(t, bytes.Equal(beta, OutputFromGamma(&gamma)), "Verify output differs from OutputFromGamma")
+		betaFromProof, err := ProofToHash(proof)
+		testutils.FatalUnless(t, err == nil && bytes.Equal(beta, betaFromProof), "Verify output differs from ProofToHash")
+
+		// Gamma == x*H
+		H := InputPoint(alpha)
+		var expectedGamma curvePoints.Point_xtw_subgroup
+		expectedGamma.Exponentiate(&H, sk.key.Scalar())
+		testutils.FatalUnless(t, gamma.IsEqual(&expectedGamma), "Gamma is not x*H")
+
+		_, proof2 := Prove(sk, alpha)
+		testutils.FatalUnless(t, bytes.Equal(proof, proof2), "Proving is not deterministic")
+
+		_, err = Verify(pk, append(alpha, 0), proof)
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidProof), "Proof verified for wrong input")
+		otherSk, _ := GenerateKey(drng)
+		_, err = Verify(otherSk.PublicKey(), alpha, proof)
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidProof), "Proof verified for wrong key")
+		otherGamma, _ := Prove(otherSk, alpha)
+		testutils.FatalUnless(t, !bytes.Equal(beta, OutputFromGamma(&otherGamma)), "Different keys give the same output")
+	}
+}
+
+func TestKnownAnswer(t *testing.T) {
+	skBytes := make([]byte, PrivateKeySize)
+	skBytes[0] = 42
+	sk, err := PrivateKeyFromBytes(skBytes)
+	testutils.FatalUnless(t, err == nil, "PrivateKeyFromBytes failed: %v", err)
+	testutils.FatalUnless(t, hex.EncodeToString(sk.PublicKey().Bytes()) == katPublicKey, "Public key encoding does not match test vector")
+	_, proof := Prove(sk, []byte("sample"))
+	testutils.FatalUnless(t, hex.EncodeToString(proof) == katProof, "Proof does not match test vector")
+	beta, err := Verify(sk.PublicKey(), []byte("sample"), proof)
+	testutils.FatalUnless(t, err == nil && hex.EncodeToString(beta) == katOutput, "Output does not match test vector")
+}
+
+// The input point must not depend on the key (this is required for ring VRFs).
+func TestInputPointIndependentOfKey(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk1, _ := GenerateKey(drng)
+	sk2, _ := GenerateKey(drng)
+	alpha := []byte("input")
+	gamma1, _ := Prove(sk1, alpha)
+	gamma2, _ := Prove(sk2, alpha)
+	// gamma2 == (x2/x1) * gamma1
+	var ratio exponents.Exponent
+	ratio.Divide(sk2.key.Scalar(), sk1.key.Scalar())
+	gamma1.ExponentiateEq(&ratio)
+	testutils.FatalUnless(t, gamma1.IsEqual(&gamma2), "Input point depends on the key")
+}
+
+func TestTamperedProofs(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk, _ := GenerateKey(drng)
+	pk := sk.PublicKey()
+	alpha := []byte("abc")
+	_, proof := Prove(sk, alpha)
+
+	// flip a bit in each byte. This either gives an invalid encoding or a proof that does not verify.
+	for i := 0; i < ProofSize; i++ {
+		modified := append([]byte{}, proof...)
+		modified[i] ^= 1
+		_, err := Verify(pk, alpha, modified)
+		testutils.FatalUnless(t, errors.Is(err, ErrInvalidProof), "Modified proof verified (byte %v)", i)
+	}
+
+	_, err := Verify(pk, alpha, proof[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidProof), "Proof of wrong length accepted")
+	_, err = ProofToHash(append(proof, 0))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidProof), "ProofToHash accepted proof of wrong length")
+
+	// non-canonical s
+	modified := append([]byte{}, proof...)
+	for i := PublicKeySize + ChallengeSize; i < ProofSize; i++ {
+		modified[i] = 0xFF
+	}
+	_, err = ProofToHash(modified)
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidProof), "ProofToHash accepted non-canonical s")
+}
+
+func TestKeys(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk, _ := GenerateKey(drng)
+	sk2, err := PrivateKeyFromBytes(sk.Bytes())
+	testutils.FatalUnless(t, err == nil && sk.Equal(sk2), "Private key roundtrip failed")
+	pk, err := PublicKeyFromBytes(sk.PublicKey().Bytes())
+	testutils.FatalUnless(t, err == nil && pk.Equal(sk.PublicKey()), "Public key roundtrip failed")
+	pkPoint := pk.Point()
+	testutils.FatalUnless(t, pkPoint.IsEqual(sk.key.PublicKey().Point()), "Public key point roundtrip failed")
+
+	_, err = PrivateKeyFromBytes(make([]byte, PrivateKeySize))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "PrivateKeyFromBytes accepted zero")
+	_, err = PrivateKeyFromBytes(make([]byte, PrivateKeySize+1))
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPrivateKey), "PrivateKeyFromBytes accepted wrong length")
+	neutral := keypair.EncodePoint(&curvePoints.NeutralElement_xtw_subgroup)
+	_, err = PublicKeyFromBytes(neutral[:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "PublicKeyFromBytes accepted neutral element")
+	_, err = PublicKeyFromBytes(neutral[1:])
+	testutils.FatalUnless(t, errors.Is(err, ErrInvalidPublicKey), "PublicKeyFromBytes accepted wrong length")
+
+	designatedErr := errors.New("designated error")
+	_, err = GenerateKey(iotest.ErrReader(designatedErr))
+	testutils.FatalUnless(t, errors.Is(err, designatedErr), "GenerateKey did not report error")
+}
+
+func BenchmarkProve(b *testing.B) {
+	sk, _ := GenerateKey(rand.New(rand.NewSource(1)))
+	alpha := []byte("abc")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Prove(sk, alpha)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	sk, _ := GenerateKey(rand.New(rand.NewSource(1)))
+	alpha := []byte("abc")
+	_, proof := Prove(sk, alpha)
+	pk := sk.PublicKey()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Verify(pk, alpha, proof)
+	}
+}
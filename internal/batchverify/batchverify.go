@@ -0,0 +1,111 @@
+package batchverify
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+)
+
+// This file contains the machinery shared by the batch verification functions of the signature packages.
+//
+// Each signature gives a verification equation of the form
+//
+//	g*G + sum_j a_j * P_j == neutral element
+//
+// where G is the generator of the prime-order subgroup and the P_j are points in the prime-order subgroup.
+// To check many such equations at once, we draw independent random weights z_i of WeightBytes bytes and check the single equation
+//
+//	(sum_i z_i * g_i) * G + sum_{i,j} (z_i * a_ij) * P_ij == neutral element
+//
+// with one multi-exponentiation. If any equation does not hold, this fails except with probability 2^-(8*WeightBytes).
+// If the combined check fails, we bisect to find the equations that do not hold.
+//
+// Note that this relies on all points being in the prime-order subgroup: For points with a small-order component, the small-order parts need not cancel.
+
+// ErrorPrefix is prepended to all errors messages originating from this package.
+const ErrorPrefix = "bandersnatch / internal / batchverify: "
+
+// WeightBytes is the size in bytes of the random weights. This gives 128 bits of security.
+const WeightBytes = 16
+
+// Equation is the verification equation GeneratorScalar*G + sum_j Scalars[j]*Points[j] == neutral element for a single signature.
+type Equation struct {
+	GeneratorScalar exponents.Exponent
+	Points          []curvePoints.Point_xtw_subgroup
+	Scalars         []exponents.Exponent
+}
+
+// FindInvalid returns the (sorted) indices of all equations that do not hold. The result is nil iff all equations hold (with overwhelming probability);
+// in particular, it is nil for an empty list of equations.
+//
+// Random weights are read from rnd; if rnd is nil, we use crypto/rand.Reader. We return an error if reading from rnd fails.
+//
+// The cost is a single multi-exponentiation if all equations hold. Otherwise, we recursively split the batch into halves,
+// so that k invalid signatures among n need O(k*log(n)) multi-exponentiations.
+func FindInvalid(rnd io.Reader, equations []Equation) (invalid []int, err error) {
+	n := len(equations)
+	if n == 0 {
+		return nil, nil
+	}
+	// We draw all weights once. Since the weights are independent of the equations, using the same weights when checking sub-batches is fine.
+	weights := make([]exponents.Exponent, n)
+	var weightBuf [WeightBytes]byte
+	var weightInt big.Int
+	for i := range weights {
+		if err = common.ReadRandomBytes(rnd, weightBuf[:]); err != nil {
+			return nil, err
+		}
+		weightInt.SetBytes(weightBuf[:])
+		weights[i].SetBigInt(&weightInt)
+	}
+	bisect(equations, weights, 0, n, true, &invalid)
+	return invalid, nil
+}
+
+// bisect appends the indices (in [from, to)) of the equations that do not hold to invalid.
+//
+// If needsCheck is false, the caller already knows that the combined check for [from, to) fails, so we skip it.
+func bisect(equations []Equation, weights []exponents.Exponent, from int, to int, needsCheck bool, invalid *[]int) {
+	if needsCheck && checkCombined(equations[from:to], weights[from:to]) {
+		return
+	}
+	if to-from == 1 {
+		*invalid = append(*invalid, from)
+		return
+	}
+	mid := from + (to-from)/2
+	// If the combined check for [from, to) fails, but the one for the first half succeeds, the one for the second half must fail, so we can skip it.
+	lenBefore := len(*invalid)
+	bisect(equations, weights, from, mid, true, invalid)
+	secondHalfNeedsCheck := len(*invalid) != lenBefore
+	bisect(equations, weights, mid, to, secondHalfNeedsCheck, invalid)
+}
+
+// checkCombined checks the random linear combination of the given equations with the given weights.
+func checkCombined(equations []Equation, weights []exponents.Exponent) bool {
+	numPoints := 1
+	for i := range equations {
+		if len(equations[i].Points) != len(equations[i].Scalars) {
+			panic(ErrorPrefix + "Equation has different number of points and scalars")
+		}
+		numPoints += len(equations[i].Points)
+	}
+	points := make(curvePoints.CurvePointSlice_xtw_subgroup, 0, numPoints)
+	scalars := make([]exponents.Exponent, 1, numPoints)
+	points = append(points, curvePoints.SubgroupGenerator_xtw_subgroup)
+	var temp exponents.Exponent
+	for i := range equations {
+		temp.Mul(&weights[i], &equations[i].GeneratorScalar)
+		scalars[0].Add(&scalars[0], &temp)
+		for j := range equations[i].Points {
+			points = append(points, equations[i].Points[j])
+			temp.Mul(&weights[i], &equations[i].Scalars[j])
+			scalars = append(scalars, temp)
+		}
+	}
+	result := curvePoints.MultiExponentiate(points, scalars)
+	return result.IsNeutralElement()
+}
@@ -0,0 +1,64 @@
+package batchverify
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+// makeEquation returns a random equation a*G - b*P - Q == neutral element with P = x*G, Q = (a - b*x)*G. If valid is false, Q is modified to make the equation fail.
+func makeEquation(drng *rand.Rand, valid bool) (ret Equation) {
+	var a, b, x, temp exponents.Exponent
+	a.SetRandom(drng)
+	b.SetRandom(drng)
+	x.SetRandom(drng)
+	var P, Q curvePoints.Point_xtw_subgroup
+	P.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &x)
+	temp.Mul(&b, &x)
+	temp.Sub(&a, &temp)
+	if !valid {
+		var one exponents.Exponent
+		one.SetOne()
+		temp.Add(&temp, &one)
+	}
+	Q.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, &temp)
+	ret.GeneratorScalar = a
+	b.Neg(&b)
+	var minusOne exponents.Exponent
+	minusOne.SetInt(-1)
+	ret.Points = []curvePoints.Point_xtw_subgroup{P, Q}
+	ret.Scalars = []exponents.Exponent{b, minusOne}
+	return
+}
+
+func TestFindInvalid(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	invalid, err := FindInvalid(drng, nil)
+	testutils.FatalUnless(t, err == nil && invalid == nil, "Empty input not accepted")
+	for n := 1; n <= 9; n++ {
+		for trial := 0; trial < 4; trial++ {
+			equations := make([]Equation, n)
+			var expected []int
+			for i := range equations {
+				valid := drng.Intn(3) != 0
+				if !valid {
+					expected = append(expected, i)
+				}
+				equations[i] = makeEquation(drng, valid)
+			}
+			invalid, err = FindInvalid(drng, equations)
+			testutils.FatalUnless(t, err == nil, "FindInvalid failed: %v", err)
+			testutils.FatalUnless(t, len(invalid) == len(expected), "FindInvalid returned %v, expected %v", invalid, expected)
+			testutils.FatalUnless(t, (invalid == nil) == (expected == nil), "FindInvalid does not signal validity by a nil result")
+			for i := range expected {
+				testutils.FatalUnless(t, invalid[i] == expected[i], "FindInvalid returned %v, expected %v", invalid, expected)
+			}
+		}
+	}
+	bad := makeEquation(drng, true)
+	bad.Scalars = bad.Scalars[:1]
+	testutils.FatalUnless(t, testutils.CheckPanic(FindInvalid, drng, []Equation{bad}), "FindInvalid did not panic on malformed equation")
+}
@@ -0,0 +1,221 @@
+package keypair
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/common"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/pointserializer"
+)
+
+// This file contains the key pairs and the encodings of points and scalars shared by the signature and encryption packages.
+//
+// Notation: G is the generator curvePoints.SubgroupGenerator_xtw_subgroup of the prime-order subgroup, a private key is an exponent x != 0 and its public key is P = x*G.
+//
+// Points are encoded with the Banderwagon encoding pointserializer.BanderwagonShort. Since this encoding can only represent points in the prime-order subgroup,
+// decoding enforces subgroup membership. Scalars are encoded as numbers in 0 <= . < p253 with the same endianness as the point encoding (i.e. little endian).
+// Both encodings are unique, so decoding is strict.
+//
+// The packages building on this wrap PrivateKey and PublicKey in their own types and wrap the errors returned here in their own error values.
+
+// ErrorPrefix is prepended to all errors messages (and panic strings) originating from this package.
+const ErrorPrefix = "bandersnatch / internal / keypair: "
+
+const (
+	PointSize  = 32                            // size in bytes of encoded points (including public keys)
+	ScalarSize = exponents.ExponentBytesLength // size in bytes of encoded scalars (including private keys)
+)
+
+var (
+	ErrZeroScalar     = errors.New(ErrorPrefix + "scalar is zero")
+	ErrNeutralElement = errors.New(ErrorPrefix + "point is the neutral element")
+	ErrNaP            = errors.New(ErrorPrefix + "point is a NaP")
+)
+
+// pointSerializer is the serializer used for all curve points.
+var pointSerializer = pointserializer.BanderwagonShort
+
+// ScalarEndianness is the endianness used for encoding scalars.
+var ScalarEndianness common.FieldElementEndianness = pointSerializer.GetFieldElementEndianness()
+
+// EncodePoint returns the encoding of p, which must be in the prime-order subgroup.
+func EncodePoint(p curvePoints.CurvePointPtrInterfaceRead) (ret [PointSize]byte) {
+	var buf bytes.Buffer
+	bytesWritten, err := pointSerializer.SerializeCurvePoint(&buf, p)
+	if err != nil || bytesWritten != PointSize {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when serializing curve point: %w", err))
+	}
+	copy(ret[:], buf.Bytes())
+	return
+}
+
+// DecodePoint decodes a curve point in the format written by EncodePoint. This includes a subgroup check.
+//
+// We return an error if buf has the wrong length or is not a valid encoding. Note that the neutral element is accepted.
+func DecodePoint(buf []byte) (ret curvePoints.Point_xtw_subgroup, err error) {
+	if len(buf) != PointSize {
+		err = fmt.Errorf(ErrorPrefix+"encoded curve point has length %v, expected %v", len(buf), PointSize)
+		return
+	}
+	_, err = pointSerializer.DeserializeCurvePoint(bytes.NewReader(buf), common.UntrustedInput, &ret)
+	return
+}
+
+// EncodeScalar returns the encoding of s. Note that the encoding of s is reduced modulo p253.
+func EncodeScalar(s *exponents.Exponent) [ScalarSize]byte {
+	return s.ToCanonicalBytes(ScalarEndianness)
+}
+
+// DecodeScalar decodes a scalar in the format written by EncodeScalar.
+//
+// We return an error if buf has the wrong length or encodes a number >= p253. The result is fully reduced.
+func DecodeScalar(buf []byte) (ret exponents.Exponent, err error) {
+	err = ret.SetCanonicalBytes(buf, ScalarEndianness)
+	return
+}
+
+// DeriveNonce computes a non-zero nonce from input as exponents.HashToExponent(input, dst).
+//
+// Since the output of HashToExponent is (close to) uniform, the result is zero with negligible probability; we panic in that case.
+func DeriveNonce(input []byte, dst string) exponents.Exponent {
+	k, err := exponents.HashToExponent(input, []byte(dst))
+	if err != nil {
+		panic(fmt.Errorf(ErrorPrefix+"unexpected error when deriving nonce: %w", err))
+	}
+	if k.IsZero_Subgroup() {
+		panic(ErrorPrefix + "derived nonce is zero")
+	}
+	return k
+}
+
+// PublicKey is a point P in the prime-order subgroup other than the neutral element, together with its cached encoding.
+// The zero value is not a valid public key; use PublicKeyFromPoint, PublicKeyFromBytes or PrivateKey.PublicKey to obtain one.
+type PublicKey struct {
+	point   curvePoints.Point_xtw_subgroup // P, never the neutral element
+	encoded [PointSize]byte                // cached encoding of point
+}
+
+// PrivateKey is a non-zero scalar x together with its public key x*G.
+// The zero value is not a valid private key; use GenerateKey, NewPrivateKey or PrivateKeyFromBytes to create one.
+type PrivateKey struct {
+	scalar    exponents.Exponent // x, fully reduced and non-zero
+	publicKey PublicKey          // x*G
+}
+
+// PublicKeyFromPoint creates a public key from the given curve point.
+//
+// We return an error wrapping ErrNaP or ErrNeutralElement if p is a NaP or the neutral element.
+func PublicKeyFromPoint(p *curvePoints.Point_xtw_subgroup) (ret PublicKey, err error) {
+	if p.IsNaP() {
+		err = ErrNaP
+		return
+	}
+	if p.IsNeutralElement() {
+		err = ErrNeutralElement
+		return
+	}
+	ret.point = *p
+	ret.encoded = EncodePoint(p)
+	return
+}
+
+// PublicKeyFromBytes decodes a public key in the format written by EncodePoint.
+//
+// We return an error if buf is not a valid encoding of a point in the prime-order subgroup or encodes the neutral element.
+func PublicKeyFromBytes(buf []byte) (ret PublicKey, err error) {
+	point, err := DecodePoint(buf)
+	if err != nil {
+		return
+	}
+	if point.IsNeutralElement() {
+		err = ErrNeutralElement
+		return
+	}
+	ret.point = point
+	copy(ret.encoded[:], buf)
+	return
+}
+
+// Point returns a pointer to the public key as a curve point. The caller must not modify the result.
+func (pk *PublicKey) Point() *curvePoints.Point_xtw_subgroup {
+	return &pk.point
+}
+
+// Encoding returns the encoding of the public key.
+func (pk *PublicKey) Encoding() [PointSize]byte {
+	return pk.encoded
+}
+
+// Bytes returns the encoding of the public key as a newly allocated slice.
+func (pk *PublicKey) Bytes() []byte {
+	ret := pk.encoded
+	return ret[:]
+}
+
+// Equal checks whether pk and other are the same public key.
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	return pk.encoded == other.encoded
+}
+
+// GenerateKey generates a new private key, reading randomness from rnd. If rnd is nil, we use crypto/rand.Reader.
+//
+// We return an error if reading from rnd fails.
+func GenerateKey(rnd io.Reader) (ret PrivateKey, err error) {
+	var scalar exponents.Exponent
+	for scalar.IsZero_Subgroup() {
+		if err = scalar.SetRandom(rnd); err != nil {
+			return
+		}
+	}
+	return NewPrivateKey(&scalar)
+}
+
+// NewPrivateKey creates the private key with scalar x = scalar (modulo p253).
+//
+// We return an error wrapping ErrZeroScalar if scalar is zero modulo p253.
+func NewPrivateKey(scalar *exponents.Exponent) (ret PrivateKey, err error) {
+	if scalar.IsZero_Subgroup() {
+		err = ErrZeroScalar
+		return
+	}
+	ret.scalar = scalar.ModuloP253()
+	ret.publicKey.point.ExponentiateConstantTime(&curvePoints.SubgroupGenerator_xtw_subgroup, &ret.scalar)
+	ret.publicKey.encoded = EncodePoint(&ret.publicKey.point)
+	return
+}
+
+// PrivateKeyFromBytes decodes a private key in the format written by PrivateKey.Bytes.
+//
+// We return an error if buf has the wrong length, encodes a number >= p253 or encodes 0.
+func PrivateKeyFromBytes(buf []byte) (ret PrivateKey, err error) {
+	scalar, err := DecodeScalar(buf)
+	if err != nil {
+		return
+	}
+	return NewPrivateKey(&scalar)
+}
+
+// Scalar returns a pointer to the (secret) scalar x. The caller must not modify the result.
+func (sk *PrivateKey) Scalar() *exponents.Exponent {
+	return &sk.scalar
+}
+
+// PublicKey returns a pointer to the public key x*G. The caller must not modify the result.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return &sk.publicKey
+}
+
+// Bytes returns the encoding of the private key. Note that the result is secret.
+func (sk *PrivateKey) Bytes() []byte {
+	ret := EncodeScalar(&sk.scalar)
+	return ret[:]
+}
+
+// Equal checks whether sk and other are the same private key.
+func (sk *PrivateKey) Equal(other *PrivateKey) bool {
+	return sk.scalar.IsEqual(&other.scalar)
+}
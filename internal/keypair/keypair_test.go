@@ -0,0 +1,75 @@
+package keypair
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/curvePoints"
+	"github.com/GottfriedHerold/Bandersnatch/bandersnatch/exponents"
+	"github.com/GottfriedHerold/Bandersnatch/internal/testutils"
+)
+
+func TestKeyRoundtrip(t *testing.T) {
+	var drng *rand.Rand = rand.New(rand.NewSource(1))
+	sk, err := GenerateKey(drng)
+	testutils.FatalUnless(t, err == nil, "GenerateKey failed: %v", err)
+	var expected curvePoints.Point_xtw_subgroup
+	expected.Exponentiate(&curvePoints.SubgroupGenerator_xtw_subgroup, sk.Scalar())
+	testutils.FatalUnless(t, expected.IsEqual(sk.PublicKey().Point()), "Public key is not x*G")
+
+	sk2, err := PrivateKeyFromBytes(sk.Bytes())
+	testutils.FatalUnless(t, err == nil && sk.Equal(&sk2), "Private key roundtrip failed")
+	testutils.FatalUnless(t, sk2.PublicKey().Equal(sk.PublicKey()), "Private key roundtrip changed public key")
+
+	pk, err := PublicKeyFromBytes(sk.PublicKey().Bytes())
+	testutils.FatalUnless(t, err == nil && pk.Equal(sk.PublicKey()), "Public key roundtrip failed")
+	pk2, err := PublicKeyFromPoint(pk.Point())
+	testutils.FatalUnless(t, err == nil && pk2.Encoding() == pk.Encoding(), "PublicKeyFromPoint gives different encoding")
+
+	// Bytes must return a copy
+	pkBytes := pk.Bytes()
+	pkBytes[0] ^= 1
+	testutils.FatalUnless(t, pk.Equal(&pk2), "Modifying result of Bytes modified public key")
+
+	var s exponents.Exponent
+	s.SetRandom(drng)
+	encoded := EncodeScalar(&s)
+	decoded, err := DecodeScalar(encoded[:])
+	testutils.FatalUnless(t, err == nil && decoded.IsEqual(&s), "Scalar roundtrip failed")
+}
+
+func TestInvalidKeys(t *testing.T) {
+	_, err := GenerateKey(iotest.ErrReader(errors.New("some error")))
+	testutils.FatalUnless(t, err != nil, "GenerateKey did not report read error")
+
+	var zero exponents.Exponent
+	_, err = NewPrivateKey(&zero)
+	testutils.FatalUnless(t, errors.Is(err, ErrZeroScalar), "NewPrivateKey accepted zero")
+	_, err = PrivateKeyFromBytes(make([]byte, ScalarSize))
+	testutils.FatalUnless(t, errors.Is(err, ErrZeroScalar), "PrivateKeyFromBytes accepted zero")
+	_, err = PrivateKeyFromBytes(make([]byte, ScalarSize-1))
+	testutils.FatalUnless(t, err != nil, "PrivateKeyFromBytes accepted wrong length")
+
+	_, err = PublicKeyFromPoint(&curvePoints.NeutralElement_xtw_subgroup)
+	testutils.FatalUnless(t, errors.Is(err, ErrNeutralElement), "PublicKeyFromPoint accepted neutral element")
+	var nap curvePoints.Point_xtw_subgroup
+	_, err = PublicKeyFromPoint(&nap)
+	testutils.FatalUnless(t, errors.Is(err, ErrNaP), "PublicKeyFromPoint accepted NaP")
+	neutral := EncodePoint(&curvePoints.NeutralElement_xtw_subgroup)
+	_, err = DecodePoint(neutral[:])
+	testutils.FatalUnless(t, err == nil, "DecodePoint rejected neutral element")
+	_, err = PublicKeyFromBytes(neutral[:])
+	testutils.FatalUnless(t, errors.Is(err, ErrNeutralElement), "PublicKeyFromBytes accepted neutral element")
+	_, err = PublicKeyFromBytes(neutral[1:])
+	testutils.FatalUnless(t, err != nil, "PublicKeyFromBytes accepted wrong length")
+}
+
+func TestDeriveNonce(t *testing.T) {
+	k1 := DeriveNonce([]byte("abc"), "DST1")
+	k2 := DeriveNonce([]byte("abc"), "DST1")
+	k3 := DeriveNonce([]byte("abc"), "DST2")
+	testutils.FatalUnless(t, k1.IsEqual(&k2), "DeriveNonce is not deterministic")
+	testutils.FatalUnless(t, !k1.IsEqual(&k3), "DeriveNonce ignores DST")
+}
@@ -0,0 +1,32 @@
+package testutils
+
+import "math"
+
+// This file provides a simple chi-squared test for uniformity. This is used to test random samplers.
+
+// ChiSquaredStatistic computes Pearson's chi-squared statistic of the observed counts, assuming that all buckets are equally likely.
+func ChiSquaredStatistic(counts []int) float64 {
+	var total int
+	for _, c := range counts {
+		total += c
+	}
+	expected := float64(total) / float64(len(counts))
+	var statistic float64
+	for _, c := range counts {
+		diff := float64(c) - expected
+		statistic += diff * diff / expected
+	}
+	return statistic
+}
+
+// ChiSquaredIsUniform returns whether the observed counts are consistent with a uniform distribution at significance level 0.001.
+//
+// The critical value is computed via the Wilson-Hilferty approximation, which is good enough for our purposes.
+// Since this is a statistical test, it fails for truly uniform samples with probability 0.001, so callers should use deterministic randomness.
+func ChiSquaredIsUniform(counts []int) bool {
+	const z = 3.090232 // 0.999 quantile of the standard normal distribution
+	degreesOfFreedom := float64(len(counts) - 1)
+	w := 2 / (9 * degreesOfFreedom)
+	criticalValue := degreesOfFreedom * math.Pow(1-w+z*math.Sqrt(w), 3)
+	return ChiSquaredStatistic(counts) < criticalValue
+}